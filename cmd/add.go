@@ -72,7 +72,7 @@ func (c *AddCommand) runE(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := getContext()
-	dto := app.AddEntryDTO{Env: env, Key: key, Value: value}
+	dto := app.AddEntryDTO{Env: env, Key: key, Value: value, Secret: isSecret}
 
 	err = c.useCase.Execute(ctx, dto)
 	if err != nil {
@@ -72,6 +72,24 @@ func TestAddCommand_Success(t *testing.T) {
 	}
 }
 
+func TestAddCommand_SecretIsPersisted(t *testing.T) {
+	mockUseCase := &mockAddUseCase{}
+	cmd, _ := NewAddCommand(mockUseCase, &test.MockPromptService{}, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", addTestConstants.env); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("secret", "true"); err != nil {
+		t.Fatalf("failed to set secret flag: %v", err)
+	}
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE returned unexpected error: %v", err)
+	}
+	if !mockUseCase.receivedDTO.Secret {
+		t.Error("expected --secret to be passed on to the use case")
+	}
+}
+
 func TestAddCommand_UseCaseError(t *testing.T) {
 	mockUseCase := &mockAddUseCase{
 		executeFunc: func(ctx context.Context, dto app.AddEntryDTO) error {
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/spf13/cobra"
+)
+
+// AgentCommand represents the agent command for holding unlocked vault keys in memory.
+type AgentCommand struct {
+	runUseCase    app.RunAgentUc
+	forgetUseCase app.ForgetAgentKeysUc
+	statusUseCase app.GetAgentStatusUc
+	logger        domain.Logger
+}
+
+// agentStatusOutput is the JSON form of the agent status.
+type agentStatusOutput struct {
+	Socket      string         `json:"socket"`
+	PID         int            `json:"pid"`
+	StartedAt   string         `json:"started_at"`
+	IdleTimeout string         `json:"idle_timeout"`
+	MaxLifetime string         `json:"max_lifetime"`
+	Keys        []agentKeyJSON `json:"keys"`
+}
+
+// agentKeyJSON describes a key held by the agent.
+type agentKeyJSON struct {
+	Env        string `json:"env"`
+	Slot       string `json:"slot"`
+	AddedAt    string `json:"added_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// NewAgentCommand creates a new agent command instance with its lock, status and forget
+// subcommands.
+func NewAgentCommand(
+	runUseCase app.RunAgentUc,
+	forgetUseCase app.ForgetAgentKeysUc,
+	statusUseCase app.GetAgentStatusUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &AgentCommand{runUseCase, forgetUseCase, statusUseCase, logger}
+
+	// lockify agent [lock|status|forget]
+	cobraCmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Hold unlocked vault keys in memory so passphrases are asked once",
+		Long: `Hold unlocked vault keys in memory so passphrases are asked once.
+
+lockify agent runs in the foreground until it is interrupted, listening on a unix socket
+that only you can use. With LOCKIFY_AGENT=1 set, a command that unlocks a vault with a
+passphrase hands the derived vault key to the agent, and later commands unlock the vault
+with it instead of asking again. The agent never sees passphrases.
+
+Commands only talk to a socket and directory that you own with modes 0600 and 0700.
+
+A key is dropped when it was not used for the idle timeout or was held for the max
+lifetime, and every key is wiped when the agent stops. The socket is $LOCKIFY_AGENT_SOCK,
+or agent.sock in a private lockify directory of $XDG_RUNTIME_DIR or the temp directory.`,
+		Example: `  lockify agent &
+  export LOCKIFY_AGENT=1
+  lockify agent --idle-timeout 5m --max-lifetime 1h
+  lockify agent status
+  lockify agent forget --env prod
+  lockify agent lock`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+	cobraCmd.Flags().Duration("idle-timeout", model.DefaultAgentIdleTimeout,
+		"Drop a key that was not used for this long")
+	cobraCmd.Flags().Duration("max-lifetime", model.DefaultAgentMaxLifetime,
+		"Drop a key this long after it was added")
+
+	lockCmd := &cobra.Command{
+		Use:     "lock",
+		Short:   "Drop every key held by the agent",
+		Example: `  lockify agent lock`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runLock,
+	}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the agent runs and which environments it holds keys for",
+		Long: `Show whether the agent runs and which environments it holds keys for.
+
+status exits with status 1 when no agent is running.`,
+		Example: `  lockify agent status
+  lockify agent status --output json`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runStatus,
+	}
+	statusCmd.Flags().String("output", "text", "The output format [text|json]")
+	forgetCmd := &cobra.Command{
+		Use:     "forget",
+		Short:   "Drop the keys the agent holds for an environment",
+		Example: `  lockify agent forget --env prod`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runForget,
+	}
+	forgetCmd.Flags().StringP("env", "e", "", "Environment name")
+	if err := forgetCmd.MarkFlagRequired("env"); err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	cobraCmd.AddCommand(lockCmd, statusCmd, forgetCmd)
+	return cobraCmd, nil
+}
+
+func (c *AgentCommand) runE(cmd *cobra.Command, args []string) error {
+	idleTimeout, err := cmd.Flags().GetDuration("idle-timeout")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve idle-timeout flag: %w", err)
+	}
+	maxLifetime, err := cmd.Flags().GetDuration("max-lifetime")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve max-lifetime flag: %w", err)
+	}
+	policy := model.AgentPolicy{IdleTimeout: idleTimeout, MaxLifetime: maxLifetime}
+
+	ctx, stop := signal.NotifyContext(getContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = c.runUseCase.Execute(ctx, policy, func(socket string) {
+		c.logger.Success("Agent listening on %s", socket)
+		c.logger.Info(
+			"Keys are dropped after %s unused or %s held; press Ctrl+C to stop",
+			idleTimeout,
+			maxLifetime,
+		)
+		c.logger.Info("Set LOCKIFY_AGENT=1 so that commands hand unlocked keys to the agent")
+	})
+	if err != nil {
+		c.logger.Error("failed to run agent: %v", err)
+		return err
+	}
+
+	c.logger.Success("Agent stopped, all keys wiped")
+	return nil
+}
+
+func (c *AgentCommand) runLock(cmd *cobra.Command, args []string) error {
+	dropped, err := c.forgetUseCase.Execute(getContext(), "")
+	if err != nil {
+		c.logger.Error("failed to lock agent: %v", err)
+		return err
+	}
+
+	c.logger.Success("Agent dropped %d key(s)", dropped)
+	return nil
+}
+
+func (c *AgentCommand) runForget(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	dropped, err := c.forgetUseCase.Execute(getContext(), env)
+	if err != nil {
+		c.logger.Error("failed to forget keys of %s: %v", env, err)
+		return err
+	}
+
+	c.logger.Success("Agent dropped %d key(s) of %s", dropped, env)
+	return nil
+}
+
+func (c *AgentCommand) runStatus(cmd *cobra.Command, args []string) error {
+	output, err := requireStringFlag(cmd, "output")
+	if err != nil {
+		return err
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be text or json", output)
+	}
+
+	status, err := c.statusUseCase.Execute(getContext())
+	if errors.Is(err, service.ErrAgentNotRunning) {
+		// Whether an agent runs is the result; the exit code lets scripts check it.
+		c.logger.Warning("%v", err)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &ExitError{Code: 1}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get agent status: %w", err)
+	}
+
+	if output == "json" {
+		return c.writeStatusJSON(status)
+	}
+
+	c.logger.Success(
+		"Agent running on %s (pid %d) since %s",
+		status.Socket,
+		status.PID,
+		status.StartedAt.UTC().Format(time.RFC3339),
+	)
+	c.logger.Info(
+		"Keys are dropped after %s unused or %s held",
+		status.Policy.IdleTimeout,
+		status.Policy.MaxLifetime,
+	)
+	if len(status.Keys) == 0 {
+		c.logger.Info("The agent holds no keys")
+		return nil
+	}
+
+	rows := [][]string{{"ENV", "SLOT", "ADDED", "LAST USED", "EXPIRES"}}
+	for _, key := range status.Keys {
+		rows = append(rows, []string{
+			key.Env,
+			orDash(key.Slot),
+			key.AddedAt.UTC().Format(time.RFC3339),
+			key.LastUsedAt.UTC().Format(time.RFC3339),
+			key.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+	for _, line := range formatTable(rows) {
+		c.logger.Output("%s", line)
+	}
+	return nil
+}
+
+func (c *AgentCommand) writeStatusJSON(status model.AgentStatus) error {
+	keys := make([]agentKeyJSON, 0, len(status.Keys))
+	for _, key := range status.Keys {
+		keys = append(keys, agentKeyJSON{
+			Env:        key.Env,
+			Slot:       key.Slot,
+			AddedAt:    key.AddedAt.UTC().Format(time.RFC3339),
+			LastUsedAt: key.LastUsedAt.UTC().Format(time.RFC3339),
+			ExpiresAt:  key.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	data, err := json.MarshalIndent(agentStatusOutput{
+		Socket:      status.Socket,
+		PID:         status.PID,
+		StartedAt:   status.StartedAt.UTC().Format(time.RFC3339),
+		IdleTimeout: status.Policy.IdleTimeout.String(),
+		MaxLifetime: status.Policy.MaxLifetime.String(),
+		Keys:        keys,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent status: %w", err)
+	}
+
+	c.logger.Output("%s", data)
+	return nil
+}
+
+func init() {
+	agentCmd, err := NewAgentCommand(
+		di.BuildRunAgent(),
+		di.BuildForgetAgentKeys(),
+		di.BuildGetAgentStatus(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(agentCmd)
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockRunAgentUseCase struct {
+	receivedPolicy model.AgentPolicy
+}
+
+func (m *mockRunAgentUseCase) Execute(
+	ctx context.Context,
+	policy model.AgentPolicy,
+	ready func(socket string),
+) error {
+	m.receivedPolicy = policy
+	ready("/run/lockify/agent.sock")
+	return nil
+}
+
+type mockForgetAgentKeysUseCase struct {
+	receivedEnv string
+}
+
+func (m *mockForgetAgentKeysUseCase) Execute(ctx context.Context, env string) (int, error) {
+	m.receivedEnv = env
+	return 2, nil
+}
+
+type mockGetAgentStatusUseCase struct {
+	status model.AgentStatus
+	err    error
+}
+
+func (m *mockGetAgentStatusUseCase) Execute(ctx context.Context) (model.AgentStatus, error) {
+	return m.status, m.err
+}
+
+func newTestAgentStatus() model.AgentStatus {
+	added := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	return model.AgentStatus{
+		Socket:    "/run/lockify/agent.sock",
+		PID:       42,
+		StartedAt: added,
+		Policy:    model.AgentPolicy{IdleTimeout: 15 * time.Minute, MaxLifetime: 8 * time.Hour},
+		Keys: []model.AgentKeyInfo{{
+			Env:        "prod",
+			Slot:       "default",
+			AddedAt:    added,
+			LastUsedAt: added.Add(5 * time.Minute),
+			ExpiresAt:  added.Add(20 * time.Minute),
+		}},
+	}
+}
+
+// newTestAgentCommand returns the agent command, or its subcommand when name is not empty
+func newTestAgentCommand(
+	t *testing.T,
+	name string,
+	runUseCase *mockRunAgentUseCase,
+	forgetUseCase *mockForgetAgentKeysUseCase,
+	statusUseCase *mockGetAgentStatusUseCase,
+	logger *test.MockLogger,
+) *cobra.Command {
+	t.Helper()
+	agentCmd, err := NewAgentCommand(runUseCase, forgetUseCase, statusUseCase, logger)
+	if err != nil {
+		t.Fatalf("NewAgentCommand() returned unexpected error: %v", err)
+	}
+	cmd := agentCmd
+	if name != "" {
+		if cmd, _, err = agentCmd.Find([]string{name}); err != nil {
+			t.Fatalf("failed to find agent %s command: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestAgentCommand_Run(t *testing.T) {
+	runUseCase := &mockRunAgentUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAgentCommand(
+		t,
+		"",
+		runUseCase,
+		&mockForgetAgentKeysUseCase{},
+		&mockGetAgentStatusUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("idle-timeout", "5m"); err != nil {
+		t.Fatalf("failed to set idle-timeout flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("agent returned unexpected error: %v", err))
+	assert.Equal(t, model.AgentPolicy{
+		IdleTimeout: 5 * time.Minute,
+		MaxLifetime: model.DefaultAgentMaxLifetime,
+	}, runUseCase.receivedPolicy)
+	assert.Count(t, 2, mockLogger.SuccessLogs)
+	assert.Contains(t, "/run/lockify/agent.sock", mockLogger.SuccessLogs[0])
+}
+
+func TestAgentCommand_LockAndForget(t *testing.T) {
+	forgetUseCase := &mockForgetAgentKeysUseCase{receivedEnv: "unset"}
+	mockLogger := &test.MockLogger{}
+	lockCmd := newTestAgentCommand(
+		t,
+		"lock",
+		&mockRunAgentUseCase{},
+		forgetUseCase,
+		&mockGetAgentStatusUseCase{},
+		mockLogger,
+	)
+
+	err := lockCmd.RunE(lockCmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("agent lock returned unexpected error: %v", err))
+	assert.Equal(t, "", forgetUseCase.receivedEnv, "agent lock should drop every key")
+
+	forgetCmd := newTestAgentCommand(
+		t,
+		"forget",
+		&mockRunAgentUseCase{},
+		forgetUseCase,
+		&mockGetAgentStatusUseCase{},
+		mockLogger,
+	)
+	if err := forgetCmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err = forgetCmd.RunE(forgetCmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("agent forget returned unexpected error: %v", err))
+	assert.Equal(t, "prod", forgetUseCase.receivedEnv)
+	assert.DeepEqual(t, []string{
+		"Agent dropped 2 key(s)",
+		"Agent dropped 2 key(s) of prod",
+	}, mockLogger.SuccessLogs)
+}
+
+func TestAgentStatusCommand_Text(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAgentCommand(
+		t,
+		"status",
+		&mockRunAgentUseCase{},
+		&mockForgetAgentKeysUseCase{},
+		&mockGetAgentStatusUseCase{status: newTestAgentStatus()},
+		mockLogger,
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("agent status returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{
+		"ENV   SLOT     ADDED                 LAST USED             EXPIRES",
+		"prod  default  2026-05-01T09:00:00Z  2026-05-01T09:05:00Z  2026-05-01T09:20:00Z",
+	}, mockLogger.OutputLogs)
+	assert.Contains(t, "15m0s unused or 8h0m0s held", mockLogger.InfoLogs[0])
+}
+
+func TestAgentStatusCommand_JSON(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAgentCommand(
+		t,
+		"status",
+		&mockRunAgentUseCase{},
+		&mockForgetAgentKeysUseCase{},
+		&mockGetAgentStatusUseCase{status: newTestAgentStatus()},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("output", "json"); err != nil {
+		t.Fatalf("failed to set output flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("agent status returned unexpected error: %v", err))
+	assert.Count(t, 1, mockLogger.OutputLogs)
+	output := mockLogger.OutputLogs[0]
+	for _, want := range []string{
+		`"idle_timeout": "15m0s"`,
+		`"env": "prod"`,
+		`"expires_at": "2026-05-01T09:20:00Z"`,
+	} {
+		assert.True(t, strings.Contains(output, want), fmt.Sprintf("output lacks %s", want))
+	}
+}
+
+func TestAgentStatusCommand_NotRunning(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAgentCommand(
+		t,
+		"status",
+		&mockRunAgentUseCase{},
+		&mockForgetAgentKeysUseCase{},
+		&mockGetAgentStatusUseCase{err: service.ErrAgentNotRunning},
+		mockLogger,
+	)
+
+	err := cmd.RunE(cmd, nil)
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), fmt.Sprintf("RunE() error = %v, want ExitError", err))
+	assert.Equal(t, 1, exitErr.Code)
+	assert.Count(t, 1, mockLogger.WarningLogs)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// AuditCommand represents the audit command for listing and verifying the audit log of a vault.
+type AuditCommand struct {
+	useCase app.ListAuditUc
+	logger  domain.Logger
+	now     func() time.Time
+}
+
+// NewAuditCommand creates a new audit command instance.
+func NewAuditCommand(useCase app.ListAuditUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &AuditCommand{useCase, logger, time.Now}
+
+	// lockify audit --env [env] --since [duration|date]
+	cobraCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List the audit log of a vault and verify its chain",
+		Long: `List the audit log of a vault and verify its chain.
+
+Lockify records who opened, read, exported, set, deleted or rotated entries of a vault, and
+who changed its key slots, recipients or policies, deleted or restored it, in
+<env>.audit.enc next to it, encrypted with the vault key. Each record holds the hash of the
+record before it, so a removed, reordered or modified record breaks the chain; audit
+reports the first broken record and exits with an error. Removing the newest records leaves
+the chain intact, so keep the audit log under version control with the vault.
+
+--since limits the listed events to a duration such as 7d or 12h, or to a date such as
+2026-01-31; the whole log is verified regardless.`,
+		Example: `  lockify audit --env prod
+  lockify audit --env prod --since 7d
+  lockify audit --env prod --since 2026-01-31`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment name")
+	cobraCmd.Flags().String(
+		"since", "", "Only list events of the last duration (7d, 12h) or since a date",
+	)
+	if err := cobraCmd.MarkFlagRequired("env"); err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *AuditCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	sinceFlag, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve since flag: %w", err)
+	}
+	var since time.Time
+	if sinceFlag != "" {
+		if since, err = parseSince(sinceFlag, c.now()); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Progress("Verifying the audit log of %s...\n", env)
+	report, err := c.useCase.Execute(getContext(), env, since)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log of %s: %w", env, err)
+	}
+
+	if len(report.Events) == 0 {
+		c.logger.Info("No audit events found")
+	} else {
+		rows := [][]string{{"#", "TIME", "OPERATION", "ACTOR", "HOST", "SLOT", "KEYS"}}
+		for _, event := range report.Events {
+			rows = append(rows, []string{
+				strconv.Itoa(event.Seq),
+				event.Time,
+				string(event.Operation),
+				orDash(event.Actor),
+				orDash(event.Host),
+				orDash(event.Slot),
+				orDash(strings.Join(event.Keys, ",")),
+			})
+		}
+		for _, line := range formatTable(rows) {
+			c.logger.Output("%s", line)
+		}
+	}
+
+	if report.Broken != nil {
+		c.logger.Error("Audit log of %s failed verification after %d record(s)", env, report.Total)
+		return report.Broken
+	}
+
+	c.logger.Success("Audit log of %s is intact (%d record(s))", env, report.Total)
+	return nil
+}
+
+// parseSince turns a duration such as 7d or 12h, or a date, into the time it starts at
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if since, err := time.Parse(layout, value); err == nil {
+			return since, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(
+		"invalid since value %q: use a duration such as 7d or 12h, or a date such as 2026-01-31",
+		value,
+	)
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	auditCmd, err := NewAuditCommand(di.BuildListAudit(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockListAuditUseCase struct {
+	report        app.AuditReport
+	receivedSince time.Time
+}
+
+func (m *mockListAuditUseCase) Execute(
+	ctx context.Context,
+	env string,
+	since time.Time,
+) (app.AuditReport, error) {
+	m.receivedSince = since
+	return m.report, nil
+}
+
+func newTestAuditCommand(
+	t *testing.T,
+	useCase app.ListAuditUc,
+	logger *test.MockLogger,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewAuditCommand(useCase, logger)
+	if err != nil {
+		t.Fatalf("NewAuditCommand() returned unexpected error: %v", err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func newTestAuditReport() app.AuditReport {
+	return app.AuditReport{
+		Events: []model.AuditEvent{
+			{
+				Seq:       1,
+				Time:      "2026-01-01T00:00:00Z",
+				Operation: model.AuditSet,
+				Keys:      []string{"API_KEY", "DB_URL"},
+				Actor:     "alice",
+				Host:      "laptop",
+				Slot:      "default",
+			},
+			{Seq: 2, Time: "2026-01-02T00:00:00Z", Operation: model.AuditOpen, Actor: "bob"},
+		},
+		Total: 2,
+	}
+}
+
+func TestAuditCommand_List(t *testing.T) {
+	useCase := &mockListAuditUseCase{report: newTestAuditReport()}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAuditCommand(t, useCase, mockLogger, map[string]string{
+		"env":   "prod",
+		"since": "2026-01-01",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("audit returned unexpected error: %v", err))
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), useCase.receivedSince)
+	assert.DeepEqual(t, []string{
+		"#  TIME                  OPERATION  ACTOR  HOST    SLOT     KEYS",
+		"1  2026-01-01T00:00:00Z  set        alice  laptop  default  API_KEY,DB_URL",
+		"2  2026-01-02T00:00:00Z  open       bob    -       -        -",
+	}, mockLogger.OutputLogs)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestAuditCommand_BrokenChain(t *testing.T) {
+	report := newTestAuditReport()
+	report.Broken = &model.AuditChainError{Seq: 3, Reason: "record was modified"}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestAuditCommand(
+		t,
+		&mockListAuditUseCase{report: report},
+		mockLogger,
+		map[string]string{"env": "prod"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	var chainErr *model.AuditChainError
+	assert.True(t, errors.As(err, &chainErr), fmt.Sprintf("audit got error %v", err))
+	assert.Count(t, 3, mockLogger.OutputLogs, "the events before the break should be listed")
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestAuditCommand_InvalidSince(t *testing.T) {
+	cmd := newTestAuditCommand(
+		t,
+		&mockListAuditUseCase{},
+		&test.MockLogger{},
+		map[string]string{"env": "prod", "since": "last week"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "audit with an invalid --since expected error, got nil")
+	assert.Contains(t, "invalid since value", err.Error())
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "7d", want: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{value: "12h", want: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-02-01", want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-02-01T08:30:00Z", want: time.Date(2026, 2, 1, 8, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			since, err := parseSince(tt.value, now)
+			assert.Nil(t, err, fmt.Sprintf("parseSince() returned unexpected error: %v", err))
+			assert.Equal(t, tt.want, since)
+		})
+	}
+
+	for _, value := range []string{"-3d", "yesterday", "-1h"} {
+		_, err := parseSince(value, now)
+		assert.NotNil(t, err, fmt.Sprintf("parseSince(%q) expected error, got nil", value))
+	}
+}
@@ -24,9 +24,10 @@ func NewClearCommand(
 	return &cobra.Command{
 		Use:   "cache clear",
 		Short: "Clear cached passphrases",
-		Long: `Clear all cached passphrases from the system keyring.
+		Long: `Clear all cached passphrases.
 
-This command removes all passphrases that were cached in the system keyring.
+This command removes all passphrases that were cached in the system keyring, or in the
+cache backend chosen with $LOCKIFY_CACHE (keyring, file or none).
 You will be prompted for passphrases again on next use.`,
 		Example: `  lockify cache clear`,
 		RunE:    cmd.runE,
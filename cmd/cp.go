@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// NewCopyCommand creates a new cp command instance.
+func NewCopyCommand(useCase app.MoveEntryUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &MoveCommand{useCase, logger, true}
+
+	// lockify cp --env [env] [key] [new key]
+	// lockify cp --env [env] --to-env [env] [key] [new key]
+	cobraCmd := &cobra.Command{
+		Use:   "cp KEY [NEW_KEY]",
+		Short: "Copy an entry to another key or environment",
+		Long: `Copy an entry to another key or environment.
+
+The copy is a new entry with the same value and secret marker. To copy several entries
+between environments at once, use lockify promote.`,
+		Example: `  lockify cp --env prod DATABASE_URL DATABASE_REPLICA_URL
+  lockify cp --env staging --to-env dev API_URL`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: cmd.runE,
+	}
+
+	if err := addMoveFlags(cobraCmd, "Environment to copy the entry to"); err != nil {
+		return nil, err
+	}
+
+	return cobraCmd, nil
+}
+
+func init() {
+	cpCmd, err := NewCopyCommand(di.BuildMoveEntry(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(cpCmd)
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/spf13/cobra"
+)
+
+// DiffCommand represents the diff command for comparing two environments.
+type DiffCommand struct {
+	useCase app.DiffEnvsUc
+	logger  domain.Logger
+}
+
+// diffOutput is the JSON document written by diff --output json.
+type diffOutput struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Added   []keyDiffJSON `json:"added"`
+	Removed []keyDiffJSON `json:"removed"`
+	Changed []keyDiffJSON `json:"changed"`
+}
+
+// keyDiffJSON is a differing key; its values are only set with --show-values.
+type keyDiffJSON struct {
+	Key  string  `json:"key"`
+	From *string `json:"from,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+// NewDiffCommand creates a new diff command instance.
+func NewDiffCommand(useCase app.DiffEnvsUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &DiffCommand{useCase, logger}
+
+	// lockify diff --from [env] --to [env]
+	// lockify diff --from [env] --file [path] --format [dotenv|json]
+	cobraCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the keys that differ between two environments",
+		Long: `Show the keys that differ between two environments.
+
+Keys that only exist in --to are shown as added (+), keys that only exist in --from as
+removed (-) and keys whose values differ as changed (~). Instead of another environment,
+--from can be compared with a dotenv or JSON file.
+
+Both vaults are unlocked to compare the values, but the values are only shown with
+--show-values. lockify exits with code 1 when there are differences and with code 2 when
+the comparison fails, so CI pipelines can fail on differences and tell them from errors.`,
+		Example: `  lockify diff --from staging --to prod
+  lockify diff --from staging --to prod --show-values
+  lockify diff --from prod --file .env.example --output json`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().String("from", "", "Environment to compare from")
+	cobraCmd.Flags().String("to", "", "Environment to compare with")
+	cobraCmd.Flags().String("file", "", "File to compare with instead of an environment")
+	cobraCmd.Flags().String("format", "dotenv", "The format of the file [dotenv|json]")
+	cobraCmd.Flags().String("output", "text", "The output format [text|json]")
+	cobraCmd.Flags().Bool("show-values", false, "Show the values of the differing keys")
+	// --from is required, but checked by runE so that its absence exits with code 2.
+	exitWithFailureCode(cobraCmd)
+
+	return cobraCmd, nil
+}
+
+func (c *DiffCommand) runE(cmd *cobra.Command, args []string) error {
+	from, err := requireStringFlag(cmd, "from")
+	if err != nil {
+		return err
+	}
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve to flag: %w", err)
+	}
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve file flag: %w", err)
+	}
+	if (to == "") == (file == "") {
+		return errors.New("exactly one of the to and file flags is required")
+	}
+
+	format, err := requireStringFlag(cmd, "format")
+	if err != nil {
+		return err
+	}
+	fileFormat, err := value.NewFileFormat(format)
+	if err != nil {
+		return err
+	}
+	output, err := requireStringFlag(cmd, "output")
+	if err != nil {
+		return err
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be text or json", output)
+	}
+	showValues, err := cmd.Flags().GetBool("show-values")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve show-values flag: %w", err)
+	}
+
+	dto := app.DiffEnvsDTO{From: from, To: to, Format: fileFormat, ShowValues: showValues}
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+		dto.File, to = f, file
+	}
+
+	result, err := c.useCase.Execute(getContext(), dto)
+	if err != nil {
+		return fmt.Errorf("failed to compare %s with %s: %w", from, to, err)
+	}
+
+	if output == "json" {
+		if err := c.writeJSON(from, to, result, showValues); err != nil {
+			return err
+		}
+	} else {
+		c.writeText(from, to, result, showValues)
+	}
+
+	if result.HasDifferences() {
+		// The differences are the result; the exit code lets scripts act on them.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &ExitError{Code: exitCodeFindings}
+	}
+
+	return nil
+}
+
+func (c *DiffCommand) writeText(from, to string, result app.DiffResult, showValues bool) {
+	if !result.HasDifferences() {
+		c.logger.Success("No differences between %s and %s", from, to)
+		return
+	}
+
+	for _, diff := range result.Added {
+		if showValues {
+			c.logger.Output("+ %s=%q", diff.Key, diff.To)
+		} else {
+			c.logger.Output("+ %s", diff.Key)
+		}
+	}
+	for _, diff := range result.Removed {
+		if showValues {
+			c.logger.Output("- %s=%q", diff.Key, diff.From)
+		} else {
+			c.logger.Output("- %s", diff.Key)
+		}
+	}
+	for _, diff := range result.Changed {
+		if showValues {
+			c.logger.Output("~ %s=%q => %q", diff.Key, diff.From, diff.To)
+		} else {
+			c.logger.Output("~ %s", diff.Key)
+		}
+	}
+
+	c.logger.Info(
+		"%s => %s: %d added, %d removed, %d changed",
+		from,
+		to,
+		len(result.Added),
+		len(result.Removed),
+		len(result.Changed),
+	)
+}
+
+func (c *DiffCommand) writeJSON(from, to string, result app.DiffResult, showValues bool) error {
+	toJSON := func(diffs []app.KeyDiff, hasFrom, hasTo bool) []keyDiffJSON {
+		out := make([]keyDiffJSON, 0, len(diffs))
+		for _, diff := range diffs {
+			entry := keyDiffJSON{Key: diff.Key}
+			if showValues && hasFrom {
+				entry.From = &diff.From
+			}
+			if showValues && hasTo {
+				entry.To = &diff.To
+			}
+			out = append(out, entry)
+		}
+		return out
+	}
+
+	data, err := json.MarshalIndent(diffOutput{
+		From:    from,
+		To:      to,
+		Added:   toJSON(result.Added, false, true),
+		Removed: toJSON(result.Removed, true, false),
+		Changed: toJSON(result.Changed, true, true),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal differences: %w", err)
+	}
+
+	c.logger.Output("%s", data)
+	return nil
+}
+
+func init() {
+	diffCmd, err := NewDiffCommand(di.BuildDiffEnvs(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockDiffEnvsUseCase struct {
+	executeFunc func(ctx context.Context, dto app.DiffEnvsDTO) (app.DiffResult, error)
+	receivedDTO app.DiffEnvsDTO
+}
+
+func (m *mockDiffEnvsUseCase) Execute(
+	ctx context.Context,
+	dto app.DiffEnvsDTO,
+) (app.DiffResult, error) {
+	m.receivedDTO = dto
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, dto)
+	}
+	return app.DiffResult{
+		Added:   []app.KeyDiff{{Key: "NEW", To: "n"}},
+		Removed: []app.KeyDiff{{Key: "OLD", From: "o"}},
+		Changed: []app.KeyDiff{{Key: "PORT", From: "80", To: "8080"}},
+	}, nil
+}
+
+func newTestDiffCommand(
+	t *testing.T,
+	useCase app.DiffEnvsUc,
+	logger *test.MockLogger,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewDiffCommand(useCase, logger)
+	if err != nil {
+		t.Fatalf("NewDiffCommand() returned unexpected error: %v", err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestDiffCommand_Differences(t *testing.T) {
+	mockUseCase := &mockDiffEnvsUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestDiffCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from": "staging",
+		"to":   "prod",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Equal(t, exitCodeFindings, exitErr.Code)
+	assert.Equal(t, "staging", mockUseCase.receivedDTO.From)
+	assert.Equal(t, "prod", mockUseCase.receivedDTO.To)
+	assert.False(t, mockUseCase.receivedDTO.ShowValues, "values should be hidden by default")
+	assert.DeepEqual(t, []string{"+ NEW", "- OLD", "~ PORT"}, mockLogger.OutputLogs)
+	assert.Count(t, 1, mockLogger.InfoLogs)
+}
+
+func TestDiffCommand_ShowValues(t *testing.T) {
+	mockUseCase := &mockDiffEnvsUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestDiffCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from":        "staging",
+		"to":          "prod",
+		"show-values": "true",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.True(t, mockUseCase.receivedDTO.ShowValues, "ShowValues should be passed on")
+	assert.DeepEqual(t, []string{
+		`+ NEW="n"`,
+		`- OLD="o"`,
+		`~ PORT="80" => "8080"`,
+	}, mockLogger.OutputLogs)
+}
+
+func TestDiffCommand_NoDifferences(t *testing.T) {
+	mockUseCase := &mockDiffEnvsUseCase{
+		executeFunc: func(ctx context.Context, dto app.DiffEnvsDTO) (app.DiffResult, error) {
+			return app.DiffResult{}, nil
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestDiffCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from": "staging",
+		"to":   "prod",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Count(t, 0, mockLogger.OutputLogs)
+}
+
+func TestDiffCommand_JSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		showValues string
+		expected   string
+	}{
+		{
+			name:       "values hidden",
+			showValues: "false",
+			expected: `{
+  "from": "staging",
+  "to": "prod",
+  "added": [
+    {
+      "key": "NEW"
+    }
+  ],
+  "removed": [
+    {
+      "key": "OLD"
+    }
+  ],
+  "changed": [
+    {
+      "key": "PORT"
+    }
+  ]
+}`,
+		},
+		{
+			name:       "values shown",
+			showValues: "true",
+			expected: `{
+  "from": "staging",
+  "to": "prod",
+  "added": [
+    {
+      "key": "NEW",
+      "to": "n"
+    }
+  ],
+  "removed": [
+    {
+      "key": "OLD",
+      "from": "o"
+    }
+  ],
+  "changed": [
+    {
+      "key": "PORT",
+      "from": "80",
+      "to": "8080"
+    }
+  ]
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := &test.MockLogger{}
+			cmd := newTestDiffCommand(t, &mockDiffEnvsUseCase{}, mockLogger, map[string]string{
+				"from":        "staging",
+				"to":          "prod",
+				"output":      "json",
+				"show-values": tt.showValues,
+			})
+
+			err := cmd.RunE(cmd, nil)
+			assert.NotNil(t, err)
+			assert.DeepEqual(t, []string{tt.expected}, mockLogger.OutputLogs)
+		})
+	}
+}
+
+func TestDiffCommand_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.json")
+	if err := os.WriteFile(path, []byte(`{"A":"1"}`), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	mockUseCase := &mockDiffEnvsUseCase{
+		executeFunc: func(ctx context.Context, dto app.DiffEnvsDTO) (app.DiffResult, error) {
+			data, err := io.ReadAll(dto.File)
+			assert.Nil(t, err)
+			assert.Equal(t, `{"A":"1"}`, string(data))
+			return app.DiffResult{}, nil
+		},
+	}
+	cmd := newTestDiffCommand(t, mockUseCase, &test.MockLogger{}, map[string]string{
+		"from":   "prod",
+		"file":   path,
+		"format": "json",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, value.JSON, mockUseCase.receivedDTO.Format)
+}
+
+func TestDiffCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "no target",
+			flags:       map[string]string{"from": "staging"},
+			expectedErr: "exactly one of the to and file flags is required",
+		},
+		{
+			name:        "two targets",
+			flags:       map[string]string{"from": "staging", "to": "prod", "file": ".env"},
+			expectedErr: "exactly one of the to and file flags is required",
+		},
+		{
+			name:        "missing from",
+			flags:       map[string]string{"to": "prod"},
+			expectedErr: "from flag is required",
+		},
+		{
+			name:        "invalid output",
+			flags:       map[string]string{"from": "staging", "to": "prod", "output": "yaml"},
+			expectedErr: "invalid output format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestDiffCommand(t, &mockDiffEnvsUseCase{}, &test.MockLogger{}, tt.flags)
+
+			err := cmd.RunE(cmd, nil)
+			assert.NotNil(t, err)
+			assert.Contains(t, tt.expectedErr, err.Error())
+			var exitErr *ExitError
+			assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+			assert.Equal(t, exitCodeFailure, exitErr.Code)
+		})
+	}
+}
+
+func TestDiffCommand_InvalidFlagExitCode(t *testing.T) {
+	cmd := newTestDiffCommand(t, &mockDiffEnvsUseCase{}, &test.MockLogger{}, nil)
+	cmd.SetArgs([]string{"--from", "staging", "--unknown"})
+
+	err := cmd.Execute()
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "Execute() should return an ExitError")
+	assert.Equal(t, exitCodeFailure, exitErr.Code)
+	assert.Contains(t, "unknown flag", err.Error())
+}
+
+func TestDiffCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockDiffEnvsUseCase{
+		executeFunc: func(ctx context.Context, dto app.DiffEnvsDTO) (app.DiffResult, error) {
+			return app.DiffResult{}, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	cmd := newTestDiffCommand(t, mockUseCase, &test.MockLogger{}, map[string]string{
+		"from": "staging",
+		"to":   "prod",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Equal(t, exitCodeFailure, exitErr.Code)
+}
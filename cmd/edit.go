@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/spf13/cobra"
+)
+
+// EditCommand represents the edit command for editing a whole vault in an editor.
+type EditCommand struct {
+	useCase app.EditEnvUc
+	prompt  service.PromptService
+	logger  domain.Logger
+}
+
+// NewEditCommand creates a new edit command instance.
+func NewEditCommand(
+	useCase app.EditEnvUc,
+	prompt service.PromptService,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &EditCommand{useCase, prompt, logger}
+
+	// lockify edit --env [env] --format [dotenv|json]
+	cobraCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit all entries of a vault in your editor",
+		Long: `Edit all entries of a vault in your editor.
+
+This command decrypts the vault into a private temporary file and opens it in $VISUAL
+or $EDITOR. Once the editor is closed, the added, changed and removed keys are shown
+for confirmation and only those entries are re-encrypted, so unchanged entries keep
+their timestamps. The temporary file is overwritten and removed afterwards.`,
+		Example: `  lockify edit --env staging
+  lockify edit --env prod --format json
+  EDITOR="code --wait" lockify edit --env dev`,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().String("format", "dotenv", "The format to edit the entries in [dotenv|json]")
+	cobraCmd.Flags().BoolP("yes", "y", false, "Save the changes without asking for confirmation")
+	err := cobraCmd.MarkFlagRequired("env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *EditCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	format, err := requireStringFlag(cmd, "format")
+	if err != nil {
+		return err
+	}
+	editFormat, err := value.NewFileFormat(format)
+	if err != nil {
+		return err
+	}
+
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve yes flag: %w", err)
+	}
+
+	confirm := func(result app.EditResult) (bool, error) {
+		c.report(result)
+		if yes {
+			return true, nil
+		}
+		return c.prompt.Confirm(fmt.Sprintf("Save these changes to %s?", env))
+	}
+
+	ctx := getContext()
+	result, err := c.useCase.Execute(ctx, env, editFormat, confirm)
+	if err != nil {
+		return fmt.Errorf("failed to edit environment %s: %w", env, err)
+	}
+
+	switch {
+	case !result.HasChanges():
+		c.logger.Info("No changes made to %s", env)
+	case !result.Saved:
+		c.logger.Warning("Changes to %s discarded", env)
+	default:
+		c.logger.Success(
+			"Saved %s: %d added, %d changed, %d removed",
+			env,
+			len(result.Added),
+			len(result.Changed),
+			len(result.Removed),
+		)
+	}
+
+	return nil
+}
+
+func (c *EditCommand) report(result app.EditResult) {
+	for _, key := range result.Added {
+		c.logger.Info("+ %s", key)
+	}
+	for _, key := range result.Changed {
+		c.logger.Info("~ %s", key)
+	}
+	for _, key := range result.Removed {
+		c.logger.Info("- %s", key)
+	}
+}
+
+func init() {
+	editCmd, err := NewEditCommand(di.BuildEditEnv(), di.BuildPromptService(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+var editResultTest = app.EditResult{
+	Added:   []string{"NEW"},
+	Changed: []string{"CHANGED"},
+	Removed: []string{"OLD"},
+}
+
+type mockEditUseCase struct {
+	executeFunc    func(ctx context.Context, env string, format value.FileFormat) error
+	receivedFormat value.FileFormat
+	confirmed      bool
+}
+
+func (m *mockEditUseCase) Execute(
+	ctx context.Context,
+	env string,
+	format value.FileFormat,
+	confirm app.EditConfirmFunc,
+) (app.EditResult, error) {
+	m.receivedFormat = format
+	if m.executeFunc != nil {
+		return app.EditResult{}, m.executeFunc(ctx, env, format)
+	}
+
+	result := editResultTest
+	confirmed, err := confirm(result)
+	if err != nil {
+		return result, err
+	}
+	m.confirmed = confirmed
+	result.Saved = confirmed
+	return result, nil
+}
+
+func TestEditCommand_Confirmed(t *testing.T) {
+	mockUseCase := &mockEditUseCase{}
+	mockLogger := &test.MockLogger{}
+	var question string
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			question = message
+			return true, nil
+		},
+	}
+	cmd, _ := NewEditCommand(mockUseCase, prompt, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("format", "json"); err != nil {
+		t.Fatalf("failed to set format flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, value.JSON, mockUseCase.receivedFormat)
+	assert.Contains(t, "test", question)
+	assert.True(t, mockUseCase.confirmed, "the changes should be confirmed")
+	assert.DeepEqual(t, []string{"+ NEW", "~ CHANGED", "- OLD"}, mockLogger.InfoLogs)
+	assert.Contains(t, "Saved test: 1 added, 1 changed, 1 removed", mockLogger.SuccessLogs)
+}
+
+func TestEditCommand_Declined(t *testing.T) {
+	mockUseCase := &mockEditUseCase{}
+	mockLogger := &test.MockLogger{}
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			return false, nil
+		},
+	}
+	cmd, _ := NewEditCommand(mockUseCase, prompt, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+	assert.Count(t, 1, mockLogger.WarningLogs)
+}
+
+func TestEditCommand_YesSkipsConfirmation(t *testing.T) {
+	mockUseCase := &mockEditUseCase{}
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			t.Error("Confirm() should not be called with --yes")
+			return false, nil
+		},
+	}
+	cmd, _ := NewEditCommand(mockUseCase, prompt, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("yes", "true"); err != nil {
+		t.Fatalf("failed to set yes flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.confirmed, "--yes should confirm the changes")
+}
+
+func TestEditCommand_InvalidFormat(t *testing.T) {
+	cmd, _ := NewEditCommand(&mockEditUseCase{}, &test.MockPromptService{}, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("format", "yaml"); err != nil {
+		t.Fatalf("failed to set format flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "invalid file format", err.Error())
+}
+
+func TestEditCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockEditUseCase{
+		executeFunc: func(ctx context.Context, env string, format value.FileFormat) error {
+			return fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	cmd, _ := NewEditCommand(mockUseCase, &test.MockPromptService{}, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+}
+
+func TestEditCommand_EmptyEnv(t *testing.T) {
+	cmd, _ := NewEditCommand(&mockEditUseCase{}, &test.MockPromptService{}, &test.MockLogger{})
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, errMsgEmptyEnv, err.Error())
+}
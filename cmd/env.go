@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/spf13/cobra"
+)
+
+// EnvCommand represents the env command for managing the environments of a project.
+type EnvCommand struct {
+	listUseCase   app.ListEnvsUc
+	cloneUseCase  app.CloneEnvUc
+	renameUseCase app.RenameEnvUc
+	deleteUseCase app.DeleteEnvUc
+	prompt        service.PromptService
+	logger        domain.Logger
+}
+
+// NewEnvCommand creates a new env command instance with its list, clone, rename and delete
+// subcommands.
+func NewEnvCommand(
+	listUseCase app.ListEnvsUc,
+	cloneUseCase app.CloneEnvUc,
+	renameUseCase app.RenameEnvUc,
+	deleteUseCase app.DeleteEnvUc,
+	prompt service.PromptService,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &EnvCommand{listUseCase, cloneUseCase, renameUseCase, deleteUseCase, prompt, logger}
+
+	// lockify env [list|clone|rename|delete]
+	cobraCmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage the environments of a project",
+		Long: `Manage the environments of a project.
+
+Every environment is stored in its own vault file. The environment name is bound into
+the encryption of its entries, so vault files must not be copied or renamed by hand:
+use clone and rename instead.`,
+		Example: `  lockify env list
+  lockify env clone --env prod --to staging
+  lockify env rename --env stage --to staging
+  lockify env delete --env staging`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the environments with their key count and last update",
+		Long: `List the environments with their key count and last update.
+
+The unlock method shows whether your identity unlocks the vault or a passphrase is needed.
+No vault is unlocked to list them.`,
+		Example: `  lockify env list`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runList,
+	}
+	cloneCmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Copy the entries of an environment into a new environment",
+		Long: `Copy the entries of an environment into a new environment.
+
+You will be prompted twice for the passphrase of the new environment, which has to meet its
+passphrase strength policy. Key slots and recipients are not copied, so the new environment
+can be shared with other people.`,
+		Example: `  lockify env clone --env prod --to staging`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runClone,
+	}
+	renameCmd := &cobra.Command{
+		Use:   "rename",
+		Short: "Rename an environment",
+		Long: `Rename an environment.
+
+The vault is re-encrypted for the new name with the same passphrase and recipients, and
+the old vault is kept as a backup. Vaults with key slots other than the default one
+cannot be renamed until those slots are removed.`,
+		Example: `  lockify env rename --env stage --to staging`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runRename,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an environment",
+		Long: `Delete an environment.
+
+The vault is unlocked first, so that the deletion is recorded in its audit log, and is
+then moved to its newest backup, so it can be brought back with
+lockify restore --env <env>.`,
+		Example: `  lockify env delete --env staging
+  lockify env delete --env staging --yes`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runDelete,
+	}
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+
+	for _, subCmd := range []*cobra.Command{cloneCmd, renameCmd, deleteCmd} {
+		subCmd.Flags().StringP("env", "e", "", "Environment Name")
+		if err := subCmd.MarkFlagRequired("env"); err != nil {
+			return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+		}
+	}
+	for _, subCmd := range []*cobra.Command{cloneCmd, renameCmd} {
+		subCmd.Flags().StringP("to", "t", "", "Name of the new environment")
+		if err := subCmd.MarkFlagRequired("to"); err != nil {
+			return nil, fmt.Errorf("failed to mark to flag as required: %w", err)
+		}
+	}
+	for _, subCmd := range []*cobra.Command{listCmd, cloneCmd, renameCmd, deleteCmd} {
+		cobraCmd.AddCommand(subCmd)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *EnvCommand) runList(cmd *cobra.Command, args []string) error {
+	envs, err := c.listUseCase.Execute(getContext())
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		c.logger.Info("No environments found, create one with lockify init --env <env>")
+		return nil
+	}
+
+	rows := [][]string{{"ENV", "KEYS", "UPDATED", "UNLOCK"}}
+	for _, env := range envs {
+		updatedAt := env.UpdatedAt
+		if updatedAt == "" {
+			updatedAt = "-"
+		}
+		rows = append(rows, []string{
+			env.Env,
+			fmt.Sprint(env.Keys),
+			updatedAt,
+			unlockMethodLabel(env),
+		})
+	}
+
+	c.logger.Success("Found %d environment(s):", len(envs))
+	for _, line := range formatTable(rows) {
+		c.logger.Output("%s", line)
+	}
+
+	return nil
+}
+
+func (c *EnvCommand) runClone(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	to, err := requireStringFlag(cmd, "to")
+	if err != nil {
+		return err
+	}
+
+	c.logger.Progress("Cloning %s into %s...\n", env, to)
+	copied, err := c.cloneUseCase.Execute(getContext(), env, to)
+	if err != nil {
+		reportWeakPassphrase(c.logger, err)
+		return fmt.Errorf("failed to clone environment %s: %w", env, err)
+	}
+
+	c.logger.Success("Cloned %d key(s) from %s into %s", copied, env, to)
+	return nil
+}
+
+func (c *EnvCommand) runRename(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	to, err := requireStringFlag(cmd, "to")
+	if err != nil {
+		return err
+	}
+
+	c.logger.Progress("Renaming %s to %s...\n", env, to)
+	backupPath, err := c.renameUseCase.Execute(getContext(), env, to)
+	if err != nil {
+		return fmt.Errorf("failed to rename environment %s: %w", env, err)
+	}
+
+	c.logger.Success("Renamed %s to %s, the old vault is kept at %s", env, to, backupPath)
+	return nil
+}
+
+func (c *EnvCommand) runDelete(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve yes flag: %w", err)
+	}
+
+	if !yes {
+		confirmed, err := c.prompt.Confirm(fmt.Sprintf("Delete environment %s?", env))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			c.logger.Warning("Environment %s was not deleted", env)
+			return nil
+		}
+	}
+
+	backupPath, err := c.deleteUseCase.Execute(getContext(), env)
+	if err != nil {
+		return fmt.Errorf("failed to delete environment %s: %w", env, err)
+	}
+
+	c.logger.Success(
+		"Deleted %s, it can be restored from %s with lockify restore --env %s",
+		env,
+		backupPath,
+		env,
+	)
+	return nil
+}
+
+// unlockMethodLabel describes how the vault is unlocked along with its other unlock options
+func unlockMethodLabel(env app.EnvInfo) string {
+	var others []string
+	if env.Slots > 1 {
+		others = append(others, fmt.Sprintf("%d slots", env.Slots))
+	}
+	if env.Recipients > 0 {
+		others = append(others, fmt.Sprintf("%d recipient(s)", env.Recipients))
+	}
+	if len(others) == 0 {
+		return env.UnlockMethod
+	}
+	return env.UnlockMethod + " (" + strings.Join(others, ", ") + ")"
+}
+
+// formatTable pads every column to its widest cell
+func formatTable(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	return lines
+}
+
+func init() {
+	envCmd, err := NewEnvCommand(
+		di.BuildListEnvs(),
+		di.BuildCloneEnv(),
+		di.BuildRenameEnv(),
+		di.BuildDeleteEnv(),
+		di.BuildPromptService(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(envCmd)
+}
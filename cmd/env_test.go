@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockListEnvsUseCase struct {
+	executeFunc func(ctx context.Context) ([]app.EnvInfo, error)
+}
+
+func (m *mockListEnvsUseCase) Execute(ctx context.Context) ([]app.EnvInfo, error) {
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx)
+	}
+	return []app.EnvInfo{
+		{
+			Env:          "dev",
+			Slots:        1,
+			UnlockMethod: app.UnlockMethodPassphrase,
+		},
+		{
+			Env:          "production",
+			Keys:         12,
+			UpdatedAt:    "2026-01-02T03:04:05Z",
+			Slots:        2,
+			Recipients:   1,
+			UnlockMethod: app.UnlockMethodIdentity,
+		},
+	}, nil
+}
+
+type mockCloneEnvUseCase struct {
+	executeFunc  func(ctx context.Context, from, to string) (int, error)
+	receivedFrom string
+	receivedTo   string
+}
+
+func (m *mockCloneEnvUseCase) Execute(ctx context.Context, from, to string) (int, error) {
+	m.receivedFrom = from
+	m.receivedTo = to
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, from, to)
+	}
+	return 3, nil
+}
+
+type mockRenameEnvUseCase struct {
+	executeFunc  func(ctx context.Context, from, to string) (string, error)
+	receivedFrom string
+	receivedTo   string
+}
+
+func (m *mockRenameEnvUseCase) Execute(ctx context.Context, from, to string) (string, error) {
+	m.receivedFrom = from
+	m.receivedTo = to
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, from, to)
+	}
+	return from + ".vault.enc.bak.1", nil
+}
+
+type mockDeleteEnvUseCase struct {
+	executeFunc func(ctx context.Context, env string) (string, error)
+	receivedEnv string
+}
+
+func (m *mockDeleteEnvUseCase) Execute(ctx context.Context, env string) (string, error) {
+	m.receivedEnv = env
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env)
+	}
+	return env + ".vault.enc.bak.1", nil
+}
+
+func newTestEnvSubcommand(
+	t *testing.T,
+	name string,
+	listUseCase app.ListEnvsUc,
+	cloneUseCase app.CloneEnvUc,
+	renameUseCase app.RenameEnvUc,
+	deleteUseCase app.DeleteEnvUc,
+	prompt *test.MockPromptService,
+	logger *test.MockLogger,
+) *cobra.Command {
+	t.Helper()
+	envCmd, err := NewEnvCommand(
+		listUseCase,
+		cloneUseCase,
+		renameUseCase,
+		deleteUseCase,
+		prompt,
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewEnvCommand() returned unexpected error: %v", err)
+	}
+	subCmd, _, err := envCmd.Find([]string{name})
+	if err != nil {
+		t.Fatalf("failed to find env %s command: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	subCmd.SetOut(&buf)
+	subCmd.SetErr(&buf)
+	return subCmd
+}
+
+func TestEnvListCommand_Success(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestEnvSubcommand(
+		t,
+		"list",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		mockLogger,
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.DeepEqual(t, []string{
+		"ENV         KEYS  UPDATED               UNLOCK",
+		"dev         0     -                     passphrase",
+		"production  12    2026-01-02T03:04:05Z  identity (2 slots, 1 recipient(s))",
+	}, mockLogger.OutputLogs)
+}
+
+func TestEnvListCommand_Empty(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	listUseCase := &mockListEnvsUseCase{
+		executeFunc: func(ctx context.Context) ([]app.EnvInfo, error) {
+			return nil, nil
+		},
+	}
+	cmd := newTestEnvSubcommand(
+		t,
+		"list",
+		listUseCase,
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		mockLogger,
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 1, mockLogger.InfoLogs)
+	assert.Count(t, 0, mockLogger.OutputLogs)
+}
+
+func TestEnvCloneCommand_Success(t *testing.T) {
+	mockUseCase := &mockCloneEnvUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestEnvSubcommand(
+		t,
+		"clone",
+		&mockListEnvsUseCase{},
+		mockUseCase,
+		&mockRenameEnvUseCase{},
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("to", "staging"); err != nil {
+		t.Fatalf("failed to set to flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedFrom)
+	assert.Equal(t, "staging", mockUseCase.receivedTo)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestEnvCloneCommand_Error_Required_To(t *testing.T) {
+	cmd := newTestEnvSubcommand(
+		t,
+		"clone",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		&test.MockLogger{},
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "to flag is required", err.Error())
+}
+
+func TestEnvRenameCommand_Success(t *testing.T) {
+	mockUseCase := &mockRenameEnvUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestEnvSubcommand(
+		t,
+		"rename",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		mockUseCase,
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "stage"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("to", "staging"); err != nil {
+		t.Fatalf("failed to set to flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "stage", mockUseCase.receivedFrom)
+	assert.Equal(t, "staging", mockUseCase.receivedTo)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, "stage.vault.enc.bak.1", mockLogger.SuccessLogs[0])
+}
+
+func TestEnvRenameCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockRenameEnvUseCase{
+		executeFunc: func(ctx context.Context, from, to string) (string, error) {
+			return "", fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestEnvSubcommand(
+		t,
+		"rename",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		mockUseCase,
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "stage"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("to", "staging"); err != nil {
+		t.Fatalf("failed to set to flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestEnvDeleteCommand_Confirmed(t *testing.T) {
+	mockUseCase := &mockDeleteEnvUseCase{}
+	mockLogger := &test.MockLogger{}
+	var confirmMessage string
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			confirmMessage = message
+			return true, nil
+		},
+	}
+	cmd := newTestEnvSubcommand(
+		t,
+		"delete",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		mockUseCase,
+		prompt,
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "staging"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Contains(t, "staging", confirmMessage)
+	assert.Equal(t, "staging", mockUseCase.receivedEnv)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestEnvDeleteCommand_Declined(t *testing.T) {
+	mockUseCase := &mockDeleteEnvUseCase{}
+	mockLogger := &test.MockLogger{}
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			return false, nil
+		},
+	}
+	cmd := newTestEnvSubcommand(
+		t,
+		"delete",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		mockUseCase,
+		prompt,
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "staging"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", mockUseCase.receivedEnv)
+	assert.Count(t, 1, mockLogger.WarningLogs)
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestEnvDeleteCommand_Yes(t *testing.T) {
+	mockUseCase := &mockDeleteEnvUseCase{}
+	prompt := &test.MockPromptService{
+		ConfirmFunc: func(message string) (bool, error) {
+			t.Error("Confirm() should not be called with --yes")
+			return false, nil
+		},
+	}
+	cmd := newTestEnvSubcommand(
+		t,
+		"delete",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		mockUseCase,
+		prompt,
+		&test.MockLogger{},
+	)
+	if err := cmd.Flags().Set("env", "staging"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("yes", "true"); err != nil {
+		t.Fatalf("failed to set yes flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", mockUseCase.receivedEnv)
+}
+
+func TestEnvDeleteCommand_Error_Required_Env(t *testing.T) {
+	cmd := newTestEnvSubcommand(
+		t,
+		"delete",
+		&mockListEnvsUseCase{},
+		&mockCloneEnvUseCase{},
+		&mockRenameEnvUseCase{},
+		&mockDeleteEnvUseCase{},
+		&test.MockPromptService{},
+		&test.MockLogger{},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/spf13/cobra"
+)
+
+// HistoryCommand represents the history command for listing previous values of an entry.
+type HistoryCommand struct {
+	listUseCase   app.ListHistoryUc
+	policyUseCase app.SetHistoryPolicyUc
+	logger        domain.Logger
+}
+
+// NewHistoryCommand creates a new history command instance.
+func NewHistoryCommand(
+	listUseCase app.ListHistoryUc,
+	policyUseCase app.SetHistoryPolicyUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &HistoryCommand{listUseCase, policyUseCase, logger}
+
+	// lockify history --env [env] --key [key]
+	cobraCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the previous values of an entry",
+		Long: `List the previous values of an entry.
+
+Every change to an entry keeps the value it replaces, still encrypted, together with when
+and by whom it was set. The author is taken from git config, or from $USER. Values stay
+hidden unless --show-values is given; restore one with lockify rollback.
+
+By default the last 10 values of each entry are kept. --keep and --max-age-days change
+this retention for the whole vault and drop the previous values it no longer keeps.`,
+		Example: `  lockify history --env prod --key STRIPE_KEY
+  lockify history --env prod --key STRIPE_KEY --show-values
+  lockify history --env prod --keep 5 --max-age-days 90`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment name")
+	cobraCmd.Flags().StringP("key", "k", "", "The key to list the versions of")
+	cobraCmd.Flags().Bool("show-values", false, "Show the decrypted values")
+	cobraCmd.Flags().Int("keep", model.DefaultHistoryVersions,
+		"Set how many previous values of each entry the vault keeps")
+	cobraCmd.Flags().Int("max-age-days", 0,
+		"Set after how many days previous values are dropped (0 keeps them)")
+	if err := cobraCmd.MarkFlagRequired("env"); err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *HistoryCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("keep") || cmd.Flags().Changed("max-age-days") {
+		return c.setPolicy(cmd, env)
+	}
+
+	key, err := requireStringFlag(cmd, "key")
+	if err != nil {
+		return err
+	}
+	showValues, err := cmd.Flags().GetBool("show-values")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve show-values flag: %w", err)
+	}
+
+	c.logger.Progress("Reading the history of %s in %s...\n", key, env)
+	versions, err := c.listUseCase.Execute(getContext(), env, key, showValues)
+	if err != nil {
+		return fmt.Errorf("failed to read history of key %s: %w", key, err)
+	}
+
+	header := []string{"VERSION", "UPDATED", "AUTHOR"}
+	if showValues {
+		header = append(header, "VALUE")
+	}
+	rows := [][]string{header}
+	for _, version := range versions {
+		label := strconv.Itoa(version.Version)
+		if version.Current {
+			label += " (current)"
+		}
+		row := []string{label, version.UpdatedAt, orDash(version.UpdatedBy)}
+		if showValues {
+			row = append(row, strconv.Quote(version.Value))
+		}
+		rows = append(rows, row)
+	}
+
+	c.logger.Success("Found %d version(s) of %s:", len(versions), key)
+	for _, line := range formatTable(rows) {
+		c.logger.Output("%s", line)
+	}
+
+	return nil
+}
+
+func (c *HistoryCommand) setPolicy(cmd *cobra.Command, env string) error {
+	keep, err := cmd.Flags().GetInt("keep")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve keep flag: %w", err)
+	}
+	maxAgeDays, err := cmd.Flags().GetInt("max-age-days")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve max-age-days flag: %w", err)
+	}
+
+	policy := model.HistoryPolicy{Versions: keep, MaxAgeDays: maxAgeDays}
+	if err := c.policyUseCase.Execute(getContext(), env, policy); err != nil {
+		return fmt.Errorf("failed to set history retention: %w", err)
+	}
+
+	if maxAgeDays > 0 {
+		c.logger.Success("%s keeps up to %d previous value(s) per entry for %d day(s)",
+			env, keep, maxAgeDays)
+	} else {
+		c.logger.Success("%s keeps up to %d previous value(s) per entry", env, keep)
+	}
+	return nil
+}
+
+func init() {
+	historyCmd, err := NewHistoryCommand(
+		di.BuildListHistory(),
+		di.BuildSetHistoryPolicy(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockListHistoryUseCase struct {
+	executeFunc func(ctx context.Context, env, key string, showValues bool) (
+		[]app.EntryVersionInfo, error)
+	receivedShowValues bool
+}
+
+func (m *mockListHistoryUseCase) Execute(
+	ctx context.Context,
+	env, key string,
+	showValues bool,
+) ([]app.EntryVersionInfo, error) {
+	m.receivedShowValues = showValues
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, key, showValues)
+	}
+	return []app.EntryVersionInfo{
+		{Version: 1, UpdatedAt: "2026-01-01T00:00:00Z", UpdatedBy: "alice", Value: "old"},
+		{Version: 2, UpdatedAt: "2026-02-01T00:00:00Z", Value: "new", Current: true},
+	}, nil
+}
+
+type mockSetHistoryPolicyUseCase struct {
+	receivedPolicy *model.HistoryPolicy
+}
+
+func (m *mockSetHistoryPolicyUseCase) Execute(
+	ctx context.Context,
+	env string,
+	policy model.HistoryPolicy,
+) error {
+	m.receivedPolicy = &policy
+	return nil
+}
+
+func newTestHistoryCommand(
+	t *testing.T,
+	listUseCase app.ListHistoryUc,
+	policyUseCase app.SetHistoryPolicyUc,
+	logger *test.MockLogger,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewHistoryCommand(listUseCase, policyUseCase, logger)
+	if err != nil {
+		t.Fatalf("NewHistoryCommand() returned unexpected error: %v", err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestHistoryCommand_List(t *testing.T) {
+	listUseCase := &mockListHistoryUseCase{}
+	policyUseCase := &mockSetHistoryPolicyUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestHistoryCommand(t, listUseCase, policyUseCase, mockLogger, map[string]string{
+		"env": "prod",
+		"key": "DB_URL",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("history returned unexpected error: %v", err))
+	assert.False(t, listUseCase.receivedShowValues, "values should be hidden by default")
+	assert.Nil(t, policyUseCase.receivedPolicy, "the retention should not change")
+	assert.DeepEqual(t, []string{
+		"VERSION      UPDATED               AUTHOR",
+		"1            2026-01-01T00:00:00Z  alice",
+		"2 (current)  2026-02-01T00:00:00Z  -",
+	}, mockLogger.OutputLogs)
+}
+
+func TestHistoryCommand_ShowValues(t *testing.T) {
+	listUseCase := &mockListHistoryUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestHistoryCommand(
+		t,
+		listUseCase,
+		&mockSetHistoryPolicyUseCase{},
+		mockLogger,
+		map[string]string{"env": "prod", "key": "DB_URL", "show-values": "true"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("history returned unexpected error: %v", err))
+	assert.True(t, listUseCase.receivedShowValues, "values should be requested")
+	assert.Equal(t, "VERSION      UPDATED               AUTHOR  VALUE", mockLogger.OutputLogs[0])
+	assert.Equal(
+		t,
+		`2 (current)  2026-02-01T00:00:00Z  -       "new"`,
+		mockLogger.OutputLogs[2],
+	)
+}
+
+func TestHistoryCommand_SetPolicy(t *testing.T) {
+	policyUseCase := &mockSetHistoryPolicyUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestHistoryCommand(
+		t,
+		&mockListHistoryUseCase{},
+		policyUseCase,
+		mockLogger,
+		map[string]string{"env": "prod", "max-age-days": "90"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("history returned unexpected error: %v", err))
+	assert.NotNil(t, policyUseCase.receivedPolicy, "the retention should be set")
+	assert.Equal(
+		t,
+		model.HistoryPolicy{Versions: model.DefaultHistoryVersions, MaxAgeDays: 90},
+		*policyUseCase.receivedPolicy,
+	)
+	assert.Count(t, 0, mockLogger.OutputLogs)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestHistoryCommand_MissingKey(t *testing.T) {
+	cmd := newTestHistoryCommand(
+		t,
+		&mockListHistoryUseCase{},
+		&mockSetHistoryPolicyUseCase{},
+		&test.MockLogger{},
+		map[string]string{"env": "prod"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "history without --key expected error, got nil")
+	assert.Contains(t, "key flag is required", err.Error())
+}
@@ -26,7 +26,10 @@ func NewInitCommand(initUc app.InitUc, logger domain.Logger) (*cobra.Command, er
 		Long: `Initialize a new Lockify vault for an environment.
 
 	This command creates a new encrypted vault file that will store your environment variables.
-	You will be prompted for a passphrase that will be used to encrypt and decrypt your secrets.`,
+	You will be prompted twice for a passphrase that will be used to encrypt and decrypt your
+	secrets, unless LOCKIFY_PASSPHRASE, --passphrase-file, --passphrase-fd or --passphrase-cmd
+	gives it. The keyring is never used for a new vault. It has to meet the passphrase
+	strength policy of the environment.`,
 		Example: `  lockify init --env prod
 	lockify init --env staging
 	lockify init -e local`,
@@ -52,6 +55,7 @@ func (c *InitCommand) runE(cmd *cobra.Command, args []string) error {
 	ctx := getContext()
 	vault, err := c.useCase.Execute(ctx, env)
 	if err != nil {
+		reportWeakPassphrase(c.logger, err)
 		return err
 	}
 
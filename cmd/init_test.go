@@ -19,7 +19,7 @@ func (m *mockInitUseCase) Execute(ctx context.Context, env string) (*model.Vault
 	if m.executeFunc != nil {
 		return m.executeFunc(ctx, env)
 	}
-	vault, _ := model.NewVault(env, "finger", "salt")
+	vault, _ := model.NewVault(env, "salt")
 	vault.SetPath("/tmp/test.vault")
 	return vault, nil
 }
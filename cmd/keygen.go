@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// KeygenCommand represents the keygen command for creating the local identity.
+type KeygenCommand struct {
+	useCase app.GenerateIdentityUc
+	logger  domain.Logger
+}
+
+// NewKeygenCommand creates a new keygen command instance.
+func NewKeygenCommand(
+	useCase app.GenerateIdentityUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &KeygenCommand{useCase, logger}
+
+	// lockify keygen [--force]
+	cobraCmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Create an identity that unlocks vaults without a passphrase",
+		Long: `Create an identity that unlocks vaults without a passphrase.
+
+An X25519 key pair is written to your identity file, readable only by you. Share the
+printed public key with the owner of a vault; once they run "lockify recipient add",
+lockify unlocks that vault with your identity instead of prompting for a passphrase.
+
+The identity file is $LOCKIFY_IDENTITY when set, otherwise lockify/identity.txt in
+your user config directory.`,
+		Example: `  lockify keygen
+  lockify keygen --force`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().BoolP("force", "f", false, "Replace an existing identity")
+
+	return cobraCmd, nil
+}
+
+func (c *KeygenCommand) runE(cmd *cobra.Command, args []string) error {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve force flag: %w", err)
+	}
+
+	publicKey, path, err := c.useCase.Execute(getContext(), force)
+	if err != nil {
+		c.logger.Error("failed to create identity: %v", err)
+		return err
+	}
+
+	c.logger.Success("Identity written to %s", path)
+	c.logger.Output("%s", publicKey)
+	return nil
+}
+
+func init() {
+	keygenCmd, err := NewKeygenCommand(di.BuildGenerateIdentity(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(keygenCmd)
+}
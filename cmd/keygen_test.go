@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockGenerateIdentityUseCase struct {
+	executeFunc       func(ctx context.Context, overwrite bool) (string, string, error)
+	receivedOverwrite bool
+}
+
+func (m *mockGenerateIdentityUseCase) Execute(
+	ctx context.Context,
+	overwrite bool,
+) (string, string, error) {
+	m.receivedOverwrite = overwrite
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, overwrite)
+	}
+	return "lockify1test", "/home/test/.config/lockify/identity.txt", nil
+}
+
+func TestKeygenCommand_Success(t *testing.T) {
+	mockUseCase := &mockGenerateIdentityUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewKeygenCommand(mockUseCase, mockLogger)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.False(t, mockUseCase.receivedOverwrite)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, "/home/test/.config/lockify/identity.txt", mockLogger.SuccessLogs[0])
+	assert.DeepEqual(t, []string{"lockify1test"}, mockLogger.OutputLogs)
+}
+
+func TestKeygenCommand_Force(t *testing.T) {
+	mockUseCase := &mockGenerateIdentityUseCase{}
+
+	cmd, _ := NewKeygenCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("force", "true"); err != nil {
+		t.Fatalf("failed to set force flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.receivedOverwrite, "--force should overwrite the identity")
+}
+
+func TestKeygenCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockGenerateIdentityUseCase{
+		executeFunc: func(ctx context.Context, overwrite bool) (string, string, error) {
+			return "", "", fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewKeygenCommand(mockUseCase, mockLogger)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.OutputLogs)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/app"
 	"github.com/ahmed-abdelgawad92/lockify/internal/di"
@@ -25,13 +26,23 @@ func NewListCommand(useCase app.ListEntriesUc, logger domain.Logger) (*cobra.Com
 		Long: `List all keys in the vault.
 
 This command displays all keys stored in the vault for the specified environment.
-Only keys are displayed, not decrypted values, for security reasons.`,
+Only keys are displayed, not decrypted values, for security reasons.
+
+--meta shows the metadata of every key set with lockify meta set. --tag, --owner, --secret
+and --expired only list the keys that match all of them.`,
 		Example: `  lockify list --env prod
-  lockify list --env staging`,
+  lockify list --env staging
+  lockify list --env prod --meta --tag payments
+  lockify list --env prod --owner alice --expired`,
 		RunE: cmd.runE,
 	}
 
 	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().Bool("meta", false, "Show the metadata of every key")
+	cobraCmd.Flags().StringSlice("tag", nil, "Only list keys with these tags (comma separated)")
+	cobraCmd.Flags().String("owner", "", "Only list keys of this owner")
+	cobraCmd.Flags().Bool("secret", false, "Only list keys marked as secret")
+	cobraCmd.Flags().Bool("expired", false, "Only list keys whose expiry has passed")
 	err := cobraCmd.MarkFlagRequired("env")
 	if err != nil {
 		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
@@ -47,25 +58,75 @@ func (c *ListCommand) runE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	showMeta, err := cmd.Flags().GetBool("meta")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve meta flag: %w", err)
+	}
+	filter, err := entryFilterFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	ctx := getContext()
-	keys, err := c.useCase.Execute(ctx, env)
+	entries, err := c.useCase.Execute(ctx, env, filter)
 	if err != nil {
 		return err
 	}
 
-	if len(keys) == 0 {
+	if len(entries) == 0 {
 		c.logger.Info("No entries found in vault")
 		return nil
 	}
 
-	c.logger.Success("Found %d key(s):", len(keys))
-	for _, v := range keys {
-		c.logger.Output("  - %s\n", v)
+	c.logger.Success("Found %d key(s):", len(entries))
+	if !showMeta {
+		for _, entry := range entries {
+			c.logger.Output("  - %s\n", entry.Key)
+		}
+		return nil
+	}
+
+	rows := [][]string{{"KEY", "SECRET", "OWNER", "TAGS", "EXPIRES", "DESCRIPTION"}}
+	for _, entry := range entries {
+		secret := "no"
+		if entry.Secret {
+			secret = "yes"
+		}
+		rows = append(rows, []string{
+			entry.Key,
+			secret,
+			orDash(entry.Metadata.Owner),
+			orDash(strings.Join(entry.Metadata.Tags, ",")),
+			orDash(entry.Metadata.ExpiresAt),
+			orDash(entry.Metadata.Description),
+		})
+	}
+	for _, line := range formatTable(rows) {
+		c.logger.Output("%s", line)
 	}
 
 	return nil
 }
 
+// entryFilterFlags reads the flags that select which entries are listed
+func entryFilterFlags(cmd *cobra.Command) (app.EntryFilter, error) {
+	var filter app.EntryFilter
+	var err error
+	if filter.Tags, err = cmd.Flags().GetStringSlice("tag"); err != nil {
+		return filter, fmt.Errorf("failed to retrieve tag flag: %w", err)
+	}
+	if filter.Owner, err = cmd.Flags().GetString("owner"); err != nil {
+		return filter, fmt.Errorf("failed to retrieve owner flag: %w", err)
+	}
+	if filter.Secret, err = cmd.Flags().GetBool("secret"); err != nil {
+		return filter, fmt.Errorf("failed to retrieve secret flag: %w", err)
+	}
+	if filter.Expired, err = cmd.Flags().GetBool("expired"); err != nil {
+		return filter, fmt.Errorf("failed to retrieve expired flag: %w", err)
+	}
+	return filter, nil
+}
+
 func init() {
 	listCmd, err := NewListCommand(di.BuildListEntries(), di.GetLogger())
 	if err != nil {
@@ -6,21 +6,38 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/test"
 	"github.com/ahmed-abdelgawad92/lockify/test/assert"
 )
 
 type mockListUseCase struct {
-	executeFunc func(ctx context.Context, env string) ([]string, error)
-	receivedEnv string
+	executeFunc    func(ctx context.Context, env string) ([]app.EntryInfo, error)
+	receivedEnv    string
+	receivedFilter app.EntryFilter
 }
 
-func (m *mockListUseCase) Execute(ctx context.Context, env string) ([]string, error) {
+func (m *mockListUseCase) Execute(
+	ctx context.Context,
+	env string,
+	filter app.EntryFilter,
+) ([]app.EntryInfo, error) {
 	m.receivedEnv = env
+	m.receivedFilter = filter
 	if m.executeFunc != nil {
 		return m.executeFunc(ctx, env)
 	}
-	return []string{"key1", "key2", "key3"}, nil
+	return []app.EntryInfo{
+		{Key: "key1", Secret: true, Metadata: model.EntryMetadata{
+			Description: "Payment API key",
+			Owner:       "alice",
+			Tags:        []string{"billing", "external"},
+			ExpiresAt:   "2026-12-31T00:00:00Z",
+		}},
+		{Key: "key2"},
+		{Key: "key3"},
+	}, nil
 }
 
 func TestListCommand_Success(t *testing.T) {
@@ -47,10 +64,40 @@ func TestListCommand_Success(t *testing.T) {
 	assert.Contains(t, "  - key3\n", mockLogger.OutputLogs)
 }
 
+func TestListCommand_Meta(t *testing.T) {
+	mockUseCase := &mockListUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewListCommand(mockUseCase, mockLogger)
+	for name, value := range map[string]string{
+		"env":   "test",
+		"meta":  "true",
+		"tag":   "billing,external",
+		"owner": "alice",
+	} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, app.EntryFilter{
+		Tags:  []string{"billing", "external"},
+		Owner: "alice",
+	}, mockUseCase.receivedFilter)
+	assert.DeepEqual(t, []string{
+		"KEY   SECRET  OWNER  TAGS              EXPIRES               DESCRIPTION",
+		"key1  yes     alice  billing,external  2026-12-31T00:00:00Z  Payment API key",
+		"key2  no      -      -                 -                     -",
+		"key3  no      -      -                 -                     -",
+	}, mockLogger.OutputLogs)
+}
+
 func TestListCommand_EmptyKeys(t *testing.T) {
 	mockUseCase := &mockListUseCase{
-		executeFunc: func(ctx context.Context, env string) ([]string, error) {
-			return []string{}, nil
+		executeFunc: func(ctx context.Context, env string) ([]app.EntryInfo, error) {
+			return []app.EntryInfo{}, nil
 		},
 	}
 	mockLogger := &test.MockLogger{}
@@ -74,7 +121,7 @@ func TestListCommand_EmptyKeys(t *testing.T) {
 
 func TestListCommand_UseCaseError(t *testing.T) {
 	mockUseCase := &mockListUseCase{
-		executeFunc: func(ctx context.Context, env string) ([]string, error) {
+		executeFunc: func(ctx context.Context, env string) ([]app.EntryInfo, error) {
 			return nil, fmt.Errorf("%s", errMsgExecuteFailed)
 		},
 	}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// MetaCommand represents the meta command for managing the metadata of vault entries.
+type MetaCommand struct {
+	setUseCase        app.SetEntryMetadataUc
+	visibilityUseCase app.SetMetadataVisibilityUc
+	logger            domain.Logger
+	now               func() time.Time
+}
+
+// NewMetaCommand creates a new meta command instance with its set, public and private
+// subcommands.
+func NewMetaCommand(
+	setUseCase app.SetEntryMetadataUc,
+	visibilityUseCase app.SetMetadataVisibilityUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &MetaCommand{setUseCase, visibilityUseCase, logger, time.Now}
+
+	// lockify meta [set|public|private] --env [env]
+	cobraCmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Manage the metadata of vault entries",
+		Long: `Manage the metadata of vault entries.
+
+Every entry can carry a description, an owner, tags, an expiry, a max age and a secret
+marker.
+Metadata is authenticated with the vault and encrypted with the vault key, unless the
+vault makes it public so reviewers can read what a key is for without the passphrase.
+Use lockify list --meta to show it.`,
+		Example: `  lockify meta set --env prod --key STRIPE_KEY --owner payments --tags billing
+  lockify meta set --env prod --key STRIPE_KEY --expires 90d --description "Live key"
+  lockify meta public --env prod`,
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Change the metadata of an entry",
+		Long: `Change the metadata of an entry.
+
+Only the given flags are changed; an empty value clears a field. --expires takes a date
+such as 2026-12-31, an RFC 3339 time or a number of days from now such as 90d.
+--max-age-days overrides the rotation policy of the vault for this key (see lockify stale).`,
+		Example: `  lockify meta set --env prod --key STRIPE_KEY --owner payments --tags billing
+  lockify meta set --env prod --key STRIPE_KEY --expires 2026-12-31 --secret
+  lockify meta set --env prod --key STRIPE_KEY --max-age-days 30
+  lockify meta set --env prod --key STRIPE_KEY --expires ""`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runSet,
+	}
+	publicCmd := &cobra.Command{
+		Use:     "public",
+		Short:   "Keep entry metadata readable without the passphrase",
+		Example: `  lockify meta public --env prod`,
+		Args:    cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.runVisibility(c, true)
+		},
+	}
+	privateCmd := &cobra.Command{
+		Use:     "private",
+		Short:   "Encrypt entry metadata with the vault key (the default)",
+		Example: `  lockify meta private --env prod`,
+		Args:    cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.runVisibility(c, false)
+		},
+	}
+
+	for _, subCmd := range []*cobra.Command{setCmd, publicCmd, privateCmd} {
+		subCmd.Flags().StringP("env", "e", "", "Environment name")
+		if err := subCmd.MarkFlagRequired("env"); err != nil {
+			return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+		}
+		cobraCmd.AddCommand(subCmd)
+	}
+
+	setCmd.Flags().StringP("key", "k", "", "The key whose metadata to change")
+	setCmd.Flags().String("description", "", "What the key is for")
+	setCmd.Flags().String("owner", "", "Who is responsible for the key")
+	setCmd.Flags().StringSlice("tags", nil, "Tags of the key, replacing the current ones")
+	setCmd.Flags().String("expires", "", "When the value expires (2026-12-31, RFC 3339 or 90d)")
+	setCmd.Flags().Int("max-age-days", 0,
+		"Days the value may be used before it has to be rotated (0 uses the vault policy)")
+	setCmd.Flags().Bool("secret", false, "Mark the value as secret (--secret=false clears it)")
+	if err := setCmd.MarkFlagRequired("key"); err != nil {
+		return nil, fmt.Errorf("failed to mark key flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *MetaCommand) runSet(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	key, err := requireStringFlag(cmd, "key")
+	if err != nil {
+		return err
+	}
+
+	dto := app.SetEntryMetadataDTO{Env: env, Key: key}
+	flags := cmd.Flags()
+	if flags.Changed("description") {
+		description, _ := flags.GetString("description")
+		dto.Description = &description
+	}
+	if flags.Changed("owner") {
+		owner, _ := flags.GetString("owner")
+		dto.Owner = &owner
+	}
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringSlice("tags")
+		dto.Tags = &tags
+	}
+	if flags.Changed("expires") {
+		value, _ := flags.GetString("expires")
+		expiresAt, err := parseExpiry(value, c.now())
+		if err != nil {
+			return err
+		}
+		dto.ExpiresAt = &expiresAt
+	}
+	if flags.Changed("max-age-days") {
+		maxAgeDays, _ := flags.GetInt("max-age-days")
+		dto.MaxAgeDays = &maxAgeDays
+	}
+	if flags.Changed("secret") {
+		secret, _ := flags.GetBool("secret")
+		dto.Secret = &secret
+	}
+	if dto.Description == nil && dto.Owner == nil && dto.Tags == nil &&
+		dto.ExpiresAt == nil && dto.MaxAgeDays == nil && dto.Secret == nil {
+		return errors.New("nothing to change: use --description, --owner, --tags, " +
+			"--expires, --max-age-days or --secret")
+	}
+
+	c.logger.Progress("Updating the metadata of %s in %s...\n", key, env)
+	if err := c.setUseCase.Execute(getContext(), dto); err != nil {
+		return fmt.Errorf("failed to update metadata of key %s: %w", key, err)
+	}
+
+	c.logger.Success("Updated the metadata of %s in %s", key, env)
+	return nil
+}
+
+func (c *MetaCommand) runVisibility(cmd *cobra.Command, public bool) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := c.visibilityUseCase.Execute(getContext(), env, public); err != nil {
+		return fmt.Errorf("failed to change metadata visibility of %s: %w", env, err)
+	}
+
+	if public {
+		c.logger.Success("Entry metadata of %s is readable without the passphrase", env)
+	} else {
+		c.logger.Success("Entry metadata of %s is encrypted with the vault key", env)
+	}
+	return nil
+}
+
+// parseExpiry turns a date, an RFC 3339 time or a number of days such as 90d into the RFC
+// 3339 time an entry expires at; an empty value clears the expiry
+func parseExpiry(value string, now time.Time) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.UTC().AddDate(0, 0, n).Format(time.RFC3339), nil
+		}
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if expiresAt, err := time.Parse(layout, value); err == nil {
+			return expiresAt.UTC().Format(time.RFC3339), nil
+		}
+	}
+	return "", fmt.Errorf(
+		"invalid expiry %q: use a date such as 2026-12-31 or a number of days such as 90d",
+		value,
+	)
+}
+
+func init() {
+	metaCmd, err := NewMetaCommand(
+		di.BuildSetEntryMetadata(),
+		di.BuildSetMetadataVisibility(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(metaCmd)
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockSetEntryMetadataUseCase struct {
+	receivedDTO *app.SetEntryMetadataDTO
+}
+
+func (m *mockSetEntryMetadataUseCase) Execute(
+	ctx context.Context,
+	dto app.SetEntryMetadataDTO,
+) error {
+	m.receivedDTO = &dto
+	return nil
+}
+
+type mockSetMetadataVisibilityUseCase struct {
+	receivedPublic *bool
+}
+
+func (m *mockSetMetadataVisibilityUseCase) Execute(
+	ctx context.Context,
+	env string,
+	public bool,
+) error {
+	m.receivedPublic = &public
+	return nil
+}
+
+func newTestMetaCommand(
+	t *testing.T,
+	setUseCase app.SetEntryMetadataUc,
+	visibilityUseCase app.SetMetadataVisibilityUc,
+	logger *test.MockLogger,
+	args []string,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	root, err := NewMetaCommand(setUseCase, visibilityUseCase, logger)
+	if err != nil {
+		t.Fatalf("NewMetaCommand() returned unexpected error: %v", err)
+	}
+	cmd, _, err := root.Find(args)
+	if err != nil {
+		t.Fatalf("failed to find meta subcommand %v: %v", args, err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestMetaCommand_Set(t *testing.T) {
+	setUseCase := &mockSetEntryMetadataUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestMetaCommand(
+		t,
+		setUseCase,
+		&mockSetMetadataVisibilityUseCase{},
+		mockLogger,
+		[]string{"set"},
+		map[string]string{
+			"env":          "prod",
+			"key":          "STRIPE_KEY",
+			"owner":        "payments",
+			"tags":         "billing,external",
+			"expires":      "2026-12-31",
+			"max-age-days": "30",
+		},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("meta set returned unexpected error: %v", err))
+	dto := setUseCase.receivedDTO
+	assert.NotNil(t, dto, "meta set should call the use case")
+	assert.Equal(t, "prod", dto.Env)
+	assert.Equal(t, "STRIPE_KEY", dto.Key)
+	assert.Equal(t, "payments", *dto.Owner)
+	assert.DeepEqual(t, []string{"billing", "external"}, *dto.Tags)
+	assert.Equal(t, "2026-12-31T00:00:00Z", *dto.ExpiresAt)
+	assert.Equal(t, 30, *dto.MaxAgeDays)
+	assert.Nil(t, dto.Description, "unchanged fields should not be sent")
+	assert.Nil(t, dto.Secret, "unchanged fields should not be sent")
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestMetaCommand_SetClearsField(t *testing.T) {
+	setUseCase := &mockSetEntryMetadataUseCase{}
+	cmd := newTestMetaCommand(
+		t,
+		setUseCase,
+		&mockSetMetadataVisibilityUseCase{},
+		&test.MockLogger{},
+		[]string{"set"},
+		map[string]string{"env": "prod", "key": "STRIPE_KEY", "expires": "", "secret": "false"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("meta set returned unexpected error: %v", err))
+	assert.Equal(t, "", *setUseCase.receivedDTO.ExpiresAt)
+	assert.False(t, *setUseCase.receivedDTO.Secret, "the secret marker should be cleared")
+}
+
+func TestMetaCommand_SetNothing(t *testing.T) {
+	setUseCase := &mockSetEntryMetadataUseCase{}
+	cmd := newTestMetaCommand(
+		t,
+		setUseCase,
+		&mockSetMetadataVisibilityUseCase{},
+		&test.MockLogger{},
+		[]string{"set"},
+		map[string]string{"env": "prod", "key": "STRIPE_KEY"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "meta set without changes expected error, got nil")
+	assert.Contains(t, "nothing to change", err.Error())
+	assert.Nil(t, setUseCase.receivedDTO, "the use case should not be called")
+}
+
+func TestMetaCommand_Visibility(t *testing.T) {
+	for _, tt := range []struct {
+		subcommand string
+		public     bool
+	}{
+		{subcommand: "public", public: true},
+		{subcommand: "private", public: false},
+	} {
+		t.Run(tt.subcommand, func(t *testing.T) {
+			visibilityUseCase := &mockSetMetadataVisibilityUseCase{}
+			mockLogger := &test.MockLogger{}
+			cmd := newTestMetaCommand(
+				t,
+				&mockSetEntryMetadataUseCase{},
+				visibilityUseCase,
+				mockLogger,
+				[]string{tt.subcommand},
+				map[string]string{"env": "prod"},
+			)
+
+			err := cmd.RunE(cmd, nil)
+			assert.Nil(t, err, fmt.Sprintf("meta returned unexpected error: %v", err))
+			assert.NotNil(t, visibilityUseCase.receivedPublic, "the use case should be called")
+			assert.Equal(t, tt.public, *visibilityUseCase.receivedPublic)
+			assert.Count(t, 1, mockLogger.SuccessLogs)
+		})
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "90d", want: "2026-06-08T12:00:00Z"},
+		{value: "2026-12-31", want: "2026-12-31T00:00:00Z"},
+		{value: "2026-12-31T18:00:00+02:00", want: "2026-12-31T16:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseExpiry(tt.value, now)
+		assert.Nil(t, err, fmt.Sprintf("parseExpiry(%q) returned error: %v", tt.value, err))
+		assert.Equal(t, tt.want, got)
+	}
+
+	for _, value := range []string{"0d", "-5d", "next year"} {
+		_, err := parseExpiry(value, now)
+		assert.NotNil(t, err, fmt.Sprintf("parseExpiry(%q) expected error, got nil", value))
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// MigrateCommand represents the migrate command for upgrading vault files.
+type MigrateCommand struct {
+	useCase app.MigrateVaultUc
+	logger  domain.Logger
+}
+
+// NewMigrateCommand creates a new migrate command instance.
+func NewMigrateCommand(useCase app.MigrateVaultUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &MigrateCommand{useCase, logger}
+
+	// lockify migrate --env [env] | --all
+	cobraCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade vault files to the current format",
+		Long: `Upgrade vault files to the current format.
+
+Vault files record a format version together with the cipher and key derivation
+parameters they were written with. This command upgrades older vault files in place.
+The previous version of each upgraded file is kept as its newest backup, which
+"lockify restore" can bring back.`,
+		Example: `  lockify migrate --env prod
+  lockify migrate --all`,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().Bool("all", false, "Migrate the vaults of all environments")
+	cobraCmd.MarkFlagsOneRequired("env", "all")
+	cobraCmd.MarkFlagsMutuallyExclusive("env", "all")
+
+	return cobraCmd, nil
+}
+
+func (c *MigrateCommand) runE(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve all flag: %w", err)
+	}
+	env, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve env flag: %w", err)
+	}
+
+	ctx := getContext()
+	if !all {
+		if env == "" {
+			return errors.New(errMsgEmptyEnv)
+		}
+		c.logger.Progress("Migrating vault for %s...\n", env)
+		result, err := c.useCase.Execute(ctx, env)
+		if err != nil {
+			return err
+		}
+		c.report(result)
+		return nil
+	}
+
+	c.logger.Progress("Migrating all vaults...\n")
+	results, err := c.useCase.ExecuteAll(ctx)
+	for _, result := range results {
+		c.report(result)
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		c.logger.Info("No vaults found")
+	}
+
+	return nil
+}
+
+func (c *MigrateCommand) report(result app.MigrationResult) {
+	if !result.Migrated() {
+		c.logger.Info("%s is already at format version %d", result.Env, result.ToVersion)
+		return
+	}
+	c.logger.Success(
+		"Migrated %s from format version %d to %d (backup: %s)",
+		result.Env,
+		result.FromVersion,
+		result.ToVersion,
+		result.BackupPath,
+	)
+}
+
+func init() {
+	migrateCmd, err := NewMigrateCommand(di.BuildMigrateVault(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockMigrateUseCase struct {
+	executeFunc    func(ctx context.Context, env string) (app.MigrationResult, error)
+	executeAllFunc func(ctx context.Context) ([]app.MigrationResult, error)
+	receivedEnv    string
+	calledAll      bool
+}
+
+func (m *mockMigrateUseCase) Execute(ctx context.Context, env string) (app.MigrationResult, error) {
+	m.receivedEnv = env
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env)
+	}
+	return app.MigrationResult{
+		Env:         env,
+		FromVersion: 0,
+		ToVersion:   1,
+		BackupPath:  env + ".vault.enc.bak",
+	}, nil
+}
+
+func (m *mockMigrateUseCase) ExecuteAll(ctx context.Context) ([]app.MigrationResult, error) {
+	m.calledAll = true
+	if m.executeAllFunc != nil {
+		return m.executeAllFunc(ctx)
+	}
+	return []app.MigrationResult{
+		{Env: "dev", FromVersion: 0, ToVersion: 1, BackupPath: "dev.vault.enc.bak"},
+		{Env: "prod", FromVersion: 1, ToVersion: 1},
+	}, nil
+}
+
+func TestMigrateCommand_Env(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", mockUseCase.receivedEnv)
+	assert.False(t, mockUseCase.calledAll)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, "test.vault.enc.bak", mockLogger.SuccessLogs[0])
+}
+
+func TestMigrateCommand_Env_AlreadyCurrent(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{
+		executeFunc: func(ctx context.Context, env string) (app.MigrationResult, error) {
+			return app.MigrationResult{Env: env, FromVersion: 1, ToVersion: 1}, nil
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+	assert.Count(t, 1, mockLogger.InfoLogs)
+	assert.Contains(t, "already at format version 1", mockLogger.InfoLogs[0])
+}
+
+func TestMigrateCommand_All(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("all", "true"); err != nil {
+		t.Fatalf("failed to set all flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.calledAll)
+	assert.Equal(t, "", mockUseCase.receivedEnv)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Count(t, 1, mockLogger.InfoLogs)
+}
+
+func TestMigrateCommand_All_NoVaults(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{
+		executeAllFunc: func(ctx context.Context) ([]app.MigrationResult, error) {
+			return []app.MigrationResult{}, nil
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("all", "true"); err != nil {
+		t.Fatalf("failed to set all flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 1, mockLogger.InfoLogs)
+	assert.Contains(t, "No vaults found", mockLogger.InfoLogs[0])
+}
+
+func TestMigrateCommand_Error_EnvAndAll(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	cmd.SetArgs([]string{"--env", "test", "--all"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+	assert.Contains(t, "none of the others can be", err.Error())
+	assert.Equal(t, "", mockUseCase.receivedEnv)
+	assert.False(t, mockUseCase.calledAll)
+}
+
+func TestMigrateCommand_Error_Required_Env(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
+
+func TestMigrateCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockMigrateUseCase{
+		executeAllFunc: func(ctx context.Context) ([]app.MigrationResult, error) {
+			return []app.MigrationResult{
+				{Env: "dev", FromVersion: 0, ToVersion: 1, BackupPath: "dev.vault.enc.bak"},
+			}, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewMigrateCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("all", "true"); err != nil {
+		t.Fatalf("failed to set all flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// MoveCommand represents the mv and cp commands for moving and copying entries.
+type MoveCommand struct {
+	useCase app.MoveEntryUc
+	logger  domain.Logger
+	copy    bool
+}
+
+// NewMoveCommand creates a new mv command instance, which can also be run as rename.
+func NewMoveCommand(useCase app.MoveEntryUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &MoveCommand{useCase, logger, false}
+
+	// lockify mv --env [env] [key] [new key]
+	// lockify mv --env [env] --to-env [env] [key] [new key]
+	cobraCmd := &cobra.Command{
+		Use:     "mv KEY [NEW_KEY]",
+		Aliases: []string{"rename"},
+		Short:   "Rename an entry or move it to another environment",
+		Long: `Rename an entry or move it to another environment.
+
+The entry keeps its creation time and secret marker. Entries are bound to their key and
+environment, so the value is decrypted and encrypted again for its new place; only
+vaults older than format version 2 rename entries without decrypting them.
+
+When moving to another environment, that vault is saved before the entry is removed
+from the original one.`,
+		Example: `  lockify rename --env prod DB_URL DATABASE_URL
+  lockify mv --env staging --to-env prod FEATURE_FLAG
+  lockify mv --env staging --to-env prod OLD_NAME NEW_NAME --overwrite`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: cmd.runE,
+	}
+
+	if err := addMoveFlags(cobraCmd, "Environment to move the entry to"); err != nil {
+		return nil, err
+	}
+
+	return cobraCmd, nil
+}
+
+// addMoveFlags adds the flags shared by mv and cp
+func addMoveFlags(cobraCmd *cobra.Command, toEnvUsage string) error {
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().String("to-env", "", toEnvUsage)
+	cobraCmd.Flags().Bool("overwrite", false, "Replace the entry at the target if it exists")
+	if err := cobraCmd.MarkFlagRequired("env"); err != nil {
+		return fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+	return nil
+}
+
+func (c *MoveCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	toEnv, err := cmd.Flags().GetString("to-env")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve to-env flag: %w", err)
+	}
+	overwrite, err := cmd.Flags().GetBool("overwrite")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve overwrite flag: %w", err)
+	}
+
+	dto := app.MoveEntryDTO{
+		Env:       env,
+		Key:       args[0],
+		ToEnv:     toEnv,
+		Copy:      c.copy,
+		Overwrite: overwrite,
+	}
+	if len(args) == 2 {
+		dto.NewKey = args[1]
+	}
+
+	target := dto.Key
+	if dto.NewKey != "" {
+		target = dto.NewKey
+	}
+	if toEnv != "" {
+		target = toEnv + "/" + target
+	}
+
+	verb, done := "move", "Moved"
+	if c.copy {
+		verb, done = "copy", "Copied"
+	}
+	if err := c.useCase.Execute(getContext(), dto); err != nil {
+		return fmt.Errorf("failed to %s key %s in environment %s: %w", verb, dto.Key, env, err)
+	}
+
+	c.logger.Success("%s %s/%s to %s", done, env, dto.Key, target)
+	return nil
+}
+
+func init() {
+	mvCmd, err := NewMoveCommand(di.BuildMoveEntry(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(mvCmd)
+}
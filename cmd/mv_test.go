@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockMoveEntryUseCase struct {
+	executeFunc func(ctx context.Context, dto app.MoveEntryDTO) error
+	receivedDTO app.MoveEntryDTO
+}
+
+func (m *mockMoveEntryUseCase) Execute(ctx context.Context, dto app.MoveEntryDTO) error {
+	m.receivedDTO = dto
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, dto)
+	}
+	return nil
+}
+
+func TestMoveCommand_Rename(t *testing.T) {
+	mockUseCase := &mockMoveEntryUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd, err := NewMoveCommand(mockUseCase, mockLogger)
+	assert.Nil(t, err)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err = cmd.RunE(cmd, []string{"DB_URL", "DATABASE_URL"})
+	assert.Nil(t, err)
+	assert.DeepEqual(t, app.MoveEntryDTO{
+		Env:    "prod",
+		Key:    "DB_URL",
+		NewKey: "DATABASE_URL",
+	}, mockUseCase.receivedDTO)
+	assert.DeepEqual(t, []string{"Moved prod/DB_URL to DATABASE_URL"}, mockLogger.SuccessLogs)
+	assert.Contains(t, "rename", cmd.Aliases)
+}
+
+func TestMoveCommand_AcrossEnvs(t *testing.T) {
+	mockUseCase := &mockMoveEntryUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd, err := NewMoveCommand(mockUseCase, mockLogger)
+	assert.Nil(t, err)
+	if err := cmd.Flags().Set("env", "staging"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("to-env", "prod"); err != nil {
+		t.Fatalf("failed to set to-env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("overwrite", "true"); err != nil {
+		t.Fatalf("failed to set overwrite flag: %v", err)
+	}
+
+	err = cmd.RunE(cmd, []string{"FEATURE_FLAG"})
+	assert.Nil(t, err)
+	assert.DeepEqual(t, app.MoveEntryDTO{
+		Env:       "staging",
+		Key:       "FEATURE_FLAG",
+		ToEnv:     "prod",
+		Overwrite: true,
+	}, mockUseCase.receivedDTO)
+	assert.DeepEqual(
+		t,
+		[]string{"Moved staging/FEATURE_FLAG to prod/FEATURE_FLAG"},
+		mockLogger.SuccessLogs,
+	)
+}
+
+func TestMoveCommand_Error_Required_Env(t *testing.T) {
+	cmd, err := NewMoveCommand(&mockMoveEntryUseCase{}, &test.MockLogger{})
+	assert.Nil(t, err)
+
+	err = cmd.RunE(cmd, []string{"DB_URL", "DATABASE_URL"})
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
+
+func TestMoveCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockMoveEntryUseCase{
+		executeFunc: func(ctx context.Context, dto app.MoveEntryDTO) error {
+			return fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd, err := NewMoveCommand(mockUseCase, mockLogger)
+	assert.Nil(t, err)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err = cmd.RunE(cmd, []string{"DB_URL", "DATABASE_URL"})
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestCopyCommand_Success(t *testing.T) {
+	mockUseCase := &mockMoveEntryUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd, err := NewCopyCommand(mockUseCase, mockLogger)
+	assert.Nil(t, err)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err = cmd.RunE(cmd, []string{"DATABASE_URL", "REPLICA_URL"})
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.receivedDTO.Copy, "cp should keep the original entry")
+	assert.DeepEqual(
+		t,
+		[]string{"Copied prod/DATABASE_URL to REPLICA_URL"},
+		mockLogger.SuccessLogs,
+	)
+}
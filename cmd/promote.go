@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// PromoteCommand represents the promote command for copying entries between environments.
+type PromoteCommand struct {
+	useCase app.PromoteEntriesUc
+	logger  domain.Logger
+}
+
+// NewPromoteCommand creates a new promote command instance.
+func NewPromoteCommand(useCase app.PromoteEntriesUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &PromoteCommand{useCase, logger}
+
+	// lockify promote --from [env] --to [env] --keys [keys] --exclude [keys]
+	cobraCmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Copy selected entries from one environment into another",
+		Long: `Copy selected entries from one environment into another.
+
+The selected entries are decrypted with the source vault and re-encrypted for the target
+vault. Keys are selected by name or by glob pattern such as FEATURE_*, and --exclude
+keeps keys that must differ per environment out of the promotion.
+
+Keys that already exist in the target with another value are skipped unless --overwrite
+is given. The target is saved once, and only if every entry could be promoted; use
+--dry-run to see the plan without saving anything.`,
+		Example: `  lockify promote --from staging --to prod --keys 'FEATURE_*,API_URL'
+  lockify promote --from staging --to prod --keys '*' --exclude 'DATABASE_*' --dry-run
+  lockify promote --from staging --to prod --keys LOG_LEVEL --overwrite`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().String("from", "", "Environment to copy the entries from")
+	cobraCmd.Flags().String("to", "", "Environment to copy the entries into")
+	cobraCmd.Flags().StringSlice("keys", nil, "Keys or glob patterns to promote (comma separated)")
+	cobraCmd.Flags().StringSlice("exclude", nil, "Keys or glob patterns never to promote")
+	cobraCmd.Flags().Bool("overwrite", false, "Overwrite keys with another value in the target")
+	cobraCmd.Flags().Bool("dry-run", false, "Show what would be promoted without saving")
+	for _, flag := range []string{"from", "to", "keys"} {
+		if err := cobraCmd.MarkFlagRequired(flag); err != nil {
+			return nil, fmt.Errorf("failed to mark %s flag as required: %w", flag, err)
+		}
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *PromoteCommand) runE(cmd *cobra.Command, args []string) error {
+	from, err := requireStringFlag(cmd, "from")
+	if err != nil {
+		return err
+	}
+	to, err := requireStringFlag(cmd, "to")
+	if err != nil {
+		return err
+	}
+	keys, err := cmd.Flags().GetStringSlice("keys")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve keys flag: %w", err)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("keys flag is required")
+	}
+	exclude, err := cmd.Flags().GetStringSlice("exclude")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve exclude flag: %w", err)
+	}
+	overwrite, err := cmd.Flags().GetBool("overwrite")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve overwrite flag: %w", err)
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dry-run flag: %w", err)
+	}
+
+	result, err := c.useCase.Execute(getContext(), app.PromoteEntriesDTO{
+		From:      from,
+		To:        to,
+		Keys:      keys,
+		Exclude:   exclude,
+		Overwrite: overwrite,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to promote entries from %s to %s: %w", from, to, err)
+	}
+
+	c.report(result)
+	promoted := len(result.Added) + len(result.Updated)
+	switch {
+	case dryRun:
+		c.logger.Info("Dry run: %d key(s) would be promoted from %s to %s", promoted, from, to)
+	case result.Saved:
+		c.logger.Success("Promoted %d key(s) from %s to %s", promoted, from, to)
+	default:
+		c.logger.Info("Nothing to promote from %s to %s", from, to)
+	}
+
+	return nil
+}
+
+func (c *PromoteCommand) report(result app.PromoteResult) {
+	for _, key := range result.Added {
+		c.logger.Info("+ %s", key)
+	}
+	for _, key := range result.Updated {
+		c.logger.Info("~ %s", key)
+	}
+	for _, key := range result.Unchanged {
+		c.logger.Info("= %s (unchanged)", key)
+	}
+	for _, key := range result.Excluded {
+		c.logger.Info("  %s (excluded)", key)
+	}
+	for _, key := range result.Skipped {
+		c.logger.Warning("Skipping existing key %q (use --overwrite to replace)", key)
+	}
+}
+
+func init() {
+	promoteCmd, err := NewPromoteCommand(di.BuildPromoteEntries(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(promoteCmd)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockPromoteEntriesUseCase struct {
+	executeFunc func(ctx context.Context, dto app.PromoteEntriesDTO) (app.PromoteResult, error)
+	receivedDTO app.PromoteEntriesDTO
+}
+
+func (m *mockPromoteEntriesUseCase) Execute(
+	ctx context.Context,
+	dto app.PromoteEntriesDTO,
+) (app.PromoteResult, error) {
+	m.receivedDTO = dto
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, dto)
+	}
+	return app.PromoteResult{
+		Added:     []string{"FEATURE_A"},
+		Updated:   []string{"API_URL"},
+		Unchanged: []string{"LOG_LEVEL"},
+		Skipped:   []string{"FEATURE_B"},
+		Excluded:  []string{"DATABASE_URL"},
+		Saved:     !dto.DryRun,
+	}, nil
+}
+
+func newTestPromoteCommand(
+	t *testing.T,
+	useCase app.PromoteEntriesUc,
+	logger *test.MockLogger,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewPromoteCommand(useCase, logger)
+	if err != nil {
+		t.Fatalf("NewPromoteCommand() returned unexpected error: %v", err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestPromoteCommand_Success(t *testing.T) {
+	mockUseCase := &mockPromoteEntriesUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestPromoteCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from":      "staging",
+		"to":        "prod",
+		"keys":      "FEATURE_*,API_URL",
+		"exclude":   "DATABASE_*",
+		"overwrite": "true",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, app.PromoteEntriesDTO{
+		From:      "staging",
+		To:        "prod",
+		Keys:      []string{"FEATURE_*", "API_URL"},
+		Exclude:   []string{"DATABASE_*"},
+		Overwrite: true,
+	}, mockUseCase.receivedDTO)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, "Promoted 2 key(s)", mockLogger.SuccessLogs[0])
+	assert.Count(t, 1, mockLogger.WarningLogs)
+	assert.Count(t, 4, mockLogger.InfoLogs)
+}
+
+func TestPromoteCommand_DryRun(t *testing.T) {
+	mockUseCase := &mockPromoteEntriesUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestPromoteCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from":    "staging",
+		"to":      "prod",
+		"keys":    "*",
+		"dry-run": "true",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.receivedDTO.DryRun, "DryRun should be passed on")
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+	summary := mockLogger.InfoLogs[len(mockLogger.InfoLogs)-1]
+	assert.Contains(t, "Dry run: 2 key(s) would be promoted", summary)
+}
+
+func TestPromoteCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "missing to",
+			flags:       map[string]string{"from": "staging", "keys": "*"},
+			expectedErr: "to flag is required",
+		},
+		{
+			name:        "missing keys",
+			flags:       map[string]string{"from": "staging", "to": "prod"},
+			expectedErr: "keys flag is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestPromoteCommand(
+				t,
+				&mockPromoteEntriesUseCase{},
+				&test.MockLogger{},
+				tt.flags,
+			)
+
+			err := cmd.RunE(cmd, nil)
+			assert.NotNil(t, err)
+			assert.Contains(t, tt.expectedErr, err.Error())
+		})
+	}
+}
+
+func TestPromoteCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockPromoteEntriesUseCase{
+		executeFunc: func(
+			ctx context.Context,
+			dto app.PromoteEntriesDTO,
+		) (app.PromoteResult, error) {
+			return app.PromoteResult{}, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestPromoteCommand(t, mockUseCase, mockLogger, map[string]string{
+		"from": "staging",
+		"to":   "prod",
+		"keys": "*",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
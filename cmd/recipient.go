@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// RecipientCommand represents the recipient command for managing the public-key recipients
+// of a vault.
+type RecipientCommand struct {
+	addUseCase    app.AddRecipientUc
+	removeUseCase app.RemoveRecipientUc
+	listUseCase   app.ListRecipientsUc
+	logger        domain.Logger
+}
+
+// NewRecipientCommand creates a new recipient command instance with its add, remove and
+// list subcommands.
+func NewRecipientCommand(
+	addUseCase app.AddRecipientUc,
+	removeUseCase app.RemoveRecipientUc,
+	listUseCase app.ListRecipientsUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &RecipientCommand{addUseCase, removeUseCase, listUseCase, logger}
+
+	// lockify recipient [add|remove|list] --env [env]
+	cobraCmd := &cobra.Command{
+		Use:   "recipient",
+		Short: "Manage the public-key recipients of a vault",
+		Long: `Manage the public-key recipients of a vault.
+
+A recipient is the public key of an identity created with "lockify keygen". The vault
+data key is wrapped for every recipient, so their identity file unlocks the vault
+without a passphrase. Passphrases and key slots keep working alongside recipients.`,
+		Example: `  lockify recipient add --env prod --name alice lockify1...
+  lockify recipient list --env prod
+  lockify recipient remove --env prod alice`,
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <public-key>",
+		Short: "Let the identity with this public key unlock the vault",
+		Long: `Let the identity with this public key unlock the vault.
+
+The vault is unlocked with your passphrase or identity, then its data key is wrapped
+for the given public key.`,
+		Example: `  lockify recipient add --env prod --name alice lockify1...`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    cmd.runAdd,
+	}
+	removeCmd := &cobra.Command{
+		Use:     "remove <name|public-key>",
+		Short:   "Remove a recipient so its identity no longer unlocks the vault",
+		Example: `  lockify recipient remove --env prod alice`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    cmd.runRemove,
+	}
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the recipients of a vault",
+		Example: `  lockify recipient list --env prod`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runList,
+	}
+
+	for _, subCmd := range []*cobra.Command{addCmd, removeCmd, listCmd} {
+		subCmd.Flags().StringP("env", "e", "", "Environment Name")
+		if err := subCmd.MarkFlagRequired("env"); err != nil {
+			return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+		}
+		cobraCmd.AddCommand(subCmd)
+	}
+	addCmd.Flags().StringP("name", "n", "", "Recipient name")
+
+	return cobraCmd, nil
+}
+
+func (c *RecipientCommand) runAdd(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve name flag: %w", err)
+	}
+	publicKey := args[0]
+
+	label := name
+	if label == "" {
+		label = publicKey
+	}
+
+	c.logger.Progress("Adding recipient %s to %s...\n", label, env)
+	if err := c.addUseCase.Execute(getContext(), env, publicKey, name); err != nil {
+		c.logger.Error("failed to add recipient: %v", err)
+		return err
+	}
+
+	c.logger.Success("Recipient %q added to %s", label, env)
+	return nil
+}
+
+func (c *RecipientCommand) runRemove(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	recipient := args[0]
+
+	c.logger.Progress("Removing recipient %s from %s...\n", recipient, env)
+	if err := c.removeUseCase.Execute(getContext(), env, recipient); err != nil {
+		c.logger.Error("failed to remove recipient: %v", err)
+		return err
+	}
+
+	c.logger.Success("Recipient %q removed from %s", recipient, env)
+	return nil
+}
+
+func (c *RecipientCommand) runList(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := c.listUseCase.Execute(getContext(), env)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Success("Found %d recipient(s):", len(recipients))
+	for _, recipient := range recipients {
+		line := "  - " + recipient.PublicKey
+		if recipient.Name != "" {
+			line = "  - " + recipient.Name + " " + recipient.PublicKey
+		}
+		if recipient.CreatedAt != "" {
+			line += " (added " + recipient.CreatedAt + ")"
+		}
+		if recipient.Unlocked {
+			line += " [unlocked]"
+		}
+		c.logger.Output("%s", line)
+	}
+
+	return nil
+}
+
+func init() {
+	recipientCmd, err := NewRecipientCommand(
+		di.BuildAddRecipient(),
+		di.BuildRemoveRecipient(),
+		di.BuildListRecipients(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(recipientCmd)
+}
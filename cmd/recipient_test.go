@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockAddRecipientUseCase struct {
+	executeFunc       func(ctx context.Context, env, publicKey, name string) error
+	receivedEnv       string
+	receivedPublicKey string
+	receivedName      string
+}
+
+func (m *mockAddRecipientUseCase) Execute(ctx context.Context, env, publicKey, name string) error {
+	m.receivedEnv = env
+	m.receivedPublicKey = publicKey
+	m.receivedName = name
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, publicKey, name)
+	}
+	return nil
+}
+
+type mockRemoveRecipientUseCase struct {
+	receivedEnv       string
+	receivedRecipient string
+}
+
+func (m *mockRemoveRecipientUseCase) Execute(ctx context.Context, env, recipient string) error {
+	m.receivedEnv = env
+	m.receivedRecipient = recipient
+	return nil
+}
+
+type mockListRecipientsUseCase struct{}
+
+func (m *mockListRecipientsUseCase) Execute(
+	ctx context.Context,
+	env string,
+) ([]app.RecipientInfo, error) {
+	return []app.RecipientInfo{
+		{Name: "alice", PublicKey: "lockify1alice", Unlocked: true},
+		{PublicKey: "lockify1bob", CreatedAt: "2026-01-02T03:04:05Z"},
+	}, nil
+}
+
+func newTestRecipientSubcommand(
+	t *testing.T,
+	name string,
+	addUseCase app.AddRecipientUc,
+	removeUseCase app.RemoveRecipientUc,
+	logger *test.MockLogger,
+) *cobra.Command {
+	t.Helper()
+	recipientCmd, err := NewRecipientCommand(
+		addUseCase,
+		removeUseCase,
+		&mockListRecipientsUseCase{},
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewRecipientCommand() returned unexpected error: %v", err)
+	}
+	subCmd, _, err := recipientCmd.Find([]string{name})
+	if err != nil {
+		t.Fatalf("failed to find recipient %s command: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	subCmd.SetOut(&buf)
+	subCmd.SetErr(&buf)
+	return subCmd
+}
+
+func TestRecipientAddCommand_Success(t *testing.T) {
+	mockUseCase := &mockAddRecipientUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestRecipientSubcommand(
+		t,
+		"add",
+		mockUseCase,
+		&mockRemoveRecipientUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("name", "alice"); err != nil {
+		t.Fatalf("failed to set name flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"lockify1alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedEnv)
+	assert.Equal(t, "lockify1alice", mockUseCase.receivedPublicKey)
+	assert.Equal(t, "alice", mockUseCase.receivedName)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestRecipientAddCommand_RequiresPublicKey(t *testing.T) {
+	cmd := newTestRecipientSubcommand(
+		t,
+		"add",
+		&mockAddRecipientUseCase{},
+		&mockRemoveRecipientUseCase{},
+		&test.MockLogger{},
+	)
+
+	assert.NotNil(t, cmd.Args(cmd, nil), "recipient add without a public key expected error")
+}
+
+func TestRecipientAddCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockAddRecipientUseCase{
+		executeFunc: func(ctx context.Context, env, publicKey, name string) error {
+			return fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestRecipientSubcommand(
+		t,
+		"add",
+		mockUseCase,
+		&mockRemoveRecipientUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"lockify1alice"})
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestRecipientRemoveCommand_Success(t *testing.T) {
+	mockUseCase := &mockRemoveRecipientUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestRecipientSubcommand(
+		t,
+		"remove",
+		&mockAddRecipientUseCase{},
+		mockUseCase,
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedEnv)
+	assert.Equal(t, "alice", mockUseCase.receivedRecipient)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestRecipientListCommand_Success(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestRecipientSubcommand(
+		t,
+		"list",
+		&mockAddRecipientUseCase{},
+		&mockRemoveRecipientUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 2, mockLogger.OutputLogs)
+	assert.Contains(t, "  - alice lockify1alice [unlocked]", mockLogger.OutputLogs)
+	assert.Contains(t, "  - lockify1bob (added 2026-01-02T03:04:05Z)", mockLogger.OutputLogs)
+}
+
+func TestRecipientListCommand_Error_Required_Env(t *testing.T) {
+	cmd := newTestRecipientSubcommand(
+		t,
+		"list",
+		&mockAddRecipientUseCase{},
+		&mockRemoveRecipientUseCase{},
+		&test.MockLogger{},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
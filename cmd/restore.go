@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// RestoreCommand represents the restore command for bringing back a vault backup.
+type RestoreCommand struct {
+	useCase app.RestoreVaultUc
+	logger  domain.Logger
+}
+
+// NewRestoreCommand creates a new restore command instance.
+func NewRestoreCommand(useCase app.RestoreVaultUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &RestoreCommand{useCase, logger}
+
+	// lockify restore --env [env] --generation [n]
+	cobraCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a vault from one of its backups",
+		Long: `Restore a vault from one of its backups.
+
+Every time a vault is saved, its previous version is kept next to it as
+<env>.vault.enc.bak.1, shifting older backups to .bak.2, .bak.3 and so on. Set
+LOCKIFY_BACKUPS to change how many backups are kept (default 3, 0 disables them).
+
+Restoring keeps the current vault as the newest backup, so a restore can be undone
+with another restore. Environments removed with lockify env delete are restored the
+same way. The restored vault is unlocked to record the restore in its audit log.`,
+		Example: `  lockify restore --env prod
+  lockify restore --env prod --generation 2`,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().IntP("generation", "g", 1, "Backup generation to restore, 1 is the newest")
+	err := cobraCmd.MarkFlagRequired("env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *RestoreCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	generation, err := cmd.Flags().GetInt("generation")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve generation flag: %w", err)
+	}
+
+	c.logger.Progress("Restoring vault for %s from backup generation %d...\n", env, generation)
+	path, err := c.useCase.Execute(getContext(), env, generation)
+	if err != nil {
+		c.logger.Error("failed to restore vault: %v", err)
+		return err
+	}
+
+	c.logger.Success("Restored %s from %s", env, path)
+	return nil
+}
+
+func init() {
+	restoreCmd, err := NewRestoreCommand(di.BuildRestoreVault(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(restoreCmd)
+}
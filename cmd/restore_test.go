@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockRestoreVaultUseCase struct {
+	executeFunc        func(ctx context.Context, env string, generation int) (string, error)
+	receivedEnv        string
+	receivedGeneration int
+}
+
+func (m *mockRestoreVaultUseCase) Execute(
+	ctx context.Context,
+	env string,
+	generation int,
+) (string, error) {
+	m.receivedEnv = env
+	m.receivedGeneration = generation
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, generation)
+	}
+	return fmt.Sprintf(".lockify/%s.vault.enc.bak.%d", env, generation), nil
+}
+
+func TestRestoreCommand_DefaultGeneration(t *testing.T) {
+	mockUseCase := &mockRestoreVaultUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewRestoreCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedEnv)
+	assert.Equal(t, 1, mockUseCase.receivedGeneration)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, ".lockify/prod.vault.enc.bak.1", mockLogger.SuccessLogs[0])
+}
+
+func TestRestoreCommand_Generation(t *testing.T) {
+	mockUseCase := &mockRestoreVaultUseCase{}
+
+	cmd, _ := NewRestoreCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("generation", "3"); err != nil {
+		t.Fatalf("failed to set generation flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, mockUseCase.receivedGeneration)
+}
+
+func TestRestoreCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockRestoreVaultUseCase{
+		executeFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			return "", fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewRestoreCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestRestoreCommand_Error_Required_Env(t *testing.T) {
+	cmd, _ := NewRestoreCommand(&mockRestoreVaultUseCase{}, &test.MockLogger{})
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// RollbackCommand represents the rollback command for restoring a previous value of an entry.
+type RollbackCommand struct {
+	useCase app.RollbackEntryUc
+	logger  domain.Logger
+}
+
+// NewRollbackCommand creates a new rollback command instance.
+func NewRollbackCommand(useCase app.RollbackEntryUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &RollbackCommand{useCase, logger}
+
+	// lockify rollback --env [env] --key [key] --version [version]
+	cobraCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore a previous value of an entry",
+		Long: `Restore a previous value of an entry.
+
+The version is one listed by lockify history. The restored value becomes a new version,
+and the value it replaces is kept in the history, so a rollback can be undone.`,
+		Example: `  lockify rollback --env prod --key STRIPE_KEY --version 3`,
+		Args:    cobra.NoArgs,
+		RunE:    cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment name")
+	cobraCmd.Flags().StringP("key", "k", "", "The key to roll back")
+	cobraCmd.Flags().Int("version", 0, "The version to restore, as listed by lockify history")
+	for _, flag := range []string{"env", "key", "version"} {
+		if err := cobraCmd.MarkFlagRequired(flag); err != nil {
+			return nil, fmt.Errorf("failed to mark %s flag as required: %w", flag, err)
+		}
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *RollbackCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	key, err := requireStringFlag(cmd, "key")
+	if err != nil {
+		return err
+	}
+	version, err := cmd.Flags().GetInt("version")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve version flag: %w", err)
+	}
+	if version < 1 {
+		return fmt.Errorf("version must be at least 1, got %d", version)
+	}
+
+	c.logger.Progress("Rolling back %s in %s to version %d...\n", key, env, version)
+	if err := c.useCase.Execute(getContext(), env, key, version); err != nil {
+		return fmt.Errorf("failed to roll back key %s: %w", key, err)
+	}
+
+	c.logger.Success("Restored version %d of %s in %s", version, key, env)
+	return nil
+}
+
+func init() {
+	rollbackCmd, err := NewRollbackCommand(di.BuildRollbackEntry(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(rollbackCmd)
+}
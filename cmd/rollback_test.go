@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockRollbackUseCase struct {
+	executeFunc     func(ctx context.Context, env, key string, version int) error
+	receivedKey     string
+	receivedVersion int
+}
+
+func (m *mockRollbackUseCase) Execute(ctx context.Context, env, key string, version int) error {
+	m.receivedKey = key
+	m.receivedVersion = version
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, key, version)
+	}
+	return nil
+}
+
+func newTestRollbackCommand(
+	t *testing.T,
+	useCase app.RollbackEntryUc,
+	logger *test.MockLogger,
+	version string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewRollbackCommand(useCase, logger)
+	if err != nil {
+		t.Fatalf("NewRollbackCommand() returned unexpected error: %v", err)
+	}
+	flags := map[string]string{"env": "prod", "key": "STRIPE_KEY", "version": version}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestRollbackCommand_Success(t *testing.T) {
+	mockUseCase := &mockRollbackUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestRollbackCommand(t, mockUseCase, mockLogger, "3")
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("rollback returned unexpected error: %v", err))
+	assert.Equal(t, "STRIPE_KEY", mockUseCase.receivedKey)
+	assert.Equal(t, 3, mockUseCase.receivedVersion)
+	assert.Contains(t, "Restored version 3 of STRIPE_KEY in prod", mockLogger.SuccessLogs)
+}
+
+func TestRollbackCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		useErr  error
+		want    string
+	}{
+		{name: "invalid version", version: "0", want: "version must be at least 1"},
+		{
+			name:    "use case error",
+			version: "2",
+			useErr:  fmt.Errorf("%s", errMsgExecuteFailed),
+			want:    errMsgExecuteFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &mockRollbackUseCase{
+				executeFunc: func(ctx context.Context, env, key string, version int) error {
+					return tt.useErr
+				},
+			}
+			cmd := newTestRollbackCommand(t, mockUseCase, &test.MockLogger{}, tt.version)
+
+			err := cmd.RunE(cmd, nil)
+			assert.NotNil(t, err, "rollback expected error, got nil")
+			assert.Contains(t, tt.want, err.Error())
+		})
+	}
+}
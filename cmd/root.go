@@ -5,7 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +32,58 @@ Your secrets are protected with a passphrase that can be stored securely in your
 	},
 }
 
+// lockTimeout is how long a command waits for another lockify process to release a vault
+var lockTimeout time.Duration
+
+// passphraseOptions configures where a command reads vault passphrases from
+var passphraseOptions = service.PassphraseOptions{FD: -1}
+
+// cacheTTL is how long a passphrase entered at the prompt stays cached
+var cacheTTL time.Duration
+
+// Commands that report their findings through their exit code exit with exitCodeFindings
+// when there are any and with exitCodeFailure when they fail, so scripts can tell them apart.
+const (
+	exitCodeFindings = 1
+	exitCodeFailure  = 2
+)
+
+// ExitError asks lockify to exit with Code, for commands that pass on the exit code of another
+// process or report their result through it. Err is the error to report, if any.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// exitWithFailureCode makes cobraCmd exit with exitCodeFailure when it fails, including on
+// invalid flags. Cobra rejects missing required flags before running the command, with the
+// default exit code, so such commands check their required flags in RunE instead.
+func exitWithFailureCode(cobraCmd *cobra.Command) {
+	runE := cobraCmd.RunE
+	cobraCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runE(cmd, args)
+		var exitErr *ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			return err
+		}
+		return &ExitError{Code: exitCodeFailure, Err: err}
+	}
+	cobraCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &ExitError{Code: exitCodeFailure, Err: err}
+	})
+}
+
 // Execute runs the root command and handles errors.
 func Execute() error {
 	return rootCmd.Execute()
@@ -54,7 +112,91 @@ func requireStringFlag(cmd *cobra.Command, flag string) (string, error) {
 	return value, nil
 }
 
+// promptNewPassphrase asks for a new passphrase with message and again with confirmMessage,
+// so a typo does not lock anyone out of the vault
+func promptNewPassphrase(
+	prompt service.PromptService,
+	message, confirmMessage string,
+) (string, error) {
+	passphrase, err := prompt.GetPassphraseInput(message)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	confirmation, err := prompt.GetPassphraseInput(confirmMessage)
+	if err != nil {
+		return "", err
+	}
+	if confirmation != passphrase {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// reportWeakPassphrase tells how to choose a stronger passphrase when err rejects one
+func reportWeakPassphrase(logger domain.Logger, err error) {
+	var weak *model.WeakPassphraseError
+	if !errors.As(err, &weak) {
+		return
+	}
+	if weak.Breached {
+		logger.Info("Choose a passphrase you have not used anywhere else")
+	}
+	for _, suggestion := range weak.Strength.Suggestions {
+		logger.Info("%s", suggestion)
+	}
+}
+
 // getContext returns a context for command execution
 func getContext() context.Context {
-	return context.Background()
+	ctx := context.Background()
+	if rootCmd.PersistentFlags().Changed("lock-timeout") {
+		ctx = repository.WithLockTimeout(ctx, lockTimeout)
+	}
+	options := passphraseOptions
+	if rootCmd.PersistentFlags().Changed("cache-ttl") {
+		options.CacheTTL = &cacheTTL
+	}
+	return service.WithPassphraseOptions(ctx, options)
+}
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(
+		&lockTimeout,
+		"lock-timeout",
+		config.DefaultLockTimeout,
+		"How long to wait for another lockify process using the vault (0 fails immediately)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&passphraseOptions.File,
+		"passphrase-file",
+		"",
+		"Read the passphrase from this file; {env} is replaced by the environment name",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&passphraseOptions.FD,
+		"passphrase-fd",
+		-1,
+		"Read the passphrase from the first line of this file descriptor",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&passphraseOptions.Command,
+		"passphrase-cmd",
+		"",
+		"Read the passphrase from the output of this shell command; it gets $LOCKIFY_ENV",
+	)
+	rootCmd.PersistentFlags().StringSliceVar(
+		&passphraseOptions.Sources,
+		"passphrase-sources",
+		nil,
+		"Passphrase sources to try in order [env,file,fd,cmd,keyring,prompt]",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&cacheTTL,
+		"cache-ttl",
+		config.DefaultCacheTTL,
+		"How long a passphrase entered at the prompt stays cached (0 keeps it until cleared)",
+	)
 }
@@ -31,14 +31,23 @@ func NewRotateCommand(
 		Short: "Rotate the passphrase for a vault",
 		Long: `Rotate the passphrase for a vault.
 
-This command allows you to change the passphrase for a vault by re-encrypting all entries
-with a new passphrase. You will be prompted for the current passphrase and a new passphrase.`,
+This command allows you to change the passphrase for a vault by re-wrapping its data key
+with a new passphrase; entries are not re-encrypted. The key slot the current passphrase
+opens is the one that changes, so each slot holder rotates their own passphrase. You will
+be prompted for the current passphrase and, twice, for a new passphrase, which has to meet
+the passphrase strength policy of the environment.
+
+Use --reencrypt to also generate a new data key and re-encrypt all entries with it. The new
+key is wrapped for every recipient and for every other key slot, whose passphrases you are
+prompted for. To offboard a slot holder, remove their slot with "lockify slot remove" and
+then run "lockify rotate-key --reencrypt", so the key they may have kept opens nothing.`,
 		Example: `  lockify rotate-key --env prod
-  lockify rotate-key --env staging`,
+  lockify rotate-key --env staging --reencrypt`,
 		RunE: cmd.runE,
 	}
 
 	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().Bool("reencrypt", false, "Generate a new data key and re-encrypt all entries")
 	err := cobraCmd.MarkFlagRequired("env")
 	if err != nil {
 		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
@@ -53,20 +62,33 @@ func (c *RotateCommand) runE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	reencrypt, err := cmd.Flags().GetBool("reencrypt")
+	if err != nil {
+		return err
+	}
+
 	passphrase, err := c.prompt.GetPassphraseInput("Enter current passphrase:")
 	if err != nil {
 		return err
 	}
-	newPassphrase, err := c.prompt.GetPassphraseInput("Enter new passphrase:")
+	newPassphrase, err := promptNewPassphrase(
+		c.prompt,
+		"Enter new passphrase:",
+		"Confirm new passphrase:",
+	)
 	if err != nil {
 		return err
 	}
 
 	c.logger.Progress("Rotating passphrase for %s...\n", env)
 	ctx := getContext()
-	err = c.useCase.Execute(ctx, env, passphrase, newPassphrase)
+	slotPassphrase := func(slot string) (string, error) {
+		return c.prompt.GetPassphraseInput(fmt.Sprintf("Enter passphrase of key slot %q:", slot))
+	}
+	err = c.useCase.Execute(ctx, env, passphrase, newPassphrase, reencrypt, slotPassphrase)
 	if err != nil {
-		c.logger.Error("failed to rotate passphrase: %w", err)
+		c.logger.Error("failed to rotate passphrase: %v", err)
+		reportWeakPassphrase(c.logger, err)
 		return err
 	}
 
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/test"
 	"github.com/ahmed-abdelgawad92/lockify/test/assert"
 )
@@ -15,15 +17,21 @@ type mockRotateUseCase struct {
 	receivedEnv               string
 	receivedCurrentPassphrase string
 	receivedNewPassphrase     string
+	receivedReencrypt         bool
+	receivedSlotPassphrase    app.SlotPassphraseFunc
 }
 
 func (m *mockRotateUseCase) Execute(
 	ctx context.Context,
 	env, currentPassphrase, newPassphrase string,
+	reencrypt bool,
+	slotPassphrase app.SlotPassphraseFunc,
 ) error {
 	m.receivedEnv = env
 	m.receivedCurrentPassphrase = currentPassphrase
 	m.receivedNewPassphrase = newPassphrase
+	m.receivedReencrypt = reencrypt
+	m.receivedSlotPassphrase = slotPassphrase
 	if m.executeFunc != nil {
 		return m.executeFunc(ctx, env, currentPassphrase, newPassphrase)
 	}
@@ -56,10 +64,45 @@ func TestRotateCommand_Success(t *testing.T) {
 	assert.Equal(t, "test", mockUseCase.receivedEnv)
 	assert.Equal(t, "current_pass", mockUseCase.receivedCurrentPassphrase)
 	assert.Equal(t, "new_pass", mockUseCase.receivedNewPassphrase)
+	assert.False(t, mockUseCase.receivedReencrypt)
 	assert.Count(t, 1, mockLogger.ProgressLogs)
 	assert.Count(t, 1, mockLogger.SuccessLogs)
 }
 
+func TestRotateCommand_Reencrypt(t *testing.T) {
+	mockUseCase := &mockRotateUseCase{}
+	mockLogger := &test.MockLogger{}
+	mockPrompt := &test.MockPromptService{
+		GetPassphraseInputFunc: func(message string) (string, error) {
+			if message == `Enter passphrase of key slot "ci":` {
+				return "ci_pass", nil
+			}
+			return "new_pass", nil
+		},
+	}
+
+	cmd, _ := NewRotateCommand(mockUseCase, mockPrompt, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("reencrypt", "true"); err != nil {
+		t.Fatalf("failed to set reencrypt flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.True(t, mockUseCase.receivedReencrypt)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+
+	slotPassphrase, err := mockUseCase.receivedSlotPassphrase("ci")
+	assert.Nil(t, err)
+	assert.Equal(t, "ci_pass", slotPassphrase)
+}
+
 func TestRotateCommand_Error_Required_Env(t *testing.T) {
 	mockUseCase := &mockRotateUseCase{}
 	mockLogger := &test.MockLogger{}
@@ -126,3 +169,64 @@ func TestRotateCommand_UseCaseError(t *testing.T) {
 	assert.Count(t, 1, mockLogger.ProgressLogs)
 	assert.Count(t, 0, mockLogger.SuccessLogs)
 }
+
+func TestRotateCommand_Error_PassphrasesDoNotMatch(t *testing.T) {
+	mockUseCase := &mockRotateUseCase{}
+	mockLogger := &test.MockLogger{}
+	mockPrompt := &test.MockPromptService{
+		GetPassphraseInputFunc: func(message string) (string, error) {
+			switch message {
+			case "Enter current passphrase:":
+				return "current_pass", nil
+			case "Enter new passphrase:":
+				return "new_pass", nil
+			}
+			return "new_pas", nil
+		},
+	}
+
+	cmd, _ := NewRotateCommand(mockUseCase, mockPrompt, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "passphrases do not match", err.Error())
+	assert.Equal(t, "", mockUseCase.receivedEnv)
+}
+
+func TestRotateCommand_WeakPassphraseSuggestions(t *testing.T) {
+	mockUseCase := &mockRotateUseCase{
+		executeFunc: func(ctx context.Context, env, currentPassphrase, newPassphrase string) error {
+			return &model.WeakPassphraseError{
+				Env:      env,
+				MinScore: 3,
+				Strength: model.PassphraseStrength{
+					Score:       1,
+					Suggestions: []string{"Add another word or two"},
+				},
+			}
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	mockPrompt := &test.MockPromptService{}
+
+	cmd, _ := NewRotateCommand(mockUseCase, mockPrompt, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "too weak", err.Error())
+	assert.DeepEqual(t, []string{"Add another word or two"}, mockLogger.InfoLogs)
+}
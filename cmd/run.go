@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// RunCommand represents the run command for running a program with vault entries.
+type RunCommand struct {
+	useCase app.RunCommandUc
+	logger  domain.Logger
+}
+
+// NewRunCommand creates a new run command instance.
+func NewRunCommand(useCase app.RunCommandUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &RunCommand{useCase, logger}
+	// lockify run --env [env] -- [command] [args...]
+	cobraCmd := &cobra.Command{
+		Use:   "run -- <command> [args...]",
+		Short: "Run a command with the vault entries as environment variables",
+		Long: `Run a command with the vault entries as environment variables.
+
+This command decrypts the entries of the vault and passes them to the command as
+environment variables, so secrets never have to be written to a .env file.
+Signals received by lockify are forwarded to the command, and lockify exits with
+the exit code of the command. lockify's own credential variables, such as
+LOCKIFY_PASSPHRASE and LOCKIFY_PASSPHRASE_<ENV>, are not passed on to the command.`,
+		Example: `  lockify run --env prod -- ./server --port 8080
+  lockify run --env prod --only DATABASE_URL,API_KEY -- make migrate
+  lockify run --env staging --prefix APP_ --no-override -- npm start`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: cmd.runE,
+	}
+
+	// Flags after the command belong to the command, not to lockify.
+	cobraCmd.Flags().SetInterspersed(false)
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().StringSlice("only", nil, "Only inject these keys (comma separated)")
+	cobraCmd.Flags().String("prefix", "", "Prefix added to the name of every injected variable")
+	cobraCmd.Flags().Bool("no-override", false, "Keep environment variables that are already set")
+	err := cobraCmd.MarkFlagRequired("env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *RunCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	only, err := cmd.Flags().GetStringSlice("only")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve only flag: %w", err)
+	}
+	prefix, err := cmd.Flags().GetString("prefix")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve prefix flag: %w", err)
+	}
+	noOverride, err := cmd.Flags().GetBool("no-override")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve no-override flag: %w", err)
+	}
+
+	ctx := getContext()
+	code, err := c.useCase.Execute(ctx, app.RunCommandDTO{
+		Env:        env,
+		Command:    args,
+		Environ:    os.Environ(),
+		Only:       only,
+		Prefix:     prefix,
+		NoOverride: noOverride,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run command with environment %s: %w", env, err)
+	}
+
+	if code != 0 {
+		// The command has reported its own failure; lockify only passes on the code.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &ExitError{Code: code}
+	}
+
+	return nil
+}
+
+func init() {
+	runCmd, err := NewRunCommand(di.BuildRunCommand(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockRunUseCase struct {
+	executeFunc func(ctx context.Context, dto app.RunCommandDTO) (int, error)
+	receivedDTO app.RunCommandDTO
+}
+
+func (m *mockRunUseCase) Execute(ctx context.Context, dto app.RunCommandDTO) (int, error) {
+	m.receivedDTO = dto
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, dto)
+	}
+	return 0, nil
+}
+
+func TestRunCommand_Success(t *testing.T) {
+	mockUseCase := &mockRunUseCase{}
+	cmd, _ := NewRunCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("only", "DB_URL,API_KEY"); err != nil {
+		t.Fatalf("failed to set only flag: %v", err)
+	}
+	if err := cmd.Flags().Set("prefix", "APP_"); err != nil {
+		t.Fatalf("failed to set prefix flag: %v", err)
+	}
+	if err := cmd.Flags().Set("no-override", "true"); err != nil {
+		t.Fatalf("failed to set no-override flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"./server", "--port", "8080"})
+	assert.Nil(t, err)
+	assert.Equal(t, "test", mockUseCase.receivedDTO.Env)
+	assert.DeepEqual(t, []string{"./server", "--port", "8080"}, mockUseCase.receivedDTO.Command)
+	assert.DeepEqual(t, []string{"DB_URL", "API_KEY"}, mockUseCase.receivedDTO.Only)
+	assert.Equal(t, "APP_", mockUseCase.receivedDTO.Prefix)
+	assert.True(t, mockUseCase.receivedDTO.NoOverride)
+}
+
+func TestRunCommand_PassesOnExitCode(t *testing.T) {
+	mockUseCase := &mockRunUseCase{
+		executeFunc: func(ctx context.Context, dto app.RunCommandDTO) (int, error) {
+			return 42, nil
+		},
+	}
+	cmd, _ := NewRunCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"false"})
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), fmt.Sprintf("RunE() error = %v, want ExitError", err))
+	assert.Equal(t, 42, exitErr.Code)
+	assert.True(t, cmd.SilenceErrors, "RunE() should not report the exit code as an error")
+}
+
+func TestRunCommand_FlagsAfterCommandBelongToIt(t *testing.T) {
+	mockUseCase := &mockRunUseCase{}
+	cmd, _ := NewRunCommand(mockUseCase, &test.MockLogger{})
+	cmd.SetArgs([]string{"--env", "test", "ls", "-la", "--env", "other"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "test", mockUseCase.receivedDTO.Env)
+	assert.DeepEqual(t, []string{"ls", "-la", "--env", "other"}, mockUseCase.receivedDTO.Command)
+}
+
+func TestRunCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockRunUseCase{
+		executeFunc: func(ctx context.Context, dto app.RunCommandDTO) (int, error) {
+			return 0, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	cmd, _ := NewRunCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"env"})
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+}
+
+func TestRunCommand_EmptyEnv(t *testing.T) {
+	cmd, _ := NewRunCommand(&mockRunUseCase{}, &test.MockLogger{})
+
+	err := cmd.RunE(cmd, []string{"env"})
+	assert.NotNil(t, err)
+	assert.Equal(t, errMsgEmptyEnv, err.Error())
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// SetCommand represents the set command for setting entries without prompts.
+type SetCommand struct {
+	useCase app.SetEntriesUc
+	logger  domain.Logger
+}
+
+// NewSetCommand creates a new set command instance.
+func NewSetCommand(useCase app.SetEntriesUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &SetCommand{useCase, logger}
+
+	// lockify set --env [env] KEY=VALUE [KEY=@file] [KEY=-]
+	cobraCmd := &cobra.Command{
+		Use:   "set KEY=VALUE [KEY=VALUE...]",
+		Short: "Set one or more entries without prompting",
+		Long: `Set one or more entries without prompting.
+
+Each argument sets one key. A value of the form @path is read from that file as is,
+and a value of - is read from stdin without its trailing newline. All entries are
+stored together in a single save: if any of them fails, none is stored.`,
+		Example: `  lockify set --env prod DATABASE_URL=postgres://db LOG_LEVEL=info
+  lockify set --env prod --secret TLS_CERT=@./cert.pem
+  echo "$API_KEY" | lockify set --env ci --secret API_KEY=-
+  lockify set --env dev --if-absent PORT=8080`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	cobraCmd.Flags().BoolP("secret", "s", false, "Mark the values as secrets")
+	cobraCmd.Flags().Bool("if-absent", false, "Skip keys that already exist")
+	err := cobraCmd.MarkFlagRequired("env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *SetCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	secret, err := cmd.Flags().GetBool("secret")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve secret flag: %w", err)
+	}
+	ifAbsent, err := cmd.Flags().GetBool("if-absent")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve if-absent flag: %w", err)
+	}
+
+	entries, err := parseAssignments(args, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	ctx := getContext()
+	result, err := c.useCase.Execute(ctx, app.SetEntriesDTO{
+		Env:      env,
+		Entries:  entries,
+		Secret:   secret,
+		IfAbsent: ifAbsent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set entries in environment %s: %w", env, err)
+	}
+
+	for _, key := range result.Skipped {
+		c.logger.Warning("Skipping existing key %q", key)
+	}
+	c.logger.Success("Set %d key(s), skipped %d key(s)", len(result.Set), len(result.Skipped))
+
+	return nil
+}
+
+// parseAssignments resolves KEY=VALUE arguments, reading @path values from files and -
+// values from stdin
+func parseAssignments(args []string, stdin io.Reader) (map[string]string, error) {
+	entries := make(map[string]string, len(args))
+	stdinUsed := false
+
+	for _, arg := range args {
+		key, raw, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid argument %q: expected KEY=VALUE", arg)
+		}
+		if _, exists := entries[key]; exists {
+			return nil, fmt.Errorf("key %q is given more than once", key)
+		}
+
+		switch {
+		case raw == "-":
+			if stdinUsed {
+				return nil, errors.New("only one value can be read from stdin")
+			}
+			stdinUsed = true
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read value of key %q from stdin: %w", key, err)
+			}
+			value := strings.TrimSuffix(string(data), "\n")
+			entries[key] = strings.TrimSuffix(value, "\r")
+		case strings.HasPrefix(raw, "@"):
+			data, err := os.ReadFile(raw[1:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to read value of key %q: %w", key, err)
+			}
+			entries[key] = string(data)
+		default:
+			entries[key] = raw
+		}
+	}
+
+	return entries, nil
+}
+
+func init() {
+	setCmd, err := NewSetCommand(di.BuildSetEntries(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(setCmd)
+}
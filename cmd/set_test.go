@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockSetUseCase struct {
+	executeFunc func(ctx context.Context, dto app.SetEntriesDTO) (app.SetEntriesResult, error)
+	receivedDTO app.SetEntriesDTO
+}
+
+func (m *mockSetUseCase) Execute(
+	ctx context.Context,
+	dto app.SetEntriesDTO,
+) (app.SetEntriesResult, error) {
+	m.receivedDTO = dto
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, dto)
+	}
+	return app.SetEntriesResult{}, nil
+}
+
+func TestSetCommand_Success(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(certPath, []byte("-----CERT-----\n"), 0o600); err != nil {
+		t.Fatalf("failed to write cert file: %v", err)
+	}
+
+	mockUseCase := &mockSetUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd, _ := NewSetCommand(mockUseCase, mockLogger)
+	cmd.SetIn(strings.NewReader("from-stdin\n"))
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("secret", "true"); err != nil {
+		t.Fatalf("failed to set secret flag: %v", err)
+	}
+	if err := cmd.Flags().Set("if-absent", "true"); err != nil {
+		t.Fatalf("failed to set if-absent flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"KEY1=a=b", "KEY2=@" + certPath, "KEY3=-", "KEY4="})
+	assert.Nil(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"KEY1": "a=b",
+		"KEY2": "-----CERT-----\n",
+		"KEY3": "from-stdin",
+		"KEY4": "",
+	}, mockUseCase.receivedDTO.Entries)
+	assert.True(t, mockUseCase.receivedDTO.Secret)
+	assert.True(t, mockUseCase.receivedDTO.IfAbsent)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestSetCommand_InvalidArguments(t *testing.T) {
+	tests := map[string][]string{
+		"missing equals": {"KEY"},
+		"empty key":      {"=value"},
+		"duplicate key":  {"KEY=1", "KEY=2"},
+		"stdin twice":    {"A=-", "B=-"},
+		"missing file":   {"KEY=@/does/not/exist"},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockUseCase := &mockSetUseCase{
+				executeFunc: func(
+					ctx context.Context,
+					dto app.SetEntriesDTO,
+				) (app.SetEntriesResult, error) {
+					t.Error("Execute() should not be called with invalid arguments")
+					return app.SetEntriesResult{}, nil
+				},
+			}
+			cmd, _ := NewSetCommand(mockUseCase, &test.MockLogger{})
+			cmd.SetIn(strings.NewReader(""))
+			if err := cmd.Flags().Set("env", "test"); err != nil {
+				t.Fatalf("failed to set env flag: %v", err)
+			}
+
+			err := cmd.RunE(cmd, args)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestSetCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockSetUseCase{
+		executeFunc: func(
+			ctx context.Context,
+			dto app.SetEntriesDTO,
+		) (app.SetEntriesResult, error) {
+			return app.SetEntriesResult{}, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	cmd, _ := NewSetCommand(mockUseCase, &test.MockLogger{})
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, []string{"KEY=value"})
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+}
+
+func TestSetCommand_EmptyEnv(t *testing.T) {
+	cmd, _ := NewSetCommand(&mockSetUseCase{}, &test.MockLogger{})
+
+	err := cmd.RunE(cmd, []string{"KEY=value"})
+	assert.NotNil(t, err)
+	assert.Equal(t, errMsgEmptyEnv, err.Error())
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/spf13/cobra"
+)
+
+// SlotCommand represents the slot command for managing the key slots of a vault.
+type SlotCommand struct {
+	addUseCase    app.AddSlotUc
+	removeUseCase app.RemoveSlotUc
+	listUseCase   app.ListSlotsUc
+	prompt        service.PromptService
+	logger        domain.Logger
+}
+
+// NewSlotCommand creates a new slot command instance with its add, remove and list subcommands.
+func NewSlotCommand(
+	addUseCase app.AddSlotUc,
+	removeUseCase app.RemoveSlotUc,
+	listUseCase app.ListSlotsUc,
+	prompt service.PromptService,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &SlotCommand{addUseCase, removeUseCase, listUseCase, prompt, logger}
+
+	// lockify slot [add|remove|list] --env [env]
+	cobraCmd := &cobra.Command{
+		Use:   "slot",
+		Short: "Manage the key slots of a vault",
+		Long: `Manage the key slots of a vault.
+
+Each key slot lets its own passphrase unlock the vault, so team members and CI pipelines
+do not have to share one secret. The "default" slot is created with the vault and changed
+with rotate-key; other slots can be added and removed at any time.`,
+		Example: `  lockify slot add --env prod --name ci
+  lockify slot list --env prod
+  lockify slot remove --env prod --name alice`,
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a key slot with its own passphrase",
+		Long: `Add a key slot with its own passphrase.
+
+The vault is unlocked with any existing passphrase, then you will be prompted twice for the
+passphrase of the new slot, which has to meet the passphrase strength policy of the
+environment.`,
+		Example: `  lockify slot add --env prod --name ci`,
+		RunE:    cmd.runAdd,
+	}
+	removeCmd := &cobra.Command{
+		Use:     "remove",
+		Short:   "Remove a key slot so its passphrase no longer unlocks the vault",
+		Example: `  lockify slot remove --env prod --name alice`,
+		RunE:    cmd.runRemove,
+	}
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the key slots of a vault",
+		Example: `  lockify slot list --env prod`,
+		RunE:    cmd.runList,
+	}
+
+	for _, subCmd := range []*cobra.Command{addCmd, removeCmd, listCmd} {
+		subCmd.Flags().StringP("env", "e", "", "Environment Name")
+		if err := subCmd.MarkFlagRequired("env"); err != nil {
+			return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+		}
+		cobraCmd.AddCommand(subCmd)
+	}
+	for _, subCmd := range []*cobra.Command{addCmd, removeCmd} {
+		subCmd.Flags().StringP("name", "n", "", "Key slot name")
+		if err := subCmd.MarkFlagRequired("name"); err != nil {
+			return nil, fmt.Errorf("failed to mark name flag as required: %w", err)
+		}
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *SlotCommand) runAdd(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	name, err := requireStringFlag(cmd, "name")
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := promptNewPassphrase(
+		c.prompt,
+		fmt.Sprintf("Enter passphrase for slot %q:", name),
+		fmt.Sprintf("Confirm passphrase for slot %q:", name),
+	)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Progress("Adding key slot %s to %s...\n", name, env)
+	if err := c.addUseCase.Execute(getContext(), env, name, passphrase); err != nil {
+		c.logger.Error("failed to add key slot: %v", err)
+		reportWeakPassphrase(c.logger, err)
+		return err
+	}
+
+	c.logger.Success("Key slot %q added to %s", name, env)
+	return nil
+}
+
+func (c *SlotCommand) runRemove(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+	name, err := requireStringFlag(cmd, "name")
+	if err != nil {
+		return err
+	}
+
+	c.logger.Progress("Removing key slot %s from %s...\n", name, env)
+	if err := c.removeUseCase.Execute(getContext(), env, name); err != nil {
+		c.logger.Error("failed to remove key slot: %v", err)
+		return err
+	}
+
+	c.logger.Success("Key slot %q removed from %s", name, env)
+	return nil
+}
+
+func (c *SlotCommand) runList(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	slots, err := c.listUseCase.Execute(getContext(), env)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Success("Found %d key slot(s):", len(slots))
+	for _, slot := range slots {
+		line := "  - " + slot.Name
+		if slot.CreatedAt != "" {
+			line += " (added " + slot.CreatedAt + ")"
+		}
+		if slot.Unlocked {
+			line += " [unlocked]"
+		}
+		c.logger.Output("%s", line)
+	}
+
+	return nil
+}
+
+func init() {
+	slotCmd, err := NewSlotCommand(
+		di.BuildAddSlot(),
+		di.BuildRemoveSlot(),
+		di.BuildListSlots(),
+		di.BuildPromptService(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(slotCmd)
+}
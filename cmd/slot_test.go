@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockAddSlotUseCase struct {
+	executeFunc        func(ctx context.Context, env, name, passphrase string) error
+	receivedEnv        string
+	receivedName       string
+	receivedPassphrase string
+}
+
+func (m *mockAddSlotUseCase) Execute(ctx context.Context, env, name, passphrase string) error {
+	m.receivedEnv = env
+	m.receivedName = name
+	m.receivedPassphrase = passphrase
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, name, passphrase)
+	}
+	return nil
+}
+
+type mockRemoveSlotUseCase struct {
+	executeFunc  func(ctx context.Context, env, name string) error
+	receivedEnv  string
+	receivedName string
+}
+
+func (m *mockRemoveSlotUseCase) Execute(ctx context.Context, env, name string) error {
+	m.receivedEnv = env
+	m.receivedName = name
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, name)
+	}
+	return nil
+}
+
+type mockListSlotsUseCase struct {
+	executeFunc func(ctx context.Context, env string) ([]app.SlotInfo, error)
+}
+
+func (m *mockListSlotsUseCase) Execute(ctx context.Context, env string) ([]app.SlotInfo, error) {
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env)
+	}
+	return []app.SlotInfo{
+		{Name: "default", Unlocked: true},
+		{Name: "ci", CreatedAt: "2026-01-02T03:04:05Z"},
+	}, nil
+}
+
+func newTestSlotSubcommand(
+	t *testing.T,
+	name string,
+	addUseCase app.AddSlotUc,
+	removeUseCase app.RemoveSlotUc,
+	listUseCase app.ListSlotsUc,
+	logger *test.MockLogger,
+) *cobra.Command {
+	t.Helper()
+	slotCmd, err := NewSlotCommand(
+		addUseCase,
+		removeUseCase,
+		listUseCase,
+		&test.MockPromptService{},
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewSlotCommand() returned unexpected error: %v", err)
+	}
+	subCmd, _, err := slotCmd.Find([]string{name})
+	if err != nil {
+		t.Fatalf("failed to find slot %s command: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	subCmd.SetOut(&buf)
+	subCmd.SetErr(&buf)
+	return subCmd
+}
+
+func TestSlotAddCommand_Success(t *testing.T) {
+	mockUseCase := &mockAddSlotUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestSlotSubcommand(
+		t,
+		"add",
+		mockUseCase,
+		&mockRemoveSlotUseCase{},
+		&mockListSlotsUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("name", "ci"); err != nil {
+		t.Fatalf("failed to set name flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedEnv)
+	assert.Equal(t, "ci", mockUseCase.receivedName)
+	assert.Equal(t, "test_passphrase", mockUseCase.receivedPassphrase)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestSlotAddCommand_Error_Required_Name(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestSlotSubcommand(
+		t,
+		"add",
+		&mockAddSlotUseCase{},
+		&mockRemoveSlotUseCase{},
+		&mockListSlotsUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "name flag is required", err.Error())
+}
+
+func TestSlotAddCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockAddSlotUseCase{
+		executeFunc: func(ctx context.Context, env, name, passphrase string) error {
+			return fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestSlotSubcommand(
+		t,
+		"add",
+		mockUseCase,
+		&mockRemoveSlotUseCase{},
+		&mockListSlotsUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("name", "ci"); err != nil {
+		t.Fatalf("failed to set name flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestSlotRemoveCommand_Success(t *testing.T) {
+	mockUseCase := &mockRemoveSlotUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestSlotSubcommand(
+		t,
+		"remove",
+		&mockAddSlotUseCase{},
+		mockUseCase,
+		&mockListSlotsUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+	if err := cmd.Flags().Set("name", "alice"); err != nil {
+		t.Fatalf("failed to set name flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", mockUseCase.receivedEnv)
+	assert.Equal(t, "alice", mockUseCase.receivedName)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestSlotListCommand_Success(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestSlotSubcommand(
+		t,
+		"list",
+		&mockAddSlotUseCase{},
+		&mockRemoveSlotUseCase{},
+		&mockListSlotsUseCase{},
+		mockLogger,
+	)
+	if err := cmd.Flags().Set("env", "prod"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 2, mockLogger.OutputLogs)
+	assert.Contains(t, "  - default [unlocked]", mockLogger.OutputLogs)
+	assert.Contains(t, "  - ci (added 2026-01-02T03:04:05Z)", mockLogger.OutputLogs)
+}
+
+func TestSlotListCommand_Error_Required_Env(t *testing.T) {
+	cmd := newTestSlotSubcommand(
+		t,
+		"list",
+		&mockAddSlotUseCase{},
+		&mockRemoveSlotUseCase{},
+		&mockListSlotsUseCase{},
+		&test.MockLogger{},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/spf13/cobra"
+)
+
+// StaleCommand represents the stale command for reporting entries due for rotation.
+type StaleCommand struct {
+	listUseCase   app.ListStaleUc
+	policyUseCase app.SetRotationPolicyUc
+	logger        domain.Logger
+	now           func() time.Time
+}
+
+// staleOutput is the JSON form of a stale report.
+type staleOutput struct {
+	Env       string           `json:"env"`
+	CheckedAt string           `json:"checked_at"`
+	Expired   int              `json:"expired"`
+	Expiring  int              `json:"expiring"`
+	Entries   []staleEntryJSON `json:"entries"`
+}
+
+// staleEntryJSON is an entry of a stale report; DaysLeft is negative once it is overdue.
+type staleEntryJSON struct {
+	Key        string `json:"key"`
+	Status     string `json:"status"`
+	DueAt      string `json:"due_at"`
+	DaysLeft   int    `json:"days_left"`
+	MaxAgeDays int    `json:"max_age_days,omitempty"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// NewStaleCommand creates a new stale command instance.
+func NewStaleCommand(
+	listUseCase app.ListStaleUc,
+	policyUseCase app.SetRotationPolicyUc,
+	logger domain.Logger,
+) (*cobra.Command, error) {
+	cmd := &StaleCommand{listUseCase, policyUseCase, logger, time.Now}
+
+	// lockify stale --env [env]
+	cobraCmd := &cobra.Command{
+		Use:   "stale",
+		Short: "Report the keys that are overdue or soon due for rotation",
+		Long: `Report the keys that are overdue or soon due for rotation.
+
+A key is due for rotation when its value is older than its max age, or when its expiry
+has passed. The max age is set for the whole vault with --max-age-days, for the keys
+carrying a tag with --max-age-days and --tag, or for a single key with lockify meta set
+--max-age-days; the most specific one applies. Keys due within 14 days, or the window set
+with --warn-days, are reported as expiring.
+
+stale exits with status 1 when any key is overdue, so it can fail a CI pipeline, and with
+status 2 when it fails itself. lockify get, export and run warn whenever they read an overdue key.`,
+		Example: `  lockify stale --env prod
+  lockify stale --env prod --within 30 --output json
+  lockify stale --env prod --max-age-days 90
+  lockify stale --env prod --max-age-days 30 --tag payments`,
+		Args: cobra.NoArgs,
+		RunE: cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment name")
+	cobraCmd.Flags().Int("within", 0,
+		"Report keys due within this many days (0 uses the warning window of the vault)")
+	cobraCmd.Flags().Bool("all", false, "Also report keys that are not due soon")
+	cobraCmd.Flags().String("output", "text", "The output format [text|json]")
+	cobraCmd.Flags().Int("max-age-days", 0,
+		"Set after how many days values have to be rotated (0 removes the limit)")
+	cobraCmd.Flags().String("tag", "", "Apply --max-age-days to the keys with this tag only")
+	cobraCmd.Flags().Int("warn-days", model.DefaultRotationWarnDays,
+		"Set how many days before they are due keys are reported")
+	// --env is required, but checked by runE so that its absence exits with status 2.
+	exitWithFailureCode(cobraCmd)
+
+	return cobraCmd, nil
+}
+
+func (c *StaleCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("tag") && !cmd.Flags().Changed("max-age-days") {
+		return errors.New("the tag flag requires the max-age-days flag")
+	}
+	if cmd.Flags().Changed("max-age-days") || cmd.Flags().Changed("warn-days") {
+		return c.setPolicy(cmd, env)
+	}
+
+	within, err := cmd.Flags().GetInt("within")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve within flag: %w", err)
+	}
+	if within < 0 {
+		return fmt.Errorf("within cannot be negative, got %d days", within)
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve all flag: %w", err)
+	}
+	output, err := requireStringFlag(cmd, "output")
+	if err != nil {
+		return err
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be text or json", output)
+	}
+
+	options := app.StaleOptions{WithinDays: within, All: all}
+	report, err := c.listUseCase.Execute(getContext(), env, options)
+	if err != nil {
+		return fmt.Errorf("failed to check %s for stale keys: %w", env, err)
+	}
+
+	now := c.now()
+	if output == "json" {
+		if err := c.writeJSON(env, report, now); err != nil {
+			return err
+		}
+	} else {
+		c.writeText(env, report, now)
+	}
+
+	if report.Expired > 0 {
+		// The overdue keys are the result; the exit code lets CI fail on them.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &ExitError{Code: exitCodeFindings}
+	}
+
+	return nil
+}
+
+func (c *StaleCommand) writeText(env string, report app.StaleReport, now time.Time) {
+	if len(report.Entries) > 0 {
+		rows := [][]string{{"KEY", "STATUS", "DUE", "DAYS LEFT", "MAX AGE", "UPDATED"}}
+		for _, entry := range report.Entries {
+			maxAge := "-"
+			if entry.MaxAgeDays > 0 {
+				maxAge = strconv.Itoa(entry.MaxAgeDays) + "d"
+			}
+			rows = append(rows, []string{
+				entry.Key,
+				string(entry.Status),
+				entry.DueAt,
+				strconv.Itoa(daysLeft(entry.DueAt, now)),
+				maxAge,
+				orDash(entry.UpdatedAt),
+			})
+		}
+		for _, line := range formatTable(rows) {
+			c.logger.Output("%s", line)
+		}
+	}
+
+	switch {
+	case report.Expired > 0:
+		c.logger.Error("%d key(s) of %s are overdue for rotation", report.Expired, env)
+	case report.Expiring > 0:
+		c.logger.Warning("%d key(s) of %s are due for rotation soon", report.Expiring, env)
+	default:
+		c.logger.Success("No keys of %s are due for rotation soon", env)
+	}
+}
+
+func (c *StaleCommand) writeJSON(env string, report app.StaleReport, now time.Time) error {
+	entries := make([]staleEntryJSON, 0, len(report.Entries))
+	for _, entry := range report.Entries {
+		entries = append(entries, staleEntryJSON{
+			Key:        entry.Key,
+			Status:     string(entry.Status),
+			DueAt:      entry.DueAt,
+			DaysLeft:   daysLeft(entry.DueAt, now),
+			MaxAgeDays: entry.MaxAgeDays,
+			UpdatedAt:  entry.UpdatedAt,
+		})
+	}
+
+	data, err := json.MarshalIndent(staleOutput{
+		Env:       env,
+		CheckedAt: now.UTC().Format(time.RFC3339),
+		Expired:   report.Expired,
+		Expiring:  report.Expiring,
+		Entries:   entries,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stale report: %w", err)
+	}
+
+	c.logger.Output("%s", data)
+	return nil
+}
+
+func (c *StaleCommand) setPolicy(cmd *cobra.Command, env string) error {
+	tag, err := cmd.Flags().GetString("tag")
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tag flag: %w", err)
+	}
+
+	dto := app.SetRotationPolicyDTO{Env: env, Tag: tag}
+	if cmd.Flags().Changed("max-age-days") {
+		maxAgeDays, err := cmd.Flags().GetInt("max-age-days")
+		if err != nil {
+			return fmt.Errorf("failed to retrieve max-age-days flag: %w", err)
+		}
+		dto.MaxAgeDays = &maxAgeDays
+	}
+	if cmd.Flags().Changed("warn-days") {
+		warnDays, err := cmd.Flags().GetInt("warn-days")
+		if err != nil {
+			return fmt.Errorf("failed to retrieve warn-days flag: %w", err)
+		}
+		dto.WarnDays = &warnDays
+	}
+
+	if err := c.policyUseCase.Execute(getContext(), dto); err != nil {
+		return fmt.Errorf("failed to set rotation policy: %w", err)
+	}
+
+	keys := "Keys of " + env
+	if tag != "" {
+		keys += " tagged " + tag
+	}
+	switch {
+	case dto.MaxAgeDays == nil:
+	case *dto.MaxAgeDays > 0:
+		c.logger.Success("%s have to be rotated every %d day(s)", keys, *dto.MaxAgeDays)
+	default:
+		c.logger.Success("%s no longer have a max age", keys)
+	}
+	if dto.WarnDays != nil {
+		c.logger.Success("Keys of %s are reported %d day(s) before they are due", env,
+			model.RotationPolicy{WarnDays: *dto.WarnDays}.Warn())
+	}
+	return nil
+}
+
+// daysLeft returns the whole days from now until due, negative once it has passed
+func daysLeft(due string, now time.Time) int {
+	dueAt, err := time.Parse(time.RFC3339, due)
+	if err != nil {
+		return 0
+	}
+	return int(math.Floor(dueAt.Sub(now).Hours() / 24))
+}
+
+func init() {
+	staleCmd, err := NewStaleCommand(
+		di.BuildListStale(),
+		di.BuildSetRotationPolicy(),
+		di.GetLogger(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(staleCmd)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+	"github.com/spf13/cobra"
+)
+
+type mockListStaleUseCase struct {
+	report          app.StaleReport
+	err             error
+	receivedOptions *app.StaleOptions
+}
+
+func (m *mockListStaleUseCase) Execute(
+	ctx context.Context,
+	env string,
+	options app.StaleOptions,
+) (app.StaleReport, error) {
+	m.receivedOptions = &options
+	return m.report, m.err
+}
+
+type mockSetRotationPolicyUseCase struct {
+	receivedDTO *app.SetRotationPolicyDTO
+}
+
+func (m *mockSetRotationPolicyUseCase) Execute(
+	ctx context.Context,
+	dto app.SetRotationPolicyDTO,
+) error {
+	m.receivedDTO = &dto
+	return nil
+}
+
+var staleReportTest = app.StaleReport{
+	Entries: []model.EntryRotation{
+		{
+			Key:        "STRIPE_KEY",
+			UpdatedAt:  "2026-01-01T00:00:00Z",
+			MaxAgeDays: 30,
+			DueAt:      "2026-01-31T00:00:00Z",
+			Status:     model.RotationExpired,
+		},
+		{
+			Key:        "DB_URL",
+			UpdatedAt:  "2026-01-01T00:00:00Z",
+			MaxAgeDays: 90,
+			DueAt:      "2026-04-01T00:00:00Z",
+			Status:     model.RotationExpiring,
+		},
+	},
+	Expired:  1,
+	Expiring: 1,
+}
+
+func newTestStaleCommand(
+	t *testing.T,
+	listUseCase app.ListStaleUc,
+	policyUseCase app.SetRotationPolicyUc,
+	logger *test.MockLogger,
+	flags map[string]string,
+) *cobra.Command {
+	t.Helper()
+	cmd, err := NewStaleCommand(listUseCase, policyUseCase, logger)
+	if err != nil {
+		t.Fatalf("NewStaleCommand() returned unexpected error: %v", err)
+	}
+	for name, flagValue := range flags {
+		if err := cmd.Flags().Set(name, flagValue); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	return cmd
+}
+
+func TestStaleCommand_Overdue(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{report: staleReportTest}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestStaleCommand(
+		t,
+		listUseCase,
+		&mockSetRotationPolicyUseCase{},
+		mockLogger,
+		map[string]string{"env": "prod", "within": "30"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Equal(t, exitCodeFindings, exitErr.Code)
+	assert.Equal(t, 30, listUseCase.receivedOptions.WithinDays)
+	assert.Count(t, 3, mockLogger.OutputLogs)
+	assert.Contains(t, "STRIPE_KEY  expired   2026-01-31T00:00:00Z", mockLogger.OutputLogs[1])
+	assert.Contains(t, "DB_URL      expiring  2026-04-01T00:00:00Z", mockLogger.OutputLogs[2])
+	assert.Count(t, 1, mockLogger.ErrorLogs)
+}
+
+func TestStaleCommand_JSON(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{report: staleReportTest}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestStaleCommand(
+		t,
+		listUseCase,
+		&mockSetRotationPolicyUseCase{},
+		mockLogger,
+		map[string]string{"env": "prod", "output": "json"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Count(t, 1, mockLogger.OutputLogs)
+
+	var output staleOutput
+	err = json.Unmarshal([]byte(mockLogger.OutputLogs[0]), &output)
+	assert.Nil(t, err, fmt.Sprintf("stale should print valid JSON: %v", err))
+	assert.Equal(t, "prod", output.Env)
+	assert.Equal(t, 1, output.Expired)
+	assert.Count(t, 2, output.Entries)
+	assert.Equal(t, "STRIPE_KEY", output.Entries[0].Key)
+	assert.Equal(t, "expired", output.Entries[0].Status)
+	assert.True(t, output.Entries[0].DaysLeft < 0, "an overdue key should have no days left")
+}
+
+func TestStaleCommand_NothingDue(t *testing.T) {
+	mockLogger := &test.MockLogger{}
+	cmd := newTestStaleCommand(
+		t,
+		&mockListStaleUseCase{},
+		&mockSetRotationPolicyUseCase{},
+		mockLogger,
+		map[string]string{"env": "prod"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("RunE() returned unexpected error: %v", err))
+	assert.Count(t, 0, mockLogger.OutputLogs)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestStaleCommand_SetPolicy(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{}
+	policyUseCase := &mockSetRotationPolicyUseCase{}
+	mockLogger := &test.MockLogger{}
+	cmd := newTestStaleCommand(t, listUseCase, policyUseCase, mockLogger, map[string]string{
+		"env":          "prod",
+		"max-age-days": "30",
+		"tag":          "payments",
+	})
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err, fmt.Sprintf("RunE() returned unexpected error: %v", err))
+	assert.Nil(t, listUseCase.receivedOptions, "setting a policy should not list stale keys")
+	dto := policyUseCase.receivedDTO
+	assert.NotNil(t, dto, "the policy use case should be called")
+	assert.Equal(t, "prod", dto.Env)
+	assert.Equal(t, "payments", dto.Tag)
+	assert.Equal(t, 30, *dto.MaxAgeDays)
+	assert.Nil(t, dto.WarnDays, "unchanged fields should not be sent")
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+}
+
+func TestStaleCommand_InvalidOutput(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{}
+	cmd := newTestStaleCommand(
+		t,
+		listUseCase,
+		&mockSetRotationPolicyUseCase{},
+		&test.MockLogger{},
+		map[string]string{"env": "prod", "output": "yaml"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "RunE() with an invalid output expected error, got nil")
+	assert.Nil(t, listUseCase.receivedOptions, "the use case should not be called")
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Equal(t, exitCodeFailure, exitErr.Code)
+}
+
+func TestStaleCommand_UseCaseError(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{err: errors.New(errMsgExecuteFailed)}
+	cmd := newTestStaleCommand(
+		t,
+		listUseCase,
+		&mockSetRotationPolicyUseCase{},
+		&test.MockLogger{},
+		map[string]string{"env": "prod"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "RunE() with a use case error expected error, got nil")
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "RunE() should return an ExitError")
+	assert.Equal(t, exitCodeFailure, exitErr.Code)
+}
+
+func TestStaleCommand_MissingEnv(t *testing.T) {
+	cmd := newTestStaleCommand(
+		t,
+		&mockListStaleUseCase{},
+		&mockSetRotationPolicyUseCase{},
+		&test.MockLogger{},
+		nil,
+	)
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	assert.NotNil(t, err, "Execute() without env expected error, got nil")
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr), "Execute() should return an ExitError")
+	assert.Equal(t, exitCodeFailure, exitErr.Code)
+}
+
+func TestStaleCommand_TagWithoutMaxAge(t *testing.T) {
+	listUseCase := &mockListStaleUseCase{}
+	policyUseCase := &mockSetRotationPolicyUseCase{}
+	cmd := newTestStaleCommand(
+		t,
+		listUseCase,
+		policyUseCase,
+		&test.MockLogger{},
+		map[string]string{"env": "prod", "tag": "payments"},
+	)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err, "RunE() with a tag but no max age expected error, got nil")
+	assert.Nil(t, listUseCase.receivedOptions, "the report should not be listed")
+	assert.Nil(t, policyUseCase.receivedDTO, "the policy should not be changed")
+}
+
+func TestDaysLeft(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		due  string
+		want int
+	}{
+		{due: "2026-04-01T12:00:00Z", want: 12},
+		{due: "2026-03-21T00:00:00Z", want: 0},
+		{due: "2026-03-20T00:00:00Z", want: -1},
+		{due: "2026-01-31T00:00:00Z", want: -49},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, daysLeft(tt.due, now), fmt.Sprintf("daysLeft(%q)", tt.due))
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/internal/di"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// VerifyCommand represents the verify command for checking vault integrity.
+type VerifyCommand struct {
+	useCase app.VerifyVaultUc
+	logger  domain.Logger
+}
+
+// NewVerifyCommand creates a new verify command instance.
+func NewVerifyCommand(useCase app.VerifyVaultUc, logger domain.Logger) (*cobra.Command, error) {
+	cmd := &VerifyCommand{useCase, logger}
+
+	// lockify verify --env [env]
+	cobraCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check a vault for tampering",
+		Long: `Check a vault for tampering.
+
+This command checks the vault MAC, compares the entry manifest against the entries
+in the file and decrypts every entry under its key name. It reports every entry
+that was removed, added, modified, rolled back or moved from another key or environment.`,
+		Example: `  lockify verify --env prod`,
+		RunE:    cmd.runE,
+	}
+
+	cobraCmd.Flags().StringP("env", "e", "", "Environment Name")
+	err := cobraCmd.MarkFlagRequired("env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark env flag as required: %w", err)
+	}
+
+	return cobraCmd, nil
+}
+
+func (c *VerifyCommand) runE(cmd *cobra.Command, args []string) error {
+	env, err := requireEnvFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Progress("Verifying vault for %s...\n", env)
+	ctx := getContext()
+	report, err := c.useCase.Execute(ctx, env)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Problems) > 0 {
+		for _, problem := range report.Problems {
+			c.logger.Error("%s", problem)
+		}
+		return fmt.Errorf(
+			"vault for %s failed verification with %d problem(s)",
+			env,
+			len(report.Problems),
+		)
+	}
+
+	if !report.HasMAC() {
+		c.logger.Warning(
+			"Vault format version %d has no vault MAC, run `lockify migrate --env %s`",
+			report.FormatVersion,
+			env,
+		)
+	}
+	c.logger.Success(
+		"Vault for %s is consistent (revision %d, unlocked with slot %q)",
+		env,
+		report.Revision,
+		report.Slot,
+	)
+
+	return nil
+}
+
+func init() {
+	verifyCmd, err := NewVerifyCommand(di.BuildVerifyVault(), di.GetLogger())
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(verifyCmd)
+}
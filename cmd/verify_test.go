@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/app"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+type mockVerifyUseCase struct {
+	executeFunc func(ctx context.Context, env string) (app.VerifyReport, error)
+	receivedEnv string
+}
+
+func (m *mockVerifyUseCase) Execute(ctx context.Context, env string) (app.VerifyReport, error) {
+	m.receivedEnv = env
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env)
+	}
+	return app.VerifyReport{Env: env, FormatVersion: 3, Revision: 7, Slot: "ci"}, nil
+}
+
+func TestVerifyCommand_Consistent(t *testing.T) {
+	mockUseCase := &mockVerifyUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewVerifyCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", mockUseCase.receivedEnv)
+	assert.Count(t, 1, mockLogger.SuccessLogs)
+	assert.Contains(t, "revision 7", mockLogger.SuccessLogs[0])
+	assert.Contains(t, `slot "ci"`, mockLogger.SuccessLogs[0])
+	assert.Count(t, 0, mockLogger.WarningLogs)
+}
+
+func TestVerifyCommand_LegacyFormat(t *testing.T) {
+	mockUseCase := &mockVerifyUseCase{
+		executeFunc: func(ctx context.Context, env string) (app.VerifyReport, error) {
+			return app.VerifyReport{Env: env, FormatVersion: 1}, nil
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewVerifyCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.Nil(t, err)
+	assert.Count(t, 1, mockLogger.WarningLogs)
+	assert.Contains(t, "lockify migrate", mockLogger.WarningLogs[0])
+}
+
+func TestVerifyCommand_Problems(t *testing.T) {
+	mockUseCase := &mockVerifyUseCase{
+		executeFunc: func(ctx context.Context, env string) (app.VerifyReport, error) {
+			return app.VerifyReport{
+				Env:           env,
+				FormatVersion: 3,
+				Problems: []string{
+					`entry "A" was removed`,
+					`entry "B" was added outside lockify`,
+				},
+			}, nil
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewVerifyCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, "2 problem(s)", err.Error())
+	assert.Count(t, 2, mockLogger.ErrorLogs)
+	assert.Contains(t, `entry "A" was removed`, mockLogger.ErrorLogs)
+	assert.Count(t, 0, mockLogger.SuccessLogs)
+}
+
+func TestVerifyCommand_Error_Required_Env(t *testing.T) {
+	mockUseCase := &mockVerifyUseCase{}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewVerifyCommand(mockUseCase, mockLogger)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgEmptyEnv, err.Error())
+}
+
+func TestVerifyCommand_UseCaseError(t *testing.T) {
+	mockUseCase := &mockVerifyUseCase{
+		executeFunc: func(ctx context.Context, env string) (app.VerifyReport, error) {
+			return app.VerifyReport{}, fmt.Errorf("%s", errMsgExecuteFailed)
+		},
+	}
+	mockLogger := &test.MockLogger{}
+
+	cmd, _ := NewVerifyCommand(mockUseCase, mockLogger)
+	if err := cmd.Flags().Set("env", "test"); err != nil {
+		t.Fatalf("failed to set env flag: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.RunE(cmd, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, errMsgExecuteFailed, err.Error())
+}
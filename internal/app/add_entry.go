@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
 
@@ -14,37 +15,35 @@ type AddEntryUc interface {
 
 // AddEntryUseCase implements the use case for adding entries to the vault.
 type AddEntryUseCase struct {
-	vaultService      service.VaultServiceInterface
-	encryptionService service.EncryptionService
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
 }
 
 // AddEntryDTO contains the data needed to add an entry to the vault.
 type AddEntryDTO struct {
-	Env   string
-	Key   string
-	Value string
+	Env    string
+	Key    string
+	Value  string
+	Secret bool
 }
 
 // NewAddEntryUseCase creates a new AddEntryUseCase instance.
 func NewAddEntryUseCase(
 	vaultService service.VaultServiceInterface,
-	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
 ) AddEntryUc {
-	return &AddEntryUseCase{vaultService, encryptionService}
+	return &AddEntryUseCase{vaultService, auditLog}
 }
 
 // Execute adds or updates an entry in the vault.
 func (useCase *AddEntryUseCase) Execute(ctx context.Context, dto AddEntryDTO) error {
-	vault, err := useCase.vaultService.Open(ctx, dto.Env)
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, dto.Env)
 	if err != nil {
 		return fmt.Errorf("failed to open vault for environment %s: %w", dto.Env, err)
 	}
+	defer vault.Lock()
 
-	encryptedValue, err := useCase.encryptionService.Encrypt(
-		[]byte(dto.Value),
-		vault.Meta.Salt,
-		vault.Passphrase(),
-	)
+	encryptedValue, err := vault.Session().Encrypt(dto.Key, []byte(dto.Value))
 	if err != nil {
 		return fmt.Errorf("failed to encrypt value: %w", err)
 	}
@@ -53,6 +52,15 @@ func (useCase *AddEntryUseCase) Execute(ctx context.Context, dto AddEntryDTO) er
 	if err != nil {
 		return fmt.Errorf("failed to set entry: %w", err)
 	}
+	if dto.Secret {
+		if err := vault.MarkSecret(dto.Key); err != nil {
+			return fmt.Errorf("failed to set entry: %w", err)
+		}
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
 
-	return useCase.vaultService.Save(ctx, vault)
+	return useCase.auditLog.Record(ctx, vault, model.AuditSet, dto.Key)
 }
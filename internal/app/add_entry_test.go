@@ -15,32 +15,19 @@ const (
 	envTest            = "test"
 	keyTest            = "test-key"
 	valueTest          = "test-value"
-	fingerprintTest    = "test-fingerprint"
 	saltTest           = "test-salt"
 	passphraseTest     = "test-passphrase"
 	encryptedValueTest = "encrypted-test-value"
 )
 
 func TestAddEntryUseCase_Execute_Success(t *testing.T) {
-	testVault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	testVault, _ := model.NewVault(envTest, saltTest)
 	testVault.SetPassphrase(passphraseTest)
 
 	var savedVault *model.Vault
 
-	vaultService := &test.MockVaultService{
-		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			vault, _ := model.NewVault(env, fingerprintTest, saltTest)
-			vault.SetPassphrase(passphraseTest)
-			return vault, nil
-		},
-		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
-			savedVault = vault
-			return nil
-		},
-	}
-
-	encryptionService := &test.MockEncryptionService{
-		EncryptFunc: func(plaintext []byte, encodedSalt, pwd string) (string, error) {
+	session := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
 			if string(plaintext) != valueTest {
 				t.Errorf(
 					"Encrypt() called with plaintext %q, want %q",
@@ -48,17 +35,24 @@ func TestAddEntryUseCase_Execute_Success(t *testing.T) {
 					valueTest,
 				)
 			}
-			if encodedSalt != saltTest {
-				t.Errorf("Encrypt() called with salt %q, want %q", encodedSalt, saltTest)
-			}
-			if pwd != passphraseTest {
-				t.Errorf("Encrypt() called with passphrase %q, want %q", pwd, passphraseTest)
-			}
 			return encryptedValueTest, nil
 		},
 	}
 
-	useCase := NewAddEntryUseCase(vaultService, encryptionService)
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetPassphrase(passphraseTest)
+			vault.SetSession(session)
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewAddEntryUseCase(vaultService, &test.MockAuditLog{})
 
 	err := useCase.Execute(context.Background(), AddEntryDTO{
 		Env:   envTest,
@@ -67,6 +61,7 @@ func TestAddEntryUseCase_Execute_Success(t *testing.T) {
 	})
 
 	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, session.Closed, "Execute() should lock the vault when done")
 	assert.NotNil(
 		t,
 		savedVault,
@@ -95,6 +90,27 @@ func TestAddEntryUseCase_Execute_Success(t *testing.T) {
 	)
 }
 
+func TestAddEntryUseCase_Execute_Secret(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewAddEntryUseCase(vaultService, &test.MockAuditLog{})
+	err := useCase.Execute(context.Background(), AddEntryDTO{
+		Env:    envTest,
+		Key:    keyTest,
+		Value:  valueTest,
+		Secret: true,
+	})
+
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, savedVault.Entries[keyTest].Secret, "Execute() should mark the entry secret")
+}
+
 func TestAddEntryUseCase_Execute_VaultOpenError(t *testing.T) {
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
@@ -102,7 +118,7 @@ func TestAddEntryUseCase_Execute_VaultOpenError(t *testing.T) {
 		},
 	}
 
-	useCase := NewAddEntryUseCase(vaultService, &test.MockEncryptionService{})
+	useCase := NewAddEntryUseCase(vaultService, &test.MockAuditLog{})
 	err := useCase.Execute(context.Background(), AddEntryDTO{
 		Env:   envTest,
 		Key:   keyTest,
@@ -114,12 +130,18 @@ func TestAddEntryUseCase_Execute_VaultOpenError(t *testing.T) {
 }
 
 func TestAddEntryUseCase_Execute_EncryptionError(t *testing.T) {
-	encryptionService := &test.MockEncryptionService{
-		EncryptFunc: func(plaintext []byte, encodedSalt, passphrase string) (string, error) {
-			return "", errors.New("encryption failed")
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{
+				EncryptFunc: func(key string, plaintext []byte) (string, error) {
+					return "", errors.New("encryption failed")
+				},
+			})
+			return vault, nil
 		},
 	}
-	useCase := NewAddEntryUseCase(&test.MockVaultService{}, encryptionService)
+	useCase := NewAddEntryUseCase(vaultService, &test.MockAuditLog{})
 	err := useCase.Execute(context.Background(), AddEntryDTO{
 		Env:   envTest,
 		Key:   keyTest,
@@ -140,7 +162,7 @@ func TestAddEntryUseCase_Execute_SaveError(t *testing.T) {
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
 			return errors.New("save failed")
 		},
-	}, &test.MockEncryptionService{})
+	}, &test.MockAuditLog{})
 
 	err := useCase.Execute(context.Background(), AddEntryDTO{
 		Env:   envTest,
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// AddRecipientUc defines the interface for adding a public-key recipient to a vault.
+type AddRecipientUc interface {
+	Execute(ctx context.Context, env, publicKey, name string) error
+}
+
+// AddRecipientUseCase implements the use case for adding a public-key recipient to a vault.
+type AddRecipientUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewAddRecipientUseCase creates a new AddRecipientUseCase instance.
+func NewAddRecipientUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) AddRecipientUc {
+	return &AddRecipientUseCase{vaultService, auditLog}
+}
+
+// Execute wraps the vault data key for the public key of a recipient, so that its
+// identity unlocks the vault without a passphrase.
+func (useCase *AddRecipientUseCase) Execute(
+	ctx context.Context,
+	env, publicKey, name string,
+) error {
+	if publicKey == "" {
+		return fmt.Errorf("public key cannot be empty")
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if vault.Meta.FormatVersion < model.FormatVersionRecipients {
+		return fmt.Errorf(
+			"vault for environment %s uses format version %d, run `lockify migrate --env %s` first",
+			env,
+			vault.Meta.FormatVersion,
+			env,
+		)
+	}
+
+	recipient := model.Recipient{Name: name, PublicKey: publicKey}
+	recipient.WrappedKey, err = vault.Session().WrapKeyForRecipient(vault.Meta, publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	if err := vault.AddRecipient(recipient); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditAddRecipient, recipient.Label())
+}
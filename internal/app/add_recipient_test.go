@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+const publicKeyTest = "lockify1alice"
+
+func TestAddRecipientUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	session := &test.MockSession{
+		WrapKeyForRecipientFunc: func(meta model.Meta, publicKey string) (string, error) {
+			assert.Equal(t, publicKeyTest, publicKey)
+			return "alice-wrapped-key", nil
+		},
+	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(session)
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{}
+	useCase := NewAddRecipientUseCase(vaultService, auditLog)
+
+	err := useCase.Execute(context.Background(), envTest, publicKeyTest, "alice")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Count(t, 1, savedVault.Meta.Recipients)
+
+	recipient := savedVault.Meta.Recipients[0]
+	assert.Equal(t, "alice", recipient.Name)
+	assert.Equal(t, publicKeyTest, recipient.PublicKey)
+	assert.Equal(t, "alice-wrapped-key", recipient.WrappedKey)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditAddRecipient, auditLog.Recorded[0].Operation)
+	assert.DeepEqual(t, []string{"alice"}, auditLog.Recorded[0].Keys)
+	assert.True(t, session.Closed, "Execute() should lock the vault")
+}
+
+func TestAddRecipientUseCase_Execute_Duplicate(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.AddRecipient(model.Recipient{PublicKey: publicKeyTest, WrappedKey: "wrapped"})
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called for a duplicate recipient")
+			return nil
+		},
+	}
+
+	useCase := NewAddRecipientUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, publicKeyTest, "")
+	assert.NotNil(t, err, "Execute() with a duplicate recipient expected error, got nil")
+	assert.Contains(t, "already exists", err.Error())
+}
+
+func TestAddRecipientUseCase_Execute_LegacyFormat(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Meta.FormatVersion = model.FormatVersionKeySlots
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+	}
+
+	useCase := NewAddRecipientUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, publicKeyTest, "")
+	assert.NotNil(t, err, "Execute() with a legacy vault expected error, got nil")
+	assert.Contains(t, "lockify migrate --env "+envTest, err.Error())
+}
+
+func TestAddRecipientUseCase_Execute_EmptyPublicKey(t *testing.T) {
+	useCase := NewAddRecipientUseCase(&test.MockVaultService{}, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, "", "alice")
+	assert.NotNil(t, err, "Execute() with an empty public key expected error, got nil")
+	assert.Contains(t, "public key cannot be empty", err.Error())
+}
+
+func TestAddRecipientUseCase_Execute_WrapKeyError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{
+				WrapKeyForRecipientFunc: func(meta model.Meta, publicKey string) (string, error) {
+					return "", errors.New("invalid recipient")
+				},
+			})
+			return vault, nil
+		},
+	}
+
+	useCase := NewAddRecipientUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, "age1abc", "")
+	assert.NotNil(t, err, "Execute() with wrap error expected error, got nil")
+	assert.Contains(t, "failed to wrap data key: invalid recipient", err.Error())
+}
+
+func TestAddRecipientUseCase_Execute_OpenError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("open error")
+		},
+	}
+
+	useCase := NewAddRecipientUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, publicKeyTest, "")
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+	assert.Equal(t, "open error", err.Error())
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// AddSlotUc defines the interface for adding a key slot to a vault.
+type AddSlotUc interface {
+	Execute(ctx context.Context, env, name, passphrase string) error
+}
+
+// AddSlotUseCase implements the use case for adding a key slot to a vault.
+type AddSlotUseCase struct {
+	vaultService      service.VaultServiceInterface
+	encryptionService service.EncryptionService
+	passphrasePolicy  service.PassphrasePolicy
+	auditLog          service.AuditLog
+}
+
+// NewAddSlotUseCase creates a new AddSlotUseCase instance.
+func NewAddSlotUseCase(
+	vaultService service.VaultServiceInterface,
+	encryptionService service.EncryptionService,
+	passphrasePolicy service.PassphrasePolicy,
+	auditLog service.AuditLog,
+) AddSlotUc {
+	return &AddSlotUseCase{vaultService, encryptionService, passphrasePolicy, auditLog}
+}
+
+// Execute wraps the vault data key with the passphrase of a new named key slot, once the
+// passphrase policy accepts it.
+func (useCase *AddSlotUseCase) Execute(ctx context.Context, env, name, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase cannot be empty")
+	}
+	if err := useCase.passphrasePolicy.Check(ctx, env, passphrase); err != nil {
+		return err
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if vault.Meta.FormatVersion < model.FormatVersionKeySlots {
+		return fmt.Errorf(
+			"vault for environment %s uses format version %d, run `lockify migrate --env %s` first",
+			env,
+			vault.Meta.FormatVersion,
+			env,
+		)
+	}
+
+	slot := model.KeySlot{Name: name}
+	slot.Salt, err = useCase.encryptionService.NewSalt()
+	if err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	slot.WrappedKey, err = vault.Session().WrapKey(vault.Meta.ForSlot(slot), passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	if err := vault.AddSlot(slot); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditAddSlot, name)
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestAddSlotUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	var wrappedMeta model.Meta
+	session := &test.MockSession{
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			wrappedMeta = meta
+			assert.Equal(t, "ci-passphrase", passphrase)
+			return "ci-wrapped-key", nil
+		},
+	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(session)
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSaltFunc: func() (string, error) {
+			return "ci-salt", nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{}
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		encryptionService,
+		&test.MockPassphrasePolicy{},
+		auditLog,
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", "ci-passphrase")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Count(t, 1, savedVault.Meta.Slots)
+
+	slot := savedVault.Meta.Slots[0]
+	assert.Equal(t, "ci", slot.Name)
+	assert.Equal(t, "ci-salt", slot.Salt)
+	assert.Equal(t, "", slot.FingerPrint, "a slot should not store a fingerprint")
+	assert.Equal(t, "ci-wrapped-key", slot.WrappedKey)
+	assert.Equal(t, "ci-salt", wrappedMeta.Salt, "WrapKey() should use the slot salt")
+	assert.Equal(t, saltTest, savedVault.Meta.Salt, "the default slot should be unchanged")
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditAddSlot, auditLog.Recorded[0].Operation)
+	assert.DeepEqual(t, []string{"ci"}, auditLog.Recorded[0].Keys)
+	assert.True(t, session.Closed, "Execute() should lock the vault")
+}
+
+func TestAddSlotUseCase_Execute_DuplicateName(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called for a duplicate slot")
+			return nil
+		},
+	}
+
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		&test.MockEncryptionService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, model.DefaultSlotName, passphraseTest)
+	assert.NotNil(t, err, "Execute() with a duplicate slot name expected error, got nil")
+	assert.Contains(t, "already exists", err.Error())
+}
+
+func TestAddSlotUseCase_Execute_LegacyFormat(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Meta.FormatVersion = model.FormatVersionEnvelope
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+	}
+
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		&test.MockEncryptionService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", passphraseTest)
+	assert.NotNil(t, err, "Execute() with a legacy vault expected error, got nil")
+	assert.Contains(t, "lockify migrate --env "+envTest, err.Error())
+}
+
+func TestAddSlotUseCase_Execute_EmptyPassphrase(t *testing.T) {
+	useCase := NewAddSlotUseCase(
+		&test.MockVaultService{},
+		&test.MockEncryptionService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", "")
+	assert.NotNil(t, err, "Execute() with an empty passphrase expected error, got nil")
+	assert.Contains(t, "passphrase cannot be empty", err.Error())
+}
+
+func TestAddSlotUseCase_Execute_WeakPassphrase(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			t.Error("OpenForUpdate() should not be called for a rejected passphrase")
+			return nil, errors.New("unexpected open")
+		},
+	}
+	policy := &test.MockPassphrasePolicy{
+		CheckFunc: func(ctx context.Context, env, passphrase string) error {
+			return &model.WeakPassphraseError{Env: env, MinScore: 3}
+		},
+	}
+
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		&test.MockEncryptionService{},
+		policy,
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", "qwerty")
+	var weak *model.WeakPassphraseError
+	assert.True(t, errors.As(err, &weak), fmt.Sprintf("Execute() error = %v, want weak", err))
+}
+
+func TestAddSlotUseCase_Execute_WrapKeyError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{
+				WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+					return "", errors.New("wrap error")
+				},
+			})
+			return vault, nil
+		},
+	}
+
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		&test.MockEncryptionService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", passphraseTest)
+	assert.NotNil(t, err, "Execute() with wrap error expected error, got nil")
+	assert.Contains(t, "failed to wrap data key", err.Error())
+}
+
+func TestAddSlotUseCase_Execute_OpenError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("open error")
+		},
+	}
+
+	useCase := NewAddSlotUseCase(
+		vaultService,
+		&test.MockEncryptionService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci", passphraseTest)
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+	assert.Equal(t, "open error", err.Error())
+}
@@ -23,7 +23,7 @@ func NewClearCachedPassphraseUseCase(
 	return &ClearCachedPassphraseUseCase{passphraseService}
 }
 
-// Execute clears all cached passphrases from the system keyring.
+// Execute clears all cached passphrases from the passphrase cache.
 func (useCase *ClearCachedPassphraseUseCase) Execute(ctx context.Context) error {
 	return useCase.passphraseService.ClearAll(ctx)
 }
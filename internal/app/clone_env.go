@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// CloneEnvUc defines the interface for copying the entries of a vault into a new one.
+type CloneEnvUc interface {
+	Execute(ctx context.Context, from, to string) (int, error)
+}
+
+// CloneEnvUseCase implements the use case for copying the entries of a vault into a new one.
+type CloneEnvUseCase struct {
+	vaultService      service.VaultServiceInterface
+	vaultRepo         repository.VaultRepository
+	passphraseService service.PassphraseService
+	auditLog          service.AuditLog
+}
+
+// NewCloneEnvUseCase creates a new CloneEnvUseCase instance.
+func NewCloneEnvUseCase(
+	vaultService service.VaultServiceInterface,
+	vaultRepo repository.VaultRepository,
+	passphraseService service.PassphraseService,
+	auditLog service.AuditLog,
+) CloneEnvUc {
+	return &CloneEnvUseCase{vaultService, vaultRepo, passphraseService, auditLog}
+}
+
+// Execute creates the vault of environment to, protected by its own passphrase, with a copy
+// of every entry of environment from, and returns the number of entries copied. Key slots
+// and recipients are not copied. The new vault starts an audit log of its own.
+func (useCase *CloneEnvUseCase) Execute(ctx context.Context, from, to string) (int, error) {
+	if err := checkTargetEnv(ctx, useCase.vaultRepo, from, to); err != nil {
+		return 0, err
+	}
+
+	source, err := useCase.vaultService.Open(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Lock()
+
+	passphrase, err := useCase.passphraseService.GetNew(ctx, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get passphrase: %w", err)
+	}
+
+	target, err := useCase.vaultService.New(ctx, to, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	defer target.Lock()
+
+	if err := copyEntries(source, target); err != nil {
+		return 0, err
+	}
+	if err := useCase.vaultService.SaveNew(ctx, target); err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(target.Entries))
+	for key := range target.Entries {
+		keys = append(keys, key)
+	}
+	if err := startAuditLog(ctx, useCase.auditLog, target, nil); err != nil {
+		return len(keys), err
+	}
+	if err := useCase.auditLog.Record(ctx, target, model.AuditSet, keys...); err != nil {
+		return len(keys), err
+	}
+	if err := useCase.auditLog.Record(ctx, source, model.AuditExport, keys...); err != nil {
+		return len(keys), err
+	}
+
+	return len(keys), nil
+}
+
+// checkTargetEnv makes sure that to is a valid name for a new environment other than from
+func checkTargetEnv(
+	ctx context.Context,
+	vaultRepo repository.VaultRepository,
+	from, to string,
+) error {
+	if err := model.ValidateEnvName(to); err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("source and target environment are both %q", from)
+	}
+
+	exists, err := vaultRepo.Exists(ctx, to)
+	if err != nil {
+		return fmt.Errorf("failed to check vault existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("vault already exists for environment %q", to)
+	}
+	return nil
+}
+
+// startAuditLog replaces whatever audit log a previous vault of the same environment left
+// with events, sealed for the new vault
+func startAuditLog(
+	ctx context.Context,
+	auditLog service.AuditLog,
+	vault *model.Vault,
+	events []model.AuditEvent,
+) error {
+	if err := auditLog.Rewrite(ctx, vault, events); err != nil {
+		return fmt.Errorf("vault for environment %s was created but %w", vault.Meta.Env, err)
+	}
+	return nil
+}
+
+// copyEntries re-encrypts every entry of source, with its history and metadata, for target.
+// Entries are bound to their environment, so their ciphertext cannot be copied as is;
+// timestamps, markers, whether metadata is public and the history and rotation policies are
+// kept.
+func copyEntries(source, target *model.Vault) error {
+	target.Meta.PublicMeta = source.Meta.PublicMeta
+	target.Meta.History = source.Meta.History
+	target.Meta.Rotation = source.Meta.Rotation
+	if target.Entries == nil {
+		target.Entries = make(map[string]model.Entry, len(source.Entries))
+	}
+	for key, entry := range source.Entries {
+		resealed, err := resealEntry(entry, key, key, source.Session(), target.Session())
+		if err != nil {
+			return err
+		}
+		target.Entries[key] = resealed
+	}
+	return nil
+}
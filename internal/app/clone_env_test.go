@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestCloneEnvUseCase_Execute_Success(t *testing.T) {
+	sourceSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			return []byte("plain-" + ciphertext), nil
+		},
+	}
+	targetSession := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return "staging-" + string(plaintext), nil
+		},
+	}
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			assert.Equal(t, "prod", env)
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Entries[keyTest] = model.Entry{
+				Value:     "cipher",
+				CreatedAt: "2026-01-01T00:00:00Z",
+				UpdatedAt: "2026-02-01T00:00:00Z",
+				Secret:    true,
+			}
+			vault.Meta.Recipients = []model.Recipient{{PublicKey: publicKeyTest}}
+			vault.SetSession(sourceSession)
+			return vault, nil
+		},
+		NewFunc: func(ctx context.Context, env, passphrase string) (*model.Vault, error) {
+			assert.Equal(t, "staging", env)
+			assert.Equal(t, passphraseTest, passphrase)
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(targetSession)
+			return vault, nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			assert.Equal(t, "staging", env)
+			return passphraseTest, nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{}
+	useCase := NewCloneEnvUseCase(
+		vaultService,
+		&test.MockVaultRepository{},
+		passphraseService,
+		auditLog,
+	)
+
+	copied, err := useCase.Execute(context.Background(), "prod", "staging")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 1, copied)
+	assert.NotNil(t, savedVault, "Execute() should save the new vault")
+
+	entry := savedVault.Entries[keyTest]
+	assert.Equal(t, "staging-plain-cipher", entry.Value)
+	assert.Equal(t, "2026-01-01T00:00:00Z", entry.CreatedAt)
+	assert.Equal(t, "2026-02-01T00:00:00Z", entry.UpdatedAt)
+	assert.True(t, entry.Secret, "Execute() should keep the secret marker")
+	assert.Count(t, 0, savedVault.Meta.Recipients)
+	assert.True(t, sourceSession.Closed, "Execute() should lock the source vault")
+	assert.True(t, targetSession.Closed, "Execute() should lock the new vault")
+	assert.Count(t, 2, auditLog.Recorded)
+	assert.Equal(t, model.AuditSet, auditLog.Recorded[0].Operation)
+	assert.Equal(t, model.AuditExport, auditLog.Recorded[1].Operation)
+}
+
+func TestCloneEnvUseCase_Execute_InvalidTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    string
+		exists      bool
+		expectedErr string
+	}{
+		{"same env", "prod", "prod", false, "are both"},
+		{"target exists", "prod", "staging", true, "already exists"},
+		{"invalid name", "prod", "../staging", false, "environment name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultRepo := &test.MockVaultRepository{
+				ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+					return tt.exists, nil
+				},
+			}
+			vaultService := &test.MockVaultService{
+				OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					t.Error("Open() should not be called for an invalid target")
+					return nil, errors.New("unexpected")
+				},
+			}
+
+			useCase := NewCloneEnvUseCase(
+				vaultService,
+				vaultRepo,
+				&test.MockPassphraseService{},
+				&test.MockAuditLog{},
+			)
+
+			_, err := useCase.Execute(context.Background(), tt.from, tt.to)
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+		})
+	}
+}
+
+func TestCloneEnvUseCase_Execute_WeakPassphrase(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("SaveNew() should not be called for a rejected passphrase")
+			return nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		GetNewFunc: func(ctx context.Context, env string) (string, error) {
+			assert.Equal(t, "staging", env)
+			return "", &model.WeakPassphraseError{Env: env, MinScore: 3}
+		},
+	}
+
+	useCase := NewCloneEnvUseCase(
+		vaultService,
+		&test.MockVaultRepository{},
+		passphraseService,
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), "prod", "staging")
+	var weak *model.WeakPassphraseError
+	assert.True(t, errors.As(err, &weak), fmt.Sprintf("Execute() error = %v, want weak", err))
+}
+
+func TestCloneEnvUseCase_Execute_DecryptFails(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Entries[keyTest] = model.Entry{Value: "cipher"}
+			vault.SetSession(&test.MockSession{
+				DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+					return nil, model.ErrTampered
+				},
+			})
+			return vault, nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("SaveNew() should not be called when an entry cannot be copied")
+			return nil
+		},
+	}
+
+	useCase := NewCloneEnvUseCase(
+		vaultService,
+		&test.MockVaultRepository{},
+		&test.MockPassphraseService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), "prod", "staging")
+	assert.NotNil(t, err, "Execute() expected error, got nil")
+	assert.Contains(t, "failed to decrypt key", err.Error())
+}
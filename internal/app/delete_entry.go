@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
 
@@ -15,23 +16,32 @@ type DeleteEntryUc interface {
 // DeleteEntryUseCase implements the use case for deleting entries from the vault.
 type DeleteEntryUseCase struct {
 	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
 }
 
 // NewDeleteEntryUseCase creates a new DeleteEntryUseCase instance.
-func NewDeleteEntryUseCase(vaultService service.VaultServiceInterface) DeleteEntryUc {
-	return &DeleteEntryUseCase{vaultService}
+func NewDeleteEntryUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) DeleteEntryUc {
+	return &DeleteEntryUseCase{vaultService, auditLog}
 }
 
 // Execute deletes an entry from the vault for the specified environment and key.
 func (useCase *DeleteEntryUseCase) Execute(ctx context.Context, env, key string) error {
-	vault, err := useCase.vaultService.Open(ctx, env)
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
 	if err != nil {
 		return err
 	}
+	defer vault.Lock()
 
 	if err = vault.DeleteEntry(key); err != nil {
 		return fmt.Errorf("failed to delete key %s: %w", key, err)
 	}
 
-	return useCase.vaultService.Save(ctx, vault)
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditDelete, key)
 }
@@ -15,32 +15,35 @@ func TestDeleteEntryUseCase_Execute_Success(t *testing.T) {
 	var savedVault *model.Vault
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			savedVault, _ = model.NewVault(env, fingerprintTest, saltTest)
+			savedVault, _ = model.NewVault(env, saltTest)
 			savedVault.SetPassphrase(passphraseTest)
 			savedVault.SetEntry(keyTest, base64.StdEncoding.EncodeToString([]byte(valueTest)))
 			return savedVault, nil
 		},
 	}
 
-	useCase := NewDeleteEntryUseCase(vaultService)
+	auditLog := &test.MockAuditLog{}
+	useCase := NewDeleteEntryUseCase(vaultService, auditLog)
 
 	err := useCase.Execute(context.Background(), envTest, keyTest)
 	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
 
 	_, err = savedVault.GetEntry(keyTest)
 	assert.NotNil(t, err, "Execute() did not delete the key successfully")
+	assert.Equal(t, 1, len(auditLog.Recorded), "Execute() should record the deletion")
+	assert.Equal(t, model.AuditDelete, auditLog.Recorded[0].Operation)
 }
 
 func TestDeleteEntryUseCase_Execute_EntryNotFound(t *testing.T) {
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			savedVault, _ := model.NewVault(env, fingerprintTest, saltTest)
+			savedVault, _ := model.NewVault(env, saltTest)
 			savedVault.SetPassphrase(passphraseTest)
 			return savedVault, nil
 		},
 	}
 
-	useCase := NewDeleteEntryUseCase(vaultService)
+	useCase := NewDeleteEntryUseCase(vaultService, &test.MockAuditLog{})
 
 	err := useCase.Execute(context.Background(), envTest, keyTest)
 	assert.NotNil(t, err, "Execute() should return non-existence error, got nil")
@@ -55,3 +58,22 @@ func TestDeleteEntryUseCase_Execute_EntryNotFound(t *testing.T) {
 		),
 	)
 }
+
+func TestDeleteEntryUseCase_Execute_OpensForUpdate(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			t.Error("Execute() should open the vault for update")
+			return nil, fmt.Errorf("unexpected Open")
+		},
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetEntry(keyTest, base64.StdEncoding.EncodeToString([]byte(valueTest)))
+			return vault, nil
+		},
+	}
+
+	useCase := NewDeleteEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, keyTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+}
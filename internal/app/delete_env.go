@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// DeleteEnvUc defines the interface for deleting the vault of an environment.
+type DeleteEnvUc interface {
+	Execute(ctx context.Context, env string) (string, error)
+}
+
+// DeleteEnvUseCase implements the use case for deleting the vault of an environment.
+type DeleteEnvUseCase struct {
+	vaultService      service.VaultServiceInterface
+	vaultRepo         repository.VaultRepository
+	passphraseService service.PassphraseService
+	auditLog          service.AuditLog
+}
+
+// NewDeleteEnvUseCase creates a new DeleteEnvUseCase instance.
+func NewDeleteEnvUseCase(
+	vaultService service.VaultServiceInterface,
+	vaultRepo repository.VaultRepository,
+	passphraseService service.PassphraseService,
+	auditLog service.AuditLog,
+) DeleteEnvUc {
+	return &DeleteEnvUseCase{vaultService, vaultRepo, passphraseService, auditLog}
+}
+
+// Execute deletes the vault of an environment and returns the path of the backup it was
+// moved to, from which `lockify restore` can bring it back. The vault is unlocked first, so
+// that the deletion is recorded in its audit log, which is kept.
+func (useCase *DeleteEnvUseCase) Execute(ctx context.Context, env string) (string, error) {
+	exists, err := useCase.vaultRepo.Exists(ctx, env)
+	if err != nil {
+		return "", fmt.Errorf("failed to check vault existence: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("vault for environment %q does not exist", env)
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return "", err
+	}
+	defer vault.Lock()
+
+	backupPath, err := useCase.vaultRepo.Delete(ctx, env)
+	if err != nil {
+		return "", fmt.Errorf("failed to delete vault for environment %s: %w", env, err)
+	}
+	//nolint:errcheck // The vault is gone either way; a stale cache entry is harmless
+	useCase.passphraseService.Clear(ctx, env)
+
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditDeleteEnv); err != nil {
+		return backupPath, err
+	}
+
+	return backupPath, nil
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestDeleteEnvUseCase_Execute_Success(t *testing.T) {
+	var session *test.MockSession
+	var clearedEnv string
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			session = &test.MockSession{}
+			vault.SetSession(session)
+			return vault, nil
+		},
+	}
+	vaultRepo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		DeleteFunc: func(ctx context.Context, env string) (string, error) {
+			assert.NotNil(t, session, "Delete() should be called with the vault opened")
+			assert.False(t, session.Closed, "Delete() should be called with the vault locked")
+			return env + ".vault.enc.bak.1", nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		ClearFunc: func(ctx context.Context, env string) error {
+			clearedEnv = env
+			return errors.New("keyring unavailable")
+		},
+	}
+	auditLog := &test.MockAuditLog{}
+
+	useCase := NewDeleteEnvUseCase(vaultService, vaultRepo, passphraseService, auditLog)
+
+	backupPath, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, envTest+".vault.enc.bak.1", backupPath)
+	assert.Equal(t, envTest, clearedEnv)
+	assert.True(t, session.Closed, "Execute() should lock the vault again")
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditDeleteEnv, auditLog.Recorded[0].Operation)
+}
+
+func TestDeleteEnvUseCase_Execute_Errors(t *testing.T) {
+	exists := func(ctx context.Context, env string) (bool, error) {
+		return true, nil
+	}
+	tests := []struct {
+		name         string
+		vaultService *test.MockVaultService
+		vaultRepo    *test.MockVaultRepository
+		expectedErr  string
+	}{
+		{
+			name:         "vault not found",
+			vaultService: &test.MockVaultService{},
+			vaultRepo:    &test.MockVaultRepository{},
+			expectedErr:  "does not exist",
+		},
+		{
+			name: "open fails",
+			vaultService: &test.MockVaultService{
+				OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return nil, errors.New("in use by another lockify process")
+				},
+			},
+			vaultRepo:   &test.MockVaultRepository{ExistsFunc: exists},
+			expectedErr: "in use by another lockify process",
+		},
+		{
+			name:         "delete fails",
+			vaultService: &test.MockVaultService{},
+			vaultRepo: &test.MockVaultRepository{
+				ExistsFunc: exists,
+				DeleteFunc: func(ctx context.Context, env string) (string, error) {
+					return "", errors.New("permission denied")
+				},
+			},
+			expectedErr: "failed to delete vault for environment test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog := &test.MockAuditLog{}
+			useCase := NewDeleteEnvUseCase(
+				tt.vaultService,
+				tt.vaultRepo,
+				&test.MockPassphraseService{},
+				auditLog,
+			)
+
+			_, err := useCase.Execute(context.Background(), envTest)
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+			assert.Count(t, 0, auditLog.Recorded)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// DiffEnvsUc defines the interface for comparing the entries of two environments.
+type DiffEnvsUc interface {
+	Execute(ctx context.Context, dto DiffEnvsDTO) (DiffResult, error)
+}
+
+// DiffEnvsDTO contains the data needed to compare a vault with another vault or a file.
+type DiffEnvsDTO struct {
+	From string
+	// To is the environment compared with From. It is ignored when File is set.
+	To string
+	// File holds entries in Format to compare with From instead of another vault.
+	File   io.Reader
+	Format value.FileFormat
+	// ShowValues fills in the values of the differing entries.
+	ShowValues bool
+}
+
+// KeyDiff is a key that differs between the two sides. From and To hold its values when
+// they were asked for and exist on that side.
+type KeyDiff struct {
+	Key  string
+	From string
+	To   string
+}
+
+// DiffResult lists the keys that only exist in To, only exist in From, or whose values
+// differ, each sorted by key.
+type DiffResult struct {
+	Added   []KeyDiff
+	Removed []KeyDiff
+	Changed []KeyDiff
+}
+
+// HasDifferences reports whether the two sides differ at all.
+func (r DiffResult) HasDifferences() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Changed) > 0
+}
+
+// DiffEnvsUseCase implements the use case for comparing the entries of two environments.
+type DiffEnvsUseCase struct {
+	vaultService  service.VaultServiceInterface
+	importService service.ImportService
+	auditLog      service.AuditLog
+}
+
+// NewDiffEnvsUseCase creates a new DiffEnvsUseCase instance.
+func NewDiffEnvsUseCase(
+	vaultService service.VaultServiceInterface,
+	importService service.ImportService,
+	auditLog service.AuditLog,
+) DiffEnvsUc {
+	return &DiffEnvsUseCase{vaultService, importService, auditLog}
+}
+
+// Execute decrypts the entries of both sides and compares them. Values are compared even
+// when they are not shown, so both vaults have to be unlocked.
+func (useCase *DiffEnvsUseCase) Execute(ctx context.Context, dto DiffEnvsDTO) (DiffResult, error) {
+	if dto.File == nil && dto.From == dto.To {
+		return DiffResult{}, fmt.Errorf("cannot compare environment %s with itself", dto.From)
+	}
+
+	from, err := useCase.decryptEnv(ctx, dto.From, dto.ShowValues)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	var to map[string]string
+	switch {
+	case dto.File == nil:
+		to, err = useCase.decryptEnv(ctx, dto.To, dto.ShowValues)
+	case dto.Format.IsJSON():
+		to, err = useCase.importService.FromJSON(dto.File)
+	case dto.Format.IsDotEnv():
+		to, err = useCase.importService.FromDotEnv(dto.File)
+	default:
+		return DiffResult{}, fmt.Errorf("unsupported format: %q", dto.Format)
+	}
+	if err != nil {
+		if dto.File != nil {
+			return DiffResult{}, fmt.Errorf("failed to parse file: %w", err)
+		}
+		return DiffResult{}, err
+	}
+
+	return compareEntries(from, to, dto.ShowValues), nil
+}
+
+// decryptEnv returns the decrypted entries of an environment and records them as read when
+// their values are shown, or the vault as opened otherwise. The vault is locked again before
+// returning, so that comparing an environment never holds two vault locks at once.
+func (useCase *DiffEnvsUseCase) decryptEnv(
+	ctx context.Context,
+	env string,
+	showValues bool,
+) (map[string]string, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	defer vault.Lock()
+
+	entries := make(map[string]string, len(vault.Entries))
+	for key, entry := range vault.Entries {
+		decrypted, err := vault.Session().Decrypt(key, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt value of key %q in %s: %w", key, env, err)
+		}
+		entries[key] = string(decrypted)
+	}
+
+	operation, keys := model.AuditOpen, []string(nil)
+	if showValues {
+		operation = model.AuditGet
+		for key := range entries {
+			keys = append(keys, key)
+		}
+	}
+	if err := useCase.auditLog.Record(ctx, vault, operation, keys...); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// compareEntries lists the keys added, removed and changed from one set of entries to
+// the other
+func compareEntries(from, to map[string]string, showValues bool) DiffResult {
+	result := DiffResult{}
+	for key, toValue := range to {
+		fromValue, exists := from[key]
+		switch {
+		case !exists:
+			result.Added = append(result.Added, KeyDiff{Key: key, To: toValue})
+		case fromValue != toValue:
+			result.Changed = append(result.Changed, KeyDiff{Key: key, From: fromValue, To: toValue})
+		}
+	}
+	for key, fromValue := range from {
+		if _, exists := to[key]; !exists {
+			result.Removed = append(result.Removed, KeyDiff{Key: key, From: fromValue})
+		}
+	}
+
+	for _, diffs := range [][]KeyDiff{result.Added, result.Removed, result.Changed} {
+		sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+		if !showValues {
+			for i := range diffs {
+				diffs[i].From, diffs[i].To = "", ""
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestDiffEnvsUseCase_Execute_Envs(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		"staging": {"SAME": "1", "CHANGED": "old", "ONLY_STAGING": "s"},
+		"prod":    {"SAME": "1", "CHANGED": "new", "ONLY_PROD": "p"},
+	})
+
+	tests := []struct {
+		name       string
+		showValues bool
+		operation  model.AuditOperation
+		expected   DiffResult
+	}{
+		{
+			name:      "values hidden",
+			operation: model.AuditOpen,
+			expected: DiffResult{
+				Added:   []KeyDiff{{Key: "ONLY_PROD"}},
+				Removed: []KeyDiff{{Key: "ONLY_STAGING"}},
+				Changed: []KeyDiff{{Key: "CHANGED"}},
+			},
+		},
+		{
+			name:       "values shown",
+			showValues: true,
+			operation:  model.AuditGet,
+			expected: DiffResult{
+				Added:   []KeyDiff{{Key: "ONLY_PROD", To: "p"}},
+				Removed: []KeyDiff{{Key: "ONLY_STAGING", From: "s"}},
+				Changed: []KeyDiff{{Key: "CHANGED", From: "old", To: "new"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog := &test.MockAuditLog{}
+			useCase := NewDiffEnvsUseCase(vaultService, &test.MockImportService{}, auditLog)
+
+			result, err := useCase.Execute(context.Background(), DiffEnvsDTO{
+				From:       "staging",
+				To:         "prod",
+				ShowValues: tt.showValues,
+			})
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			assert.DeepEqual(t, tt.expected, result)
+			assert.True(t, result.HasDifferences(), "HasDifferences() should be true")
+			assert.Count(t, 2, auditLog.Recorded, "both vaults should be recorded")
+			for _, event := range auditLog.Recorded {
+				assert.Equal(t, tt.operation, event.Operation)
+			}
+		})
+	}
+}
+
+func TestDiffEnvsUseCase_Execute_File(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		"prod": {"A": "1", "B": "2"},
+	})
+	importService := &test.MockImportService{
+		FromJSONFunc: func(r io.Reader) (map[string]string, error) {
+			data, _ := io.ReadAll(r)
+			assert.Equal(t, "file-content", string(data))
+			return map[string]string{"A": "1", "B": "2"}, nil
+		},
+	}
+
+	useCase := NewDiffEnvsUseCase(vaultService, importService, &test.MockAuditLog{})
+
+	result, err := useCase.Execute(context.Background(), DiffEnvsDTO{
+		From:   "prod",
+		File:   strings.NewReader("file-content"),
+		Format: value.JSON,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.False(t, result.HasDifferences(), "HasDifferences() should be false")
+}
+
+func TestDiffEnvsUseCase_Execute_Errors(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		"prod": {"A": "1"},
+	})
+	importService := &test.MockImportService{
+		FromDotEnvFunc: func(r io.Reader) (map[string]string, error) {
+			return nil, errors.New("invalid line 3")
+		},
+	}
+
+	tests := []struct {
+		name        string
+		dto         DiffEnvsDTO
+		expectedErr string
+	}{
+		{
+			name:        "same env",
+			dto:         DiffEnvsDTO{From: "prod", To: "prod"},
+			expectedErr: "with itself",
+		},
+		{
+			name:        "missing env",
+			dto:         DiffEnvsDTO{From: "prod", To: "staging"},
+			expectedErr: "does not exist",
+		},
+		{
+			name: "invalid file",
+			dto: DiffEnvsDTO{
+				From:   "prod",
+				File:   strings.NewReader(""),
+				Format: value.DotEnv,
+			},
+			expectedErr: "failed to parse file: invalid line 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewDiffEnvsUseCase(vaultService, importService, &test.MockAuditLog{})
+
+			_, err := useCase.Execute(context.Background(), tt.dto)
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+		})
+	}
+}
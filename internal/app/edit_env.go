@@ -0,0 +1,254 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// EditEnvUc defines the interface for editing all entries of a vault at once.
+type EditEnvUc interface {
+	Execute(
+		ctx context.Context,
+		env string,
+		format value.FileFormat,
+		confirm EditConfirmFunc,
+	) (EditResult, error)
+}
+
+// EditConfirmFunc is asked whether the changes made in the editor should be saved.
+type EditConfirmFunc func(result EditResult) (bool, error)
+
+// EditResult lists the keys that were added, changed and removed in the editor.
+type EditResult struct {
+	Added   []string
+	Changed []string
+	Removed []string
+	Saved   bool
+}
+
+// HasChanges reports whether anything was edited.
+func (r EditResult) HasChanges() bool {
+	return len(r.Added)+len(r.Changed)+len(r.Removed) > 0
+}
+
+// EditEnvUseCase implements the use case for editing a vault in the user's editor.
+type EditEnvUseCase struct {
+	vaultService  service.VaultServiceInterface
+	importService service.ImportService
+	editorService service.EditorService
+	auditLog      service.AuditLog
+}
+
+// NewEditEnvUseCase creates a new EditEnvUseCase instance.
+func NewEditEnvUseCase(
+	vaultService service.VaultServiceInterface,
+	importService service.ImportService,
+	editorService service.EditorService,
+	auditLog service.AuditLog,
+) EditEnvUc {
+	return &EditEnvUseCase{vaultService, importService, editorService, auditLog}
+}
+
+// Execute decrypts the vault into the editor and, once confirmed, saves the entries that
+// were added, changed or removed. Unchanged entries keep their value and timestamps. Opening
+// the editor is recorded as an export, and the saved changes as set and delete.
+func (useCase *EditEnvUseCase) Execute(
+	ctx context.Context,
+	env string,
+	format value.FileFormat,
+	confirm EditConfirmFunc,
+) (EditResult, error) {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return EditResult{}, fmt.Errorf("failed to open vault for environment %s: %w", env, err)
+	}
+	defer vault.Lock()
+
+	keys := make([]string, 0, len(vault.Entries))
+	current := make(map[string]string, len(vault.Entries))
+	for key, entry := range vault.Entries {
+		keys = append(keys, key)
+		decrypted, err := vault.Session().Decrypt(key, entry.Value)
+		if err != nil {
+			return EditResult{}, fmt.Errorf("failed to decrypt value of key %q: %w", key, err)
+		}
+		current[key] = string(decrypted)
+	}
+
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditExport, keys...); err != nil {
+		return EditResult{}, err
+	}
+
+	content, err := encodeForEditing(env, current, format)
+	if err != nil {
+		return EditResult{}, err
+	}
+
+	edited, err := useCase.editorService.Edit(env+"."+editorExtension(format), content)
+	if err != nil {
+		return EditResult{}, err
+	}
+
+	var entries map[string]string
+	if format.IsJSON() {
+		entries, err = useCase.importService.FromJSON(bytes.NewReader(edited))
+	} else {
+		entries, err = useCase.importService.FromDotEnv(bytes.NewReader(edited))
+	}
+	if err != nil {
+		return EditResult{}, fmt.Errorf("failed to parse edited entries: %w", err)
+	}
+
+	result := diffEntries(current, entries)
+	if !result.HasChanges() {
+		return result, nil
+	}
+
+	confirmed, err := confirm(result)
+	if err != nil || !confirmed {
+		return result, err
+	}
+
+	if err := applyEdit(vault, entries, result); err != nil {
+		return result, err
+	}
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return result, fmt.Errorf("failed to save vault: %w", err)
+	}
+	result.Saved = true
+
+	if err := useCase.recordEdit(ctx, vault, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// recordEdit records the saved changes of an edit in the audit log
+func (useCase *EditEnvUseCase) recordEdit(
+	ctx context.Context,
+	vault *model.Vault,
+	result EditResult,
+) error {
+	if set := append(append([]string{}, result.Added...), result.Changed...); len(set) > 0 {
+		if err := useCase.auditLog.Record(ctx, vault, model.AuditSet, set...); err != nil {
+			return err
+		}
+	}
+	if len(result.Removed) > 0 {
+		return useCase.auditLog.Record(ctx, vault, model.AuditDelete, result.Removed...)
+	}
+	return nil
+}
+
+// encodeForEditing writes the entries sorted by key in the given format
+func encodeForEditing(
+	env string,
+	entries map[string]string,
+	format value.FileFormat,
+) ([]byte, error) {
+	if format.IsJSON() {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal entries: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Entries of environment %s. Lines starting with # are ignored.\n", env)
+	fmt.Fprintf(&buf, "# Save and close the editor to review the changes.\n")
+	for _, key := range keys {
+		entryValue := entries[key]
+		if strings.ContainsAny(entryValue, "\r\n") {
+			return nil, fmt.Errorf(
+				"value of key %q spans several lines, edit with --format json instead",
+				key,
+			)
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, quoteDotEnvValue(entryValue))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// quoteDotEnvValue quotes values whose surrounding whitespace or quotes would otherwise be
+// stripped when the dotenv file is parsed
+func quoteDotEnvValue(entryValue string) string {
+	if strings.TrimSpace(entryValue) != entryValue {
+		return `"` + entryValue + `"`
+	}
+	if len(entryValue) >= 2 {
+		first, last := entryValue[0], entryValue[len(entryValue)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return `"` + entryValue + `"`
+		}
+	}
+	return entryValue
+}
+
+func editorExtension(format value.FileFormat) string {
+	if format.IsJSON() {
+		return "json"
+	}
+	return "env"
+}
+
+// diffEntries compares the entries before and after editing
+func diffEntries(before, after map[string]string) EditResult {
+	result := EditResult{}
+	for key, newValue := range after {
+		oldValue, exists := before[key]
+		switch {
+		case !exists:
+			result.Added = append(result.Added, key)
+		case oldValue != newValue:
+			result.Changed = append(result.Changed, key)
+		}
+	}
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Changed)
+	sort.Strings(result.Removed)
+	return result
+}
+
+// applyEdit re-encrypts the added and changed entries and deletes the removed ones
+func applyEdit(vault *model.Vault, entries map[string]string, result EditResult) error {
+	for _, key := range append(append([]string{}, result.Added...), result.Changed...) {
+		encryptedValue, err := vault.Session().Encrypt(key, []byte(entries[key]))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt value of key %q: %w", key, err)
+		}
+		if err := vault.SetEntry(key, encryptedValue); err != nil {
+			return fmt.Errorf("failed to set key %q: %w", key, err)
+		}
+	}
+
+	for _, key := range result.Removed {
+		if err := vault.DeleteEntry(key); err != nil {
+			return fmt.Errorf("failed to delete key %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
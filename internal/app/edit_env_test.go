@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
+	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/fs"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func confirmEdit(confirmed bool) EditConfirmFunc {
+	return func(result EditResult) (bool, error) {
+		return confirmed, nil
+	}
+}
+
+func TestEditEnvUseCase_Execute_AppliesOnlyChanges(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		envTest: {"KEEP": "same", "CHANGE": "old", "REMOVE": "gone"},
+	})
+	var savedVault *model.Vault
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		savedVault = vault
+		return nil
+	}
+	var editedContent string
+	editor := &test.MockEditorService{
+		EditFunc: func(name string, content []byte) ([]byte, error) {
+			editedContent = string(content)
+			assert.Equal(t, envTest+".env", name)
+			return []byte("KEEP=same\nCHANGE=new\nADD=\"  spaced  \"\n"), nil
+		},
+	}
+
+	useCase := NewEditEnvUseCase(vaultService, fs.NewImportService(), editor, &test.MockAuditLog{})
+	result, err := useCase.Execute(context.Background(), envTest, value.DotEnv, confirmEdit(true))
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+
+	assert.Contains(t, "CHANGE=old\nKEEP=same\nREMOVE=gone\n", editedContent)
+	assert.DeepEqual(t, []string{"ADD"}, result.Added)
+	assert.DeepEqual(t, []string{"CHANGE"}, result.Changed)
+	assert.DeepEqual(t, []string{"REMOVE"}, result.Removed)
+	assert.True(t, result.Saved, "Execute() should report the changes as saved")
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+
+	assert.Equal(t, test.FixtureTimestamp, savedVault.Entries["KEEP"].UpdatedAt)
+	assert.NotEqual(t, test.FixtureTimestamp, savedVault.Entries["CHANGE"].UpdatedAt)
+	assert.Equal(t, test.FixtureTimestamp, savedVault.Entries["CHANGE"].CreatedAt)
+	assert.Equal(t, envTest+":  spaced  ", savedVault.Entries["ADD"].Value)
+	_, err = savedVault.GetEntry("REMOVE")
+	assert.NotNil(t, err, "Execute() should remove deleted keys")
+}
+
+func TestEditEnvUseCase_Execute_Declined(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		envTest: {keyTest: valueTest},
+	})
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		t.Error("Save() should not be called when the changes are declined")
+		return nil
+	}
+	editor := &test.MockEditorService{
+		EditFunc: func(name string, content []byte) ([]byte, error) {
+			return []byte("{}"), nil
+		},
+	}
+
+	useCase := NewEditEnvUseCase(vaultService, fs.NewImportService(), editor, &test.MockAuditLog{})
+	result, err := useCase.Execute(context.Background(), envTest, value.JSON, confirmEdit(false))
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{keyTest}, result.Removed)
+	assert.False(t, result.Saved, "Execute() should not report declined changes as saved")
+}
+
+func TestEditEnvUseCase_Execute_NoChanges(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		envTest: {keyTest: `"quoted"`, "SPACE": " padded"},
+	})
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		t.Error("Save() should not be called without changes")
+		return nil
+	}
+	confirm := func(result EditResult) (bool, error) {
+		t.Error("confirm should not be asked without changes")
+		return false, nil
+	}
+
+	useCase := NewEditEnvUseCase(
+		vaultService,
+		fs.NewImportService(),
+		&test.MockEditorService{},
+		&test.MockAuditLog{},
+	)
+	result, err := useCase.Execute(context.Background(), envTest, value.DotEnv, confirm)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.False(t, result.HasChanges(), "an unedited file should round-trip without changes")
+}
+
+func TestEditEnvUseCase_Execute_MultilineValueNeedsJSON(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		envTest: {keyTest: "line1\nline2"},
+	})
+	editor := &test.MockEditorService{
+		EditFunc: func(name string, content []byte) ([]byte, error) {
+			t.Error("Edit() should not be called for values dotenv cannot hold")
+			return content, nil
+		},
+	}
+
+	useCase := NewEditEnvUseCase(vaultService, fs.NewImportService(), editor, &test.MockAuditLog{})
+	_, err := useCase.Execute(context.Background(), envTest, value.DotEnv, confirmEdit(true))
+	assert.NotNil(t, err, "Execute() with a multi-line value expected error, got nil")
+	assert.Contains(t, "--format json", err.Error())
+
+	useCase = NewEditEnvUseCase(
+		vaultService,
+		fs.NewImportService(),
+		&test.MockEditorService{},
+		&test.MockAuditLog{},
+	)
+	result, err := useCase.Execute(context.Background(), envTest, value.JSON, confirmEdit(true))
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.False(t, result.HasChanges(), "a multi-line value should round-trip through JSON")
+}
+
+func TestEditEnvUseCase_Execute_EditorError(t *testing.T) {
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{envTest: nil})
+	editor := &test.MockEditorService{
+		EditFunc: func(name string, content []byte) ([]byte, error) {
+			return nil, errors.New("editor failed")
+		},
+	}
+
+	useCase := NewEditEnvUseCase(vaultService, fs.NewImportService(), editor, &test.MockAuditLog{})
+	_, err := useCase.Execute(context.Background(), envTest, value.DotEnv, confirmEdit(true))
+	assert.NotNil(t, err, "Execute() with editor error expected error, got nil")
+	assert.Contains(t, "editor failed", err.Error())
+}
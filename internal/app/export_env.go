@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
@@ -17,21 +18,22 @@ type ExportEnvUc interface {
 
 // ExportEnvUseCase implements the use case for exporting vault entries in various formats.
 type ExportEnvUseCase struct {
-	vaultService      service.VaultServiceInterface
-	encryptionService service.EncryptionService
-	logger            domain.Logger
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+	logger       domain.Logger
 }
 
 // NewExportEnvUseCase creates a new ExportEnvUseCase instance.
 func NewExportEnvUseCase(
 	vaultService service.VaultServiceInterface,
-	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
 	logger domain.Logger,
 ) ExportEnvUc {
-	return &ExportEnvUseCase{vaultService, encryptionService, logger}
+	return &ExportEnvUseCase{vaultService, auditLog, logger}
 }
 
-// Execute exports all entries from the vault in the specified format.
+// Execute exports all entries from the vault in the specified format, after recording the
+// export in the audit log and warning about the entries overdue for rotation.
 func (useCase *ExportEnvUseCase) Execute(
 	ctx context.Context,
 	env string,
@@ -41,14 +43,21 @@ func (useCase *ExportEnvUseCase) Execute(
 	if err != nil {
 		return err
 	}
+	defer vault.Lock()
 
+	keys := make([]string, 0, len(vault.Entries))
+	for key := range vault.Entries {
+		keys = append(keys, key)
+	}
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditExport, keys...); err != nil {
+		return err
+	}
+	warnExpired(useCase.logger, vault, keys)
+
+	session := vault.Session()
 	if exportFormat.IsDotEnv() {
 		for k, v := range vault.Entries {
-			decryptedVal, err := useCase.encryptionService.Decrypt(
-				v.Value,
-				vault.Meta.Salt,
-				vault.Passphrase(),
-			)
+			decryptedVal, err := session.Decrypt(k, v.Value)
 			if err != nil {
 				return fmt.Errorf("failed to decrypt value: %v", err)
 			}
@@ -57,11 +66,7 @@ func (useCase *ExportEnvUseCase) Execute(
 	} else {
 		mappedEntries := make(map[string]string)
 		for k, v := range vault.Entries {
-			decryptedVal, err := useCase.encryptionService.Decrypt(
-				v.Value,
-				vault.Meta.Salt,
-				vault.Passphrase(),
-			)
+			decryptedVal, err := session.Decrypt(k, v.Value)
 			if err != nil {
 				return fmt.Errorf("failed to decrypt value: %v", err)
 			}
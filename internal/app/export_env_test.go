@@ -6,28 +6,39 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/security"
 	"github.com/ahmed-abdelgawad92/lockify/test"
 	"github.com/ahmed-abdelgawad92/lockify/test/assert"
 )
 
-func TestExportEnvUseCase_Execute_Json(t *testing.T) {
-	vaultService := &test.MockVaultService{
+func newExportTestVaultService(session *test.MockSession) *test.MockVaultService {
+	return &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			vault, _ := model.NewVault(envTest, saltTest)
 			vault.SetPassphrase(passphraseTest)
+			vault.SetSession(session)
 			vault.SetEntry(keyTest, valueTest)
 			return vault, nil
 		},
 	}
-	loggerService := &test.MockLogger{}
-	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
+}
+
+func TestExportEnvUseCase_Execute_Json(t *testing.T) {
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
 			return []byte(valueTest), nil
 		},
 	}
+	loggerService := &test.MockLogger{}
 
-	useCase := NewExportEnvUseCase(vaultService, encryptionService, loggerService)
+	useCase := NewExportEnvUseCase(
+		newExportTestVaultService(session),
+		&test.MockAuditLog{},
+		loggerService,
+	)
 
 	useCase.Execute(context.Background(), envTest, "json")
 
@@ -42,22 +53,18 @@ func TestExportEnvUseCase_Execute_Json(t *testing.T) {
 }
 
 func TestExportEnvUseCase_Execute_Dotenv(t *testing.T) {
-	vaultService := &test.MockVaultService{
-		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
-			vault.SetPassphrase(passphraseTest)
-			vault.SetEntry(keyTest, valueTest)
-			return vault, nil
-		},
-	}
-	loggerService := &test.MockLogger{}
-	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
 			return []byte(valueTest), nil
 		},
 	}
+	loggerService := &test.MockLogger{}
 
-	useCase := NewExportEnvUseCase(vaultService, encryptionService, loggerService)
+	useCase := NewExportEnvUseCase(
+		newExportTestVaultService(session),
+		&test.MockAuditLog{},
+		loggerService,
+	)
 
 	useCase.Execute(context.Background(), envTest, "dotenv")
 
@@ -65,3 +72,110 @@ func TestExportEnvUseCase_Execute_Dotenv(t *testing.T) {
 	got := loggerService.OutputLogs[0]
 	assert.Equal(t, want, got)
 }
+
+func TestExportEnvUseCase_Execute_LocksVault(t *testing.T) {
+	session := &test.MockSession{}
+
+	useCase := NewExportEnvUseCase(
+		newExportTestVaultService(session),
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "dotenv")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, session.Closed, "Execute() should close the vault session when done")
+}
+
+// BenchmarkExportEnvUseCase_Execute runs a full export against real Argon2id and
+// AES-GCM. The key is derived once per export, so the time per operation should
+// stay roughly flat as the number of entries grows.
+func BenchmarkExportEnvUseCase_Execute(b *testing.B) {
+	for _, size := range []int{1, 10, 50, 150} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			vaultService := newBenchmarkVaultService(b, size)
+			useCase := NewExportEnvUseCase(vaultService, &test.MockAuditLog{}, &test.MockLogger{})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := useCase.Execute(context.Background(), envTest, "dotenv"); err != nil {
+					b.Fatalf("Execute() returned unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// TestExportEnvUseCase_Execute_BenchmarkVault keeps the vault of the export benchmark
+// openable, as benchmarks do not run with the tests.
+func TestExportEnvUseCase_Execute_BenchmarkVault(t *testing.T) {
+	useCase := NewExportEnvUseCase(
+		newBenchmarkVaultService(t, 2),
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "dotenv")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+}
+
+// newBenchmarkVaultService returns a vault service with real encryption whose repository
+// holds a vault of size entries, created and sealed by the service itself so that it carries
+// a wrapped data key and a MAC.
+func newBenchmarkVaultService(tb testing.TB, size int) service.VaultServiceInterface {
+	tb.Helper()
+
+	var stored []byte
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return stored != nil, nil
+		},
+		CreateFunc: func(ctx context.Context, vault *model.Vault) error {
+			var err error
+			stored, err = json.Marshal(vault)
+			return err
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			var vault model.Vault
+			if err := json.Unmarshal(stored, &vault); err != nil {
+				return nil, err
+			}
+			return &vault, nil
+		},
+	}
+	vaultService := service.NewVaultService(
+		repo,
+		&test.MockPassphraseService{
+			GetFunc: func(ctx context.Context, env string) (string, error) {
+				return passphraseTest, nil
+			},
+		},
+		security.NewAESEncryptionService(config.DefaultEncryptionConfig()),
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.New(context.Background(), envTest, passphraseTest)
+	if err != nil {
+		tb.Fatalf("New() returned unexpected error: %v", err)
+	}
+	defer vault.Lock()
+	for i := 0; i < size; i++ {
+		key := fmt.Sprintf("KEY_%d", i)
+		ciphertext, err := vault.Session().Encrypt(key, []byte(valueTest))
+		if err != nil {
+			tb.Fatalf("Encrypt() returned unexpected error: %v", err)
+		}
+		if err := vault.SetEntry(key, ciphertext); err != nil {
+			tb.Fatalf("SetEntry() returned unexpected error: %v", err)
+		}
+	}
+	if err := vaultService.SaveNew(context.Background(), vault); err != nil {
+		tb.Fatalf("SaveNew() returned unexpected error: %v", err)
+	}
+
+	return vaultService
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// ForgetAgentKeysUc defines the interface for dropping vault keys held by the agent.
+type ForgetAgentKeysUc interface {
+	Execute(ctx context.Context, env string) (int, error)
+}
+
+// ForgetAgentKeysUseCase implements the use case for dropping vault keys held by the agent.
+type ForgetAgentKeysUseCase struct {
+	keyAgent service.KeyAgent
+}
+
+// NewForgetAgentKeysUseCase creates a new ForgetAgentKeysUseCase instance.
+func NewForgetAgentKeysUseCase(keyAgent service.KeyAgent) ForgetAgentKeysUc {
+	return &ForgetAgentKeysUseCase{keyAgent}
+}
+
+// Execute drops the keys the agent holds for env, or every key when env is empty, and
+// returns how many were dropped.
+func (useCase *ForgetAgentKeysUseCase) Execute(ctx context.Context, env string) (int, error) {
+	if env == "" {
+		return useCase.keyAgent.Lock(ctx)
+	}
+	if err := model.ValidateEnvName(env); err != nil {
+		return 0, err
+	}
+	return useCase.keyAgent.Forget(ctx, env)
+}
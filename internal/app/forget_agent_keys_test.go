@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestForgetAgentKeysUseCase_Execute_Env(t *testing.T) {
+	forgotten := ""
+	keyAgent := &test.MockKeyAgent{
+		ForgetFunc: func(ctx context.Context, env string) (int, error) {
+			forgotten = env
+			return 2, nil
+		},
+		LockFunc: func(ctx context.Context) (int, error) {
+			t.Error("Execute() with an env should not lock the whole agent")
+			return 0, nil
+		},
+	}
+
+	dropped, err := NewForgetAgentKeysUseCase(keyAgent).Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, envTest, forgotten, "Execute() should forget the keys of the env")
+}
+
+func TestForgetAgentKeysUseCase_Execute_All(t *testing.T) {
+	keyAgent := &test.MockKeyAgent{
+		LockFunc: func(ctx context.Context) (int, error) {
+			return 3, nil
+		},
+	}
+
+	dropped, err := NewForgetAgentKeysUseCase(keyAgent).Execute(context.Background(), "")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 3, dropped, "Execute() without an env should drop every key")
+}
+
+func TestForgetAgentKeysUseCase_Execute_Errors(t *testing.T) {
+	keyAgent := &test.MockKeyAgent{
+		ForgetFunc: func(ctx context.Context, env string) (int, error) {
+			return 0, errors.New("agent error")
+		},
+	}
+	useCase := NewForgetAgentKeysUseCase(keyAgent)
+
+	_, err := useCase.Execute(context.Background(), "../prod")
+	assert.NotNil(t, err, "Execute() with an invalid env expected error, got nil")
+
+	_, err = useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with an agent error expected error, got nil")
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// GenerateIdentityUc defines the interface for creating the local identity.
+type GenerateIdentityUc interface {
+	Execute(ctx context.Context, overwrite bool) (publicKey, path string, err error)
+}
+
+// GenerateIdentityUseCase implements the use case for creating the local identity.
+type GenerateIdentityUseCase struct {
+	encryptionService service.EncryptionService
+	identityRepo      repository.IdentityRepository
+}
+
+// NewGenerateIdentityUseCase creates a new GenerateIdentityUseCase instance.
+func NewGenerateIdentityUseCase(
+	encryptionService service.EncryptionService,
+	identityRepo repository.IdentityRepository,
+) GenerateIdentityUc {
+	return &GenerateIdentityUseCase{encryptionService, identityRepo}
+}
+
+// Execute generates a new key pair, stores it as the local identity and returns its
+// public key, which is shared with vault owners, and the identity file path.
+func (useCase *GenerateIdentityUseCase) Execute(
+	ctx context.Context,
+	overwrite bool,
+) (string, string, error) {
+	identity, err := useCase.encryptionService.GenerateIdentity()
+	if err != nil {
+		return "", "", err
+	}
+
+	path, err := useCase.identityRepo.Save(ctx, identity, overwrite)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to save identity: %w", err)
+	}
+
+	return identity.PublicKey, path, nil
+}
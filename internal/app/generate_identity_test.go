@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestGenerateIdentityUseCase_Execute_Success(t *testing.T) {
+	var savedIdentity model.Identity
+	identityRepo := &test.MockIdentityRepository{
+		SaveFunc: func(
+			ctx context.Context,
+			identity model.Identity,
+			overwrite bool,
+		) (string, error) {
+			savedIdentity = identity
+			assert.True(t, overwrite, "Execute() should pass overwrite through")
+			return "/home/test/.config/lockify/identity.txt", nil
+		},
+	}
+
+	useCase := NewGenerateIdentityUseCase(&test.MockEncryptionService{}, identityRepo)
+
+	publicKey, path, err := useCase.Execute(context.Background(), true)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, "lockify1-test", publicKey)
+	assert.Equal(t, "/home/test/.config/lockify/identity.txt", path)
+	assert.Equal(t, "LOCKIFY-SECRET-KEY-1-test", savedIdentity.PrivateKey)
+}
+
+func TestGenerateIdentityUseCase_Execute_GenerateError(t *testing.T) {
+	encryptionService := &test.MockEncryptionService{
+		GenerateIdentityFunc: func() (model.Identity, error) {
+			return model.Identity{}, errors.New("generate error")
+		},
+	}
+	identityRepo := &test.MockIdentityRepository{
+		SaveFunc: func(
+			ctx context.Context,
+			identity model.Identity,
+			overwrite bool,
+		) (string, error) {
+			t.Error("Save() should not be called when no identity was generated")
+			return "", nil
+		},
+	}
+
+	useCase := NewGenerateIdentityUseCase(encryptionService, identityRepo)
+
+	_, _, err := useCase.Execute(context.Background(), false)
+	assert.NotNil(t, err, "Execute() with generate error expected error, got nil")
+	assert.Equal(t, "generate error", err.Error())
+}
+
+func TestGenerateIdentityUseCase_Execute_SaveError(t *testing.T) {
+	identityRepo := &test.MockIdentityRepository{
+		SaveFunc: func(
+			ctx context.Context,
+			identity model.Identity,
+			overwrite bool,
+		) (string, error) {
+			return "", errors.New("identity already exists")
+		},
+	}
+
+	useCase := NewGenerateIdentityUseCase(&test.MockEncryptionService{}, identityRepo)
+
+	_, _, err := useCase.Execute(context.Background(), false)
+	assert.NotNil(t, err, "Execute() with save error expected error, got nil")
+	assert.Contains(t, "failed to save identity: identity already exists", err.Error())
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// GetAgentStatusUc defines the interface for describing the running agent.
+type GetAgentStatusUc interface {
+	Execute(ctx context.Context) (model.AgentStatus, error)
+}
+
+// GetAgentStatusUseCase implements the use case for describing the running agent.
+type GetAgentStatusUseCase struct {
+	keyAgent service.KeyAgent
+}
+
+// NewGetAgentStatusUseCase creates a new GetAgentStatusUseCase instance.
+func NewGetAgentStatusUseCase(keyAgent service.KeyAgent) GetAgentStatusUc {
+	return &GetAgentStatusUseCase{keyAgent}
+}
+
+// Execute returns the status of the agent and the environments it holds keys for.
+func (useCase *GetAgentStatusUseCase) Execute(ctx context.Context) (model.AgentStatus, error) {
+	return useCase.keyAgent.Status(ctx)
+}
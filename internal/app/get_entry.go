@@ -3,6 +3,8 @@ package app
 import (
 	"context"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
 
@@ -13,38 +15,43 @@ type GetEntryUc interface {
 
 // GetEntryUseCase implements the use case for retrieving entries from the vault.
 type GetEntryUseCase struct {
-	vaultService      service.VaultServiceInterface
-	encryptionService service.EncryptionService
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+	logger       domain.Logger
 }
 
 // NewGetEntryUseCase creates a new GetEntryUseCase instance.
 func NewGetEntryUseCase(
 	vaultService service.VaultServiceInterface,
-	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
+	logger domain.Logger,
 ) GetEntryUc {
-	return &GetEntryUseCase{vaultService, encryptionService}
+	return &GetEntryUseCase{vaultService, auditLog, logger}
 }
 
-// Execute retrieves and decrypts an entry from the vault.
+// Execute retrieves and decrypts an entry from the vault. The value is only returned once
+// the read is recorded in the audit log, with a warning when it is overdue for rotation.
 func (useCase *GetEntryUseCase) Execute(ctx context.Context, env, key string) (string, error) {
 	vault, err := useCase.vaultService.Open(ctx, env)
 	if err != nil {
 		return "", err
 	}
+	defer vault.Lock()
 
 	entry, err := vault.GetEntry(key)
 	if err != nil {
 		return "", err
 	}
 
-	value, err := useCase.encryptionService.Decrypt(
-		entry.Value,
-		vault.Meta.Salt,
-		vault.Passphrase(),
-	)
+	value, err := vault.Session().Decrypt(key, entry.Value)
 	if err != nil {
 		return "", err
 	}
 
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditGet, key); err != nil {
+		return "", err
+	}
+	warnExpired(useCase.logger, vault, []string{key})
+
 	return string(value), nil
 }
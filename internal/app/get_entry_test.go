@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -12,23 +13,24 @@ import (
 )
 
 func TestGetEntryUseCase_Execute_Success(t *testing.T) {
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			decodedValue, _ := base64.StdEncoding.DecodeString(ciphertext)
+			return []byte(decodedValue), nil
+		},
+	}
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			savedVault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			savedVault, _ := model.NewVault(envTest, saltTest)
 			savedVault.SetPassphrase(passphraseTest)
+			savedVault.SetSession(session)
 			savedVault.SetEntry(keyTest, base64.StdEncoding.EncodeToString([]byte(valueTest)))
 			return savedVault, nil
 		},
 	}
 
-	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
-			decodedValue, _ := base64.StdEncoding.DecodeString(ciphertext)
-			return []byte(decodedValue), nil
-		},
-	}
-
-	useCase := NewGetEntryUseCase(vaultService, encryptionService)
+	auditLog := &test.MockAuditLog{}
+	useCase := NewGetEntryUseCase(vaultService, auditLog, &test.MockLogger{})
 
 	valueRetrieved, err := useCase.Execute(context.Background(), envTest, keyTest)
 	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
@@ -38,25 +40,80 @@ func TestGetEntryUseCase_Execute_Success(t *testing.T) {
 		valueRetrieved,
 		fmt.Sprintf("Execute() got %s, want %s", valueRetrieved, valueTest),
 	)
+	assert.Equal(t, 1, len(auditLog.Recorded), "Execute() should record the read")
+	assert.Equal(t, model.AuditGet, auditLog.Recorded[0].Operation)
+	assert.Contains(t, keyTest, auditLog.Recorded[0].Keys)
 }
 
-func TestGetEntryUseCase_Execute_EntryNotFound(t *testing.T) {
+func TestGetEntryUseCase_Execute_AuditError(t *testing.T) {
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			savedVault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
-			savedVault.SetPassphrase(passphraseTest)
+			savedVault, _ := model.NewVault(envTest, saltTest)
+			savedVault.SetSession(&test.MockSession{})
+			savedVault.SetEntry(keyTest, encryptedValueTest)
 			return savedVault, nil
 		},
 	}
+	auditLog := &test.MockAuditLog{
+		RecordFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			operation model.AuditOperation,
+			keys ...string,
+		) error {
+			return errors.New("audit log is not writable")
+		},
+	}
+
+	useCase := NewGetEntryUseCase(vaultService, auditLog, &test.MockLogger{})
+
+	value, err := useCase.Execute(context.Background(), envTest, keyTest)
+	assert.NotNil(t, err, "Execute() should fail when the read cannot be recorded")
+	assert.Equal(t, "", value, "Execute() should not reveal a value that was not recorded")
+}
 
-	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
+func TestGetEntryUseCase_Execute_Tampered(t *testing.T) {
+	receivedKey := ""
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			receivedKey = key
+			return nil, fmt.Errorf("decryption failed for key %q: %w", key, model.ErrTampered)
+		},
+	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			savedVault, _ := model.NewVault(envTest, saltTest)
+			savedVault.SetSession(session)
+			savedVault.SetEntry(keyTest, encryptedValueTest)
+			return savedVault, nil
+		},
+	}
+
+	useCase := NewGetEntryUseCase(vaultService, &test.MockAuditLog{}, &test.MockLogger{})
+
+	_, err := useCase.Execute(context.Background(), envTest, keyTest)
+	assert.NotNil(t, err, "Execute() with a tampered entry expected error, got nil")
+	assert.True(t, errors.Is(err, model.ErrTampered), "Execute() should report tampering")
+	assert.Equal(t, keyTest, receivedKey, "Execute() should decrypt under the requested key")
+}
+
+func TestGetEntryUseCase_Execute_EntryNotFound(t *testing.T) {
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
 			decodedValue, _ := base64.StdEncoding.DecodeString(ciphertext)
 			return []byte(decodedValue), nil
 		},
 	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			savedVault, _ := model.NewVault(envTest, saltTest)
+			savedVault.SetPassphrase(passphraseTest)
+			savedVault.SetSession(session)
+			return savedVault, nil
+		},
+	}
 
-	useCase := NewGetEntryUseCase(vaultService, encryptionService)
+	useCase := NewGetEntryUseCase(vaultService, &test.MockAuditLog{}, &test.MockLogger{})
 
 	_, err := useCase.Execute(context.Background(), envTest, keyTest)
 	assert.NotNil(t, err, "Execute() should return non-existence error, got nil")
@@ -71,3 +128,22 @@ func TestGetEntryUseCase_Execute_EntryNotFound(t *testing.T) {
 		),
 	)
 }
+
+func TestGetEntryUseCase_Execute_WarnsWhenOverdue(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newStaleVault(env), nil
+		},
+	}
+	logger := &test.MockLogger{}
+	useCase := NewGetEntryUseCase(vaultService, &test.MockAuditLog{}, logger)
+
+	_, err := useCase.Execute(context.Background(), envTest, "FRESH_KEY")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 0, logger.WarningLogs)
+
+	_, err = useCase.Execute(context.Background(), envTest, "OLD_KEY")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 1, logger.WarningLogs)
+	assert.Contains(t, "OLD_KEY", logger.WarningLogs[0])
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model/value"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
@@ -23,20 +24,20 @@ type ImportEnvUc interface {
 
 // ImportEnvUseCase implements the use case for importing entries into the vault.
 type ImportEnvUseCase struct {
-	vaultService      service.VaultServiceInterface
-	importService     service.ImportService
-	encryptionService service.EncryptionService
-	logger            domain.Logger
+	vaultService  service.VaultServiceInterface
+	importService service.ImportService
+	auditLog      service.AuditLog
+	logger        domain.Logger
 }
 
 // NewImportEnvUseCase creates a new ImportEnvUseCase instance.
 func NewImportEnvUseCase(
 	vaultService service.VaultServiceInterface,
 	importService service.ImportService,
-	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
 	logger domain.Logger,
 ) ImportEnvUc {
-	return &ImportEnvUseCase{vaultService, importService, encryptionService, logger}
+	return &ImportEnvUseCase{vaultService, importService, auditLog, logger}
 }
 
 // Execute imports entries from a reader into the vault.
@@ -47,10 +48,11 @@ func (uc *ImportEnvUseCase) Execute(
 	r io.Reader,
 	overwrite bool,
 ) (imported, skipped int, err error) {
-	vault, err := uc.vaultService.Open(ctx, env)
+	vault, err := uc.vaultService.OpenForUpdate(ctx, env)
 	if err != nil {
 		return 0, 0, fmt.Errorf("couln't open vault for env %s: %w", env, err)
 	}
+	defer vault.Lock()
 
 	var entries map[string]string
 	switch format {
@@ -70,6 +72,7 @@ func (uc *ImportEnvUseCase) Execute(
 		return imported, skipped, fmt.Errorf("no entries found in file")
 	}
 
+	importedKeys := make([]string, 0, len(entries))
 	for key, value := range entries {
 		_, err := vault.GetEntry(key)
 		if err == nil && !overwrite {
@@ -78,11 +81,7 @@ func (uc *ImportEnvUseCase) Execute(
 			continue
 		}
 
-		encryptedValue, err := uc.encryptionService.Encrypt(
-			[]byte(value),
-			vault.Meta.Salt,
-			vault.Passphrase(),
-		)
+		encryptedValue, err := vault.Session().Encrypt(key, []byte(value))
 		if err != nil {
 			return imported, skipped, fmt.Errorf("failed to encrypt value: %w", err)
 		}
@@ -90,6 +89,7 @@ func (uc *ImportEnvUseCase) Execute(
 		if err := vault.SetEntry(key, encryptedValue); err != nil {
 			return imported, skipped, fmt.Errorf("failed to import key %q: %w", key, err)
 		}
+		importedKeys = append(importedKeys, key)
 		imported++
 	}
 
@@ -98,6 +98,9 @@ func (uc *ImportEnvUseCase) Execute(
 		if err != nil {
 			return imported, skipped, fmt.Errorf("failed to save vault: %w", err)
 		}
+		if err := uc.auditLog.Record(ctx, vault, model.AuditSet, importedKeys...); err != nil {
+			return imported, skipped, err
+		}
 	}
 
 	return imported, skipped, nil
@@ -21,8 +21,13 @@ func TestImportEnvUseCase_Execute_Json(t *testing.T) {
 	var savedVault *model.Vault
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			vault, _ := model.NewVault(envTest, saltTest)
 			vault.SetPassphrase(passphraseTest)
+			vault.SetSession(&test.MockSession{
+				EncryptFunc: func(key string, plaintext []byte) (string, error) {
+					return "encrypted-" + string(plaintext), nil
+				},
+			})
 			return vault, nil
 		},
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
@@ -37,15 +42,9 @@ func TestImportEnvUseCase_Execute_Json(t *testing.T) {
 		},
 	}
 
-	encryptionService := &test.MockEncryptionService{
-		EncryptFunc: func(plaintext []byte, encodedSalt, passphrase string) (string, error) {
-			return "encrypted-" + string(plaintext), nil
-		},
-	}
-
 	loggerService := &test.MockLogger{}
 
-	useCase := NewImportEnvUseCase(vaultService, importService, encryptionService, loggerService)
+	useCase := NewImportEnvUseCase(vaultService, importService, &test.MockAuditLog{}, loggerService)
 
 	jsonInput := `{"test-key": "test-value"}`
 	reader := strings.NewReader(jsonInput)
@@ -91,8 +90,13 @@ func TestImportEnvUseCase_Execute_Dotenv(t *testing.T) {
 	var savedVault *model.Vault
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			vault, _ := model.NewVault(envTest, saltTest)
 			vault.SetPassphrase(passphraseTest)
+			vault.SetSession(&test.MockSession{
+				EncryptFunc: func(key string, plaintext []byte) (string, error) {
+					return "encrypted-" + string(plaintext), nil
+				},
+			})
 			return vault, nil
 		},
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
@@ -107,15 +111,9 @@ func TestImportEnvUseCase_Execute_Dotenv(t *testing.T) {
 		},
 	}
 
-	encryptionService := &test.MockEncryptionService{
-		EncryptFunc: func(plaintext []byte, encodedSalt, passphrase string) (string, error) {
-			return "encrypted-" + string(plaintext), nil
-		},
-	}
-
 	loggerService := &test.MockLogger{}
 
-	useCase := NewImportEnvUseCase(vaultService, importService, encryptionService, loggerService)
+	useCase := NewImportEnvUseCase(vaultService, importService, &test.MockAuditLog{}, loggerService)
 
 	dotenvInput := "test-key=test-value"
 	reader := strings.NewReader(dotenvInput)
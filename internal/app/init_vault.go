@@ -15,17 +15,28 @@ type InitUc interface {
 // InitializeVaultUseCase implements the use case for initializing a new vault.
 type InitializeVaultUseCase struct {
 	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
 }
 
 // NewInitializeVaultUseCase creates a new InitializeVaultUseCase instance.
-func NewInitializeVaultUseCase(vaultService service.VaultServiceInterface) InitUc {
-	return &InitializeVaultUseCase{vaultService}
+func NewInitializeVaultUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) InitUc {
+	return &InitializeVaultUseCase{vaultService, auditLog}
 }
 
-// Execute initializes a new vault for the specified environment.
+// Execute initializes a new vault for the specified environment with an empty audit log.
 func (useCase *InitializeVaultUseCase) Execute(
 	ctx context.Context,
 	env string,
 ) (*model.Vault, error) {
-	return useCase.vaultService.Create(ctx, env)
+	vault, err := useCase.vaultService.Create(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	if err := startAuditLog(ctx, useCase.auditLog, vault, nil); err != nil {
+		return nil, err
+	}
+	return vault, nil
 }
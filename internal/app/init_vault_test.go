@@ -12,7 +12,7 @@ import (
 )
 
 func TestInitializeVaultUseCase_Execute_Success(t *testing.T) {
-	expectedVault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	expectedVault, _ := model.NewVault(envTest, saltTest)
 
 	vaultService := &test.MockVaultService{
 		CreateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
@@ -23,7 +23,7 @@ func TestInitializeVaultUseCase_Execute_Success(t *testing.T) {
 		},
 	}
 
-	useCase := NewInitializeVaultUseCase(vaultService)
+	useCase := NewInitializeVaultUseCase(vaultService, &test.MockAuditLog{})
 
 	vault, err := useCase.Execute(context.Background(), envTest)
 
@@ -35,16 +35,6 @@ func TestInitializeVaultUseCase_Execute_Success(t *testing.T) {
 		vault.Meta.Env,
 		fmt.Sprintf("Execute() returned vault with env %q, want %q", vault.Meta.Env, envTest),
 	)
-	assert.Equal(
-		t,
-		fingerprintTest,
-		vault.Meta.FingerPrint,
-		fmt.Sprintf(
-			"Execute() returned vault with fingerprint %q, want %q",
-			vault.Meta.FingerPrint,
-			fingerprintTest,
-		),
-	)
 	assert.Equal(
 		t,
 		saltTest,
@@ -62,7 +52,7 @@ func TestInitializeVaultUseCase_Execute_CreateError(t *testing.T) {
 		},
 	}
 
-	useCase := NewInitializeVaultUseCase(vaultService)
+	useCase := NewInitializeVaultUseCase(vaultService, &test.MockAuditLog{})
 
 	vault, err := useCase.Execute(context.Background(), envTest)
 
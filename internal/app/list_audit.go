@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// AuditReport holds the audit events of an environment and the result of verifying them.
+type AuditReport struct {
+	// Events are the verified events at or after the requested time, oldest first.
+	Events []model.AuditEvent
+	// Total is the number of records whose chain was verified.
+	Total int
+	// Broken describes the first record that breaks the chain, nil when it is intact.
+	Broken *model.AuditChainError
+}
+
+// ListAuditUc defines the interface for listing and verifying the audit log of a vault.
+type ListAuditUc interface {
+	Execute(ctx context.Context, env string, since time.Time) (AuditReport, error)
+}
+
+// ListAuditUseCase implements the use case for listing and verifying the audit log of a vault.
+type ListAuditUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewListAuditUseCase creates a new ListAuditUseCase instance.
+func NewListAuditUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) ListAuditUc {
+	return &ListAuditUseCase{vaultService, auditLog}
+}
+
+// Execute verifies the whole audit log of an environment and returns the events recorded at
+// or after since. A broken chain is reported in the report, along with the events before
+// the break, rather than as an error.
+func (useCase *ListAuditUseCase) Execute(
+	ctx context.Context,
+	env string,
+	since time.Time,
+) (AuditReport, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return AuditReport{}, err
+	}
+	defer vault.Lock()
+
+	events, err := useCase.auditLog.Events(ctx, vault)
+	var broken *model.AuditChainError
+	if err != nil && !errors.As(err, &broken) {
+		return AuditReport{}, err
+	}
+
+	report := AuditReport{Total: len(events), Broken: broken}
+	for _, event := range events {
+		recordedAt, err := time.Parse(time.RFC3339, event.Time)
+		if err == nil && recordedAt.Before(since) {
+			continue
+		}
+		report.Events = append(report.Events, event)
+	}
+
+	return report, nil
+}
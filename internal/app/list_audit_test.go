@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newAuditEvents returns get events recorded at noon on the first days of January 2026
+func newAuditEvents(days int) []model.AuditEvent {
+	events := make([]model.AuditEvent, 0, days)
+	for day := 1; day <= days; day++ {
+		events = append(events, model.AuditEvent{
+			Seq:       day,
+			Time:      time.Date(2026, 1, day, 12, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			Operation: model.AuditGet,
+		})
+	}
+	return events
+}
+
+func TestListAuditUseCase_Execute_Since(t *testing.T) {
+	january := func(day int) time.Time { return time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name  string
+		since time.Time
+		seqs  []int
+	}{
+		{name: "whole log", since: time.Time{}, seqs: []int{1, 2, 3}},
+		{name: "since a date", since: january(2), seqs: []int{2, 3}},
+		{name: "nothing new", since: january(31), seqs: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog := &test.MockAuditLog{Recorded: newAuditEvents(3)}
+			useCase := NewListAuditUseCase(&test.MockVaultService{}, auditLog)
+
+			report, err := useCase.Execute(context.Background(), envTest, tt.since)
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			assert.Equal(t, 3, report.Total, "Execute() should verify the whole log")
+			assert.Nil(t, report.Broken, "Execute() should report an intact chain")
+			assert.Count(t, len(tt.seqs), report.Events)
+			for i, event := range report.Events {
+				assert.Equal(t, tt.seqs[i], event.Seq)
+			}
+		})
+	}
+}
+
+func TestListAuditUseCase_Execute_BrokenChain(t *testing.T) {
+	auditLog := &test.MockAuditLog{
+		EventsFunc: func(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error) {
+			return newAuditEvents(2), &model.AuditChainError{Seq: 3, Reason: "tampered"}
+		},
+	}
+	useCase := NewListAuditUseCase(&test.MockVaultService{}, auditLog)
+
+	report, err := useCase.Execute(context.Background(), envTest, time.Time{})
+	assert.Nil(t, err, "Execute() should report a broken chain in the report")
+	assert.NotNil(t, report.Broken, "Execute() should report the broken chain")
+	assert.Equal(t, 3, report.Broken.Seq)
+	assert.Equal(t, 2, report.Total)
+	assert.Count(t, 2, report.Events)
+}
+
+func TestListAuditUseCase_Execute_Errors(t *testing.T) {
+	openErr := errors.New("wrong passphrase")
+	readErr := errors.New("permission denied")
+
+	tests := []struct {
+		name         string
+		vaultService *test.MockVaultService
+		auditLog     *test.MockAuditLog
+		want         error
+	}{
+		{
+			name: "open error",
+			vaultService: &test.MockVaultService{
+				OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return nil, openErr
+				},
+			},
+			auditLog: &test.MockAuditLog{},
+			want:     openErr,
+		},
+		{
+			name:         "read error",
+			vaultService: &test.MockVaultService{},
+			auditLog: &test.MockAuditLog{
+				EventsFunc: func(
+					ctx context.Context,
+					vault *model.Vault,
+				) ([]model.AuditEvent, error) {
+					return nil, readErr
+				},
+			},
+			want: readErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewListAuditUseCase(tt.vaultService, tt.auditLog)
+
+			_, err := useCase.Execute(context.Background(), envTest, time.Time{})
+			assert.True(t, errors.Is(err, tt.want), fmt.Sprintf("Execute() got error %v", err))
+		})
+	}
+}
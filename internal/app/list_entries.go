@@ -2,36 +2,97 @@ package app
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
 
 // ListEntriesUc defines the interface for listing entries in the vault.
 type ListEntriesUc interface {
-	Execute(ctx context.Context, env string) ([]string, error)
+	Execute(ctx context.Context, env string, filter EntryFilter) ([]EntryInfo, error)
 }
 
 // ListEntriesUseCase implements the use case for listing entries in the vault.
 type ListEntriesUseCase struct {
 	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// EntryFilter selects the entries to list; its zero value lists every entry.
+type EntryFilter struct {
+	// Tags lists tags that every listed entry must carry.
+	Tags []string
+	// Owner only lists the entries of an owner.
+	Owner string
+	// Secret only lists entries marked as secret.
+	Secret bool
+	// Expired only lists entries whose expiry has passed.
+	Expired bool
+}
+
+// EntryInfo describes an entry without its value.
+type EntryInfo struct {
+	Key       string
+	Secret    bool
+	UpdatedAt string
+	Metadata  model.EntryMetadata
 }
 
 // NewListEntriesUseCase creates a new ListEntriesUseCase instance.
-func NewListEntriesUseCase(vaultService service.VaultServiceInterface) ListEntriesUc {
-	return &ListEntriesUseCase{vaultService}
+func NewListEntriesUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) ListEntriesUc {
+	return &ListEntriesUseCase{vaultService, auditLog}
 }
 
-// Execute lists all entry keys in the vault for the specified environment.
-func (useCase *ListEntriesUseCase) Execute(ctx context.Context, env string) ([]string, error) {
+// Execute lists the entries of the vault for the specified environment that match filter,
+// sorted by key.
+func (useCase *ListEntriesUseCase) Execute(
+	ctx context.Context,
+	env string,
+	filter EntryFilter,
+) ([]EntryInfo, error) {
 	vault, err := useCase.vaultService.Open(ctx, env)
 	if err != nil {
 		return nil, err
 	}
+	defer vault.Lock()
+
+	now := time.Now()
+	entries := make([]EntryInfo, 0, len(vault.Entries))
+	for key, entry := range vault.Entries {
+		metadata, err := vault.EntryMetadata(key)
+		if err != nil {
+			return nil, err
+		}
+		info := EntryInfo{key, entry.Secret, entry.UpdatedAt, metadata}
+		if filter.matches(info, now) {
+			entries = append(entries, info)
+		}
+	}
+	slices.SortFunc(entries, func(a, b EntryInfo) int {
+		return strings.Compare(a.Key, b.Key)
+	})
 
-	keys := make([]string, 0, len(vault.Entries))
-	for k := range vault.Entries {
-		keys = append(keys, k)
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditOpen); err != nil {
+		return nil, err
 	}
 
-	return keys, nil
+	return entries, nil
+}
+
+// matches reports whether an entry passes every condition of the filter
+func (filter EntryFilter) matches(info EntryInfo, now time.Time) bool {
+	for _, tag := range filter.Tags {
+		if !info.Metadata.HasTag(tag) {
+			return false
+		}
+	}
+	return (filter.Owner == "" || info.Metadata.Owner == filter.Owner) &&
+		(!filter.Secret || info.Secret) &&
+		(!filter.Expired || info.Metadata.Expired(now))
 }
@@ -17,7 +17,7 @@ func TestListEntriesUseCase_Execute_Success(t *testing.T) {
 
 	vaultService := &test.MockVaultService{
 		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			savedVault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			savedVault, _ := model.NewVault(envTest, saltTest)
 			savedVault.SetPassphrase(passphraseTest)
 			savedVault.SetEntry(key1, base64.StdEncoding.EncodeToString([]byte(valueTest)))
 			savedVault.SetEntry(key2, base64.StdEncoding.EncodeToString([]byte(valueTest)))
@@ -25,11 +25,67 @@ func TestListEntriesUseCase_Execute_Success(t *testing.T) {
 		},
 	}
 
-	useCase := NewListEntriesUseCase(vaultService)
+	useCase := NewListEntriesUseCase(vaultService, &test.MockAuditLog{})
 
-	allKeys, err := useCase.Execute(context.Background(), envTest)
+	entries, err := useCase.Execute(context.Background(), envTest, EntryFilter{})
 	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
-	assert.Count(t, 2, allKeys, fmt.Sprintf("length of keys error, want: 2, got: %v", len(allKeys)))
-	assert.Contains(t, key1, allKeys, fmt.Sprintf("keys should contain %v", key1))
-	assert.Contains(t, key2, allKeys, fmt.Sprintf("keys should contain %v", key2))
+	assert.Count(t, 2, entries, fmt.Sprintf("length of keys error, want: 2, got: %v", len(entries)))
+	assert.Equal(t, key1, entries[0].Key, "Execute() should sort entries by key")
+	assert.Equal(t, key2, entries[1].Key, "Execute() should sort entries by key")
+}
+
+func TestListEntriesUseCase_Execute_Filter(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newMoveVault(env, model.CurrentFormatVersion, nil)
+			for _, key := range []string{"A", "B", "C", "D"} {
+				vault.SetEntry(key, env+"/"+key+":value")
+			}
+			vault.SetEntryMetadata("A", model.EntryMetadata{Owner: "alice", Tags: []string{"api"}})
+			vault.SetEntryMetadata("B", model.EntryMetadata{
+				Owner:     "alice",
+				Tags:      []string{"api", "billing"},
+				ExpiresAt: "2000-01-01T00:00:00Z",
+			})
+			vault.SetEntryMetadata("C", model.EntryMetadata{
+				Owner:     "bob",
+				Tags:      []string{"billing"},
+				ExpiresAt: "2999-01-01T00:00:00Z",
+			})
+			vault.MarkSecret("C")
+			return vault, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter EntryFilter
+		keys   []string
+	}{
+		{name: "no filter", filter: EntryFilter{}, keys: []string{"A", "B", "C", "D"}},
+		{name: "tag", filter: EntryFilter{Tags: []string{"billing"}}, keys: []string{"B", "C"}},
+		{
+			name:   "every tag",
+			filter: EntryFilter{Tags: []string{"api", "billing"}},
+			keys:   []string{"B"},
+		},
+		{name: "owner", filter: EntryFilter{Owner: "alice"}, keys: []string{"A", "B"}},
+		{name: "secret", filter: EntryFilter{Secret: true}, keys: []string{"C"}},
+		{name: "expired", filter: EntryFilter{Expired: true}, keys: []string{"B"}},
+		{name: "no match", filter: EntryFilter{Owner: "bob", Expired: true}, keys: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewListEntriesUseCase(vaultService, &test.MockAuditLog{})
+
+			entries, err := useCase.Execute(context.Background(), envTest, tt.filter)
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			keys := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				keys = append(keys, entry.Key)
+			}
+			assert.DeepEqual(t, tt.keys, keys)
+		})
+	}
 }
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+)
+
+const (
+	// UnlockMethodPassphrase means the vault is unlocked with one of its passphrases.
+	UnlockMethodPassphrase = "passphrase"
+	// UnlockMethodIdentity means the local identity is a recipient of the vault.
+	UnlockMethodIdentity = "identity"
+)
+
+// EnvInfo summarizes the vault of an environment without unlocking it.
+type EnvInfo struct {
+	Env  string
+	Keys int
+	// UpdatedAt is the time of the newest entry change, empty when the vault has no entries.
+	UpdatedAt    string
+	Slots        int
+	Recipients   int
+	UnlockMethod string
+}
+
+// ListEnvsUc defines the interface for listing the environments that have a vault.
+type ListEnvsUc interface {
+	Execute(ctx context.Context) ([]EnvInfo, error)
+}
+
+// ListEnvsUseCase implements the use case for listing the environments that have a vault.
+type ListEnvsUseCase struct {
+	vaultRepo    repository.VaultRepository
+	identityRepo repository.IdentityRepository
+}
+
+// NewListEnvsUseCase creates a new ListEnvsUseCase instance.
+func NewListEnvsUseCase(
+	vaultRepo repository.VaultRepository,
+	identityRepo repository.IdentityRepository,
+) ListEnvsUc {
+	return &ListEnvsUseCase{vaultRepo, identityRepo}
+}
+
+// Execute lists every environment with its key count, last update and the way this
+// machine unlocks it. Only unencrypted metadata is read, so no passphrase is needed.
+func (useCase *ListEnvsUseCase) Execute(ctx context.Context) ([]EnvInfo, error) {
+	envs, err := useCase.vaultRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vaults: %w", err)
+	}
+
+	publicKey := ""
+	identity, err := useCase.identityRepo.Load(ctx)
+	switch {
+	case err == nil:
+		publicKey = identity.PublicKey
+	case !errors.Is(err, model.ErrNoIdentity):
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	infos := make([]EnvInfo, 0, len(envs))
+	for _, env := range envs {
+		info, err := useCase.describe(ctx, env, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (useCase *ListEnvsUseCase) describe(
+	ctx context.Context,
+	env, publicKey string,
+) (EnvInfo, error) {
+	release, err := useCase.vaultRepo.Lock(ctx, env, false)
+	if err != nil {
+		return EnvInfo{}, err
+	}
+	defer release()
+
+	vault, err := useCase.vaultRepo.Load(ctx, env)
+	if err != nil {
+		return EnvInfo{}, fmt.Errorf("failed to load vault for environment %s: %w", env, err)
+	}
+
+	info := EnvInfo{
+		Env:          env,
+		Keys:         len(vault.Entries),
+		Slots:        len(vault.Meta.KeySlots()),
+		Recipients:   len(vault.Meta.Recipients),
+		UnlockMethod: UnlockMethodPassphrase,
+	}
+	for _, entry := range vault.Entries {
+		// RFC 3339 timestamps in UTC sort lexically.
+		if entry.UpdatedAt > info.UpdatedAt {
+			info.UpdatedAt = entry.UpdatedAt
+		}
+	}
+	if _, ok := vault.Meta.FindRecipient(publicKey); publicKey != "" && ok {
+		info.UnlockMethod = UnlockMethodIdentity
+	}
+
+	return info, nil
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestListEnvsUseCase_Execute_Success(t *testing.T) {
+	locked := map[string]bool{}
+	vaultRepo := &test.MockVaultRepository{
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"dev", "prod"}, nil
+		},
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			assert.False(t, exclusive, "Execute() should only share-lock the vaults")
+			locked[env] = true
+			return func() {}, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			if env == "prod" {
+				vault.Entries["A"] = model.Entry{Value: "a", UpdatedAt: "2026-01-02T00:00:00Z"}
+				vault.Entries["B"] = model.Entry{Value: "b", UpdatedAt: "2026-03-04T00:00:00Z"}
+				vault.Meta.Slots = []model.KeySlot{{Name: "ci"}}
+				vault.Meta.Recipients = []model.Recipient{{PublicKey: publicKeyTest}}
+			}
+			return vault, nil
+		},
+	}
+	identityRepo := &test.MockIdentityRepository{
+		LoadFunc: func(ctx context.Context) (model.Identity, error) {
+			return model.Identity{PublicKey: publicKeyTest}, nil
+		},
+	}
+
+	useCase := NewListEnvsUseCase(vaultRepo, identityRepo)
+
+	infos, err := useCase.Execute(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 2, infos)
+	assert.True(t, locked["dev"] && locked["prod"], "Execute() should lock every vault")
+
+	assert.Equal(t, "dev", infos[0].Env)
+	assert.Equal(t, 0, infos[0].Keys)
+	assert.Equal(t, "", infos[0].UpdatedAt)
+	assert.Equal(t, 1, infos[0].Slots)
+	assert.Equal(t, UnlockMethodPassphrase, infos[0].UnlockMethod)
+
+	assert.Equal(t, "prod", infos[1].Env)
+	assert.Equal(t, 2, infos[1].Keys)
+	assert.Equal(t, "2026-03-04T00:00:00Z", infos[1].UpdatedAt)
+	assert.Equal(t, 2, infos[1].Slots)
+	assert.Equal(t, 1, infos[1].Recipients)
+	assert.Equal(t, UnlockMethodIdentity, infos[1].UnlockMethod)
+}
+
+func TestListEnvsUseCase_Execute_NoIdentity(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{envTest}, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Meta.Recipients = []model.Recipient{{PublicKey: publicKeyTest}}
+			return vault, nil
+		},
+	}
+
+	useCase := NewListEnvsUseCase(vaultRepo, &test.MockIdentityRepository{})
+
+	infos, err := useCase.Execute(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 1, infos)
+	assert.Equal(t, UnlockMethodPassphrase, infos[0].UnlockMethod)
+}
+
+func TestListEnvsUseCase_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		vaultRepo    *test.MockVaultRepository
+		identityRepo *test.MockIdentityRepository
+		expectedErr  string
+	}{
+		{
+			name: "list fails",
+			vaultRepo: &test.MockVaultRepository{
+				ListFunc: func(ctx context.Context) ([]string, error) {
+					return nil, errors.New("permission denied")
+				},
+			},
+			identityRepo: &test.MockIdentityRepository{},
+			expectedErr:  "failed to list vaults",
+		},
+		{
+			name:      "identity fails",
+			vaultRepo: &test.MockVaultRepository{},
+			identityRepo: &test.MockIdentityRepository{
+				LoadFunc: func(ctx context.Context) (model.Identity, error) {
+					return model.Identity{}, errors.New("malformed identity")
+				},
+			},
+			expectedErr: "failed to load identity",
+		},
+		{
+			name: "load fails",
+			vaultRepo: &test.MockVaultRepository{
+				ListFunc: func(ctx context.Context) ([]string, error) {
+					return []string{envTest}, nil
+				},
+				LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return nil, errors.New("corrupted")
+				},
+			},
+			identityRepo: &test.MockIdentityRepository{},
+			expectedErr:  "failed to load vault for environment test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewListEnvsUseCase(tt.vaultRepo, tt.identityRepo)
+
+			_, err := useCase.Execute(context.Background())
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+		})
+	}
+}
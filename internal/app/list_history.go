@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// EntryVersionInfo describes one value of an entry. Value is only set when values were
+// requested.
+type EntryVersionInfo struct {
+	Version   int
+	UpdatedAt string
+	UpdatedBy string
+	Value     string
+	Current   bool
+}
+
+// ListHistoryUc defines the interface for listing the versions of an entry.
+type ListHistoryUc interface {
+	Execute(ctx context.Context, env, key string, showValues bool) ([]EntryVersionInfo, error)
+}
+
+// ListHistoryUseCase implements the use case for listing the versions of an entry.
+type ListHistoryUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewListHistoryUseCase creates a new ListHistoryUseCase instance.
+func NewListHistoryUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) ListHistoryUc {
+	return &ListHistoryUseCase{vaultService, auditLog}
+}
+
+// Execute lists the previous values of an entry, oldest first, followed by its current
+// value. Values are only decrypted when showValues is set, which is recorded as a get.
+func (useCase *ListHistoryUseCase) Execute(
+	ctx context.Context,
+	env, key string,
+	showValues bool,
+) ([]EntryVersionInfo, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	defer vault.Lock()
+
+	entry, err := vault.GetEntry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := append(slices.Clip(entry.History), model.EntryVersion{
+		Version:   entry.CurrentVersion(),
+		Value:     entry.Value,
+		UpdatedAt: entry.UpdatedAt,
+		UpdatedBy: entry.UpdatedBy,
+	})
+	infos := make([]EntryVersionInfo, 0, len(versions))
+	for i, version := range versions {
+		info := EntryVersionInfo{
+			Version:   version.Version,
+			UpdatedAt: version.UpdatedAt,
+			UpdatedBy: version.UpdatedBy,
+			Current:   i == len(versions)-1,
+		}
+		if showValues {
+			value, err := vault.Session().Decrypt(key, version.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt version %d of key %s: %w",
+					version.Version, key, err)
+			}
+			info.Value = string(value)
+			clear(value)
+		}
+		infos = append(infos, info)
+	}
+
+	if showValues {
+		if err := useCase.auditLog.Record(ctx, vault, model.AuditGet, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newHistoryVault returns a vault with DB_URL set to v1, v2 and v3 by alice
+func newHistoryVault(env string) *model.Vault {
+	vault := newMoveVault(env, model.CurrentFormatVersion, nil)
+	vault.SetAuthor("alice")
+	for _, value := range []string{"v1", "v2", "v3"} {
+		vault.SetEntry("DB_URL", env+"/DB_URL:"+value)
+	}
+	return vault
+}
+
+func TestListHistoryUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		showValues bool
+		values     []string
+	}{
+		{name: "hidden values", showValues: false, values: []string{"", "", ""}},
+		{name: "shown values", showValues: true, values: []string{"v1", "v2", "v3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultService := &test.MockVaultService{
+				OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return newHistoryVault(env), nil
+				},
+			}
+
+			useCase := NewListHistoryUseCase(vaultService, &test.MockAuditLog{})
+
+			versions, err := useCase.Execute(context.Background(), "prod", "DB_URL", tt.showValues)
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			assert.Count(t, 3, versions)
+			for i, version := range versions {
+				assert.Equal(t, i+1, version.Version)
+				assert.Equal(t, "alice", version.UpdatedBy)
+				assert.Equal(t, tt.values[i], version.Value)
+				assert.Equal(t, i == 2, version.Current)
+			}
+		})
+	}
+}
+
+func TestListHistoryUseCase_Execute_KeyNotFound(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newHistoryVault(env), nil
+		},
+	}
+
+	useCase := NewListHistoryUseCase(vaultService, &test.MockAuditLog{})
+
+	_, err := useCase.Execute(context.Background(), "prod", "MISSING", false)
+	assert.NotNil(t, err, "Execute() with an unknown key expected error, got nil")
+	assert.Contains(t, "not found", err.Error())
+}
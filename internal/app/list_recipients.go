@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RecipientInfo describes a recipient without its wrapped key.
+type RecipientInfo struct {
+	Name      string
+	PublicKey string
+	CreatedAt string
+	Unlocked  bool
+}
+
+// ListRecipientsUc defines the interface for listing the recipients of a vault.
+type ListRecipientsUc interface {
+	Execute(ctx context.Context, env string) ([]RecipientInfo, error)
+}
+
+// ListRecipientsUseCase implements the use case for listing the recipients of a vault.
+type ListRecipientsUseCase struct {
+	vaultService service.VaultServiceInterface
+}
+
+// NewListRecipientsUseCase creates a new ListRecipientsUseCase instance.
+func NewListRecipientsUseCase(vaultService service.VaultServiceInterface) ListRecipientsUc {
+	return &ListRecipientsUseCase{vaultService}
+}
+
+// Execute lists the recipients of a vault and marks the one whose identity unlocked it.
+func (useCase *ListRecipientsUseCase) Execute(
+	ctx context.Context,
+	env string,
+) ([]RecipientInfo, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	defer vault.Lock()
+
+	infos := make([]RecipientInfo, 0, len(vault.Meta.Recipients))
+	for _, recipient := range vault.Meta.Recipients {
+		infos = append(infos, RecipientInfo{
+			Name:      recipient.Name,
+			PublicKey: recipient.PublicKey,
+			CreatedAt: recipient.CreatedAt,
+			Unlocked:  model.RecipientSlotPrefix+recipient.Label() == vault.UnlockedSlot(),
+		})
+	}
+
+	return infos, nil
+}
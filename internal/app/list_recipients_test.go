@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestListRecipientsUseCase_Execute_Success(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newRecipientVault(env)
+			vault.AddRecipient(model.Recipient{PublicKey: "lockify1bob", WrappedKey: "wrapped"})
+			vault.SetUnlockedSlot(model.RecipientSlotPrefix + "alice")
+			return vault, nil
+		},
+	}
+
+	useCase := NewListRecipientsUseCase(vaultService)
+
+	recipients, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 2, recipients)
+	assert.Equal(t, "alice", recipients[0].Name)
+	assert.Equal(t, publicKeyTest, recipients[0].PublicKey)
+	assert.True(t, recipients[0].Unlocked, "Execute() should mark the recipient that unlocked")
+	assert.Equal(t, "lockify1bob", recipients[1].PublicKey)
+	assert.False(t, recipients[1].Unlocked)
+}
+
+func TestListRecipientsUseCase_Execute_OpenError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("open error")
+		},
+	}
+
+	useCase := NewListRecipientsUseCase(vaultService)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SlotInfo describes a key slot without its key material.
+type SlotInfo struct {
+	Name      string
+	CreatedAt string
+	Unlocked  bool
+}
+
+// ListSlotsUc defines the interface for listing the key slots of a vault.
+type ListSlotsUc interface {
+	Execute(ctx context.Context, env string) ([]SlotInfo, error)
+}
+
+// ListSlotsUseCase implements the use case for listing the key slots of a vault.
+type ListSlotsUseCase struct {
+	vaultService service.VaultServiceInterface
+}
+
+// NewListSlotsUseCase creates a new ListSlotsUseCase instance.
+func NewListSlotsUseCase(vaultService service.VaultServiceInterface) ListSlotsUc {
+	return &ListSlotsUseCase{vaultService}
+}
+
+// Execute lists the key slots of a vault and marks the one that unlocked it.
+func (useCase *ListSlotsUseCase) Execute(ctx context.Context, env string) ([]SlotInfo, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	defer vault.Lock()
+
+	slots := vault.Meta.KeySlots()
+	infos := make([]SlotInfo, 0, len(slots))
+	for _, slot := range slots {
+		infos = append(infos, SlotInfo{
+			Name:      slot.Name,
+			CreatedAt: slot.CreatedAt,
+			Unlocked:  slot.Name == vault.UnlockedSlot(),
+		})
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestListSlotsUseCase_Execute_Success(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newSlottedVault(env)
+			vault.SetUnlockedSlot("ci")
+			return vault, nil
+		},
+	}
+
+	useCase := NewListSlotsUseCase(vaultService)
+
+	slots, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 2, slots)
+	assert.Equal(t, model.DefaultSlotName, slots[0].Name)
+	assert.False(t, slots[0].Unlocked)
+	assert.Equal(t, "ci", slots[1].Name)
+	assert.True(t, slots[1].Unlocked, "Execute() should mark the slot that unlocked the vault")
+	assert.True(t, slots[1].CreatedAt != "", "Execute() should report when a slot was added")
+}
+
+func TestListSlotsUseCase_Execute_OpenError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("open error")
+		},
+	}
+
+	useCase := NewListSlotsUseCase(vaultService)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// ListStaleUc defines the interface for listing the entries of a vault that are due for
+// rotation.
+type ListStaleUc interface {
+	Execute(ctx context.Context, env string, options StaleOptions) (StaleReport, error)
+}
+
+// StaleOptions selects the entries of a stale report.
+type StaleOptions struct {
+	// WithinDays reports the entries due within this many days as expiring; 0 uses the
+	// warning window of the rotation policy.
+	WithinDays int
+	// All also reports the entries that are not due soon.
+	All bool
+}
+
+// StaleReport lists the entries of a vault that are due for rotation, earliest first.
+type StaleReport struct {
+	Entries  []model.EntryRotation
+	Expired  int
+	Expiring int
+}
+
+// ListStaleUseCase implements the use case for reporting the entries of a vault that are
+// overdue or soon due for rotation.
+type ListStaleUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewListStaleUseCase creates a new ListStaleUseCase instance.
+func NewListStaleUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) ListStaleUc {
+	return &ListStaleUseCase{vaultService, auditLog}
+}
+
+// Execute checks every entry of the vault against its expiry and the rotation policy and
+// reports the expired and expiring ones. Values are never decrypted.
+func (useCase *ListStaleUseCase) Execute(
+	ctx context.Context,
+	env string,
+	options StaleOptions,
+) (StaleReport, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return StaleReport{}, err
+	}
+	defer vault.Lock()
+
+	now := time.Now()
+	var report StaleReport
+	for key := range vault.Entries {
+		rotation, err := vault.EntryRotation(key, now, options.WithinDays)
+		if err != nil {
+			return StaleReport{}, err
+		}
+		switch {
+		case rotation.Status == model.RotationExpired:
+			report.Expired++
+		case rotation.Status == model.RotationExpiring:
+			report.Expiring++
+		case !options.All || rotation.DueAt == "":
+			continue
+		}
+		report.Entries = append(report.Entries, rotation)
+	}
+	// RFC 3339 times in UTC sort chronologically as strings.
+	slices.SortFunc(report.Entries, func(a, b model.EntryRotation) int {
+		if c := strings.Compare(a.DueAt, b.DueAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditOpen); err != nil {
+		return StaleReport{}, err
+	}
+
+	return report, nil
+}
+
+// warnExpired warns about every key of keys whose value is past its expiry or overdue for
+// rotation, so reading it does not go unnoticed.
+func warnExpired(logger domain.Logger, vault *model.Vault, keys []string) {
+	now := time.Now()
+	for _, key := range slices.Sorted(slices.Values(keys)) {
+		rotation, err := vault.EntryRotation(key, now, 0)
+		if err != nil || rotation.Status != model.RotationExpired {
+			continue
+		}
+		logger.Warning("%s in %s was due for rotation on %s (see lockify stale)",
+			key, vault.Meta.Env, rotation.DueAt)
+	}
+}
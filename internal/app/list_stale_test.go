@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newStaleVault returns a vault with a 90 day max age holding an overdue, an expiring and a
+// fresh entry
+func newStaleVault(env string) *model.Vault {
+	now := time.Now().UTC()
+	entries := make(map[string]model.Entry)
+	for key, daysAgo := range map[string]int{"OLD_KEY": 400, "SOON_KEY": 80, "FRESH_KEY": 1} {
+		entries[key] = model.Entry{
+			Value:     env + "/" + key + ":value",
+			UpdatedAt: now.AddDate(0, 0, -daysAgo).Format(time.RFC3339),
+		}
+	}
+	vault := newMoveVault(env, model.CurrentFormatVersion, entries)
+	vault.SetRotationPolicy(model.RotationPolicy{MaxAgeDays: 90})
+	return vault
+}
+
+func newStaleVaultService() *test.MockVaultService {
+	return &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newStaleVault(env), nil
+		},
+	}
+}
+
+func TestListStaleUseCase_Execute(t *testing.T) {
+	auditLog := &test.MockAuditLog{}
+	useCase := NewListStaleUseCase(newStaleVaultService(), auditLog)
+
+	report, err := useCase.Execute(context.Background(), envTest, StaleOptions{})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, 1, report.Expiring)
+	assert.Count(t, 2, report.Entries)
+	assert.Equal(t, "OLD_KEY", report.Entries[0].Key, "the most overdue key should come first")
+	assert.Equal(t, model.RotationExpired, report.Entries[0].Status)
+	assert.Equal(t, "SOON_KEY", report.Entries[1].Key)
+	assert.Equal(t, model.RotationExpiring, report.Entries[1].Status)
+	assert.Equal(t, 1, len(auditLog.Recorded), "Execute() should record the check")
+	assert.Equal(t, model.AuditOpen, auditLog.Recorded[0].Operation)
+}
+
+func TestListStaleUseCase_Execute_Options(t *testing.T) {
+	useCase := NewListStaleUseCase(newStaleVaultService(), &test.MockAuditLog{})
+
+	report, err := useCase.Execute(context.Background(), envTest, StaleOptions{WithinDays: 5})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 0, report.Expiring, "a narrower window should not report SOON_KEY")
+	assert.Count(t, 1, report.Entries)
+
+	report, err = useCase.Execute(context.Background(), envTest, StaleOptions{All: true})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 3, report.Entries)
+	assert.Equal(t, "FRESH_KEY", report.Entries[2].Key)
+	assert.Equal(t, model.RotationOK, report.Entries[2].Status)
+}
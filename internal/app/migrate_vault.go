@@ -0,0 +1,344 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// MigrationResult describes the outcome of migrating a single vault.
+type MigrationResult struct {
+	Env         string
+	FromVersion int
+	ToVersion   int
+	BackupPath  string
+}
+
+// Migrated reports whether the vault file was rewritten.
+func (r MigrationResult) Migrated() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// migrationStep upgrades an unlocked vault from one format version to the next.
+type migrationStep func(useCase *MigrateVaultUseCase, vault *model.Vault) error
+
+// migrationSteps maps a format version to the step that upgrades it to the next version.
+var migrationSteps = map[int]migrationStep{
+	0: (*MigrateVaultUseCase).migrateV0ToV1,
+	1: (*MigrateVaultUseCase).migrateV1ToV2,
+	2: (*MigrateVaultUseCase).migrateV2ToV3,
+	3: (*MigrateVaultUseCase).migrateV3ToV4,
+	4: (*MigrateVaultUseCase).migrateV4ToV5,
+	5: (*MigrateVaultUseCase).migrateV5ToV6,
+	6: (*MigrateVaultUseCase).migrateV6ToV7,
+	7: (*MigrateVaultUseCase).migrateV7ToV8,
+}
+
+// MigrateVaultUc defines the interface for upgrading vault files to the current format.
+type MigrateVaultUc interface {
+	Execute(ctx context.Context, env string) (MigrationResult, error)
+	ExecuteAll(ctx context.Context) ([]MigrationResult, error)
+}
+
+// MigrateVaultUseCase implements the use case for upgrading vault files to the current format.
+type MigrateVaultUseCase struct {
+	vaultService      service.VaultServiceInterface
+	vaultRepo         repository.VaultRepository
+	encryptionService service.EncryptionService
+	auditLog          service.AuditLog
+}
+
+// NewMigrateVaultUseCase creates a new MigrateVaultUseCase instance.
+func NewMigrateVaultUseCase(
+	vaultService service.VaultServiceInterface,
+	vaultRepo repository.VaultRepository,
+	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
+) MigrateVaultUc {
+	return &MigrateVaultUseCase{vaultService, vaultRepo, encryptionService, auditLog}
+}
+
+// Execute upgrades the vault of an environment in place, keeping a backup of the old file.
+// Migrations that change the vault key re-encrypt the audit log as well.
+func (useCase *MigrateVaultUseCase) Execute(
+	ctx context.Context,
+	env string,
+) (MigrationResult, error) {
+	stored, err := useCase.vaultRepo.Load(ctx, env)
+	if err != nil {
+		return MigrationResult{}, fmt.Errorf(
+			"failed to open vault for environment %s: %w",
+			env,
+			err,
+		)
+	}
+
+	result := MigrationResult{
+		Env:         env,
+		FromVersion: stored.Meta.FormatVersion,
+		ToVersion:   stored.Meta.FormatVersion,
+	}
+	if result.FromVersion > model.CurrentFormatVersion {
+		return result, fmt.Errorf(
+			"vault for environment %s uses format version %d, this lockify supports up to %d",
+			env,
+			result.FromVersion,
+			model.CurrentFormatVersion,
+		)
+	}
+	if result.FromVersion == model.CurrentFormatVersion {
+		return result, nil
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return result, err
+	}
+	defer vault.Lock()
+
+	events, err := useCase.auditLog.Events(ctx, vault)
+	if err != nil {
+		return result, fmt.Errorf(
+			"cannot migrate the audit log of environment %s, inspect it with "+
+				"`lockify audit --env %s`: %w",
+			env,
+			env,
+			err,
+		)
+	}
+
+	if err := upgradeVault(useCase.encryptionService, vault); err != nil {
+		return result, err
+	}
+
+	result.BackupPath, err = useCase.vaultRepo.Backup(ctx, env)
+	if err != nil {
+		return result, fmt.Errorf("failed to back up vault for environment %s: %w", env, err)
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return result, fmt.Errorf("failed to save vault for environment %s: %w", env, err)
+	}
+	result.ToVersion = vault.Meta.FormatVersion
+
+	if len(events) > 0 {
+		if err := useCase.auditLog.Rewrite(ctx, vault, events); err != nil {
+			return result, fmt.Errorf("vault was migrated but its audit log was not: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// ExecuteAll upgrades the vaults of every environment, stopping at the first failure.
+func (useCase *MigrateVaultUseCase) ExecuteAll(ctx context.Context) ([]MigrationResult, error) {
+	envs, err := useCase.vaultRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vaults: %w", err)
+	}
+
+	results := make([]MigrationResult, 0, len(envs))
+	for _, env := range envs {
+		result, err := useCase.Execute(ctx, env)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// VaultUpgrader implements service.VaultUpgrader with the migration steps of
+// `lockify migrate`.
+type VaultUpgrader struct {
+	encryptionService service.EncryptionService
+}
+
+// NewVaultUpgrader creates a new VaultUpgrader instance.
+func NewVaultUpgrader(encryptionService service.EncryptionService) service.VaultUpgrader {
+	return &VaultUpgrader{encryptionService}
+}
+
+// Upgrade brings an unlocked vault to the current format version.
+func (upgrader *VaultUpgrader) Upgrade(vault *model.Vault) error {
+	return upgradeVault(upgrader.encryptionService, vault)
+}
+
+// upgradeVault runs the migration steps that bring an unlocked vault to the current format
+// version. Vaults older than model.FormatVersionEnvelope need their passphrase set.
+func upgradeVault(encryptionService service.EncryptionService, vault *model.Vault) error {
+	useCase := &MigrateVaultUseCase{encryptionService: encryptionService}
+	for vault.Meta.FormatVersion < model.CurrentFormatVersion {
+		step, ok := migrationSteps[vault.Meta.FormatVersion]
+		if !ok {
+			return fmt.Errorf("no migration from format version %d", vault.Meta.FormatVersion)
+		}
+		if err := step(useCase, vault); err != nil {
+			return fmt.Errorf(
+				"failed to migrate vault for environment %s from format version %d: %w",
+				vault.Meta.Env,
+				vault.Meta.FormatVersion,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// migrateV0ToV1 records the cipher and KDF parameters that format version 0 vaults implied.
+func (useCase *MigrateVaultUseCase) migrateV0ToV1(vault *model.Vault) error {
+	vault.Meta.Cipher = model.LegacyCipherParams()
+	vault.Meta.KDF = model.LegacyKDFParams()
+	vault.Meta.FormatVersion = 1
+	return nil
+}
+
+// migrateV1ToV2 re-seals every entry so that it is bound to its env and key name.
+func (useCase *MigrateVaultUseCase) migrateV1ToV2(vault *model.Vault) error {
+	meta := vault.Meta
+	meta.FormatVersion = 2
+	session, err := useCase.encryptionService.NewSession(meta, vault.Passphrase())
+	if err != nil {
+		return fmt.Errorf("failed to derive vault key: %w", err)
+	}
+
+	if err := resealEntries(vault, session); err != nil {
+		session.Close()
+		return err
+	}
+
+	vault.Meta = meta
+	vault.Session().Close()
+	vault.SetSession(session)
+	return nil
+}
+
+// migrateV2ToV3 enables the vault MAC; the manifest and MAC are written when the vault is saved.
+func (useCase *MigrateVaultUseCase) migrateV2ToV3(vault *model.Vault) error {
+	vault.Meta.FormatVersion = 3
+	return nil
+}
+
+// migrateV3ToV4 generates a random data key, re-seals every entry with it and stores it
+// wrapped by the key derived from the current passphrase.
+func (useCase *MigrateVaultUseCase) migrateV3ToV4(vault *model.Vault) error {
+	meta := vault.Meta
+	meta.FormatVersion = 4
+	session, err := useCase.encryptionService.NewDataKey(meta)
+	if err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	if err := resealEntries(vault, session); err != nil {
+		session.Close()
+		return err
+	}
+
+	meta.WrappedKey, err = session.WrapKey(meta, vault.Passphrase())
+	if err != nil {
+		session.Close()
+		return fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	vault.Meta = meta
+	vault.Session().Close()
+	vault.SetSession(session)
+	return nil
+}
+
+// migrateV4ToV5 enables key slots; the existing passphrase becomes the default slot.
+func (useCase *MigrateVaultUseCase) migrateV4ToV5(vault *model.Vault) error {
+	vault.Meta.FormatVersion = 5
+	return nil
+}
+
+// migrateV5ToV6 enables public-key recipients; the vault has none until one is added.
+func (useCase *MigrateVaultUseCase) migrateV5ToV6(vault *model.Vault) error {
+	vault.Meta.FormatVersion = 6
+	return nil
+}
+
+// migrateV6ToV7 drops the bcrypt fingerprints; passphrases are checked against the wrapped
+// data key of their slot instead.
+func (useCase *MigrateVaultUseCase) migrateV6ToV7(vault *model.Vault) error {
+	vault.Meta.UpgradeKeyCheck()
+	return nil
+}
+
+// migrateV7ToV8 moves the audit records to a domain of their own; the audit log is sealed
+// again after the vault is saved.
+func (useCase *MigrateVaultUseCase) migrateV7ToV8(vault *model.Vault) error {
+	vault.Meta.FormatVersion = model.FormatVersionAuditRecords
+	return nil
+}
+
+// resealEntries decrypts every entry with the current vault session and encrypts it with
+// the given session, keeping entry timestamps unchanged.
+func resealEntries(vault *model.Vault, session model.Session) error {
+	entries := make(map[string]model.Entry, len(vault.Entries))
+	for key, entry := range vault.Entries {
+		resealed, err := resealEntry(entry, key, key, vault.Session(), session)
+		if err != nil {
+			return err
+		}
+		entries[key] = resealed
+	}
+
+	vault.Entries = entries
+	return nil
+}
+
+// resealEntry decrypts the current and previous values and the sealed metadata of an entry
+// sealed for key with from and encrypts them for newKey with to. The history is copied, so
+// the original entry is left untouched.
+func resealEntry(
+	entry model.Entry,
+	key, newKey string,
+	from, to model.Session,
+) (model.Entry, error) {
+	reseal := func(value, key, newKey string) (string, error) {
+		plaintext, err := from.Decrypt(key, value)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt key %s: %w", key, err)
+		}
+		defer clear(plaintext)
+
+		resealed, err := to.Encrypt(newKey, plaintext)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt key %s: %w", newKey, err)
+		}
+		return resealed, nil
+	}
+
+	var err error
+	if entry.Value, err = reseal(entry.Value, key, newKey); err != nil {
+		return model.Entry{}, err
+	}
+	if entry.SealedMetadata != "" {
+		entry.SealedMetadata, err = reseal(
+			entry.SealedMetadata,
+			model.MetadataRecordKey(key),
+			model.MetadataRecordKey(newKey),
+		)
+		if err != nil {
+			return model.Entry{}, err
+		}
+	}
+
+	history := make([]model.EntryVersion, len(entry.History))
+	for i, previous := range entry.History {
+		if previous.Value, err = reseal(previous.Value, key, newKey); err != nil {
+			return model.Entry{}, fmt.Errorf("version %d: %w", previous.Version, err)
+		}
+		history[i] = previous
+	}
+	if len(history) > 0 {
+		entry.History = history
+	}
+
+	return entry, nil
+}
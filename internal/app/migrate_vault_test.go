@@ -0,0 +1,340 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func newLegacyVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, saltTest)
+	vault.Meta.FormatVersion = 0
+	vault.Meta.FingerPrint = "legacy-fingerprint"
+	vault.SetEntry(keyTest, encryptedValueTest)
+	return vault
+}
+
+func newLegacyVaultService(session *test.MockSession) *test.MockVaultService {
+	return &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newLegacyVault(env)
+			vault.SetPassphrase(passphraseTest)
+			vault.SetSession(session)
+			return vault, nil
+		},
+	}
+}
+
+func TestMigrateVaultUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	backupCalled := false
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newLegacyVault(env), nil
+		},
+		BackupFunc: func(ctx context.Context, env string) (string, error) {
+			if savedVault != nil {
+				t.Error("Backup() should be called before Save()")
+			}
+			backupCalled = true
+			return env + ".vault.enc.bak", nil
+		},
+	}
+
+	oldSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			return []byte(valueTest), nil
+		},
+	}
+	newSession := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return "resealed:" + key + ":" + string(plaintext), nil
+		},
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			assert.Equal(t, "resealed:"+key+":"+valueTest, ciphertext)
+			return []byte(valueTest), nil
+		},
+	}
+	dataKeySession := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return "data-key:" + key + ":" + string(plaintext), nil
+		},
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			assert.Equal(t, model.FormatVersionEnvelope, meta.FormatVersion)
+			assert.Equal(t, passphraseTest, passphrase)
+			return "wrapped-data-key", nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			assert.Equal(t, 2, meta.FormatVersion, "entries should be re-sealed for format 2")
+			assert.Equal(t, passphraseTest, passphrase)
+			return newSession, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			assert.Equal(t, model.FormatVersionEnvelope, meta.FormatVersion)
+			return dataKeySession, nil
+		},
+	}
+
+	vaultService := newLegacyVaultService(oldSession)
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		savedVault = vault
+		return nil
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		vaultService,
+		vaultRepo,
+		encryptionService,
+		&test.MockAuditLog{},
+	)
+
+	result, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, backupCalled, "Execute() should back up the vault before migrating it")
+	assert.NotNil(t, savedVault, "Execute() should save the migrated vault")
+	assert.Equal(t, 0, result.FromVersion)
+	assert.Equal(t, model.CurrentFormatVersion, result.ToVersion)
+	assert.Equal(t, envTest+".vault.enc.bak", result.BackupPath)
+	assert.True(t, result.Migrated())
+
+	assert.Equal(t, model.CurrentFormatVersion, savedVault.Meta.FormatVersion)
+	assert.Equal(t, model.LegacyCipherParams(), savedVault.Meta.Cipher)
+	assert.Equal(t, model.LegacyKDFParams(), savedVault.Meta.KDF)
+
+	assert.Equal(t, "wrapped-data-key", savedVault.Meta.WrappedKey)
+	assert.Equal(t, "", savedVault.Meta.FingerPrint, "Execute() should drop the fingerprint")
+
+	entry, _ := savedVault.GetEntry(keyTest)
+	assert.Equal(t, "data-key:"+keyTest+":"+valueTest, entry.Value)
+	assert.True(t, oldSession.Closed, "Execute() should close the old session")
+	assert.True(t, newSession.Closed, "Execute() should close the intermediate session")
+	assert.True(t, dataKeySession.Closed, "Execute() should lock the vault when done")
+}
+
+func TestMigrateVaultUseCase_Execute_ResealError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newLegacyVault(env), nil
+		},
+		BackupFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Backup() should not be called when re-sealing fails")
+			return "", nil
+		},
+	}
+	oldSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			return nil, model.ErrTampered
+		},
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		newLegacyVaultService(oldSession),
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with a tampered entry expected error, got nil")
+	assert.True(t, errors.Is(err, model.ErrTampered), "Execute() should report tampering")
+}
+
+func TestMigrateVaultUseCase_Execute_AlreadyCurrent(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			return vault, nil
+		},
+		BackupFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Backup() should not be called for a current vault")
+			return "", nil
+		},
+	}
+	vaultService := newLegacyVaultService(&test.MockSession{})
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		t.Error("Save() should not be called for a current vault")
+		return nil
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		vaultService,
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	result, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.False(t, result.Migrated())
+	assert.Equal(t, "", result.BackupPath)
+}
+
+func TestMigrateVaultUseCase_Execute_DropsFingerprints(t *testing.T) {
+	newFingerprintVault := func(env string) *model.Vault {
+		vault, _ := model.NewVault(env, saltTest)
+		vault.Meta.FormatVersion = model.FormatVersionRecipients
+		vault.Meta.FingerPrint = "default-fingerprint"
+		vault.Meta.Slots = []model.KeySlot{{Name: "ci", FingerPrint: "ci-fingerprint"}}
+		return vault
+	}
+
+	var savedVault *model.Vault
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newFingerprintVault(env), nil
+		},
+	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newFingerprintVault(env)
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		vaultService,
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	result, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, model.FormatVersionRecipients, result.FromVersion)
+	assert.Equal(t, model.CurrentFormatVersion, savedVault.Meta.FormatVersion)
+	assert.Equal(t, "", savedVault.Meta.FingerPrint)
+	assert.Equal(t, "", savedVault.Meta.Slots[0].FingerPrint)
+}
+
+func TestMigrateVaultUseCase_Execute_NewerVersion(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Meta.FormatVersion = model.CurrentFormatVersion + 1
+			return vault, nil
+		},
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		newLegacyVaultService(&test.MockSession{}),
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with newer format version expected error, got nil")
+	assert.Contains(t, "uses format version", err.Error())
+}
+
+func TestMigrateVaultUseCase_Execute_BackupError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newLegacyVault(env), nil
+		},
+		BackupFunc: func(ctx context.Context, env string) (string, error) {
+			return "", errors.New("backup error")
+		},
+	}
+	vaultService := newLegacyVaultService(&test.MockSession{})
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		t.Error("Save() should not be called when the backup fails")
+		return nil
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		vaultService,
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with backup error expected error, got nil")
+	assert.Contains(t, "failed to back up vault", err.Error())
+}
+
+func TestMigrateVaultUseCase_Execute_LoadError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("load error")
+		},
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		newLegacyVaultService(&test.MockSession{}),
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with load error expected error, got nil")
+	assert.Contains(t, "failed to open vault for environment", err.Error())
+}
+
+func TestMigrateVaultUseCase_ExecuteAll(t *testing.T) {
+	saved := []string{}
+	vaultRepo := &test.MockVaultRepository{
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"dev", "prod"}, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			if env == "prod" {
+				vault, _ := model.NewVault(env, saltTest)
+				return vault, nil
+			}
+			return newLegacyVault(env), nil
+		},
+	}
+	vaultService := newLegacyVaultService(&test.MockSession{})
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		saved = append(saved, vault.Meta.Env)
+		return nil
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		vaultService,
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	results, err := useCase.ExecuteAll(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("ExecuteAll() returned unexpected error: %v", err))
+	assert.Count(t, 2, results)
+	assert.True(t, results[0].Migrated(), "ExecuteAll() should migrate the legacy vault")
+	assert.False(t, results[1].Migrated(), "ExecuteAll() should skip the current vault")
+	assert.DeepEqual(t, []string{"dev"}, saved)
+}
+
+func TestMigrateVaultUseCase_ExecuteAll_ListError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return nil, errors.New("list error")
+		},
+	}
+
+	useCase := NewMigrateVaultUseCase(
+		newLegacyVaultService(&test.MockSession{}),
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.ExecuteAll(context.Background())
+	assert.NotNil(t, err, "ExecuteAll() with list error expected error, got nil")
+	assert.Contains(t, "failed to list vaults", err.Error())
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// MoveEntryUc defines the interface for renaming, copying and moving entries.
+type MoveEntryUc interface {
+	Execute(ctx context.Context, dto MoveEntryDTO) error
+}
+
+// MoveEntryDTO contains the data needed to move or copy an entry.
+type MoveEntryDTO struct {
+	Env string
+	Key string
+	// NewKey is the key of the entry in the target, Key when empty.
+	NewKey string
+	// ToEnv is the environment the entry is moved to, Env when empty.
+	ToEnv string
+	// Copy keeps the original entry.
+	Copy bool
+	// Overwrite replaces an existing entry at the target instead of failing.
+	Overwrite bool
+}
+
+// MoveEntryUseCase implements the use case for renaming, copying and moving entries.
+type MoveEntryUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewMoveEntryUseCase creates a new MoveEntryUseCase instance.
+func NewMoveEntryUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) MoveEntryUc {
+	return &MoveEntryUseCase{vaultService, auditLog}
+}
+
+// Execute moves or copies an entry to another key, possibly in another environment. A moved
+// entry keeps its timestamps and markers, while a copy is a new entry.
+func (useCase *MoveEntryUseCase) Execute(ctx context.Context, dto MoveEntryDTO) error {
+	if dto.NewKey == "" {
+		dto.NewKey = dto.Key
+	}
+	if dto.ToEnv == "" {
+		dto.ToEnv = dto.Env
+	}
+	if dto.Key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	if dto.ToEnv == dto.Env && dto.NewKey == dto.Key {
+		return fmt.Errorf("source and target of key %q are the same", dto.Key)
+	}
+
+	if dto.ToEnv == dto.Env {
+		vault, err := useCase.vaultService.OpenForUpdate(ctx, dto.Env)
+		if err != nil {
+			return err
+		}
+		defer vault.Lock()
+
+		if err := transferEntry(vault, vault, dto); err != nil {
+			return err
+		}
+		if err := useCase.vaultService.Save(ctx, vault); err != nil {
+			return err
+		}
+		return useCase.recordMove(ctx, vault, vault, dto)
+	}
+
+	source, target, err := useCase.openBoth(ctx, dto)
+	if err != nil {
+		return err
+	}
+	defer source.Lock()
+	defer target.Lock()
+
+	if err := transferEntry(source, target, dto); err != nil {
+		return err
+	}
+	// The target is saved first, so that a failure leaves the entry in both vaults rather
+	// than in none.
+	if err := useCase.vaultService.Save(ctx, target); err != nil {
+		return err
+	}
+	if !dto.Copy {
+		if err := useCase.vaultService.Save(ctx, source); err != nil {
+			return fmt.Errorf(
+				"key %q was copied to %s but could not be removed from %s: %w",
+				dto.Key,
+				dto.ToEnv,
+				dto.Env,
+				err,
+			)
+		}
+	}
+
+	return useCase.recordMove(ctx, source, target, dto)
+}
+
+// recordMove records the new entry in the audit log of target and, unless the entry was
+// copied, its removal in the audit log of source
+func (useCase *MoveEntryUseCase) recordMove(
+	ctx context.Context,
+	source, target *model.Vault,
+	dto MoveEntryDTO,
+) error {
+	if err := useCase.auditLog.Record(ctx, target, model.AuditSet, dto.NewKey); err != nil {
+		return err
+	}
+	if dto.Copy {
+		return nil
+	}
+	return useCase.auditLog.Record(ctx, source, model.AuditDelete, dto.Key)
+}
+
+// openBoth opens the source and target vaults in the order of their names, so that two
+// moves in opposite directions cannot each hold the vault the other one waits for.
+func (useCase *MoveEntryUseCase) openBoth(
+	ctx context.Context,
+	dto MoveEntryDTO,
+) (*model.Vault, *model.Vault, error) {
+	open := func(env string) (*model.Vault, error) {
+		if env == dto.Env && dto.Copy {
+			return useCase.vaultService.Open(ctx, env)
+		}
+		return useCase.vaultService.OpenForUpdate(ctx, env)
+	}
+
+	first, second := dto.Env, dto.ToEnv
+	if second < first {
+		first, second = second, first
+	}
+
+	firstVault, err := open(first)
+	if err != nil {
+		return nil, nil, err
+	}
+	secondVault, err := open(second)
+	if err != nil {
+		firstVault.Lock()
+		return nil, nil, err
+	}
+
+	if first == dto.Env {
+		return firstVault, secondVault, nil
+	}
+	return secondVault, firstVault, nil
+}
+
+// transferEntry copies or moves an entry of source into target. Entries are bound to their
+// key and environment, so values are decrypted and sealed again for their new place; only
+// legacy vaults, which have no such binding, move entries within the vault as they are.
+func transferEntry(source, target *model.Vault, dto MoveEntryDTO) error {
+	entry, err := source.GetEntry(dto.Key)
+	if err != nil {
+		return err
+	}
+	if _, err := target.GetEntry(dto.NewKey); err == nil && !dto.Overwrite {
+		return fmt.Errorf(
+			"key %q already exists in %s (use --overwrite to replace it)",
+			dto.NewKey,
+			dto.ToEnv,
+		)
+	}
+
+	if dto.Copy {
+		// A copy is a new entry: only the current value is copied, not its history.
+		plaintext, err := source.Session().Decrypt(dto.Key, entry.Value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt value of key %q: %w", dto.Key, err)
+		}
+		encryptedValue, err := target.Session().Encrypt(dto.NewKey, plaintext)
+		clear(plaintext)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt value of key %q: %w", dto.NewKey, err)
+		}
+		if err := target.SetEntry(dto.NewKey, encryptedValue); err != nil {
+			return err
+		}
+		if entry.Secret {
+			if err := target.MarkSecret(dto.NewKey); err != nil {
+				return err
+			}
+		}
+		metadata, err := source.EntryMetadata(dto.Key)
+		if err != nil {
+			return err
+		}
+		return target.SetEntryMetadata(dto.NewKey, metadata)
+	}
+
+	if source != target || source.Meta.EntryFormatVersion() != 0 {
+		entry, err = resealEntry(entry, dto.Key, dto.NewKey, source.Session(), target.Session())
+		if err != nil {
+			return err
+		}
+	}
+	if err := target.PutEntry(dto.NewKey, entry); err != nil {
+		return err
+	}
+	if err := target.ApplyMetadataVisibility(dto.NewKey); err != nil {
+		return err
+	}
+	return source.DeleteEntry(dto.Key)
+}
@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newMoveVault returns a vault whose ciphertexts are "env/key:plaintext", so that values
+// sealed for the wrong key or environment are noticed
+func newMoveVault(env string, formatVersion int, entries map[string]model.Entry) *model.Vault {
+	vault, _ := model.NewVault(env, saltTest)
+	vault.Meta.FormatVersion = formatVersion
+	for key, entry := range entries {
+		vault.Entries[key] = entry
+	}
+	vault.SetSession(&test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return env + "/" + key + ":" + string(plaintext), nil
+		},
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			prefix := env + "/" + key + ":"
+			if !strings.HasPrefix(ciphertext, prefix) {
+				return nil, model.ErrTampered
+			}
+			return []byte(strings.TrimPrefix(ciphertext, prefix)), nil
+		},
+	})
+	return vault
+}
+
+var movedEntry = model.Entry{
+	Value:     "prod/DB_URL:postgres://db",
+	CreatedAt: "2026-01-01T00:00:00Z",
+	UpdatedAt: "2026-02-01T00:00:00Z",
+	Secret:    true,
+}
+
+func TestMoveEntryUseCase_Execute_Rename(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newMoveVault(env, model.CurrentFormatVersion, map[string]model.Entry{
+				"DB_URL": movedEntry,
+			}), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), MoveEntryDTO{
+		Env:    "prod",
+		Key:    "DB_URL",
+		NewKey: "DATABASE_URL",
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+
+	_, exists := savedVault.Entries["DB_URL"]
+	assert.False(t, exists, "the old key should be removed")
+	entry := savedVault.Entries["DATABASE_URL"]
+	assert.Equal(t, "prod/DATABASE_URL:postgres://db", entry.Value)
+	assert.Equal(t, movedEntry.CreatedAt, entry.CreatedAt)
+	assert.Equal(t, movedEntry.UpdatedAt, entry.UpdatedAt)
+	assert.True(t, entry.Secret, "the secret marker should be kept")
+}
+
+func TestMoveEntryUseCase_Execute_RenameLegacyWithoutDecrypting(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newMoveVault(env, 1, map[string]model.Entry{"DB_URL": {Value: "opaque"}})
+			vault.SetSession(&test.MockSession{
+				DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+					t.Error("Decrypt() should not be called for a legacy vault")
+					return nil, errors.New("unexpected")
+				},
+			})
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), MoveEntryDTO{
+		Env:    "prod",
+		Key:    "DB_URL",
+		NewKey: "DATABASE_URL",
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, "opaque", savedVault.Entries["DATABASE_URL"].Value)
+}
+
+func TestMoveEntryUseCase_Execute_AcrossEnvs(t *testing.T) {
+	tests := []struct {
+		name       string
+		copy       bool
+		sourceSave bool
+	}{
+		{name: "move", copy: false, sourceSave: true},
+		{name: "copy", copy: true, sourceSave: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opened []string
+			saved := map[string]*model.Vault{}
+			open := func(ctx context.Context, env string) (*model.Vault, error) {
+				opened = append(opened, env)
+				if env == "prod" {
+					vault := newMoveVault(env, model.CurrentFormatVersion, map[string]model.Entry{
+						"DB_URL": movedEntry,
+					})
+					vault.SetEntryMetadata("DB_URL", model.EntryMetadata{Owner: "alice"})
+					return vault, nil
+				}
+				vault := newMoveVault(env, model.CurrentFormatVersion, nil)
+				vault.Meta.PublicMeta = true
+				return vault, nil
+			}
+			vaultService := &test.MockVaultService{
+				OpenFunc:          open,
+				OpenForUpdateFunc: open,
+				SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+					saved[vault.Meta.Env] = vault
+					return nil
+				},
+			}
+
+			useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+			err := useCase.Execute(context.Background(), MoveEntryDTO{
+				Env:   "prod",
+				Key:   "DB_URL",
+				ToEnv: "archive",
+				Copy:  tt.copy,
+			})
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			assert.DeepEqual(t, []string{"archive", "prod"}, opened)
+
+			target := saved["archive"]
+			assert.NotNil(t, target, "Execute() should save the target")
+			assert.Equal(t, "archive/DB_URL:postgres://db", target.Entries["DB_URL"].Value)
+			assert.True(t, target.Entries["DB_URL"].Secret, "the secret marker should be kept")
+			metadata := target.Entries["DB_URL"].Metadata
+			assert.NotNil(t, metadata, "the metadata should be public in the target")
+			assert.Equal(t, "alice", metadata.Owner)
+
+			source, sourceSaved := saved["prod"]
+			assert.Equal(t, tt.sourceSave, sourceSaved)
+			if sourceSaved {
+				assert.Count(t, 0, source.Entries)
+			}
+		})
+	}
+}
+
+func TestMoveEntryUseCase_Execute_History(t *testing.T) {
+	withHistory := movedEntry
+	withHistory.Version = 2
+	withHistory.History = []model.EntryVersion{
+		{Version: 1, Value: "prod/DB_URL:postgres://old", ReplacedAt: "2026-02-01T00:00:00Z"},
+	}
+
+	for _, copyEntry := range []bool{false, true} {
+		t.Run(fmt.Sprintf("copy=%t", copyEntry), func(t *testing.T) {
+			saved := map[string]*model.Vault{}
+			open := func(ctx context.Context, env string) (*model.Vault, error) {
+				if env == "prod" {
+					return newMoveVault(env, model.CurrentFormatVersion, map[string]model.Entry{
+						"DB_URL": withHistory,
+					}), nil
+				}
+				return newMoveVault(env, model.CurrentFormatVersion, nil), nil
+			}
+			vaultService := &test.MockVaultService{
+				OpenFunc:          open,
+				OpenForUpdateFunc: open,
+				SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+					saved[vault.Meta.Env] = vault
+					return nil
+				},
+			}
+
+			useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+			err := useCase.Execute(context.Background(), MoveEntryDTO{
+				Env:    "prod",
+				Key:    "DB_URL",
+				NewKey: "DATABASE_URL",
+				ToEnv:  "archive",
+				Copy:   copyEntry,
+			})
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+
+			moved := saved["archive"].Entries["DATABASE_URL"]
+			if copyEntry {
+				assert.Count(t, 0, moved.History)
+				assert.Equal(t, 1, moved.Version)
+				return
+			}
+			assert.Equal(t, 2, moved.Version)
+			assert.Count(t, 1, moved.History)
+			assert.Equal(t, "archive/DATABASE_URL:postgres://old", moved.History[0].Value)
+		})
+	}
+}
+
+func TestMoveEntryUseCase_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		dto         MoveEntryDTO
+		expectedErr string
+	}{
+		{
+			name:        "same key",
+			dto:         MoveEntryDTO{Env: "prod", Key: "DB_URL"},
+			expectedErr: "are the same",
+		},
+		{
+			name:        "missing key",
+			dto:         MoveEntryDTO{Env: "prod", Key: "MISSING", NewKey: "OTHER"},
+			expectedErr: `key "MISSING" not found`,
+		},
+		{
+			name:        "existing target",
+			dto:         MoveEntryDTO{Env: "prod", Key: "DB_URL", NewKey: "PORT"},
+			expectedErr: `key "PORT" already exists in prod`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultService := &test.MockVaultService{
+				OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return newMoveVault(env, model.CurrentFormatVersion, map[string]model.Entry{
+						"DB_URL": movedEntry,
+						"PORT":   {Value: "prod/PORT:80"},
+					}), nil
+				},
+				SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+					t.Error("Save() should not be called on error")
+					return nil
+				},
+			}
+
+			useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+			err := useCase.Execute(context.Background(), tt.dto)
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+		})
+	}
+}
+
+func TestMoveEntryUseCase_Execute_Overwrite(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newMoveVault(env, model.CurrentFormatVersion, map[string]model.Entry{
+				"DB_URL": movedEntry,
+				"PORT":   {Value: "prod/PORT:80"},
+			}), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewMoveEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), MoveEntryDTO{
+		Env:       "prod",
+		Key:       "DB_URL",
+		NewKey:    "PORT",
+		Copy:      true,
+		Overwrite: true,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 2, savedVault.Entries)
+	assert.Equal(t, "prod/PORT:postgres://db", savedVault.Entries["PORT"].Value)
+}
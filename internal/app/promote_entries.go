@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// PromoteEntriesUc defines the interface for copying entries from one environment to another.
+type PromoteEntriesUc interface {
+	Execute(ctx context.Context, dto PromoteEntriesDTO) (PromoteResult, error)
+}
+
+// PromoteEntriesDTO contains the data needed to promote entries between environments.
+type PromoteEntriesDTO struct {
+	From string
+	To   string
+	// Keys are key names or glob patterns, such as FEATURE_*, selecting the entries of From.
+	Keys []string
+	// Exclude are key names or glob patterns that are never promoted, even when selected.
+	Exclude []string
+	// Overwrite replaces entries of To whose values differ instead of skipping them.
+	Overwrite bool
+	// DryRun plans the promotion without saving To.
+	DryRun bool
+}
+
+// PromoteResult is the plan of a promotion, each list sorted by key.
+type PromoteResult struct {
+	// Added keys do not exist in To yet.
+	Added []string
+	// Updated keys exist in To with another value and are overwritten.
+	Updated []string
+	// Unchanged keys already have the same value in To.
+	Unchanged []string
+	// Skipped keys exist in To with another value but overwriting was not allowed.
+	Skipped []string
+	// Excluded keys were selected but match an exclusion.
+	Excluded []string
+	Saved    bool
+}
+
+// PromoteEntriesUseCase implements the use case for copying entries between environments.
+type PromoteEntriesUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewPromoteEntriesUseCase creates a new PromoteEntriesUseCase instance.
+func NewPromoteEntriesUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) PromoteEntriesUc {
+	return &PromoteEntriesUseCase{vaultService, auditLog}
+}
+
+// Execute decrypts the selected entries of From and re-encrypts them for To, which is saved
+// once, and only when every entry could be promoted.
+func (useCase *PromoteEntriesUseCase) Execute(
+	ctx context.Context,
+	dto PromoteEntriesDTO,
+) (PromoteResult, error) {
+	if dto.From == dto.To {
+		return PromoteResult{}, fmt.Errorf("cannot promote environment %s into itself", dto.From)
+	}
+	if len(dto.Keys) == 0 {
+		return PromoteResult{}, fmt.Errorf("no keys to promote")
+	}
+	for _, pattern := range append(append([]string{}, dto.Keys...), dto.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return PromoteResult{}, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+	}
+
+	result := PromoteResult{}
+	entries, secrets, err := useCase.selectEntries(ctx, dto, &result)
+	if err != nil {
+		return PromoteResult{}, err
+	}
+
+	target, err := useCase.vaultService.OpenForUpdate(ctx, dto.To)
+	if err != nil {
+		return PromoteResult{}, err
+	}
+	defer target.Lock()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entryValue := entries[key]
+		if entry, err := target.GetEntry(key); err == nil {
+			current, err := target.Session().Decrypt(key, entry.Value)
+			if err != nil {
+				return PromoteResult{}, fmt.Errorf(
+					"failed to decrypt value of key %q in %s: %w",
+					key,
+					dto.To,
+					err,
+				)
+			}
+			switch {
+			case string(current) == entryValue:
+				result.Unchanged = append(result.Unchanged, key)
+				continue
+			case !dto.Overwrite:
+				result.Skipped = append(result.Skipped, key)
+				continue
+			}
+			result.Updated = append(result.Updated, key)
+		} else {
+			result.Added = append(result.Added, key)
+		}
+
+		encryptedValue, err := target.Session().Encrypt(key, []byte(entryValue))
+		if err != nil {
+			return PromoteResult{}, fmt.Errorf("failed to encrypt value of key %q: %w", key, err)
+		}
+		if err := target.SetEntry(key, encryptedValue); err != nil {
+			return PromoteResult{}, fmt.Errorf("failed to set key %q: %w", key, err)
+		}
+		if secrets[key] {
+			if err := target.MarkSecret(key); err != nil {
+				return PromoteResult{}, fmt.Errorf("failed to set key %q: %w", key, err)
+			}
+		}
+	}
+
+	if dto.DryRun || len(result.Added)+len(result.Updated) == 0 {
+		return result, nil
+	}
+	if err := useCase.vaultService.Save(ctx, target); err != nil {
+		return PromoteResult{}, fmt.Errorf("failed to save vault: %w", err)
+	}
+	result.Saved = true
+
+	promoted := append(append([]string{}, result.Added...), result.Updated...)
+	if err := useCase.auditLog.Record(ctx, target, model.AuditSet, promoted...); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// selectEntries decrypts the entries of From selected by the DTO, along with their secret
+// markers, and records them as exported from From. The source vault is locked again before
+// the target is opened, so that two promotions in opposite directions cannot wait on each
+// other.
+func (useCase *PromoteEntriesUseCase) selectEntries(
+	ctx context.Context,
+	dto PromoteEntriesDTO,
+	result *PromoteResult,
+) (map[string]string, map[string]bool, error) {
+	source, err := useCase.vaultService.Open(ctx, dto.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer source.Lock()
+
+	for _, pattern := range dto.Keys {
+		if _, exists := source.Entries[pattern]; !exists && !isKeyPattern(pattern) {
+			return nil, nil, fmt.Errorf("key %q not found in %s", pattern, dto.From)
+		}
+	}
+
+	entries := make(map[string]string)
+	secrets := make(map[string]bool)
+	for key, entry := range source.Entries {
+		if !matchesAny(key, dto.Keys) {
+			continue
+		}
+		if matchesAny(key, dto.Exclude) {
+			result.Excluded = append(result.Excluded, key)
+			continue
+		}
+
+		decrypted, err := source.Session().Decrypt(key, entry.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"failed to decrypt value of key %q in %s: %w",
+				key,
+				dto.From,
+				err,
+			)
+		}
+		entries[key] = string(decrypted)
+		secrets[key] = entry.Secret
+	}
+	sort.Strings(result.Excluded)
+
+	if len(entries) == 0 && len(result.Excluded) > 0 {
+		return nil, nil, fmt.Errorf(
+			"every key of %s matching %s is excluded",
+			dto.From,
+			strings.Join(dto.Keys, ","),
+		)
+	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf(
+			"no keys of %s match %s",
+			dto.From,
+			strings.Join(dto.Keys, ","),
+		)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	if err := useCase.auditLog.Record(ctx, source, model.AuditExport, keys...); err != nil {
+		return nil, nil, err
+	}
+
+	return entries, secrets, nil
+}
+
+// isKeyPattern reports whether a key selector contains glob characters
+func isKeyPattern(selector string) bool {
+	return strings.ContainsAny(selector, `*?[\`)
+}
+
+// matchesAny reports whether key matches one of the key names or glob patterns
+func matchesAny(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		// Patterns were validated up front, so Match cannot fail here.
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newPromoteVaultService opens staging for reading and prod for update, as prefix cipher
+// vaults, so that values re-encrypted for prod can be told apart
+func newPromoteVaultService(t *testing.T, saved **model.Vault) *test.MockVaultService {
+	t.Helper()
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		"staging": {
+			"FEATURE_A":    "on",
+			"FEATURE_B":    "off",
+			"API_URL":      "https://api",
+			"DATABASE_URL": "postgres://staging",
+			"LOG_LEVEL":    "debug",
+		},
+		"prod": {
+			"FEATURE_B":    "on",
+			"LOG_LEVEL":    "debug",
+			"DATABASE_URL": "postgres://prod",
+		},
+	})
+	open, openForUpdate := vaultService.OpenFunc, vaultService.OpenForUpdateFunc
+	vaultService.OpenFunc = func(ctx context.Context, env string) (*model.Vault, error) {
+		assert.Equal(t, "staging", env)
+		vault, err := open(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		entry := vault.Entries["API_URL"]
+		entry.Secret = true
+		vault.Entries["API_URL"] = entry
+		return vault, nil
+	}
+	vaultService.OpenForUpdateFunc = func(ctx context.Context, env string) (*model.Vault, error) {
+		assert.Equal(t, "prod", env)
+		return openForUpdate(ctx, env)
+	}
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		*saved = vault
+		return nil
+	}
+	return vaultService
+}
+
+func TestPromoteEntriesUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	auditLog := &test.MockAuditLog{}
+	useCase := NewPromoteEntriesUseCase(newPromoteVaultService(t, &savedVault), auditLog)
+
+	result, err := useCase.Execute(context.Background(), PromoteEntriesDTO{
+		From:    "staging",
+		To:      "prod",
+		Keys:    []string{"FEATURE_*", "API_URL", "LOG_LEVEL", "DATABASE_URL"},
+		Exclude: []string{"DATABASE_*"},
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"API_URL", "FEATURE_A"}, result.Added)
+	assert.Count(t, 0, result.Updated)
+	assert.DeepEqual(t, []string{"LOG_LEVEL"}, result.Unchanged)
+	assert.DeepEqual(t, []string{"FEATURE_B"}, result.Skipped)
+	assert.DeepEqual(t, []string{"DATABASE_URL"}, result.Excluded)
+	assert.True(t, result.Saved, "Execute() should report the target as saved")
+
+	assert.NotNil(t, savedVault, "Execute() should save the target")
+	assert.Equal(t, "prod:on", savedVault.Entries["FEATURE_A"].Value)
+	assert.Equal(t, "prod:on", savedVault.Entries["FEATURE_B"].Value)
+	assert.Equal(t, "prod:postgres://prod", savedVault.Entries["DATABASE_URL"].Value)
+	assert.True(t, savedVault.Entries["API_URL"].Secret, "Execute() should keep the marker")
+
+	assert.Count(t, 2, auditLog.Recorded)
+	assert.Equal(t, model.AuditExport, auditLog.Recorded[0].Operation)
+	assert.DeepEqual(
+		t,
+		[]string{"API_URL", "FEATURE_A", "FEATURE_B", "LOG_LEVEL"},
+		auditLog.Recorded[0].Keys,
+		"the entries read from staging should be recorded",
+	)
+	assert.Equal(t, model.AuditSet, auditLog.Recorded[1].Operation)
+	assert.DeepEqual(t, []string{"API_URL", "FEATURE_A"}, auditLog.Recorded[1].Keys)
+}
+
+func TestPromoteEntriesUseCase_Execute_Overwrite(t *testing.T) {
+	var savedVault *model.Vault
+	useCase := NewPromoteEntriesUseCase(
+		newPromoteVaultService(t, &savedVault),
+		&test.MockAuditLog{},
+	)
+
+	result, err := useCase.Execute(context.Background(), PromoteEntriesDTO{
+		From:      "staging",
+		To:        "prod",
+		Keys:      []string{"FEATURE_B"},
+		Overwrite: true,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"FEATURE_B"}, result.Updated)
+	assert.Equal(t, "prod:off", savedVault.Entries["FEATURE_B"].Value)
+}
+
+func TestPromoteEntriesUseCase_Execute_DryRun(t *testing.T) {
+	var savedVault *model.Vault
+	useCase := NewPromoteEntriesUseCase(
+		newPromoteVaultService(t, &savedVault),
+		&test.MockAuditLog{},
+	)
+
+	result, err := useCase.Execute(context.Background(), PromoteEntriesDTO{
+		From:   "staging",
+		To:     "prod",
+		Keys:   []string{"*"},
+		DryRun: true,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"API_URL", "FEATURE_A"}, result.Added)
+	assert.False(t, result.Saved, "a dry run should not save")
+	assert.Nil(t, savedVault, "a dry run should not save")
+}
+
+func TestPromoteEntriesUseCase_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		dto         PromoteEntriesDTO
+		expectedErr string
+	}{
+		{
+			name:        "same env",
+			dto:         PromoteEntriesDTO{From: "staging", To: "staging", Keys: []string{"*"}},
+			expectedErr: "into itself",
+		},
+		{
+			name:        "no keys",
+			dto:         PromoteEntriesDTO{From: "staging", To: "prod"},
+			expectedErr: "no keys to promote",
+		},
+		{
+			name:        "invalid pattern",
+			dto:         PromoteEntriesDTO{From: "staging", To: "prod", Keys: []string{"[A"}},
+			expectedErr: "invalid key pattern",
+		},
+		{
+			name:        "missing key",
+			dto:         PromoteEntriesDTO{From: "staging", To: "prod", Keys: []string{"MISSING"}},
+			expectedErr: `key "MISSING" not found in staging`,
+		},
+		{
+			name:        "no match",
+			dto:         PromoteEntriesDTO{From: "staging", To: "prod", Keys: []string{"NONE_*"}},
+			expectedErr: "no keys of staging match NONE_*",
+		},
+		{
+			name: "all excluded",
+			dto: PromoteEntriesDTO{
+				From:    "staging",
+				To:      "prod",
+				Keys:    []string{"FEATURE_*"},
+				Exclude: []string{"FEATURE_*"},
+			},
+			expectedErr: "is excluded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedVault *model.Vault
+			useCase := NewPromoteEntriesUseCase(
+				newPromoteVaultService(t, &savedVault),
+				&test.MockAuditLog{},
+			)
+
+			_, err := useCase.Execute(context.Background(), tt.dto)
+			assert.NotNil(t, err, "Execute() expected error, got nil")
+			assert.Contains(t, tt.expectedErr, err.Error())
+			assert.Nil(t, savedVault, "Execute() should not save on error")
+		})
+	}
+}
+
+func TestPromoteEntriesUseCase_Execute_EncryptFails(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{
+				EncryptFunc: func(key string, plaintext []byte) (string, error) {
+					return "", fmt.Errorf("encryption failed")
+				},
+			})
+			return vault, nil
+		},
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Entries[keyTest] = model.Entry{Value: "cipher"}
+			vault.SetSession(&test.MockSession{})
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when a key fails")
+			return nil
+		},
+	}
+
+	useCase := NewPromoteEntriesUseCase(vaultService, &test.MockAuditLog{})
+
+	_, err := useCase.Execute(context.Background(), PromoteEntriesDTO{
+		From: "staging",
+		To:   "prod",
+		Keys: []string{keyTest},
+	})
+	assert.NotNil(t, err, "Execute() expected error, got nil")
+	assert.Contains(t, "encryption failed", err.Error())
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RemoveRecipientUc defines the interface for removing a public-key recipient from a vault.
+type RemoveRecipientUc interface {
+	Execute(ctx context.Context, env, recipient string) error
+}
+
+// RemoveRecipientUseCase implements the use case for removing a public-key recipient
+// from a vault.
+type RemoveRecipientUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewRemoveRecipientUseCase creates a new RemoveRecipientUseCase instance.
+func NewRemoveRecipientUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) RemoveRecipientUc {
+	return &RemoveRecipientUseCase{vaultService, auditLog}
+}
+
+// Execute removes a recipient, given by name or public key, so its identity no longer
+// unlocks the vault.
+func (useCase *RemoveRecipientUseCase) Execute(ctx context.Context, env, recipient string) error {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if err := vault.RemoveRecipient(recipient); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditRemoveRecipient, recipient)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func newRecipientVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, saltTest)
+	vault.AddRecipient(model.Recipient{
+		Name:       "alice",
+		PublicKey:  publicKeyTest,
+		WrappedKey: "alice-wrapped-key",
+	})
+	vault.SetSession(&test.MockSession{})
+	return vault
+}
+
+func TestRemoveRecipientUseCase_Execute_Success(t *testing.T) {
+	for _, recipient := range []string{"alice", publicKeyTest} {
+		t.Run(recipient, func(t *testing.T) {
+			var savedVault *model.Vault
+			vaultService := &test.MockVaultService{
+				OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+					return newRecipientVault(env), nil
+				},
+				SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+					savedVault = vault
+					return nil
+				},
+			}
+
+			auditLog := &test.MockAuditLog{}
+			useCase := NewRemoveRecipientUseCase(vaultService, auditLog)
+
+			err := useCase.Execute(context.Background(), envTest, recipient)
+			assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+			assert.NotNil(t, savedVault, "Execute() should save the vault")
+			assert.Count(t, 0, savedVault.Meta.Recipients)
+			assert.Count(t, 1, auditLog.Recorded)
+			assert.Equal(t, model.AuditRemoveRecipient, auditLog.Recorded[0].Operation)
+			assert.DeepEqual(t, []string{recipient}, auditLog.Recorded[0].Keys)
+		})
+	}
+}
+
+func TestRemoveRecipientUseCase_Execute_NotFound(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when no recipient was removed")
+			return nil
+		},
+	}
+
+	useCase := NewRemoveRecipientUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, "bob")
+	assert.NotNil(t, err, "Execute() with an unknown recipient expected error, got nil")
+	assert.Contains(t, `recipient "bob" not found`, err.Error())
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RemoveSlotUc defines the interface for removing a key slot from a vault.
+type RemoveSlotUc interface {
+	Execute(ctx context.Context, env, name string) error
+}
+
+// RemoveSlotUseCase implements the use case for removing a key slot from a vault.
+type RemoveSlotUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewRemoveSlotUseCase creates a new RemoveSlotUseCase instance.
+func NewRemoveSlotUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) RemoveSlotUc {
+	return &RemoveSlotUseCase{vaultService, auditLog}
+}
+
+// Execute removes a named key slot so its passphrase no longer unlocks the vault.
+func (useCase *RemoveSlotUseCase) Execute(ctx context.Context, env, name string) error {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if err := vault.RemoveSlot(name); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditRemoveSlot, name)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func newSlottedVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, saltTest)
+	vault.AddSlot(model.KeySlot{
+		Name:        "ci",
+		Salt:        "ci-salt",
+		FingerPrint: "ci-fingerprint",
+		WrappedKey:  "ci-wrapped-key",
+	})
+	vault.SetSession(&test.MockSession{})
+	return vault
+}
+
+func TestRemoveSlotUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newSlottedVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{}
+	useCase := NewRemoveSlotUseCase(vaultService, auditLog)
+
+	err := useCase.Execute(context.Background(), envTest, "ci")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Count(t, 0, savedVault.Meta.Slots)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditRemoveSlot, auditLog.Recorded[0].Operation)
+	assert.DeepEqual(t, []string{"ci"}, auditLog.Recorded[0].Keys)
+}
+
+func TestRemoveSlotUseCase_Execute_DefaultSlot(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newSlottedVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when the slot cannot be removed")
+			return nil
+		},
+	}
+
+	useCase := NewRemoveSlotUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, model.DefaultSlotName)
+	assert.NotNil(t, err, "Execute() removing the default slot expected error, got nil")
+	assert.Contains(t, "cannot be removed", err.Error())
+}
+
+func TestRemoveSlotUseCase_Execute_NotFound(t *testing.T) {
+	useCase := NewRemoveSlotUseCase(&test.MockVaultService{}, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), envTest, "missing")
+	assert.NotNil(t, err, "Execute() with an unknown slot expected error, got nil")
+	assert.Contains(t, `slot "missing" not found`, err.Error())
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RenameEnvUc defines the interface for renaming the environment of a vault.
+type RenameEnvUc interface {
+	Execute(ctx context.Context, from, to string) (string, error)
+}
+
+// RenameEnvUseCase implements the use case for renaming the environment of a vault.
+type RenameEnvUseCase struct {
+	vaultService      service.VaultServiceInterface
+	vaultRepo         repository.VaultRepository
+	passphraseService service.PassphraseService
+	encryptionService service.EncryptionService
+	auditLog          service.AuditLog
+}
+
+// NewRenameEnvUseCase creates a new RenameEnvUseCase instance.
+func NewRenameEnvUseCase(
+	vaultService service.VaultServiceInterface,
+	vaultRepo repository.VaultRepository,
+	passphraseService service.PassphraseService,
+	encryptionService service.EncryptionService,
+	auditLog service.AuditLog,
+) RenameEnvUc {
+	return &RenameEnvUseCase{
+		vaultService,
+		vaultRepo,
+		passphraseService,
+		encryptionService,
+		auditLog,
+	}
+}
+
+// Execute moves the vault of environment from to environment to, keeping its passphrase and
+// recipients, and returns the path of the backup left of the old vault. The environment is
+// bound into every entry and wrapped key, so the vault is re-encrypted rather than moved. The
+// audit log is carried over to the new environment.
+func (useCase *RenameEnvUseCase) Execute(ctx context.Context, from, to string) (string, error) {
+	if err := checkTargetEnv(ctx, useCase.vaultRepo, from, to); err != nil {
+		return "", err
+	}
+
+	source, err := useCase.vaultService.OpenForUpdate(ctx, from)
+	if err != nil {
+		return "", err
+	}
+	defer source.Lock()
+
+	if len(source.Meta.Slots) > 0 {
+		// The data key cannot be wrapped again for slots whose passphrases are unknown here.
+		return "", fmt.Errorf(
+			"cannot rename environment %s: remove its %d other key slot(s) first",
+			from,
+			len(source.Meta.Slots),
+		)
+	}
+
+	events, err := useCase.auditLog.Events(ctx, source)
+	if err != nil {
+		return "", fmt.Errorf(
+			"cannot carry over the audit log of environment %s, inspect it with "+
+				"`lockify audit --env %s`: %w",
+			from,
+			from,
+			err,
+		)
+	}
+
+	passphrase, err := useCase.defaultPassphrase(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := useCase.vaultService.New(ctx, to, passphrase)
+	if err != nil {
+		return "", err
+	}
+	defer target.Lock()
+
+	if err := copyEntries(source, target); err != nil {
+		return "", err
+	}
+	for _, recipient := range source.Meta.Recipients {
+		recipient.WrappedKey, err = target.Session().WrapKeyForRecipient(
+			target.Meta,
+			recipient.PublicKey,
+		)
+		if err != nil {
+			return "", fmt.Errorf(
+				"failed to wrap data key for recipient %q: %w",
+				recipient.Label(),
+				err,
+			)
+		}
+		target.Meta.Recipients = append(target.Meta.Recipients, recipient)
+	}
+
+	if err := useCase.vaultService.SaveNew(ctx, target); err != nil {
+		return "", err
+	}
+	if err := startAuditLog(ctx, useCase.auditLog, target, events); err != nil {
+		return "", err
+	}
+
+	// The old vault is still locked by this process, so it is deleted without locking again.
+	backupPath, err := useCase.vaultRepo.Delete(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf(
+			"vault was copied to environment %s but environment %s could not be deleted: %w",
+			to,
+			from,
+			err,
+		)
+	}
+	//nolint:errcheck // The rename already happened; a stale cache entry is harmless
+	useCase.passphraseService.Clear(ctx, from)
+
+	return backupPath, nil
+}
+
+// defaultPassphrase returns the passphrase of the default key slot of the vault, asking for
+// it when the vault was unlocked with a recipient identity
+func (useCase *RenameEnvUseCase) defaultPassphrase(
+	ctx context.Context,
+	vault *model.Vault,
+) (string, error) {
+	if vault.UnlockedSlot() == model.DefaultSlotName && vault.Passphrase() != "" {
+		return vault.Passphrase(), nil
+	}
+
+	passphrase, err := useCase.passphraseService.Get(ctx, vault.Meta.Env)
+	if err != nil {
+		return "", fmt.Errorf("failed to get passphrase: %w", err)
+	}
+	session, err := useCase.encryptionService.NewSession(vault.Meta, passphrase)
+	if errors.Is(err, model.ErrWrongPassphrase) {
+		return "", fmt.Errorf("invalid credentials: %w", err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check passphrase: %w", err)
+	}
+	session.Close()
+	return passphrase, nil
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func newRenameSourceVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, saltTest)
+	vault.Entries[keyTest] = model.Entry{Value: "cipher", UpdatedAt: "2026-02-01T00:00:00Z"}
+	vault.Meta.Recipients = []model.Recipient{
+		{Name: "alice", PublicKey: publicKeyTest, WrappedKey: "old-wrapped-key"},
+	}
+	vault.SetPassphrase(passphraseTest)
+	vault.SetSession(&test.MockSession{})
+	vault.SetUnlockedSlot(model.DefaultSlotName)
+	return vault
+}
+
+func TestRenameEnvUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newRenameSourceVault(env), nil
+		},
+		NewFunc: func(ctx context.Context, env, passphrase string) (*model.Vault, error) {
+			assert.Equal(t, "production", env)
+			assert.Equal(t, passphraseTest, passphrase)
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetSession(&test.MockSession{
+				WrapKeyForRecipientFunc: func(meta model.Meta, publicKey string) (string, error) {
+					assert.Equal(t, "production", meta.Env)
+					return "new-wrapped-key", nil
+				},
+			})
+			return vault, nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	var deletedEnv, clearedEnv string
+	vaultRepo := &test.MockVaultRepository{
+		DeleteFunc: func(ctx context.Context, env string) (string, error) {
+			assert.NotNil(t, savedVault, "Delete() should only be called once the copy is saved")
+			deletedEnv = env
+			return "prod.vault.enc.bak.1", nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Get() should not be called when the default passphrase is known")
+			return "", nil
+		},
+		ClearFunc: func(ctx context.Context, env string) error {
+			clearedEnv = env
+			return nil
+		},
+	}
+
+	useCase := NewRenameEnvUseCase(
+		vaultService,
+		vaultRepo,
+		passphraseService,
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	backupPath, err := useCase.Execute(context.Background(), "prod", "production")
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, "prod.vault.enc.bak.1", backupPath)
+	assert.Equal(t, "prod", deletedEnv)
+	assert.Equal(t, "prod", clearedEnv)
+
+	assert.Equal(t, "production", savedVault.Meta.Env)
+	assert.Equal(t, "2026-02-01T00:00:00Z", savedVault.Entries[keyTest].UpdatedAt)
+	assert.Count(t, 1, savedVault.Meta.Recipients)
+	assert.Equal(t, "alice", savedVault.Meta.Recipients[0].Name)
+	assert.Equal(t, "new-wrapped-key", savedVault.Meta.Recipients[0].WrappedKey)
+}
+
+func TestRenameEnvUseCase_Execute_UnlockedWithIdentity(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newRenameSourceVault(env)
+			vault.SetPassphrase("")
+			vault.SetUnlockedSlot(model.RecipientSlotPrefix + "alice")
+			return vault, nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			assert.Equal(t, "prod", env)
+			return "wrong-passphrase", nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			assert.Equal(t, "wrong-passphrase", passphrase)
+			return nil, model.ErrWrongPassphrase
+		},
+	}
+	vaultRepo := &test.MockVaultRepository{
+		DeleteFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Delete() should not be called with invalid credentials")
+			return "", nil
+		},
+	}
+
+	useCase := NewRenameEnvUseCase(
+		vaultService,
+		vaultRepo,
+		passphraseService,
+		encryptionService,
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), "prod", "production")
+	assert.NotNil(t, err, "Execute() with a wrong passphrase expected error, got nil")
+	assert.Contains(t, "invalid credentials", err.Error())
+}
+
+func TestRenameEnvUseCase_Execute_ExtraSlots(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newRenameSourceVault(env)
+			vault.Meta.Slots = []model.KeySlot{{Name: "ci"}}
+			return vault, nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("SaveNew() should not be called for a vault with extra key slots")
+			return nil
+		},
+	}
+
+	useCase := NewRenameEnvUseCase(
+		vaultService,
+		&test.MockVaultRepository{},
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), "prod", "production")
+	assert.NotNil(t, err, "Execute() expected error, got nil")
+	assert.Contains(t, "remove its 1 other key slot(s) first", err.Error())
+}
+
+func TestRenameEnvUseCase_Execute_SaveFails(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newRenameSourceVault(env), nil
+		},
+		SaveNewFunc: func(ctx context.Context, vault *model.Vault) error {
+			return errors.New("disk full")
+		},
+	}
+	vaultRepo := &test.MockVaultRepository{
+		DeleteFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Delete() should not be called when the copy was not saved")
+			return "", nil
+		},
+	}
+
+	useCase := NewRenameEnvUseCase(
+		vaultService,
+		vaultRepo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := useCase.Execute(context.Background(), "prod", "production")
+	assert.NotNil(t, err, "Execute() expected error, got nil")
+	assert.Contains(t, "disk full", err.Error())
+}
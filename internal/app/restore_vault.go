@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RestoreVaultUc defines the interface for restoring a vault from one of its backups.
+type RestoreVaultUc interface {
+	Execute(ctx context.Context, env string, generation int) (string, error)
+}
+
+// RestoreVaultUseCase implements the use case for restoring a vault from one of its backups.
+type RestoreVaultUseCase struct {
+	vaultRepo    repository.VaultRepository
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewRestoreVaultUseCase creates a new RestoreVaultUseCase instance.
+func NewRestoreVaultUseCase(
+	vaultRepo repository.VaultRepository,
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) RestoreVaultUc {
+	return &RestoreVaultUseCase{vaultRepo, vaultService, auditLog}
+}
+
+// Execute replaces the vault of an environment with a backup generation, 1 being the newest,
+// and returns the path of the backup it was restored from. A deleted environment can be
+// restored from its backups too. The restored vault is unlocked to record the restore in its
+// audit log.
+func (useCase *RestoreVaultUseCase) Execute(
+	ctx context.Context,
+	env string,
+	generation int,
+) (string, error) {
+	if generation < 1 {
+		return "", fmt.Errorf("generation must be at least 1, got %d", generation)
+	}
+
+	path, err := useCase.vaultRepo.Restore(ctx, env, generation)
+	if err != nil {
+		return "", fmt.Errorf("failed to restore vault for environment %s: %w", env, err)
+	}
+
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return path, fmt.Errorf("vault was restored from %s but cannot be unlocked: %w", path, err)
+	}
+	defer vault.Lock()
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditRestore); err != nil {
+		return path, err
+	}
+
+	return path, nil
+}
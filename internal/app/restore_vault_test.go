@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestRestoreVaultUseCase_Execute_Success(t *testing.T) {
+	var restoredGeneration int
+	vaultRepo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		RestoreFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			restoredGeneration = generation
+			return "prod.vault.enc.bak.2", nil
+		},
+	}
+	auditLog := &test.MockAuditLog{}
+
+	useCase := NewRestoreVaultUseCase(vaultRepo, &test.MockVaultService{}, auditLog)
+
+	path, err := useCase.Execute(context.Background(), envTest, 2)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 2, restoredGeneration)
+	assert.Equal(t, "prod.vault.enc.bak.2", path)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditRestore, auditLog.Recorded[0].Operation)
+}
+
+func TestRestoreVaultUseCase_Execute_UnlockError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		RestoreFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			return envTest + ".vault.enc.bak.1", nil
+		},
+	}
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, model.ErrWrongPassphrase
+		},
+	}
+	auditLog := &test.MockAuditLog{}
+	useCase := NewRestoreVaultUseCase(vaultRepo, vaultService, auditLog)
+
+	path, err := useCase.Execute(context.Background(), envTest, 1)
+	assert.True(t, errors.Is(err, model.ErrWrongPassphrase), "Execute() should report the unlock")
+	assert.Contains(t, "was restored from", err.Error())
+	assert.Equal(t, envTest+".vault.enc.bak.1", path)
+	assert.Count(t, 0, auditLog.Recorded)
+}
+
+func TestRestoreVaultUseCase_Execute_InvalidGeneration(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		RestoreFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			t.Error("Restore() should not be called for an invalid generation")
+			return "", nil
+		},
+	}
+
+	useCase := NewRestoreVaultUseCase(vaultRepo, &test.MockVaultService{}, &test.MockAuditLog{})
+
+	_, err := useCase.Execute(context.Background(), envTest, 0)
+	assert.NotNil(t, err, "Execute() with generation 0 expected error, got nil")
+	assert.Contains(t, "generation must be at least 1", err.Error())
+}
+
+func TestRestoreVaultUseCase_Execute_DeletedVault(t *testing.T) {
+	restored := false
+	vaultRepo := &test.MockVaultRepository{
+		RestoreFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			restored = true
+			return envTest + ".vault.enc.bak.1", nil
+		},
+	}
+	useCase := NewRestoreVaultUseCase(vaultRepo, &test.MockVaultService{}, &test.MockAuditLog{})
+
+	_, err := useCase.Execute(context.Background(), envTest, 1)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, restored, "Execute() should restore a deleted environment from its backup")
+}
+
+func TestRestoreVaultUseCase_Execute_RestoreError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		RestoreFunc: func(ctx context.Context, env string, generation int) (string, error) {
+			return "", errors.New("backup generation 3 not found")
+		},
+	}
+
+	useCase := NewRestoreVaultUseCase(vaultRepo, &test.MockVaultService{}, &test.MockAuditLog{})
+
+	_, err := useCase.Execute(context.Background(), envTest, 3)
+	assert.NotNil(t, err, "Execute() with restore error expected error, got nil")
+	assert.Contains(t, "backup generation 3 not found", err.Error())
+}
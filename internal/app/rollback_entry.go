@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RollbackEntryUc defines the interface for restoring a previous value of an entry.
+type RollbackEntryUc interface {
+	Execute(ctx context.Context, env, key string, version int) error
+}
+
+// RollbackEntryUseCase implements the use case for restoring a previous value of an entry.
+type RollbackEntryUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewRollbackEntryUseCase creates a new RollbackEntryUseCase instance.
+func NewRollbackEntryUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) RollbackEntryUc {
+	return &RollbackEntryUseCase{vaultService, auditLog}
+}
+
+// Execute makes a version from the history of an entry its current value again. The value
+// it replaces is kept in the history like any other change.
+func (useCase *RollbackEntryUseCase) Execute(
+	ctx context.Context,
+	env, key string,
+	version int,
+) error {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if err := vault.RollbackEntry(key, version); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditSet, key)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestRollbackEntryUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newHistoryVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	useCase := NewRollbackEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), "prod", "DB_URL", 1)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+
+	entry := savedVault.Entries["DB_URL"]
+	assert.Equal(t, "prod/DB_URL:v1", entry.Value)
+	assert.Equal(t, 4, entry.Version)
+	assert.Equal(t, "prod/DB_URL:v3", entry.History[len(entry.History)-1].Value)
+}
+
+func TestRollbackEntryUseCase_Execute_UnknownVersion(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newHistoryVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when the version does not exist")
+			return nil
+		},
+	}
+
+	useCase := NewRollbackEntryUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), "prod", "DB_URL", 9)
+	assert.NotNil(t, err, "Execute() with an unknown version expected error, got nil")
+	assert.Contains(t, "version 9 of key \"DB_URL\" not found", err.Error())
+}
@@ -2,83 +2,285 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
-	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
 
+// SlotPassphraseFunc returns the passphrase of the named key slot, so that a new data key can
+// be wrapped for it.
+type SlotPassphraseFunc func(slot string) (string, error)
+
 // RotatePassphraseUc defines the interface for rotating vault passphrases.
 type RotatePassphraseUc interface {
-	Execute(ctx context.Context, env, currentPassphrase, newPassphrase string) error
+	Execute(
+		ctx context.Context,
+		env, currentPassphrase, newPassphrase string,
+		reencrypt bool,
+		slotPassphrase SlotPassphraseFunc,
+	) error
 }
 
 // RotatePassphraseUseCase implements the use case for rotating vault passphrases.
 type RotatePassphraseUseCase struct {
 	vaultRepo         repository.VaultRepository
 	encryptionService service.EncryptionService
-	hashService       service.HashService
+	passphraseService service.PassphraseService
+	passphrasePolicy  service.PassphrasePolicy
+	auditLog          service.AuditLog
 }
 
 // NewRotatePassphraseUseCase creates a new RotatePassphraseUseCase instance.
 func NewRotatePassphraseUseCase(
 	vaultRepo repository.VaultRepository,
 	encryptionService service.EncryptionService,
-	hashService service.HashService,
+	passphraseService service.PassphraseService,
+	passphrasePolicy service.PassphrasePolicy,
+	auditLog service.AuditLog,
 ) RotatePassphraseUc {
-	return &RotatePassphraseUseCase{vaultRepo, encryptionService, hashService}
+	return &RotatePassphraseUseCase{
+		vaultRepo,
+		encryptionService,
+		passphraseService,
+		passphrasePolicy,
+		auditLog,
+	}
 }
 
-// Execute rotates the passphrase for a vault by re-encrypting all entries with the new passphrase.
+// Execute rotates the passphrase of the key slot that currentPassphrase unlocks by
+// re-wrapping the vault data key with the new passphrase, once the passphrase policy accepts
+// it. With reencrypt, a new data key is generated and every entry, as well as the audit log,
+// is re-encrypted; the key is wrapped for every recipient and for every other key slot, whose
+// passphrase slotPassphrase gives. Without slotPassphrase, vaults with other key slots fail
+// with model.ErrOtherKeySlots. Vaults older than
+// model.FormatVersionEnvelope have no data key yet and are upgraded to the current format
+// first, as `lockify migrate` would.
 func (useCase *RotatePassphraseUseCase) Execute(
 	ctx context.Context,
 	env, currentPassphrase, newPassphrase string,
+	reencrypt bool,
+	slotPassphrase SlotPassphraseFunc,
 ) error {
+	if err := useCase.passphrasePolicy.Check(ctx, env, newPassphrase); err != nil {
+		return err
+	}
+
+	release, err := useCase.vaultRepo.Lock(ctx, env, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	vault, err := useCase.vaultRepo.Load(ctx, env)
 	if err != nil {
 		return fmt.Errorf("failed to open vault for environment %s: %w", env, err)
 	}
 
-	if err = useCase.hashService.Verify(vault.Meta.FingerPrint, currentPassphrase); err != nil {
-		return fmt.Errorf("invalid credentials: %w", err)
+	if vault.Meta.FormatVersion > model.CurrentFormatVersion {
+		return fmt.Errorf(
+			"vault for environment %s uses format version %d, this lockify supports up to %d",
+			env,
+			vault.Meta.FormatVersion,
+			model.CurrentFormatVersion,
+		)
 	}
 
-	currentSalt := vault.Meta.Salt
-	newSalt, err := useCase.hashService.GenerateSalt(config.DefaultSaltSize)
-	if err != nil {
-		return fmt.Errorf("failed to generate salt: %w", err)
+	upgrade := vault.Meta.FormatVersion < model.FormatVersionEnvelope
+	if err := useCase.unlock(ctx, vault, currentPassphrase); err != nil {
+		return err
+	}
+	defer vault.Lock()
+	if err = vault.VerifyIntegrity(); err != nil {
+		return err
+	}
+	// Every other slot keeps its passphrase; the unlocked one gets newPassphrase below.
+	passphrases := map[string]string{}
+	if reencrypt {
+		passphrases, err = useCase.otherSlotPassphrases(vault, slotPassphrase)
+		if err != nil {
+			return err
+		}
 	}
 
-	vault.Meta.Salt = newSalt
-	vault.Meta.FingerPrint, err = useCase.hashService.Hash(newPassphrase)
-	if err != nil {
-		return fmt.Errorf("failed to hash the fingerprint")
+	var events []model.AuditEvent
+	if reencrypt || upgrade {
+		// The audit log is sealed with the vault key, so it is read before the key changes.
+		events, err = useCase.auditLog.Events(ctx, vault)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot re-encrypt the audit log of environment %s, inspect it with "+
+					"`lockify audit --env %s`: %w",
+				env,
+				env,
+				err,
+			)
+		}
 	}
 
-	for key := range vault.Entries {
-		entry := vault.Entries[key]
-		decryptedValue, err := useCase.encryptionService.Decrypt(
-			entry.Value,
-			currentSalt,
-			currentPassphrase,
+	if upgrade {
+		if err := upgradeVault(useCase.encryptionService, vault); err != nil {
+			return err
+		}
+	}
+	if reencrypt {
+		if err := useCase.reencrypt(vault); err != nil {
+			return err
+		}
+	}
+
+	passphrases[vault.UnlockedSlot()] = newPassphrase
+	if err := useCase.rewrapSlots(vault, passphrases); err != nil {
+		return err
+	}
+
+	if err = vault.Seal(); err != nil {
+		return fmt.Errorf("failed to seal vault: %w", err)
+	}
+
+	if err = useCase.vaultRepo.Save(ctx, vault); err != nil {
+		return err
+	}
+	if reencrypt || upgrade {
+		if err := useCase.auditLog.Rewrite(ctx, vault, events); err != nil {
+			return fmt.Errorf("vault was re-encrypted but its audit log was not: %w", err)
+		}
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditRotate)
+}
+
+// unlock unlocks vault with the key slot that passphrase opens. Vaults older than
+// model.FormatVersionEnvelope have no wrapped data key, so only their bcrypt fingerprint tells
+// a wrong passphrase.
+func (useCase *RotatePassphraseUseCase) unlock(
+	ctx context.Context,
+	vault *model.Vault,
+	passphrase string,
+) error {
+	slots := vault.Meta.KeySlots()
+	if vault.Meta.FormatVersion < model.FormatVersionEnvelope {
+		slot, err := useCase.passphraseService.Validate(ctx, vault, passphrase)
+		if err != nil {
+			return fmt.Errorf("invalid credentials: %w", err)
+		}
+		slots = []model.KeySlot{slot}
+	}
+
+	for _, slot := range slots {
+		session, err := useCase.encryptionService.NewSession(vault.Meta.ForSlot(slot), passphrase)
+		if errors.Is(err, model.ErrWrongPassphrase) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to unlock vault: %w", err)
+		}
+		vault.SetSession(session)
+		vault.SetPassphrase(passphrase)
+		vault.SetUnlockedSlot(slot.Name)
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid credentials: passphrase does not match any key slot: %w",
+		model.ErrWrongPassphrase,
+	)
+}
+
+// otherSlotPassphrases asks slotPassphrase for the passphrase of every key slot besides the
+// unlocked one and checks that it opens its slot, so that a new data key can be wrapped for
+// every slot. It returns the passphrases by slot name.
+func (useCase *RotatePassphraseUseCase) otherSlotPassphrases(
+	vault *model.Vault,
+	slotPassphrase SlotPassphraseFunc,
+) (map[string]string, error) {
+	passphrases := map[string]string{}
+	others := vault.OtherSlotNames()
+	if len(others) == 0 {
+		return passphrases, nil
+	}
+	if slotPassphrase == nil {
+		return nil, fmt.Errorf(
+			"cannot re-encrypt vault for environment %s: %w: %s; give their passphrases or "+
+				"remove them with `lockify slot remove`",
+			vault.Meta.Env,
+			model.ErrOtherKeySlots,
+			strings.Join(others, ", "),
 		)
+	}
+
+	for _, slot := range vault.Meta.KeySlots() {
+		if slot.Name == vault.UnlockedSlot() {
+			continue
+		}
+		passphrase, err := slotPassphrase(slot.Name)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt key %s: %w", key, err)
+			return nil, fmt.Errorf("failed to get passphrase of key slot %q: %w", slot.Name, err)
 		}
+		session, err := useCase.encryptionService.NewSession(vault.Meta.ForSlot(slot), passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("passphrase does not open key slot %q: %w", slot.Name, err)
+		}
+		session.Close()
+		passphrases[slot.Name] = passphrase
+	}
+	return passphrases, nil
+}
 
-		encryptedValue, err := useCase.encryptionService.Encrypt(
-			decryptedValue,
-			newSalt,
-			newPassphrase,
+// rewrapSlots wraps the current data key of vault for each named key slot in passphrases,
+// with a new salt and the passphrase given for it
+func (useCase *RotatePassphraseUseCase) rewrapSlots(
+	vault *model.Vault,
+	passphrases map[string]string,
+) error {
+	for name, passphrase := range passphrases {
+		salt, err := useCase.encryptionService.NewSalt()
+		if err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		wrappedKey, err := vault.Session().WrapKey(
+			vault.Meta.ForSlot(model.KeySlot{Salt: salt}),
+			passphrase,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to encrypt key %s: %w", key, err)
+			return fmt.Errorf("failed to wrap data key for key slot %q: %w", name, err)
 		}
+		if err := vault.RewrapSlot(name, salt, wrappedKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		entry.Value = encryptedValue
-		vault.Entries[key] = entry
+// reencrypt re-seals every entry of vault with a new data key, which is wrapped for every
+// recipient, and makes it the vault session
+func (useCase *RotatePassphraseUseCase) reencrypt(vault *model.Vault) error {
+	newSession, err := useCase.encryptionService.NewDataKey(vault.Meta)
+	if err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
 	}
+	if err = resealEntries(vault, newSession); err != nil {
+		newSession.Close()
+		return err
+	}
+	vault.Session().Close()
+	vault.SetSession(newSession)
 
-	return useCase.vaultRepo.Save(ctx, vault)
+	// Recipients only need their public key to receive the new data key.
+	for i, recipient := range vault.Meta.Recipients {
+		vault.Meta.Recipients[i].WrappedKey, err = newSession.WrapKeyForRecipient(
+			vault.Meta,
+			recipient.PublicKey,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to wrap data key for recipient %q: %w",
+				recipient.Label(),
+				err,
+			)
+		}
+	}
+	return nil
 }
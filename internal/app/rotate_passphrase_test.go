@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
@@ -11,24 +12,28 @@ import (
 	"github.com/ahmed-abdelgawad92/lockify/test/assert"
 )
 
-const (
-	newSalt        = "new-salt"
-	newFingerprint = "new-fingerprint"
-)
+const newSalt = "new-salt"
+
+// sealTestVault writes the manifest and MAC a saved vault carries, using a mock session.
+func sealTestVault(vault *model.Vault) {
+	vault.SetSession(&test.MockSession{})
+	vault.Seal()
+	vault.SetSession(nil)
+}
 
 func TestRotatePassphraseUseCase_Execute_Success(t *testing.T) {
 	currentPassphrase := "old-passphrase"
 	newPassphrase := "new-passphrase"
 	currentSalt := "old-salt"
-	currentFingerprint := "old-fingerprint"
 
-	vault, _ := model.NewVault(envTest, currentFingerprint, currentSalt)
+	vault, _ := model.NewVault(envTest, currentSalt)
 	vault.SetEntry("key1", "encrypted-value-1")
 	vault.SetEntry("key2", "encrypted-value-2")
+	sealTestVault(vault)
 
 	var savedVault *model.Vault
-	decryptCallCount := 0
-	encryptCallCount := 0
+	var wrappedMeta model.Meta
+	wrappedPassphrase := ""
 
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
@@ -40,71 +45,56 @@ func TestRotatePassphraseUseCase_Execute_Success(t *testing.T) {
 		},
 	}
 
-	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
-			decryptCallCount++
-			if encodedSalt != currentSalt {
-				t.Errorf("Decrypt() called with salt %q, want %q", encodedSalt, currentSalt)
-			}
-			if passphrase != currentPassphrase {
-				t.Errorf(
-					"Decrypt() called with passphrase %q, want %q",
-					passphrase,
-					currentPassphrase,
-				)
-			}
-			return []byte("decrypted-value"), nil
+	currentSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			t.Error("Execute() should not decrypt entries without reencrypt")
+			return nil, nil
 		},
-		EncryptFunc: func(plaintext []byte, encodedSalt, passphrase string) (string, error) {
-			encryptCallCount++
-			if encodedSalt != newSalt {
-				t.Errorf("Encrypt() called with salt %q, want %q", encodedSalt, newSalt)
-			}
-			if passphrase != newPassphrase {
-				t.Errorf("Encrypt() called with passphrase %q, want %q", passphrase, newPassphrase)
-			}
-			return "new-encrypted-value", nil
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			t.Error("Execute() should not encrypt entries without reencrypt")
+			return "", nil
+		},
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			wrappedMeta = meta
+			wrappedPassphrase = passphrase
+			return "rewrapped-key", nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			if hashedPassphrase != currentFingerprint {
-				t.Errorf(
-					"Verify() called with fingerprint %q, want %q",
-					hashedPassphrase,
-					currentFingerprint,
-				)
-			}
-			if passphrase != currentPassphrase {
-				t.Errorf(
-					"Verify() called with passphrase %q, want %q",
-					passphrase,
-					currentPassphrase,
-				)
-			}
-			return nil
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			assert.Equal(t, currentSalt, meta.Salt)
+			assert.Equal(t, currentPassphrase, passphrase)
+			return currentSession, nil
 		},
-		GenerateSaltFunc: func(size int) (string, error) {
-			if size != 16 {
-				t.Errorf("GenerateSalt() called with size %d, want 16", size)
-			}
-			return newSalt, nil
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			t.Error("Execute() should not generate a data key without reencrypt")
+			return &test.MockSession{}, nil
 		},
-		HashFunc: func(passphrase string) (string, error) {
-			if passphrase != newPassphrase {
-				t.Errorf("Hash() called with passphrase %q, want %q", passphrase, newPassphrase)
-			}
-			return newFingerprint, nil
+		NewSaltFunc: func() (string, error) {
+			return newSalt, nil
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, encryptionService, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, currentPassphrase, newPassphrase)
+	err := useCase.Execute(
+		context.Background(),
+		envTest,
+		currentPassphrase,
+		newPassphrase,
+		false,
+		nil,
+	)
 	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
 
-	// Verify vault was saved with new salt and fingerprint
+	// Verify vault was saved with new salt and wrapped key and no fingerprint
 	assert.NotNil(
 		t,
 		savedVault,
@@ -116,18 +106,84 @@ func TestRotatePassphraseUseCase_Execute_Success(t *testing.T) {
 		savedVault.Meta.Salt,
 		fmt.Sprintf("Execute() should update salt to %q, got %q", newSalt, savedVault.Meta.Salt),
 	)
-	assert.Equal(
-		t,
-		newFingerprint,
-		savedVault.Meta.FingerPrint,
-		fmt.Sprintf(
-			"Execute() should update fingerprint to %q, got %q",
-			newFingerprint,
-			savedVault.Meta.FingerPrint,
-		),
+	assert.Equal(t, "", savedVault.Meta.FingerPrint, "Execute() should not store a fingerprint")
+	assert.Equal(t, "rewrapped-key", savedVault.Meta.WrappedKey)
+	assert.Equal(t, newSalt, wrappedMeta.Salt, "WrapKey() should use the new salt")
+	assert.Equal(t, newPassphrase, wrappedPassphrase, "WrapKey() should use the new passphrase")
+	assert.True(t, currentSession.Closed, "Execute() should close the current session")
+
+	// Verify entries were left untouched
+	entry1, _ := savedVault.GetEntry("key1")
+	entry2, _ := savedVault.GetEntry("key2")
+	assert.Equal(t, "encrypted-value-1", entry1.Value)
+	assert.Equal(t, "encrypted-value-2", entry2.Value)
+}
+
+func TestRotatePassphraseUseCase_Execute_Reencrypt(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	vault.SetEntry("key1", "encrypted-value-1")
+	vault.SetEntry("key2", "encrypted-value-2")
+	sealTestVault(vault)
+
+	var savedVault *model.Vault
+	decryptCallCount := 0
+	encryptCallCount := 0
+
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	currentSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			decryptCallCount++
+			return []byte("decrypted-value"), nil
+		},
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			t.Error("Execute() should wrap the new data key, not the old one")
+			return "", nil
+		},
+	}
+	newSession := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			encryptCallCount++
+			return "new-encrypted-value", nil
+		},
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			return "new-wrapped-key", nil
+		},
+	}
+
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return currentSession, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return newSession, nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{
+		Recorded: []model.AuditEvent{{Seq: 1, Operation: model.AuditSet, Keys: []string{"key1"}}},
+	}
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		auditLog,
 	)
 
-	// Verify all entries were re-encrypted
+	err := useCase.Execute(context.Background(), envTest, "old", "new", true, nil)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+
+	// Verify all entries were re-encrypted with the new data key
 	assert.Equal(
 		t,
 		2,
@@ -140,23 +196,147 @@ func TestRotatePassphraseUseCase_Execute_Success(t *testing.T) {
 		encryptCallCount,
 		fmt.Sprintf("Execute() should encrypt 2 entries, encrypted %d", encryptCallCount),
 	)
+	assert.Equal(t, "new-wrapped-key", savedVault.Meta.WrappedKey)
+	assert.True(t, currentSession.Closed, "Execute() should close the current session")
+	assert.True(t, newSession.Closed, "Execute() should close the new session")
 
-	// Verify entries have new encrypted values
 	entry1, _ := savedVault.GetEntry("key1")
 	entry2, _ := savedVault.GetEntry("key2")
+	assert.Equal(t, "new-encrypted-value", entry1.Value)
+	assert.Equal(t, "new-encrypted-value", entry2.Value)
 
-	assert.Equal(
-		t,
-		"new-encrypted-value",
-		entry1.Value,
-		fmt.Sprintf("Execute() should update entry1 value, got %q", entry1.Value),
+	// Verify the audit log was kept and the rotation recorded
+	assert.Equal(t, 2, len(auditLog.Recorded), "Execute() should keep the audit log")
+	assert.Equal(t, model.AuditSet, auditLog.Recorded[0].Operation)
+	assert.Equal(t, model.AuditRotate, auditLog.Recorded[1].Operation)
+}
+
+func TestRotatePassphraseUseCase_Execute_LegacyFormat(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	vault.Meta.FormatVersion = model.FormatVersionVaultMAC
+	vault.Meta.FingerPrint = "legacy-fingerprint"
+	vault.SetEntry(keyTest, encryptedValueTest)
+	sealTestVault(vault)
+
+	var savedVault *model.Vault
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	validated := false
+	passphraseService := &test.MockPassphraseService{
+		ValidateFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			passphrase string,
+		) (model.KeySlot, error) {
+			validated = true
+			assert.Equal(t, "old", passphrase)
+			return vault.Meta.KeySlots()[0], nil
+		},
+	}
+	oldSession := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			return []byte(valueTest), nil
+		},
+	}
+	var wrappedPassphrase string
+	dataKeySession := &test.MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return "data-key:" + key + ":" + string(plaintext), nil
+		},
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			wrappedPassphrase = passphrase
+			return "wrapped-with-" + passphrase, nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return oldSession, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			assert.Equal(t, model.FormatVersionEnvelope, meta.FormatVersion)
+			return dataKeySession, nil
+		},
+		NewSaltFunc: func() (string, error) {
+			return newSalt, nil
+		},
+	}
+	rewritten := false
+	auditLog := &test.MockAuditLog{
+		RewriteFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			events []model.AuditEvent,
+		) error {
+			rewritten = true
+			return nil
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		passphraseService,
+		&test.MockPassphrasePolicy{},
+		auditLog,
 	)
-	assert.Equal(
-		t,
-		"new-encrypted-value",
-		entry2.Value,
-		fmt.Sprintf("Execute() should update entry2 value, got %q", entry2.Value),
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, validated, "Execute() should check the fingerprint of a legacy vault")
+	assert.NotNil(t, savedVault, "Execute() should save the upgraded vault")
+	assert.Equal(t, model.CurrentFormatVersion, savedVault.Meta.FormatVersion)
+	assert.Equal(t, "", savedVault.Meta.FingerPrint, "Execute() should drop the fingerprint")
+	assert.Equal(t, newSalt, savedVault.Meta.Salt)
+	assert.Equal(t, "wrapped-with-new", savedVault.Meta.WrappedKey)
+	assert.Equal(t, "new", wrappedPassphrase)
+	entry, _ := savedVault.GetEntry(keyTest)
+	assert.Equal(t, "data-key:"+keyTest+":"+valueTest, entry.Value)
+	assert.True(t, rewritten, "Execute() should re-encrypt the audit log with the data key")
+	assert.True(t, oldSession.Closed, "Execute() should close the old session")
+	assert.True(t, dataKeySession.Closed, "Execute() should close the data key session")
+}
+
+func TestRotatePassphraseUseCase_Execute_LegacyFormatWrongPassphrase(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.Meta.FormatVersion = model.FormatVersionVaultMAC
+			vault.Meta.FingerPrint = "legacy-fingerprint"
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called with a wrong passphrase")
+			return nil
+		},
+	}
+	passphraseService := &test.MockPassphraseService{
+		ValidateFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			passphrase string,
+		) (model.KeySlot, error) {
+			return model.KeySlot{}, errors.New("passphrase does not match any key slot")
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		passphraseService,
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
 	)
+
+	err := useCase.Execute(context.Background(), envTest, "wrong", "new", false, nil)
+	assert.NotNil(t, err, "Execute() with a wrong passphrase expected error, got nil")
+	assert.Contains(t, "invalid credentials", err.Error())
 }
 
 func TestRotatePassphraseUseCase_Execute_LoadError(t *testing.T) {
@@ -169,10 +349,12 @@ func TestRotatePassphraseUseCase_Execute_LoadError(t *testing.T) {
 	useCase := NewRotatePassphraseUseCase(
 		vaultRepo,
 		&test.MockEncryptionService{},
-		&test.MockHashService{},
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
 	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
 	assert.NotNil(t, err, "Execute() with load error expected error, got nil")
 	assert.Contains(
 		t,
@@ -185,23 +367,83 @@ func TestRotatePassphraseUseCase_Execute_LoadError(t *testing.T) {
 	)
 }
 
+func TestRotatePassphraseUseCase_Execute_WeakPassphrase(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			t.Error("Lock() should not be called for a rejected passphrase")
+			return func() {}, nil
+		},
+	}
+	policy := &test.MockPassphrasePolicy{
+		CheckFunc: func(ctx context.Context, env, passphrase string) error {
+			assert.Equal(t, envTest, env)
+			assert.Equal(t, "new", passphrase)
+			return &model.WeakPassphraseError{Env: env, MinScore: 3}
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockPassphraseService{},
+		policy,
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
+	var weak *model.WeakPassphraseError
+	assert.True(t, errors.As(err, &weak), fmt.Sprintf("Execute() error = %v, want weak", err))
+}
+
+func TestRotatePassphraseUseCase_Execute_LockError(t *testing.T) {
+	vaultRepo := &test.MockVaultRepository{
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			assert.True(t, exclusive, "Execute() should lock the vault exclusively")
+			return nil, errors.New("vault is in use")
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			t.Error("Load() should not be called without the vault lock")
+			return nil, errors.New("unexpected load")
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
+	assert.NotNil(t, err, "Execute() with lock error expected error, got nil")
+	assert.Contains(t, "vault is in use", err.Error())
+}
+
 func TestRotatePassphraseUseCase_Execute_VerifyError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	vault, _ := model.NewVault(envTest, saltTest)
+	sealTestVault(vault)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
 			return vault, nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return errors.New("invalid passphrase")
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return nil, fmt.Errorf("failed to unwrap data key: %w", model.ErrWrongPassphrase)
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, &test.MockEncryptionService{}, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "wrong", "new")
+	err := useCase.Execute(context.Background(), envTest, "wrong", "new", false, nil)
 	assert.NotNil(t, err, "Execute() with invalid passphrase expected error, got nil")
 	assert.Contains(
 		t,
@@ -212,25 +454,29 @@ func TestRotatePassphraseUseCase_Execute_VerifyError(t *testing.T) {
 }
 
 func TestRotatePassphraseUseCase_Execute_GenerateSaltError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	vault, _ := model.NewVault(envTest, saltTest)
+	sealTestVault(vault)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
 			return vault, nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return nil
-		},
-		GenerateSaltFunc: func(size int) (string, error) {
+	encryptionService := &test.MockEncryptionService{
+		NewSaltFunc: func() (string, error) {
 			return "", errors.New("salt error")
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, &test.MockEncryptionService{}, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
 	assert.NotNil(t, err, "Execute() with salt error expected error, got nil")
 	assert.Contains(
 		t,
@@ -240,73 +486,71 @@ func TestRotatePassphraseUseCase_Execute_GenerateSaltError(t *testing.T) {
 	)
 }
 
-func TestRotatePassphraseUseCase_Execute_HashError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+func TestRotatePassphraseUseCase_Execute_UnlockError(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	sealTestVault(vault)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
 			return vault, nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return nil
-		},
-		GenerateSaltFunc: func(size int) (string, error) {
-			return newSalt, nil
-		},
-		HashFunc: func(passphrase string) (string, error) {
-			return "", errors.New("hash error")
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return nil, errors.New("unsupported cipher")
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, &test.MockEncryptionService{}, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
-	assert.NotNil(t, err, "Execute() with hash error expected error, got nil")
-	assert.Contains(
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
+	assert.NotNil(t, err, "Execute() with unlock error expected error, got nil")
+	assert.Contains(t, "failed to unlock vault", err.Error())
+	assert.False(
 		t,
-		"failed to hash the fingerprint",
-		err.Error(),
-		fmt.Sprintf(
-			"Execute() error = %q, want to contain 'failed to hash the fingerprint'",
-			err.Error(),
-		),
+		strings.Contains(err.Error(), "invalid credentials"),
+		"Execute() should only report invalid credentials for a wrong passphrase",
 	)
 }
 
 func TestRotatePassphraseUseCase_Execute_DecryptError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	vault, _ := model.NewVault(envTest, saltTest)
 	vault.SetEntry("key1", "encrypted-value")
+	sealTestVault(vault)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			v, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			v, _ := model.NewVault(envTest, saltTest)
 			v.SetEntry("key1", "encrypted-value")
+			sealTestVault(v)
 			return v, nil
 		},
 	}
 
 	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
-			return nil, errors.New("decrypt error")
-		},
-	}
-
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return nil
-		},
-		GenerateSaltFunc: func(size int) (string, error) {
-			return newSalt, nil
-		},
-		HashFunc: func(passphrase string) (string, error) {
-			return newFingerprint, nil
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return &test.MockSession{
+				DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+					return nil, errors.New("decrypt error")
+				},
+			}, nil
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, encryptionService, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
+	err := useCase.Execute(context.Background(), envTest, "old", "new", true, nil)
 	assert.NotNil(t, err, "Execute() with decrypt error expected error, got nil")
 	assert.Contains(
 		t,
@@ -317,40 +561,44 @@ func TestRotatePassphraseUseCase_Execute_DecryptError(t *testing.T) {
 }
 
 func TestRotatePassphraseUseCase_Execute_EncryptError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+	vault, _ := model.NewVault(envTest, saltTest)
 	vault.SetEntry("key1", "encrypted-value")
+	sealTestVault(vault)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
-			v, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+			v, _ := model.NewVault(envTest, saltTest)
 			v.SetEntry("key1", "encrypted-value")
+			sealTestVault(v)
 			return v, nil
 		},
 	}
 
 	encryptionService := &test.MockEncryptionService{
-		DecryptFunc: func(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
-			return []byte("decrypted"), nil
-		},
-		EncryptFunc: func(plaintext []byte, encodedSalt, passphrase string) (string, error) {
-			return "", errors.New("encrypt error")
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return &test.MockSession{
+				DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+					return []byte("decrypted"), nil
+				},
+			}, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return &test.MockSession{
+				EncryptFunc: func(key string, plaintext []byte) (string, error) {
+					return "", errors.New("encrypt error")
+				},
+			}, nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return nil
-		},
-		GenerateSaltFunc: func(size int) (string, error) {
-			return newSalt, nil
-		},
-		HashFunc: func(passphrase string) (string, error) {
-			return newFingerprint, nil
-		},
-	}
-
-	useCase := NewRotatePassphraseUseCase(vaultRepo, encryptionService, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
+	err := useCase.Execute(context.Background(), envTest, "old", "new", true, nil)
 	assert.NotNil(t, err, "Execute() with encrypt error expected error, got nil")
 	assert.Contains(
 		t,
@@ -360,32 +608,59 @@ func TestRotatePassphraseUseCase_Execute_EncryptError(t *testing.T) {
 	)
 }
 
-func TestRotatePassphraseUseCase_Execute_SaveError(t *testing.T) {
-	vault, _ := model.NewVault(envTest, fingerprintTest, saltTest)
+func TestRotatePassphraseUseCase_Execute_IntegrityError(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	vault.SetEntry(keyTest, encryptedValueTest)
+	sealTestVault(vault)
+	delete(vault.Entries, keyTest)
 	vaultRepo := &test.MockVaultRepository{
 		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
 			return vault, nil
 		},
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
-			return errors.New("save error")
+			t.Error("Save() should not be called for a vault that fails verification")
+			return nil
 		},
 	}
 
-	hashService := &test.MockHashService{
-		VerifyFunc: func(hashedPassphrase, passphrase string) error {
-			return nil
-		},
-		GenerateSaltFunc: func(size int) (string, error) {
-			return newSalt, nil
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
+	var integrityErr *model.IntegrityError
+	assert.True(
+		t,
+		errors.As(err, &integrityErr),
+		fmt.Sprintf("Execute() error = %v, want *model.IntegrityError", err),
+	)
+}
+
+func TestRotatePassphraseUseCase_Execute_SaveError(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	sealTestVault(vault)
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
 		},
-		HashFunc: func(passphrase string) (string, error) {
-			return newFingerprint, nil
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			return errors.New("save error")
 		},
 	}
 
-	useCase := NewRotatePassphraseUseCase(vaultRepo, &test.MockEncryptionService{}, hashService)
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
 
-	err := useCase.Execute(context.Background(), envTest, "old", "new")
+	err := useCase.Execute(context.Background(), envTest, "old", "new", false, nil)
 	assert.NotNil(t, err, "Execute() with save error expected error, got nil")
 	assert.Equal(
 		t,
@@ -394,3 +669,240 @@ func TestRotatePassphraseUseCase_Execute_SaveError(t *testing.T) {
 		fmt.Sprintf("Execute() error = %q, want %q", err.Error(), "save error"),
 	)
 }
+
+func TestRotatePassphraseUseCase_Execute_ReencryptWithSlots(t *testing.T) {
+	vault := newSlottedVault(envTest)
+	vault.SetSession(nil)
+	sealTestVault(vault)
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when other slots would be locked out")
+			return nil
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		&test.MockEncryptionService{},
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", true, nil)
+	assert.True(
+		t,
+		errors.Is(err, model.ErrOtherKeySlots),
+		fmt.Sprintf("Execute() error = %v, want ErrOtherKeySlots", err),
+	)
+	assert.Contains(t, ": ci;", err.Error())
+}
+
+func TestRotatePassphraseUseCase_Execute_NamedSlot(t *testing.T) {
+	vault := newSlottedVault(envTest)
+	vault.SetSession(nil)
+	sealTestVault(vault)
+
+	var savedVault *model.Vault
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	session := &test.MockSession{
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			assert.Equal(t, newSalt, meta.Salt)
+			return "wrapped-with-" + passphrase, nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			if meta.Salt != "ci-salt" {
+				return nil, model.ErrWrongPassphrase
+			}
+			return session, nil
+		},
+		NewSaltFunc: func() (string, error) {
+			return newSalt, nil
+		},
+	}
+	auditLog := &test.MockAuditLog{}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		auditLog,
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "ci-passphrase", "new", false, nil)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Equal(t, saltTest, savedVault.Meta.Salt, "Execute() should keep the default slot")
+	assert.Equal(t, "", savedVault.Meta.WrappedKey)
+	assert.Equal(t, newSalt, savedVault.Meta.Slots[0].Salt)
+	assert.Equal(t, "wrapped-with-new", savedVault.Meta.Slots[0].WrappedKey)
+	assert.Equal(t, "", savedVault.Meta.Slots[0].FingerPrint)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, "ci", auditLog.Recorded[0].Slot)
+}
+
+func TestRotatePassphraseUseCase_Execute_ReencryptRewrapsRecipients(t *testing.T) {
+	vault, _ := model.NewVault(envTest, saltTest)
+	vault.Meta.Recipients = []model.Recipient{
+		{Name: "alice", PublicKey: "lockify1alice", WrappedKey: "old-wrapped-key"},
+	}
+	sealTestVault(vault)
+
+	var savedVault *model.Vault
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	newSession := &test.MockSession{}
+	encryptionService := &test.MockEncryptionService{
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return newSession, nil
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(context.Background(), envTest, "old", "new", true, nil)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Equal(t, "wrapped-key-lockify1alice", savedVault.Meta.Recipients[0].WrappedKey)
+}
+
+func TestRotatePassphraseUseCase_Execute_RemoveSlotThenReencrypt(t *testing.T) {
+	vault := newSlottedVault(envTest)
+	vault.AddSlot(model.KeySlot{Name: "ops", Salt: "ops-salt", WrappedKey: "ops-wrapped-key"})
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+	}
+	err := NewRemoveSlotUseCase(vaultService, &test.MockAuditLog{}).
+		Execute(context.Background(), envTest, "ops")
+	assert.Nil(t, err, fmt.Sprintf("RemoveSlot Execute() returned unexpected error: %v", err))
+	vault.SetSession(nil)
+	sealTestVault(vault)
+
+	var savedVault *model.Vault
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	passphrases := map[string]string{saltTest: "old", "ci-salt": "ci-passphrase"}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			if passphrases[meta.Salt] != passphrase {
+				return nil, model.ErrWrongPassphrase
+			}
+			return &test.MockSession{}, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return &test.MockSession{
+				WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+					return "new-key-wrapped-with-" + passphrase, nil
+				},
+			}, nil
+		},
+	}
+
+	var asked []string
+	slotPassphrase := func(slot string) (string, error) {
+		asked = append(asked, slot)
+		return "ci-passphrase", nil
+	}
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err = useCase.Execute(context.Background(), envTest, "old", "new", true, slotPassphrase)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"ci"}, asked)
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Equal(t, "new-key-wrapped-with-new", savedVault.Meta.WrappedKey)
+	assert.Count(t, 1, savedVault.Meta.Slots)
+	assert.Equal(t, "ci", savedVault.Meta.Slots[0].Name)
+	assert.Equal(t, "new-key-wrapped-with-ci-passphrase", savedVault.Meta.Slots[0].WrappedKey)
+}
+
+func TestRotatePassphraseUseCase_Execute_ReencryptWrongSlotPassphrase(t *testing.T) {
+	vault := newSlottedVault(envTest)
+	vault.SetSession(nil)
+	sealTestVault(vault)
+	vaultRepo := &test.MockVaultRepository{
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not be called when a slot passphrase is wrong")
+			return nil
+		},
+	}
+	encryptionService := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			if meta.Salt == "ci-salt" {
+				return nil, model.ErrWrongPassphrase
+			}
+			return &test.MockSession{}, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			t.Error("Execute() should not generate a data key before every slot is opened")
+			return &test.MockSession{}, nil
+		},
+	}
+
+	useCase := NewRotatePassphraseUseCase(
+		vaultRepo,
+		encryptionService,
+		&test.MockPassphraseService{},
+		&test.MockPassphrasePolicy{},
+		&test.MockAuditLog{},
+	)
+
+	err := useCase.Execute(
+		context.Background(),
+		envTest,
+		"old",
+		"new",
+		true,
+		func(slot string) (string, error) { return "wrong", nil },
+	)
+	assert.True(
+		t,
+		errors.Is(err, model.ErrWrongPassphrase),
+		fmt.Sprintf("Execute() error = %v, want ErrWrongPassphrase", err),
+	)
+	assert.Contains(t, `key slot "ci"`, err.Error())
+}
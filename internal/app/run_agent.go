@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RunAgentUc defines the interface for running the lockify agent.
+type RunAgentUc interface {
+	Execute(ctx context.Context, policy model.AgentPolicy, ready func(socket string)) error
+}
+
+// RunAgentUseCase implements the use case for running the lockify agent.
+type RunAgentUseCase struct {
+	agentServer service.AgentServer
+}
+
+// NewRunAgentUseCase creates a new RunAgentUseCase instance.
+func NewRunAgentUseCase(agentServer service.AgentServer) RunAgentUc {
+	return &RunAgentUseCase{agentServer}
+}
+
+// Execute runs the agent, holding vault keys under policy until ctx is done. ready is
+// called with the socket path once the agent accepts connections.
+func (useCase *RunAgentUseCase) Execute(
+	ctx context.Context,
+	policy model.AgentPolicy,
+	ready func(socket string),
+) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	return useCase.agentServer.Serve(ctx, policy, ready)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestRunAgentUseCase_Execute(t *testing.T) {
+	policy := model.AgentPolicy{IdleTimeout: time.Minute, MaxLifetime: time.Hour}
+	var served model.AgentPolicy
+	agentServer := &test.MockAgentServer{
+		ServeFunc: func(ctx context.Context, p model.AgentPolicy, ready func(string)) error {
+			served = p
+			ready("agent.sock")
+			return nil
+		},
+	}
+
+	socket := ""
+	err := NewRunAgentUseCase(agentServer).Execute(
+		context.Background(),
+		policy,
+		func(s string) { socket = s },
+	)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, policy, served, "Execute() should serve with the given policy")
+	assert.Equal(t, "agent.sock", socket, "Execute() should pass ready on to the agent")
+}
+
+func TestRunAgentUseCase_Execute_InvalidPolicy(t *testing.T) {
+	agentServer := &test.MockAgentServer{
+		ServeFunc: func(ctx context.Context, p model.AgentPolicy, ready func(string)) error {
+			t.Error("Execute() with an invalid policy should not start the agent")
+			return nil
+		},
+	}
+
+	err := NewRunAgentUseCase(agentServer).Execute(
+		context.Background(),
+		model.AgentPolicy{IdleTimeout: 0, MaxLifetime: time.Hour},
+		nil,
+	)
+	assert.NotNil(t, err, "Execute() with a zero idle timeout expected error, got nil")
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// RunCommandUc defines the interface for running a command with vault entries in its
+// environment.
+type RunCommandUc interface {
+	Execute(ctx context.Context, dto RunCommandDTO) (int, error)
+}
+
+// RunCommandDTO contains the data needed to run a command with vault entries.
+type RunCommandDTO struct {
+	Env     string
+	Command []string
+	// Environ is the environment the command inherits, as KEY=VALUE pairs.
+	Environ []string
+	// Only restricts the injected entries to these keys when it is not empty.
+	Only []string
+	// Prefix is prepended to the name of every injected variable.
+	Prefix string
+	// NoOverride keeps variables that are already set in Environ.
+	NoOverride bool
+}
+
+// RunCommandUseCase implements the use case for running a command with vault entries.
+type RunCommandUseCase struct {
+	vaultService  service.VaultServiceInterface
+	processRunner service.ProcessRunner
+	auditLog      service.AuditLog
+	logger        domain.Logger
+	// credentials are the variables lockify reads credentials from, such as
+	// LOCKIFY_PASSPHRASE. They and their per-env variants are never passed to the command.
+	credentials []string
+}
+
+// NewRunCommandUseCase creates a new RunCommandUseCase instance.
+func NewRunCommandUseCase(
+	vaultService service.VaultServiceInterface,
+	processRunner service.ProcessRunner,
+	auditLog service.AuditLog,
+	logger domain.Logger,
+	credentials []string,
+) RunCommandUc {
+	return &RunCommandUseCase{vaultService, processRunner, auditLog, logger, credentials}
+}
+
+// Execute decrypts the vault entries into the environment of the command, runs it and
+// returns its exit code. Decrypted values are only ever held in memory.
+func (useCase *RunCommandUseCase) Execute(ctx context.Context, dto RunCommandDTO) (int, error) {
+	if len(dto.Command) == 0 {
+		return 0, errors.New("no command given to run")
+	}
+
+	variables, err := useCase.decryptEntries(ctx, dto.Env, dto.Only)
+	if err != nil {
+		return 0, err
+	}
+
+	environ := stripCredentials(dto.Environ, useCase.credentials)
+	env := mergeEnviron(environ, variables, dto.Prefix, dto.NoOverride)
+	return useCase.processRunner.Run(ctx, dto.Command[0], dto.Command[1:], env)
+}
+
+// decryptEntries returns the decrypted entries of the vault, limited to only when given, and
+// records them as exported, warning about the ones overdue for rotation. The vault is locked
+// again before the command starts, so it is not held for the command's whole lifetime.
+func (useCase *RunCommandUseCase) decryptEntries(
+	ctx context.Context,
+	env string,
+	only []string,
+) (map[string]string, error) {
+	vault, err := useCase.vaultService.Open(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	defer vault.Lock()
+
+	keys := only
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(vault.Entries))
+		for key := range vault.Entries {
+			keys = append(keys, key)
+		}
+	}
+
+	variables := make(map[string]string, len(keys))
+	for _, key := range keys {
+		entry, err := vault.GetEntry(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := vault.Session().Decrypt(key, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt value of key %q: %w", key, err)
+		}
+		variables[key] = string(value)
+	}
+
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditExport, keys...); err != nil {
+		return nil, err
+	}
+	warnExpired(useCase.logger, vault, keys)
+
+	return variables, nil
+}
+
+// stripCredentials returns environ without the credential variables and their per-env
+// variants, such as LOCKIFY_PASSPHRASE and LOCKIFY_PASSPHRASE_PROD, so the command cannot
+// open the vault it was started from.
+func stripCredentials(environ, credentials []string) []string {
+	stripped := make([]string, 0, len(environ))
+	for _, pair := range environ {
+		name, _, _ := strings.Cut(pair, "=")
+		if !isCredential(name, credentials) {
+			stripped = append(stripped, pair)
+		}
+	}
+	return stripped
+}
+
+func isCredential(name string, credentials []string) bool {
+	for _, credential := range credentials {
+		if credential != "" && (name == credential || strings.HasPrefix(name, credential+"_")) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeEnviron adds variables to environ, replacing variables of the same name unless
+// noOverride is set.
+func mergeEnviron(
+	environ []string,
+	variables map[string]string,
+	prefix string,
+	noOverride bool,
+) []string {
+	merged := make([]string, 0, len(environ)+len(variables))
+	index := make(map[string]int, len(environ))
+	for _, pair := range environ {
+		name, _, _ := strings.Cut(pair, "=")
+		index[name] = len(merged)
+		merged = append(merged, pair)
+	}
+
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := prefix + key
+		pair := name + "=" + variables[key]
+		if i, exists := index[name]; exists {
+			if !noOverride {
+				merged[i] = pair
+			}
+			continue
+		}
+		index[name] = len(merged)
+		merged = append(merged, pair)
+	}
+
+	return merged
+}
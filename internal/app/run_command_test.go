@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestRunCommandUseCase_Execute_InjectsEntries(t *testing.T) {
+	var gotName string
+	var gotArgs, gotEnv []string
+	runner := &test.MockProcessRunner{
+		RunFunc: func(ctx context.Context, name string, args, env []string) (int, error) {
+			gotName, gotArgs, gotEnv = name, args, env
+			return 3, nil
+		},
+	}
+	useCase := NewRunCommandUseCase(
+		test.NewPrefixCipherVaultService(map[string]map[string]string{
+			envTest: {"DB_URL": "db", "API_KEY": "api"},
+		}),
+		runner,
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+
+	code, err := useCase.Execute(context.Background(), RunCommandDTO{
+		Env:     envTest,
+		Command: []string{"./server", "--port", "8080"},
+		Environ: []string{"PATH=/bin", "DB_URL=local"},
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 3, code, "Execute() should return the exit code of the command")
+	assert.Equal(t, "./server", gotName)
+	assert.DeepEqual(t, []string{"--port", "8080"}, gotArgs)
+	assert.DeepEqual(
+		t,
+		[]string{"PATH=/bin", "DB_URL=db", "API_KEY=api"},
+		gotEnv,
+	)
+}
+
+func TestRunCommandUseCase_Execute_OnlyPrefixAndNoOverride(t *testing.T) {
+	var gotEnv []string
+	runner := &test.MockProcessRunner{
+		RunFunc: func(ctx context.Context, name string, args, env []string) (int, error) {
+			gotEnv = env
+			return 0, nil
+		},
+	}
+	useCase := NewRunCommandUseCase(
+		test.NewPrefixCipherVaultService(map[string]map[string]string{
+			envTest: {"DB_URL": "db", "API_KEY": "api", "X": "x"},
+		}),
+		runner,
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+
+	_, err := useCase.Execute(context.Background(), RunCommandDTO{
+		Env:        envTest,
+		Command:    []string{"env"},
+		Environ:    []string{"APP_DB_URL=local"},
+		Only:       []string{"DB_URL", "API_KEY"},
+		Prefix:     "APP_",
+		NoOverride: true,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"APP_DB_URL=local", "APP_API_KEY=api"}, gotEnv)
+}
+
+func TestRunCommandUseCase_Execute_StripsCredentials(t *testing.T) {
+	var gotEnv []string
+	runner := &test.MockProcessRunner{
+		RunFunc: func(ctx context.Context, name string, args, env []string) (int, error) {
+			gotEnv = env
+			return 0, nil
+		},
+	}
+	useCase := NewRunCommandUseCase(
+		test.NewPrefixCipherVaultService(map[string]map[string]string{
+			envTest: {"DB_URL": "db"},
+		}),
+		runner,
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		[]string{"LOCKIFY_PASSPHRASE", "LOCKIFY_IDENTITY", "LOCKIFY_AGENT_SOCK"},
+	)
+
+	_, err := useCase.Execute(context.Background(), RunCommandDTO{
+		Env:     envTest,
+		Command: []string{"env"},
+		Environ: []string{
+			"PATH=/bin",
+			"LOCKIFY_PASSPHRASE=secret",
+			"LOCKIFY_PASSPHRASE_PROD=prod-secret",
+			"LOCKIFY_IDENTITY=/home/me/.lockify/identity",
+			"LOCKIFY_AGENT_SOCK=/tmp/agent.sock",
+			"LOCKIFY_PASSPHRASEX=kept",
+		},
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(
+		t,
+		[]string{"PATH=/bin", "LOCKIFY_PASSPHRASEX=kept", "DB_URL=db"},
+		gotEnv,
+	)
+}
+
+func TestRunCommandUseCase_Execute_UnknownOnlyKey(t *testing.T) {
+	runner := &test.MockProcessRunner{
+		RunFunc: func(ctx context.Context, name string, args, env []string) (int, error) {
+			t.Error("Run() should not be called when a key is missing")
+			return 0, nil
+		},
+	}
+	useCase := NewRunCommandUseCase(
+		test.NewPrefixCipherVaultService(map[string]map[string]string{envTest: nil}),
+		runner,
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+
+	_, err := useCase.Execute(context.Background(), RunCommandDTO{
+		Env:     envTest,
+		Command: []string{"env"},
+		Only:    []string{"MISSING"},
+	})
+	assert.NotNil(t, err, "Execute() with an unknown key expected error, got nil")
+	assert.Contains(t, `key "MISSING" not found`, err.Error())
+}
+
+func TestRunCommandUseCase_Execute_LocksVaultBeforeRunning(t *testing.T) {
+	var session *test.MockSession
+	vaultService := &test.MockVaultService{
+		OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			session = &test.MockSession{}
+			vault.SetSession(session)
+			return vault, nil
+		},
+	}
+	runner := &test.MockProcessRunner{
+		RunFunc: func(ctx context.Context, name string, args, env []string) (int, error) {
+			assert.True(t, session.Closed, "the vault should be locked before the command runs")
+			return 0, nil
+		},
+	}
+	useCase := NewRunCommandUseCase(
+		vaultService,
+		runner,
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+
+	_, err := useCase.Execute(context.Background(), RunCommandDTO{
+		Env:     envTest,
+		Command: []string{"env"},
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+}
+
+func TestRunCommandUseCase_Execute_Errors(t *testing.T) {
+	useCase := NewRunCommandUseCase(
+		test.NewPrefixCipherVaultService(map[string]map[string]string{envTest: nil}),
+		&test.MockProcessRunner{},
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+	_, err := useCase.Execute(context.Background(), RunCommandDTO{Env: envTest})
+	assert.NotNil(t, err, "Execute() without a command expected error, got nil")
+
+	useCase = NewRunCommandUseCase(
+		&test.MockVaultService{
+			OpenFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+				return nil, errors.New("open error")
+			},
+		},
+		&test.MockProcessRunner{},
+		&test.MockAuditLog{},
+		&test.MockLogger{},
+		nil,
+	)
+	_, err = useCase.Execute(context.Background(), RunCommandDTO{
+		Env:     envTest,
+		Command: []string{"env"},
+	})
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+	assert.Contains(t, "open error", err.Error())
+}
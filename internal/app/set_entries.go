@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SetEntriesUc defines the interface for setting several entries at once.
+type SetEntriesUc interface {
+	Execute(ctx context.Context, dto SetEntriesDTO) (SetEntriesResult, error)
+}
+
+// SetEntriesDTO contains the data needed to set entries in the vault.
+type SetEntriesDTO struct {
+	Env     string
+	Entries map[string]string
+	// Secret marks every entry that is set as holding a secret value.
+	Secret bool
+	// IfAbsent skips keys that already exist instead of overwriting them.
+	IfAbsent bool
+}
+
+// SetEntriesResult lists the keys that were set and those skipped because they existed.
+type SetEntriesResult struct {
+	Set     []string
+	Skipped []string
+}
+
+// SetEntriesUseCase implements the use case for setting several entries in one batch.
+type SetEntriesUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewSetEntriesUseCase creates a new SetEntriesUseCase instance.
+func NewSetEntriesUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) SetEntriesUc {
+	return &SetEntriesUseCase{vaultService, auditLog}
+}
+
+// Execute sets all entries with a single vault save. Either every entry is stored or,
+// when any of them fails, none is.
+func (useCase *SetEntriesUseCase) Execute(
+	ctx context.Context,
+	dto SetEntriesDTO,
+) (SetEntriesResult, error) {
+	if len(dto.Entries) == 0 {
+		return SetEntriesResult{}, errors.New("no entries to set")
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, dto.Env)
+	if err != nil {
+		return SetEntriesResult{}, fmt.Errorf(
+			"failed to open vault for environment %s: %w",
+			dto.Env,
+			err,
+		)
+	}
+	defer vault.Lock()
+
+	keys := make([]string, 0, len(dto.Entries))
+	for key := range dto.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := SetEntriesResult{}
+	for _, key := range keys {
+		if _, err := vault.GetEntry(key); err == nil && dto.IfAbsent {
+			result.Skipped = append(result.Skipped, key)
+			continue
+		}
+
+		encryptedValue, err := vault.Session().Encrypt(key, []byte(dto.Entries[key]))
+		if err != nil {
+			return SetEntriesResult{}, fmt.Errorf("failed to encrypt value of key %q: %w", key, err)
+		}
+		if err := vault.SetEntry(key, encryptedValue); err != nil {
+			return SetEntriesResult{}, fmt.Errorf("failed to set key %q: %w", key, err)
+		}
+		if dto.Secret {
+			if err := vault.MarkSecret(key); err != nil {
+				return SetEntriesResult{}, fmt.Errorf("failed to set key %q: %w", key, err)
+			}
+		}
+		result.Set = append(result.Set, key)
+	}
+
+	if len(result.Set) == 0 {
+		return result, nil
+	}
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return SetEntriesResult{}, fmt.Errorf("failed to save vault: %w", err)
+	}
+	if err := useCase.auditLog.Record(ctx, vault, model.AuditSet, result.Set...); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newSetTestVaultService opens a prefix cipher vault holding existing, whose session fails to
+// encrypt the value "fail", and counts its saves
+func newSetTestVaultService(existing ...string) (*test.MockVaultService, *int) {
+	entries := map[string]string{}
+	for _, key := range existing {
+		entries[key] = "old"
+	}
+	vaultService := test.NewPrefixCipherVaultService(map[string]map[string]string{
+		envTest: entries,
+	})
+	open := vaultService.OpenForUpdateFunc
+	vaultService.OpenForUpdateFunc = func(ctx context.Context, env string) (*model.Vault, error) {
+		vault, err := open(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		session := vault.Session().(*test.MockSession)
+		encrypt := session.EncryptFunc
+		session.EncryptFunc = func(key string, plaintext []byte) (string, error) {
+			if string(plaintext) == "fail" {
+				return "", errors.New("encrypt error")
+			}
+			return encrypt(key, plaintext)
+		}
+		return vault, nil
+	}
+
+	saves := 0
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		saves++
+		return nil
+	}
+	return vaultService, &saves
+}
+
+func TestSetEntriesUseCase_Execute_SavesOnce(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService, saves := newSetTestVaultService()
+	save := vaultService.SaveFunc
+	vaultService.SaveFunc = func(ctx context.Context, vault *model.Vault) error {
+		savedVault = vault
+		return save(ctx, vault)
+	}
+
+	useCase := NewSetEntriesUseCase(vaultService, &test.MockAuditLog{})
+	result, err := useCase.Execute(context.Background(), SetEntriesDTO{
+		Env:     envTest,
+		Entries: map[string]string{"B": "2", "A": "1"},
+		Secret:  true,
+	})
+
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Equal(t, 1, *saves, "Execute() should save the vault once")
+	assert.DeepEqual(t, []string{"A", "B"}, result.Set)
+	assert.Equal(t, envTest+":1", savedVault.Entries["A"].Value)
+	assert.True(t, savedVault.Entries["B"].Secret, "Execute() should mark the entries secret")
+}
+
+func TestSetEntriesUseCase_Execute_IfAbsent(t *testing.T) {
+	vaultService, saves := newSetTestVaultService("A")
+
+	useCase := NewSetEntriesUseCase(vaultService, &test.MockAuditLog{})
+	result, err := useCase.Execute(context.Background(), SetEntriesDTO{
+		Env:      envTest,
+		Entries:  map[string]string{"A": "1"},
+		IfAbsent: true,
+	})
+
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{"A"}, result.Skipped)
+	assert.Count(t, 0, result.Set)
+	assert.Equal(t, 0, *saves, "Execute() should not save when every key is skipped")
+}
+
+func TestSetEntriesUseCase_Execute_AllOrNothing(t *testing.T) {
+	vaultService, saves := newSetTestVaultService()
+
+	useCase := NewSetEntriesUseCase(vaultService, &test.MockAuditLog{})
+	_, err := useCase.Execute(context.Background(), SetEntriesDTO{
+		Env:     envTest,
+		Entries: map[string]string{"A": "1", "B": "fail"},
+	})
+
+	assert.NotNil(t, err, "Execute() with a failing entry expected error, got nil")
+	assert.Contains(t, `key "B"`, err.Error())
+	assert.Equal(t, 0, *saves, "Execute() should not save when an entry fails")
+}
+
+func TestSetEntriesUseCase_Execute_Errors(t *testing.T) {
+	vaultService, _ := newSetTestVaultService()
+	useCase := NewSetEntriesUseCase(vaultService, &test.MockAuditLog{})
+	_, err := useCase.Execute(context.Background(), SetEntriesDTO{Env: envTest})
+	assert.NotNil(t, err, "Execute() without entries expected error, got nil")
+
+	vaultService.OpenForUpdateFunc = func(ctx context.Context, env string) (*model.Vault, error) {
+		return nil, errors.New("open error")
+	}
+	_, err = useCase.Execute(context.Background(), SetEntriesDTO{
+		Env:     envTest,
+		Entries: map[string]string{"A": "1"},
+	})
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+	assert.Contains(t, "open error", err.Error())
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SetEntryMetadataUc defines the interface for changing the metadata of an entry.
+type SetEntryMetadataUc interface {
+	Execute(ctx context.Context, dto SetEntryMetadataDTO) error
+}
+
+// SetEntryMetadataUseCase implements the use case for changing the metadata of an entry.
+type SetEntryMetadataUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// SetEntryMetadataDTO contains the metadata fields to change; nil fields are left as they
+// are and empty values clear a field.
+type SetEntryMetadataDTO struct {
+	Env         string
+	Key         string
+	Description *string
+	Owner       *string
+	Tags        *[]string
+	ExpiresAt   *string
+	MaxAgeDays  *int
+	Secret      *bool
+}
+
+// NewSetEntryMetadataUseCase creates a new SetEntryMetadataUseCase instance.
+func NewSetEntryMetadataUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) SetEntryMetadataUc {
+	return &SetEntryMetadataUseCase{vaultService, auditLog}
+}
+
+// Execute applies the changed fields to the metadata of an entry and saves the vault.
+func (useCase *SetEntryMetadataUseCase) Execute(
+	ctx context.Context,
+	dto SetEntryMetadataDTO,
+) error {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, dto.Env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	metadata, err := vault.EntryMetadata(dto.Key)
+	if err != nil {
+		return err
+	}
+	if dto.Description != nil {
+		metadata.Description = *dto.Description
+	}
+	if dto.Owner != nil {
+		metadata.Owner = *dto.Owner
+	}
+	if dto.Tags != nil {
+		metadata.Tags = *dto.Tags
+	}
+	if dto.ExpiresAt != nil {
+		metadata.ExpiresAt = *dto.ExpiresAt
+	}
+	if dto.MaxAgeDays != nil {
+		metadata.MaxAgeDays = *dto.MaxAgeDays
+	}
+	if err := vault.SetEntryMetadata(dto.Key, metadata); err != nil {
+		return err
+	}
+
+	if dto.Secret != nil {
+		markSecret := vault.UnmarkSecret
+		if *dto.Secret {
+			markSecret = vault.MarkSecret
+		}
+		if err := markSecret(dto.Key); err != nil {
+			return err
+		}
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditSet, dto.Key)
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+// newMetadataVaultService opens a vault holding DB_URL owned by alice and keeps the vault it
+// saves in saved
+func newMetadataVaultService(saved **model.Vault) *test.MockVaultService {
+	return &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := newMoveVault(env, model.CurrentFormatVersion, nil)
+			vault.SetEntry("DB_URL", env+"/DB_URL:postgres://db")
+			vault.SetEntryMetadata("DB_URL", model.EntryMetadata{
+				Description: "Primary database",
+				Owner:       "alice",
+				Tags:        []string{"db"},
+			})
+			return vault, nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			*saved = vault
+			return nil
+		},
+	}
+}
+
+func TestSetEntryMetadataUseCase_Execute(t *testing.T) {
+	var savedVault *model.Vault
+	auditLog := &test.MockAuditLog{}
+	useCase := NewSetEntryMetadataUseCase(newMetadataVaultService(&savedVault), auditLog)
+
+	owner := "bob"
+	tags := []string{"db", "critical"}
+	secret := true
+	err := useCase.Execute(context.Background(), SetEntryMetadataDTO{
+		Env:    envTest,
+		Key:    "DB_URL",
+		Owner:  &owner,
+		Tags:   &tags,
+		Secret: &secret,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+
+	// The saved vault is locked again, so its entries are read with a new session.
+	reopened := newMoveVault(envTest, model.CurrentFormatVersion, savedVault.Entries)
+	metadata, err := reopened.EntryMetadata("DB_URL")
+	assert.Nil(t, err, fmt.Sprintf("EntryMetadata() returned unexpected error: %v", err))
+	assert.Equal(t, "Primary database", metadata.Description, "unchanged fields should be kept")
+	assert.Equal(t, "bob", metadata.Owner)
+	assert.DeepEqual(t, []string{"critical", "db"}, metadata.Tags)
+	assert.True(t, savedVault.Entries["DB_URL"].Secret, "Execute() should mark the entry secret")
+	assert.Equal(t, 1, len(auditLog.Recorded), "Execute() should record the change")
+	assert.Equal(t, model.AuditSet, auditLog.Recorded[0].Operation)
+}
+
+func TestSetEntryMetadataUseCase_Execute_ClearsFields(t *testing.T) {
+	var savedVault *model.Vault
+	useCase := NewSetEntryMetadataUseCase(
+		newMetadataVaultService(&savedVault),
+		&test.MockAuditLog{},
+	)
+
+	empty := ""
+	noTags := []string{}
+	err := useCase.Execute(context.Background(), SetEntryMetadataDTO{
+		Env:         envTest,
+		Key:         "DB_URL",
+		Description: &empty,
+		Owner:       &empty,
+		Tags:        &noTags,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+
+	entry := savedVault.Entries["DB_URL"]
+	assert.Equal(t, "", entry.SealedMetadata, "Execute() should remove empty metadata")
+	assert.Nil(t, entry.Metadata, "Execute() should remove empty metadata")
+}
+
+func TestSetEntryMetadataUseCase_Execute_KeyNotFound(t *testing.T) {
+	var savedVault *model.Vault
+	useCase := NewSetEntryMetadataUseCase(
+		newMetadataVaultService(&savedVault),
+		&test.MockAuditLog{},
+	)
+
+	owner := "bob"
+	err := useCase.Execute(context.Background(), SetEntryMetadataDTO{
+		Env:   envTest,
+		Key:   "MISSING",
+		Owner: &owner,
+	})
+	assert.NotNil(t, err, "Execute() with a missing key expected error, got nil")
+	assert.Nil(t, savedVault, "Execute() should not save the vault")
+}
+
+func TestSetMetadataVisibilityUseCase_Execute(t *testing.T) {
+	var savedVault *model.Vault
+	auditLog := &test.MockAuditLog{}
+	useCase := NewSetMetadataVisibilityUseCase(newMetadataVaultService(&savedVault), auditLog)
+
+	err := useCase.Execute(context.Background(), envTest, true)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.True(t, savedVault.Meta.PublicMeta, "Execute() should make metadata public")
+
+	entry := savedVault.Entries["DB_URL"]
+	assert.Equal(t, "", entry.SealedMetadata, "Execute() should store the metadata in plain text")
+	assert.NotNil(t, entry.Metadata, "Execute() should store the metadata in plain text")
+	assert.Equal(t, "alice", entry.Metadata.Owner)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditMetadataVisibility, auditLog.Recorded[0].Operation)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SetHistoryPolicyUc defines the interface for changing how much entry history a vault keeps.
+type SetHistoryPolicyUc interface {
+	Execute(ctx context.Context, env string, policy model.HistoryPolicy) error
+}
+
+// SetHistoryPolicyUseCase implements the use case for changing the history retention of a
+// vault.
+type SetHistoryPolicyUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewSetHistoryPolicyUseCase creates a new SetHistoryPolicyUseCase instance.
+func NewSetHistoryPolicyUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) SetHistoryPolicyUc {
+	return &SetHistoryPolicyUseCase{vaultService, auditLog}
+}
+
+// Execute stores the retention policy in the vault header and drops the previous values it
+// no longer keeps.
+func (useCase *SetHistoryPolicyUseCase) Execute(
+	ctx context.Context,
+	env string,
+	policy model.HistoryPolicy,
+) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if err := vault.SetHistoryPolicy(policy); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditHistoryPolicy)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestSetHistoryPolicyUseCase_Execute_Success(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newHistoryVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+
+	auditLog := &test.MockAuditLog{}
+	useCase := NewSetHistoryPolicyUseCase(vaultService, auditLog)
+
+	policy := model.HistoryPolicy{Versions: 1, MaxAgeDays: 30}
+	err := useCase.Execute(context.Background(), "prod", policy)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	assert.Equal(t, policy, savedVault.Meta.Retention())
+	assert.Count(t, 1, savedVault.Entries["DB_URL"].History)
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditHistoryPolicy, auditLog.Recorded[0].Operation)
+}
+
+func TestSetHistoryPolicyUseCase_Execute_Invalid(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			t.Error("OpenForUpdate() should not be called for an invalid policy")
+			return newHistoryVault(env), nil
+		},
+	}
+
+	useCase := NewSetHistoryPolicyUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), "prod", model.HistoryPolicy{MaxAgeDays: -1})
+	assert.NotNil(t, err, "Execute() with a negative max age expected error, got nil")
+	assert.Contains(t, "cannot be negative", err.Error())
+}
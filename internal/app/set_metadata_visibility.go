@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SetMetadataVisibilityUc defines the interface for choosing whether entry metadata of a
+// vault can be read without its passphrase.
+type SetMetadataVisibilityUc interface {
+	Execute(ctx context.Context, env string, public bool) error
+}
+
+// SetMetadataVisibilityUseCase implements the use case for making the entry metadata of a
+// vault public or sealing it.
+type SetMetadataVisibilityUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewSetMetadataVisibilityUseCase creates a new SetMetadataVisibilityUseCase instance.
+func NewSetMetadataVisibilityUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) SetMetadataVisibilityUc {
+	return &SetMetadataVisibilityUseCase{vaultService, auditLog}
+}
+
+// Execute stores the metadata of every entry in plain text when public is set, or encrypts
+// it with the vault key otherwise, and saves the vault.
+func (useCase *SetMetadataVisibilityUseCase) Execute(
+	ctx context.Context,
+	env string,
+	public bool,
+) error {
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	if err := vault.SetPublicMetadata(public); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditMetadataVisibility)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// SetRotationPolicyUc defines the interface for changing how long the entry values of a
+// vault may be used before they have to be rotated.
+type SetRotationPolicyUc interface {
+	Execute(ctx context.Context, dto SetRotationPolicyDTO) error
+}
+
+// SetRotationPolicyDTO contains the changes to the rotation policy of a vault; nil fields
+// are left as they are.
+type SetRotationPolicyDTO struct {
+	Env string
+	// Tag applies MaxAgeDays to the entries carrying it instead of to every entry.
+	Tag string
+	// MaxAgeDays is the new max age in days; 0 removes the limit.
+	MaxAgeDays *int
+	// WarnDays is the new warning window in days; 0 restores the default.
+	WarnDays *int
+}
+
+// SetRotationPolicyUseCase implements the use case for changing the rotation policy of a
+// vault.
+type SetRotationPolicyUseCase struct {
+	vaultService service.VaultServiceInterface
+	auditLog     service.AuditLog
+}
+
+// NewSetRotationPolicyUseCase creates a new SetRotationPolicyUseCase instance.
+func NewSetRotationPolicyUseCase(
+	vaultService service.VaultServiceInterface,
+	auditLog service.AuditLog,
+) SetRotationPolicyUc {
+	return &SetRotationPolicyUseCase{vaultService, auditLog}
+}
+
+// Execute applies the changes to the rotation policy stored in the vault header.
+func (useCase *SetRotationPolicyUseCase) Execute(
+	ctx context.Context,
+	dto SetRotationPolicyDTO,
+) error {
+	if dto.Tag != "" && dto.MaxAgeDays == nil {
+		return errors.New("a tag needs a max age")
+	}
+
+	vault, err := useCase.vaultService.OpenForUpdate(ctx, dto.Env)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+
+	policy := vault.Meta.RotationPolicy()
+	if dto.MaxAgeDays != nil {
+		policy = policy.WithMaxAge(dto.Tag, *dto.MaxAgeDays)
+	}
+	if dto.WarnDays != nil {
+		policy.WarnDays = *dto.WarnDays
+	}
+	if err := vault.SetRotationPolicy(policy); err != nil {
+		return err
+	}
+
+	if err := useCase.vaultService.Save(ctx, vault); err != nil {
+		return err
+	}
+
+	return useCase.auditLog.Record(ctx, vault, model.AuditRotationPolicy)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func TestSetRotationPolicyUseCase_Execute(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newStaleVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	auditLog := &test.MockAuditLog{}
+	useCase := NewSetRotationPolicyUseCase(vaultService, auditLog)
+
+	maxAgeDays, warnDays := 30, 7
+	err := useCase.Execute(context.Background(), SetRotationPolicyDTO{
+		Env:        envTest,
+		Tag:        "payments",
+		MaxAgeDays: &maxAgeDays,
+		WarnDays:   &warnDays,
+	})
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.NotNil(t, savedVault, "Execute() should save the vault")
+	policy := savedVault.Meta.RotationPolicy()
+	assert.Count(t, 1, auditLog.Recorded)
+	assert.Equal(t, model.AuditRotationPolicy, auditLog.Recorded[0].Operation)
+	assert.Equal(t, 90, policy.MaxAgeDays, "the max age of every entry should be kept")
+	assert.Equal(t, 30, policy.Tags["payments"])
+	assert.Equal(t, 7, policy.WarnDays)
+}
+
+func TestSetRotationPolicyUseCase_Execute_Invalid(t *testing.T) {
+	var savedVault *model.Vault
+	vaultService := &test.MockVaultService{
+		OpenForUpdateFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return newStaleVault(env), nil
+		},
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			savedVault = vault
+			return nil
+		},
+	}
+	useCase := NewSetRotationPolicyUseCase(vaultService, &test.MockAuditLog{})
+
+	err := useCase.Execute(context.Background(), SetRotationPolicyDTO{Env: envTest, Tag: "db"})
+	assert.NotNil(t, err, "Execute() with a tag but no max age expected error, got nil")
+
+	maxAgeDays := -1
+	err = useCase.Execute(context.Background(), SetRotationPolicyDTO{
+		Env:        envTest,
+		MaxAgeDays: &maxAgeDays,
+	})
+	assert.NotNil(t, err, "Execute() with a negative max age expected error, got nil")
+	assert.Contains(t, "cannot be negative", err.Error())
+	assert.Nil(t, savedVault, "Execute() should not save an invalid policy")
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// VerifyReport describes the result of verifying a vault.
+type VerifyReport struct {
+	Env           string
+	FormatVersion int
+	Revision      uint64
+	Slot          string
+	Problems      []string
+}
+
+// HasMAC reports whether the vault format carries a whole-vault MAC.
+func (r VerifyReport) HasMAC() bool {
+	return r.FormatVersion >= model.FormatVersionVaultMAC
+}
+
+// VerifyVaultUc defines the interface for verifying the integrity of a vault.
+type VerifyVaultUc interface {
+	Execute(ctx context.Context, env string) (VerifyReport, error)
+}
+
+// VerifyVaultUseCase implements the use case for verifying the integrity of a vault.
+type VerifyVaultUseCase struct {
+	vaultService service.VaultServiceInterface
+}
+
+// NewVerifyVaultUseCase creates a new VerifyVaultUseCase instance.
+func NewVerifyVaultUseCase(vaultService service.VaultServiceInterface) VerifyVaultUc {
+	return &VerifyVaultUseCase{vaultService}
+}
+
+// Execute checks the vault MAC and manifest and that every entry decrypts under its key.
+func (useCase *VerifyVaultUseCase) Execute(ctx context.Context, env string) (VerifyReport, error) {
+	vault, err := useCase.vaultService.OpenUnverified(ctx, env)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	defer vault.Lock()
+
+	report := VerifyReport{
+		Env:           env,
+		FormatVersion: vault.Meta.FormatVersion,
+		Revision:      vault.Meta.Revision,
+		Slot:          vault.UnlockedSlot(),
+	}
+
+	if err := vault.VerifyIntegrity(); err != nil {
+		var integrityErr *model.IntegrityError
+		if !errors.As(err, &integrityErr) {
+			return report, err
+		}
+		report.Problems = append(report.Problems, integrityErr.Problems...)
+	}
+
+	keys := vault.ListKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		plaintext, err := vault.Session().Decrypt(key, vault.Entries[key].Value)
+		if err != nil {
+			if errors.Is(err, model.ErrTampered) {
+				err = model.ErrTampered
+			}
+			report.Problems = append(report.Problems, fmt.Sprintf("entry %q: %v", key, err))
+			continue
+		}
+		clear(plaintext)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+	"github.com/ahmed-abdelgawad92/lockify/test/assert"
+)
+
+func newVerifyTestVaultService(
+	session *test.MockSession,
+	tamper func(vault *model.Vault),
+) *test.MockVaultService {
+	return &test.MockVaultService{
+		OpenUnverifiedFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault, _ := model.NewVault(env, saltTest)
+			vault.SetEntry(keyTest, encryptedValueTest)
+			vault.SetEntry("OTHER_KEY", encryptedValueTest)
+			sealTestVault(vault)
+			tamper(vault)
+			vault.SetSession(session)
+			vault.SetUnlockedSlot(model.DefaultSlotName)
+			return vault, nil
+		},
+	}
+}
+
+func TestVerifyVaultUseCase_Execute_Consistent(t *testing.T) {
+	session := &test.MockSession{}
+	useCase := NewVerifyVaultUseCase(
+		newVerifyTestVaultService(session, func(vault *model.Vault) {}),
+	)
+
+	report, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.Count(t, 0, report.Problems)
+	assert.Equal(t, uint64(1), report.Revision)
+	assert.Equal(t, model.DefaultSlotName, report.Slot)
+	assert.True(t, report.HasMAC())
+	assert.True(t, session.Closed, "Execute() should lock the vault when done")
+}
+
+func TestVerifyVaultUseCase_Execute_ReportsEveryProblem(t *testing.T) {
+	session := &test.MockSession{
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			if key == "OTHER_KEY" {
+				return nil, fmt.Errorf("decryption failed for key %q: %w", key, model.ErrTampered)
+			}
+			return []byte(valueTest), nil
+		},
+	}
+	useCase := NewVerifyVaultUseCase(newVerifyTestVaultService(session, func(vault *model.Vault) {
+		delete(vault.Entries, keyTest)
+	}))
+
+	report, err := useCase.Execute(context.Background(), envTest)
+	assert.Nil(t, err, fmt.Sprintf("Execute() returned unexpected error: %v", err))
+	assert.DeepEqual(t, []string{
+		fmt.Sprintf("entry %q was removed", keyTest),
+		fmt.Sprintf("entry %q: %v", "OTHER_KEY", model.ErrTampered),
+	}, report.Problems)
+}
+
+func TestVerifyVaultUseCase_Execute_OpenError(t *testing.T) {
+	vaultService := &test.MockVaultService{
+		OpenUnverifiedFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("open error")
+		},
+	}
+	useCase := NewVerifyVaultUseCase(vaultService)
+
+	_, err := useCase.Execute(context.Background(), envTest)
+	assert.NotNil(t, err, "Execute() with open error expected error, got nil")
+	assert.Contains(t, "open error", err.Error())
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 const (
 	// DefaultArgonTime is the default time parameter for Argon2 key derivation.
 	DefaultArgonTime uint32 = 3
@@ -19,6 +21,40 @@ const (
 	DefaultFileMode uint32 = 0o600
 	// DefaultDirMode is the default directory mode for vault directories (rwx------).
 	DefaultDirMode uint32 = 0o700
+	// VaultFileSuffix is the file name suffix of vault files.
+	VaultFileSuffix = ".vault.enc"
+	// BackupFileSuffix is appended to a vault path, followed by the generation number,
+	// to name its backups.
+	BackupFileSuffix = ".bak"
+	// DefaultBackupGenerations is the default number of backups kept for each vault.
+	DefaultBackupGenerations = 3
+	// AuditFileSuffix is the file name suffix of the audit log of a vault.
+	AuditFileSuffix = ".audit.enc"
+	// LockFileSuffix is appended to a vault path to name the file that locks it.
+	LockFileSuffix = ".lock"
+	// DefaultLockTimeout is how long commands wait for another lockify process by default.
+	DefaultLockTimeout = 10 * time.Second
+	// DefaultCacheTTL is how long a prompted passphrase stays cached by default.
+	DefaultCacheTTL = 15 * time.Minute
+	// IdentityFileName is the file name of the identity in the user config directory.
+	IdentityFileName = "lockify/identity.txt"
+	// AgentSocketFileName is the file name of the agent socket in its private directory.
+	AgentSocketFileName = "agent.sock"
+	// CacheDirName is the name of the file cache directory in the private lockify directory
+	// of $XDG_RUNTIME_DIR.
+	CacheDirName = "cache"
+	// CacheBackendKeyring caches passphrases in the OS keyring.
+	CacheBackendKeyring = "keyring"
+	// CacheBackendFile caches passphrases in files encrypted for the login session.
+	CacheBackendFile = "file"
+	// CacheBackendNone caches nothing.
+	CacheBackendNone = "none"
+	// DefaultMinPassphraseScore is the strength score new passphrases need by default, on the
+	// 0 to 4 scale of the strength estimator.
+	DefaultMinPassphraseScore = 3
+	// StrictMinPassphraseScore is the strength score new passphrases of production
+	// environments need by default.
+	StrictMinPassphraseScore = 4
 )
 
 // EncryptionConfig holds cryptographic configuration
@@ -43,28 +79,74 @@ func DefaultEncryptionConfig() EncryptionConfig {
 
 // VaultConfig holds vault-related configuration
 type VaultConfig struct {
-	BaseDir       string
-	FileMode      uint32
-	DirMode       uint32
-	DefaultEnv    string
-	PassphraseEnv string
+	BaseDir              string
+	FileMode             uint32
+	DirMode              uint32
+	DefaultEnv           string
+	PassphraseEnv        string
+	PassphraseSourcesEnv string
+	IdentityEnv          string
+	IdentityFile         string
+	AgentSocketEnv       string
+	AgentSocket          string
+	UseAgent             bool
+	UseAgentEnv          string
+	CacheBackend         string
+	CacheBackendEnv      string
+	CacheDir             string
+	CacheTTL             time.Duration
+	CacheTTLEnv          string
+	BackupGenerations    int
+	BackupGenerationsEnv string
+	LockTimeout          time.Duration
+	MinPassphraseScore   int
+	MinPassphraseScores  map[string]int
+	PassphraseScoreEnv   string
+	BreachedFile         string
+	BreachedFileEnv      string
 }
 
 // DefaultVaultConfig returns default vault configuration
 func DefaultVaultConfig() VaultConfig {
 	return VaultConfig{
-		BaseDir:       ".lockify",
-		FileMode:      DefaultFileMode,
-		DirMode:       DefaultDirMode,
-		DefaultEnv:    "local",
-		PassphraseEnv: "LOCKIFY_PASSPHRASE",
+		BaseDir:              ".lockify",
+		FileMode:             DefaultFileMode,
+		DirMode:              DefaultDirMode,
+		DefaultEnv:           "local",
+		PassphraseEnv:        "LOCKIFY_PASSPHRASE",
+		PassphraseSourcesEnv: "LOCKIFY_PASSPHRASE_SOURCES",
+		IdentityEnv:          "LOCKIFY_IDENTITY",
+		AgentSocketEnv:       "LOCKIFY_AGENT_SOCK",
+		UseAgentEnv:          "LOCKIFY_AGENT",
+		CacheBackend:         CacheBackendKeyring,
+		CacheBackendEnv:      "LOCKIFY_CACHE",
+		CacheTTL:             DefaultCacheTTL,
+		CacheTTLEnv:          "LOCKIFY_CACHE_TTL",
+		BackupGenerations:    DefaultBackupGenerations,
+		BackupGenerationsEnv: "LOCKIFY_BACKUPS",
+		LockTimeout:          DefaultLockTimeout,
+		MinPassphraseScore:   DefaultMinPassphraseScore,
+		MinPassphraseScores: map[string]int{
+			"prod":       StrictMinPassphraseScore,
+			"production": StrictMinPassphraseScore,
+		},
+		PassphraseScoreEnv: "LOCKIFY_MIN_PASSPHRASE_SCORE",
+		BreachedFileEnv:    "LOCKIFY_BREACHED_PASSWORDS",
 	}
 }
 
 // GetVaultPath returns the path to a vault file for an environment
 func (c VaultConfig) GetVaultPath(env string) string {
 	if c.BaseDir == "" {
-		return env + ".vault.enc"
+		return env + VaultFileSuffix
+	}
+	return c.BaseDir + "/" + env + VaultFileSuffix
+}
+
+// GetAuditPath returns the path to the audit log of an environment, next to its vault
+func (c VaultConfig) GetAuditPath(env string) string {
+	if c.BaseDir == "" {
+		return env + AuditFileSuffix
 	}
-	return c.BaseDir + "/" + env + ".vault.enc"
+	return c.BaseDir + "/" + env + AuditFileSuffix
 }
@@ -7,9 +7,11 @@ import (
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/agent"
 	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/cache"
 	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/fs"
 	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/logger"
+	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/process"
 	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/prompt"
 	"github.com/ahmed-abdelgawad92/lockify/internal/infrastructure/security"
 )
@@ -20,19 +22,38 @@ var (
 	log              = logger.New()
 )
 
+// getHashService returns the bcrypt service that checks the fingerprints of vaults older
+// than model.FormatVersionEnvelope
 func getHashService() service.HashService {
 	return security.NewBcryptHashService()
 }
 
 func getCacheService() service.Cache {
-	return cache.NewOSKeyring("lockify")
+	return cache.New(vaultConfig, "lockify")
+}
+
+func getPassphrasePolicy() service.PassphrasePolicy {
+	return security.NewPassphrasePolicy(vaultConfig)
 }
 
 func getPassphraseService() service.PassphraseService {
+	cache := getCacheService()
 	return security.NewPassphraseService(
-		getCacheService(),
+		cache,
 		getHashService(),
-		vaultConfig.PassphraseEnv,
+		getPassphrasePolicy(),
+		vaultConfig.PassphraseSourcesEnv,
+		security.NewEnvPassphraseSource(vaultConfig.PassphraseEnv),
+		security.NewFilePassphraseSource(),
+		security.NewFDPassphraseSource(),
+		security.NewCommandPassphraseSource(),
+		security.NewKeyringPassphraseSource(cache),
+		security.NewPromptPassphraseSource(
+			prompt.NewService(),
+			cache,
+			vaultConfig.CacheTTLEnv,
+			vaultConfig.CacheTTL,
+		),
 	)
 }
 
@@ -48,14 +69,55 @@ func getVaultRepository() repository.VaultRepository {
 	return fs.NewFileVaultRepository(getFileSystemStorage(), vaultConfig)
 }
 
+func getIdentityRepository() repository.IdentityRepository {
+	return fs.NewFileIdentityRepository(
+		getFileSystemStorage(),
+		getEncryptionService(),
+		vaultConfig,
+	)
+}
+
+func getAuditRepository() repository.AuditRepository {
+	return fs.NewFileAuditRepository(getFileSystemStorage(), vaultConfig)
+}
+
 func getVaultService() service.VaultServiceInterface {
-	return service.NewVaultService(getVaultRepository(), getPassphraseService(), getHashService())
+	return service.NewVaultService(
+		getVaultRepository(),
+		getPassphraseService(),
+		getEncryptionService(),
+		getIdentityRepository(),
+		getAuthorService(),
+		getKeyAgent(),
+		app.NewVaultUpgrader(getEncryptionService()),
+		getAuditLog(),
+	)
+}
+
+func getKeyAgent() service.KeyAgent {
+	return agent.NewClient(vaultConfig)
 }
 
 func getImportService() service.ImportService {
 	return fs.NewImportService()
 }
 
+func getProcessRunner() service.ProcessRunner {
+	return process.NewExecRunner()
+}
+
+func getEditorService() service.EditorService {
+	return process.NewEditor()
+}
+
+func getAuthorService() service.AuthorService {
+	return process.NewGitAuthor()
+}
+
+func getAuditLog() service.AuditLog {
+	return service.NewAuditService(getAuditRepository(), getAuthorService())
+}
+
 // GetLogger returns the logger instance.
 func GetLogger() domain.Logger {
 	return log
@@ -63,7 +125,7 @@ func GetLogger() domain.Logger {
 
 // BuildAddEntry creates and returns an AddEntry use case.
 func BuildAddEntry() app.AddEntryUc {
-	return app.NewAddEntryUseCase(getVaultService(), getEncryptionService())
+	return app.NewAddEntryUseCase(getVaultService(), getAuditLog())
 }
 
 // BuildPromptService creates and returns a prompt service instance.
@@ -83,27 +145,42 @@ func BuildClearEnvCachedPassphrase() app.ClearEnvCachedPassphraseUseCase {
 
 // BuildDeleteEntry creates and returns a DeleteEntry use case.
 func BuildDeleteEntry() app.DeleteEntryUc {
-	return app.NewDeleteEntryUseCase(getVaultService())
+	return app.NewDeleteEntryUseCase(getVaultService(), getAuditLog())
 }
 
 // BuildExportEnv creates and returns an ExportEnv use case.
 func BuildExportEnv() app.ExportEnvUc {
-	return app.NewExportEnvUseCase(getVaultService(), getEncryptionService(), GetLogger())
+	return app.NewExportEnvUseCase(getVaultService(), getAuditLog(), GetLogger())
+}
+
+// BuildEditEnv creates and returns an EditEnv use case.
+func BuildEditEnv() app.EditEnvUc {
+	return app.NewEditEnvUseCase(
+		getVaultService(),
+		getImportService(),
+		getEditorService(),
+		getAuditLog(),
+	)
+}
+
+// BuildSetEntries creates and returns a SetEntries use case.
+func BuildSetEntries() app.SetEntriesUc {
+	return app.NewSetEntriesUseCase(getVaultService(), getAuditLog())
 }
 
 // BuildGetEntry creates and returns a GetEntry use case.
 func BuildGetEntry() app.GetEntryUc {
-	return app.NewGetEntryUseCase(getVaultService(), getEncryptionService())
+	return app.NewGetEntryUseCase(getVaultService(), getAuditLog(), GetLogger())
 }
 
 // BuildInitializeVault creates and returns an InitializeVault use case.
 func BuildInitializeVault() app.InitUc {
-	return app.NewInitializeVaultUseCase(getVaultService())
+	return app.NewInitializeVaultUseCase(getVaultService(), getAuditLog())
 }
 
 // BuildListEntries creates and returns a ListEntries use case.
 func BuildListEntries() app.ListEntriesUc {
-	return app.NewListEntriesUseCase(getVaultService())
+	return app.NewListEntriesUseCase(getVaultService(), getAuditLog())
 }
 
 // BuildRotatePassphrase creates and returns a RotatePassphrase use case.
@@ -111,16 +188,195 @@ func BuildRotatePassphrase() app.RotatePassphraseUc {
 	return app.NewRotatePassphraseUseCase(
 		getVaultRepository(),
 		getEncryptionService(),
-		getHashService(),
+		getPassphraseService(),
+		getPassphrasePolicy(),
+		getAuditLog(),
+	)
+}
+
+// BuildAddSlot creates and returns an AddSlot use case.
+func BuildAddSlot() app.AddSlotUc {
+	return app.NewAddSlotUseCase(
+		getVaultService(),
+		getEncryptionService(),
+		getPassphrasePolicy(),
+		getAuditLog(),
 	)
 }
 
+// BuildRemoveSlot creates and returns a RemoveSlot use case.
+func BuildRemoveSlot() app.RemoveSlotUc {
+	return app.NewRemoveSlotUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildListSlots creates and returns a ListSlots use case.
+func BuildListSlots() app.ListSlotsUc {
+	return app.NewListSlotsUseCase(getVaultService())
+}
+
+// BuildGenerateIdentity creates and returns a GenerateIdentity use case.
+func BuildGenerateIdentity() app.GenerateIdentityUc {
+	return app.NewGenerateIdentityUseCase(getEncryptionService(), getIdentityRepository())
+}
+
+// BuildAddRecipient creates and returns an AddRecipient use case.
+func BuildAddRecipient() app.AddRecipientUc {
+	return app.NewAddRecipientUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildRemoveRecipient creates and returns a RemoveRecipient use case.
+func BuildRemoveRecipient() app.RemoveRecipientUc {
+	return app.NewRemoveRecipientUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildListRecipients creates and returns a ListRecipients use case.
+func BuildListRecipients() app.ListRecipientsUc {
+	return app.NewListRecipientsUseCase(getVaultService())
+}
+
 // BuildImportEnv creates and returns an ImportEnv use case.
 func BuildImportEnv() app.ImportEnvUc {
 	return app.NewImportEnvUseCase(
 		getVaultService(),
 		getImportService(),
+		getAuditLog(),
+		GetLogger(),
+	)
+}
+
+// BuildMigrateVault creates and returns a MigrateVault use case.
+func BuildMigrateVault() app.MigrateVaultUc {
+	return app.NewMigrateVaultUseCase(
+		getVaultService(),
+		getVaultRepository(),
 		getEncryptionService(),
+		getAuditLog(),
+	)
+}
+
+// BuildRestoreVault creates and returns a RestoreVault use case.
+func BuildRestoreVault() app.RestoreVaultUc {
+	return app.NewRestoreVaultUseCase(getVaultRepository(), getVaultService(), getAuditLog())
+}
+
+// BuildVerifyVault creates and returns a VerifyVault use case.
+func BuildVerifyVault() app.VerifyVaultUc {
+	return app.NewVerifyVaultUseCase(getVaultService())
+}
+
+// BuildRunCommand creates and returns a RunCommand use case.
+func BuildRunCommand() app.RunCommandUc {
+	return app.NewRunCommandUseCase(
+		getVaultService(),
+		getProcessRunner(),
+		getAuditLog(),
 		GetLogger(),
+		[]string{vaultConfig.PassphraseEnv, vaultConfig.IdentityEnv, vaultConfig.AgentSocketEnv},
+	)
+}
+
+// BuildListEnvs creates and returns a ListEnvs use case.
+func BuildListEnvs() app.ListEnvsUc {
+	return app.NewListEnvsUseCase(getVaultRepository(), getIdentityRepository())
+}
+
+// BuildCloneEnv creates and returns a CloneEnv use case.
+func BuildCloneEnv() app.CloneEnvUc {
+	return app.NewCloneEnvUseCase(
+		getVaultService(),
+		getVaultRepository(),
+		getPassphraseService(),
+		getAuditLog(),
 	)
 }
+
+// BuildRenameEnv creates and returns a RenameEnv use case.
+func BuildRenameEnv() app.RenameEnvUc {
+	return app.NewRenameEnvUseCase(
+		getVaultService(),
+		getVaultRepository(),
+		getPassphraseService(),
+		getEncryptionService(),
+		getAuditLog(),
+	)
+}
+
+// BuildDeleteEnv creates and returns a DeleteEnv use case.
+func BuildDeleteEnv() app.DeleteEnvUc {
+	return app.NewDeleteEnvUseCase(
+		getVaultService(),
+		getVaultRepository(),
+		getPassphraseService(),
+		getAuditLog(),
+	)
+}
+
+// BuildDiffEnvs creates and returns a DiffEnvs use case.
+func BuildDiffEnvs() app.DiffEnvsUc {
+	return app.NewDiffEnvsUseCase(getVaultService(), getImportService(), getAuditLog())
+}
+
+// BuildPromoteEntries creates and returns a PromoteEntries use case.
+func BuildPromoteEntries() app.PromoteEntriesUc {
+	return app.NewPromoteEntriesUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildMoveEntry creates and returns a MoveEntry use case.
+func BuildMoveEntry() app.MoveEntryUc {
+	return app.NewMoveEntryUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildListHistory creates and returns a ListHistory use case.
+func BuildListHistory() app.ListHistoryUc {
+	return app.NewListHistoryUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildRollbackEntry creates and returns a RollbackEntry use case.
+func BuildRollbackEntry() app.RollbackEntryUc {
+	return app.NewRollbackEntryUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildSetHistoryPolicy creates and returns a SetHistoryPolicy use case.
+func BuildSetHistoryPolicy() app.SetHistoryPolicyUc {
+	return app.NewSetHistoryPolicyUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildListAudit creates and returns a ListAudit use case.
+func BuildListAudit() app.ListAuditUc {
+	return app.NewListAuditUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildSetEntryMetadata creates and returns a SetEntryMetadata use case.
+func BuildSetEntryMetadata() app.SetEntryMetadataUc {
+	return app.NewSetEntryMetadataUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildSetMetadataVisibility creates and returns a SetMetadataVisibility use case.
+func BuildSetMetadataVisibility() app.SetMetadataVisibilityUc {
+	return app.NewSetMetadataVisibilityUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildListStale creates and returns a ListStale use case.
+func BuildListStale() app.ListStaleUc {
+	return app.NewListStaleUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildSetRotationPolicy creates and returns a SetRotationPolicy use case.
+func BuildSetRotationPolicy() app.SetRotationPolicyUc {
+	return app.NewSetRotationPolicyUseCase(getVaultService(), getAuditLog())
+}
+
+// BuildRunAgent creates and returns a RunAgent use case.
+func BuildRunAgent() app.RunAgentUc {
+	return app.NewRunAgentUseCase(agent.NewServer(vaultConfig))
+}
+
+// BuildForgetAgentKeys creates and returns a ForgetAgentKeys use case.
+func BuildForgetAgentKeys() app.ForgetAgentKeysUc {
+	return app.NewForgetAgentKeysUseCase(getKeyAgent())
+}
+
+// BuildGetAgentStatus creates and returns a GetAgentStatus use case.
+func BuildGetAgentStatus() app.GetAgentStatusUc {
+	return app.NewGetAgentStatusUseCase(getKeyAgent())
+}
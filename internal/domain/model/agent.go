@@ -0,0 +1,83 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultAgentIdleTimeout is how long the agent keeps a vault key that is not used.
+	DefaultAgentIdleTimeout = 15 * time.Minute
+	// DefaultAgentMaxLifetime is how long the agent keeps a vault key at most.
+	DefaultAgentMaxLifetime = 8 * time.Hour
+)
+
+// ErrAgentNoKey is returned when the agent holds no key for a vault.
+var ErrAgentNoKey = errors.New("the agent holds no key for this vault")
+
+// AgentKey is an unlocked vault data key held in memory by the lockify agent.
+type AgentKey struct {
+	// ID identifies the vault the key unlocks, see Meta.AgentKeyID.
+	ID  string `json:"id"`
+	Env string `json:"env"`
+	// Slot is the key slot whose passphrase unlocked the key.
+	Slot string `json:"slot"`
+	Key  []byte `json:"key"`
+}
+
+// AgentKeyInfo describes a key held by the agent without revealing it.
+type AgentKeyInfo struct {
+	Env        string    `json:"env"`
+	Slot       string    `json:"slot"`
+	AddedAt    time.Time `json:"added_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// AgentPolicy limits how long the agent keeps vault keys.
+type AgentPolicy struct {
+	// IdleTimeout drops a key that was not used for this long.
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	// MaxLifetime drops a key this long after it was added, however often it is used.
+	MaxLifetime time.Duration `json:"max_lifetime"`
+}
+
+// Validate checks that keys are dropped after a positive idle timeout and max lifetime
+func (p AgentPolicy) Validate() error {
+	if p.IdleTimeout <= 0 {
+		return fmt.Errorf("idle timeout must be positive, got %s", p.IdleTimeout)
+	}
+	if p.MaxLifetime <= 0 {
+		return fmt.Errorf("max lifetime must be positive, got %s", p.MaxLifetime)
+	}
+	return nil
+}
+
+// ExpiresAt returns when a key added at addedAt and last used at lastUsedAt is dropped
+func (p AgentPolicy) ExpiresAt(addedAt, lastUsedAt time.Time) time.Time {
+	idle := lastUsedAt.Add(p.IdleTimeout)
+	if lifetime := addedAt.Add(p.MaxLifetime); lifetime.Before(idle) {
+		return lifetime
+	}
+	return idle
+}
+
+// AgentStatus describes a running agent and the keys it holds.
+type AgentStatus struct {
+	Socket    string         `json:"socket"`
+	PID       int            `json:"pid"`
+	StartedAt time.Time      `json:"started_at"`
+	Policy    AgentPolicy    `json:"policy"`
+	Keys      []AgentKeyInfo `json:"keys"`
+}
+
+// AgentKeyID identifies the vault of env for the agent, so vaults of the same env in
+// different projects never share a key. It changes when the vault is re-keyed with a new
+// salt.
+func (m Meta) AgentKeyID() string {
+	sum := sha256.Sum256([]byte(m.Env + "\x00" + m.Salt))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgentPolicy_ExpiresAt(t *testing.T) {
+	policy := AgentPolicy{IdleTimeout: 15 * time.Minute, MaxLifetime: time.Hour}
+	added := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if got, want := policy.ExpiresAt(added, added), added.Add(15*time.Minute); !got.Equal(want) {
+		t.Errorf("ExpiresAt() of an unused key = %v, want %v", got, want)
+	}
+	used := added.Add(55 * time.Minute)
+	if got, want := policy.ExpiresAt(added, used), added.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt() near the max lifetime = %v, want %v", got, want)
+	}
+}
+
+func TestAgentPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  AgentPolicy
+		wantErr bool
+	}{
+		{name: "valid", policy: AgentPolicy{IdleTimeout: time.Minute, MaxLifetime: time.Hour}},
+		{name: "no idle timeout", policy: AgentPolicy{MaxLifetime: time.Hour}, wantErr: true},
+		{
+			name:    "negative lifetime",
+			policy:  AgentPolicy{IdleTimeout: time.Minute, MaxLifetime: -time.Hour},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMeta_AgentKeyID(t *testing.T) {
+	meta := Meta{Env: "prod", Salt: "salt"}
+	if meta.AgentKeyID() != (Meta{Env: "prod", Salt: "salt", Revision: 3}).AgentKeyID() {
+		t.Error("AgentKeyID() changed with the revision of the vault")
+	}
+	if meta.AgentKeyID() == (Meta{Env: "prod", Salt: "other"}).AgentKeyID() {
+		t.Error("AgentKeyID() is the same for vaults with different salts")
+	}
+	if meta.AgentKeyID() == (Meta{Env: "staging", Salt: "salt"}).AgentKeyID() {
+		t.Error("AgentKeyID() is the same for vaults of different environments")
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// AuditOperation names an operation recorded in the audit log of a vault.
+type AuditOperation string
+
+const (
+	// AuditOpen records that a vault was unlocked without revealing values.
+	AuditOpen AuditOperation = "open"
+	// AuditGet records that single values were decrypted.
+	AuditGet AuditOperation = "get"
+	// AuditExport records that values left the vault in bulk, as a file, to a process or to
+	// another vault.
+	AuditExport AuditOperation = "export"
+	// AuditSet records that entries were added or changed.
+	AuditSet AuditOperation = "set"
+	// AuditDelete records that entries were removed.
+	AuditDelete AuditOperation = "delete"
+	// AuditRotate records that the passphrase or data key of a vault was rotated.
+	AuditRotate AuditOperation = "rotate"
+	// AuditAddSlot records that a key slot was added; the keys of the event hold its name.
+	AuditAddSlot AuditOperation = "add-slot"
+	// AuditRemoveSlot records that a key slot was removed; the keys of the event hold its name.
+	AuditRemoveSlot AuditOperation = "remove-slot"
+	// AuditAddRecipient records that a recipient was added; the keys of the event hold its
+	// name, or its public key when it has none.
+	AuditAddRecipient AuditOperation = "add-recipient"
+	// AuditRemoveRecipient records that a recipient was removed; the keys of the event hold
+	// its name or public key.
+	AuditRemoveRecipient AuditOperation = "remove-recipient"
+	// AuditHistoryPolicy records that the history retention of a vault was changed.
+	AuditHistoryPolicy AuditOperation = "history-policy"
+	// AuditMetadataVisibility records that entry metadata was made public or sealed.
+	AuditMetadataVisibility AuditOperation = "metadata-visibility"
+	// AuditRotationPolicy records that the rotation policy of a vault was changed.
+	AuditRotationPolicy AuditOperation = "rotation-policy"
+	// AuditDeleteEnv records that the vault of an environment was deleted.
+	AuditDeleteEnv AuditOperation = "delete-env"
+	// AuditRestore records that a vault was restored from one of its backups.
+	AuditRestore AuditOperation = "restore"
+)
+
+const (
+	// auditRecordDomain is the record domain audit records are sealed under, which keeps them
+	// apart from entries.
+	auditRecordDomain = "audit"
+	// legacyAuditRecordKey is the entry key name the audit records of vaults older than
+	// FormatVersionAuditRecords are sealed under.
+	legacyAuditRecordKey = "lockify-audit"
+)
+
+// AuditEvent is one record of the audit log of an environment. Seq numbers the records
+// from 1 and PrevHash is the hash of the record before, so that records cannot be removed
+// or reordered without breaking the chain.
+type AuditEvent struct {
+	Seq       int            `json:"seq"`
+	Time      string         `json:"time"`
+	Operation AuditOperation `json:"operation"`
+	Keys      []string       `json:"keys,omitempty"`
+	Actor     string         `json:"actor,omitempty"`
+	Host      string         `json:"host,omitempty"`
+	Slot      string         `json:"slot,omitempty"`
+	PrevHash  string         `json:"prev_hash,omitempty"`
+}
+
+// AuditChainError reports the first audit record that does not continue the chain.
+type AuditChainError struct {
+	Seq    int
+	Reason string
+}
+
+// Error describes where and why the chain is broken.
+func (e *AuditChainError) Error() string {
+	return fmt.Sprintf("audit log chain is broken at record %d: %s", e.Seq, e.Reason)
+}
+
+// AuditRecordHash returns the hash that the record after record refers to, or an empty
+// string for the start of the log.
+func AuditRecordHash(record string) string {
+	if record == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(record))
+	return hex.EncodeToString(sum[:])
+}
+
+// SealAuditEvent encrypts an event with the session of a vault of format version and returns
+// the record to store.
+func SealAuditEvent(session Session, version int, event AuditEvent) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	if version < FormatVersionAuditRecords {
+		return session.Encrypt(legacyAuditRecordKey, data)
+	}
+	return session.SealRecord(auditRecordDomain, data)
+}
+
+// openAuditRecord decrypts a record sealed by SealAuditEvent for the same format version.
+func openAuditRecord(session Session, version int, record string) ([]byte, error) {
+	if version < FormatVersionAuditRecords {
+		return session.Decrypt(legacyAuditRecordKey, record)
+	}
+	return session.OpenRecord(auditRecordDomain, record)
+}
+
+// ChainAuditEvents numbers events from 1, links each to the one before and seals them with
+// the session of a vault of format version, returning the records of a new audit log.
+func ChainAuditEvents(session Session, version int, events []AuditEvent) ([]string, error) {
+	records := make([]string, 0, len(events))
+	last := ""
+	for i, event := range events {
+		event.Seq = i + 1
+		event.PrevHash = AuditRecordHash(last)
+		record, err := SealAuditEvent(session, version, event)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		last = record
+	}
+	return records, nil
+}
+
+// OpenAuditLog decrypts the records of the audit log of a vault of format version and
+// verifies their chain. When the chain is broken it returns the events before the break
+// together with an *AuditChainError.
+func OpenAuditLog(session Session, version int, records []string) ([]AuditEvent, error) {
+	events := make([]AuditEvent, 0, len(records))
+	last := ""
+	for i, record := range records {
+		seq := i + 1
+		data, err := openAuditRecord(session, version, record)
+		if err != nil {
+			return events, &AuditChainError{seq, "record cannot be decrypted with the vault key"}
+		}
+
+		var event AuditEvent
+		err = json.Unmarshal(data, &event)
+		clear(data)
+		if err != nil {
+			return events, &AuditChainError{seq, "record is not a valid audit event"}
+		}
+		if event.Seq != seq {
+			return events, &AuditChainError{
+				seq,
+				fmt.Sprintf("record claims to be number %d", event.Seq),
+			}
+		}
+		if event.PrevHash != AuditRecordHash(last) {
+			return events, &AuditChainError{seq, "previous record was removed or modified"}
+		}
+
+		events = append(events, event)
+		last = record
+	}
+	return events, nil
+}
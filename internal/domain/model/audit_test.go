@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func createTestAuditLog(t *testing.T) []string {
+	t.Helper()
+	events := []AuditEvent{
+		{Time: "2026-01-01T00:00:00Z", Operation: AuditSet, Keys: []string{"API_KEY"}},
+		{Time: "2026-01-02T00:00:00Z", Operation: AuditGet, Keys: []string{"API_KEY"}},
+		{Time: "2026-01-03T00:00:00Z", Operation: AuditExport, Keys: []string{"API_KEY", "DB"}},
+	}
+	records, err := ChainAuditEvents(&fakeSession{}, CurrentFormatVersion, events)
+	if err != nil {
+		t.Fatalf("ChainAuditEvents() failed: %v", err)
+	}
+	return records
+}
+
+func TestChainAuditEvents_OpensIntact(t *testing.T) {
+	records := createTestAuditLog(t)
+
+	events, err := OpenAuditLog(&fakeSession{}, CurrentFormatVersion, records)
+	if err != nil {
+		t.Fatalf("OpenAuditLog() failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	for i, event := range events {
+		if event.Seq != i+1 {
+			t.Errorf("events[%d].Seq = %d, want %d", i, event.Seq, i+1)
+		}
+	}
+	if events[0].PrevHash != "" {
+		t.Errorf("the first event should not refer to a record, got %q", events[0].PrevHash)
+	}
+	if events[2].PrevHash != AuditRecordHash(records[1]) {
+		t.Error("the last event should refer to the hash of the record before it")
+	}
+}
+
+func TestOpenAuditLog_DetectsBrokenChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(records []string) []string
+		wantSeq int
+		intact  int
+	}{
+		{
+			name: "modified record",
+			tamper: func(records []string) []string {
+				records[1] = strings.Replace(records[1], `"get"`, `"open"`, 1)
+				return records
+			},
+			wantSeq: 3,
+			intact:  2,
+		},
+		{
+			name: "removed record",
+			tamper: func(records []string) []string {
+				return append(records[:1], records[2:]...)
+			},
+			wantSeq: 2,
+			intact:  1,
+		},
+		{
+			name: "reordered records",
+			tamper: func(records []string) []string {
+				records[0], records[1] = records[1], records[0]
+				return records
+			},
+			wantSeq: 1,
+			intact:  0,
+		},
+		{
+			name: "invalid record",
+			tamper: func(records []string) []string {
+				records[2] = "garbage"
+				return records
+			},
+			wantSeq: 3,
+			intact:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.tamper(createTestAuditLog(t))
+
+			events, err := OpenAuditLog(&fakeSession{}, CurrentFormatVersion, records)
+			var chainErr *AuditChainError
+			if !errors.As(err, &chainErr) {
+				t.Fatalf("expected an AuditChainError, got %v", err)
+			}
+			if chainErr.Seq != tt.wantSeq {
+				t.Errorf("expected a break at record %d, got %d", tt.wantSeq, chainErr.Seq)
+			}
+			if len(events) != tt.intact {
+				t.Errorf("expected %d events before the break, got %d", tt.intact, len(events))
+			}
+		})
+	}
+}
+
+func TestChainAuditEvents_RenumbersEvents(t *testing.T) {
+	events := []AuditEvent{
+		{Seq: 7, Operation: AuditOpen, PrevHash: "stale"},
+		{Seq: 9, Operation: AuditRotate, PrevHash: "stale"},
+	}
+
+	records, err := ChainAuditEvents(&fakeSession{}, CurrentFormatVersion, events)
+	if err != nil {
+		t.Fatalf("ChainAuditEvents() failed: %v", err)
+	}
+	if _, err := OpenAuditLog(&fakeSession{}, CurrentFormatVersion, records); err != nil {
+		t.Errorf("rechained events should open intact, got %v", err)
+	}
+}
+
+func TestSealAuditEvent_SeparatesRecordsFromEntries(t *testing.T) {
+	event := AuditEvent{Seq: 1, Operation: AuditOpen}
+
+	record, err := SealAuditEvent(&fakeSession{}, CurrentFormatVersion, event)
+	if err != nil {
+		t.Fatalf("SealAuditEvent() failed: %v", err)
+	}
+	if !strings.HasPrefix(record, auditRecordDomain+":") {
+		t.Errorf("expected a record of the audit domain, got %q", record)
+	}
+
+	// An entry sealed under the legacy key name must not pass for an audit record.
+	entry, _ := (&fakeSession{}).Encrypt(legacyAuditRecordKey, []byte(`{"seq":1}`))
+	if _, err := OpenAuditLog(&fakeSession{}, CurrentFormatVersion, []string{entry}); err == nil {
+		t.Error("expected an entry to be rejected as an audit record")
+	}
+
+	legacy, err := SealAuditEvent(&fakeSession{}, FormatVersionKeyCheck, event)
+	if err != nil {
+		t.Fatalf("SealAuditEvent() failed: %v", err)
+	}
+	events, err := OpenAuditLog(&fakeSession{}, FormatVersionKeyCheck, []string{legacy})
+	if err != nil || len(events) != 1 {
+		t.Errorf("expected the legacy log to open with one event, got %v, %v", events, err)
+	}
+}
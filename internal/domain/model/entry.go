@@ -5,4 +5,22 @@ type Entry struct {
 	Value     string `json:"value"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	// Secret marks values that should not be shown in a terminal.
+	Secret bool `json:"secret,omitempty"`
+	// Version numbers the values of the entry, starting at 1. Entries written before
+	// versions were recorded have version 0, which counts as 1.
+	Version int `json:"version,omitempty"`
+	// UpdatedBy is the author of the current value.
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// History holds previous encrypted values, oldest first, within the vault retention.
+	History []EntryVersion `json:"history,omitempty"`
+	// Metadata describes the entry in vaults that make metadata public.
+	Metadata *EntryMetadata `json:"metadata,omitempty"`
+	// SealedMetadata is the metadata encrypted with the vault key, in other vaults.
+	SealedMetadata string `json:"sealed_metadata,omitempty"`
+}
+
+// CurrentVersion returns the version number of the current value.
+func (e Entry) CurrentVersion() int {
+	return max(e.Version, 1)
 }
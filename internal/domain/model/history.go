@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHistoryVersions is how many previous values of each entry are kept when the vault
+// header sets no retention policy.
+const DefaultHistoryVersions = 10
+
+// EntryVersion is a previous value of an entry, still encrypted for the entry key.
+type EntryVersion struct {
+	Version    int    `json:"version"`
+	Value      string `json:"value"`
+	UpdatedAt  string `json:"updated_at"`
+	UpdatedBy  string `json:"updated_by,omitempty"`
+	ReplacedAt string `json:"replaced_at"`
+}
+
+// HistoryPolicy limits the previous values kept for every entry of a vault.
+type HistoryPolicy struct {
+	// Versions is how many previous values are kept; 0 keeps none.
+	Versions int `json:"versions"`
+	// MaxAgeDays drops previous values replaced longer ago than this; 0 keeps them forever.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+}
+
+// Validate checks that the policy limits are not negative
+func (p HistoryPolicy) Validate() error {
+	if p.Versions < 0 {
+		return fmt.Errorf("history versions cannot be negative, got %d", p.Versions)
+	}
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("history max age cannot be negative, got %d days", p.MaxAgeDays)
+	}
+	return nil
+}
+
+// Retention returns the history policy of the vault, which defaults to keeping the last
+// DefaultHistoryVersions values.
+func (m Meta) Retention() HistoryPolicy {
+	if m.History == nil {
+		return HistoryPolicy{Versions: DefaultHistoryVersions}
+	}
+	return *m.History
+}
+
+// SetAuthor sets the author recorded with the entries changed through this vault
+func (v *Vault) SetAuthor(author string) {
+	v.author = author
+}
+
+// SetHistoryPolicy stores the retention policy in the vault header and applies it to the
+// history of every entry
+func (v *Vault) SetHistoryPolicy(policy HistoryPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	v.Meta.History = &policy
+
+	now := time.Now().UTC()
+	for key, entry := range v.Entries {
+		entry.History = policy.prune(entry.History, now)
+		v.Entries[key] = entry
+	}
+	return nil
+}
+
+// RollbackEntry makes a previous value of an entry its current value again. The rollback is
+// recorded as a new version, so it can be rolled back as well.
+func (v *Vault) RollbackEntry(key string, version int) error {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return err
+	}
+	if version == entry.CurrentVersion() {
+		return fmt.Errorf("version %d is already the current value of key %q", version, key)
+	}
+
+	for _, previous := range entry.History {
+		if previous.Version == version {
+			return v.SetEntry(key, previous.Value)
+		}
+	}
+	return fmt.Errorf("version %d of key %q not found in its history", version, key)
+}
+
+// recordVersion moves the current value of an entry into its history before it is replaced
+func (v *Vault) recordVersion(entry Entry, now time.Time) Entry {
+	entry.History = append(entry.History, EntryVersion{
+		Version:    entry.CurrentVersion(),
+		Value:      entry.Value,
+		UpdatedAt:  entry.UpdatedAt,
+		UpdatedBy:  entry.UpdatedBy,
+		ReplacedAt: now.Format(time.RFC3339),
+	})
+	entry.History = v.Meta.Retention().prune(entry.History, now)
+	entry.Version = entry.CurrentVersion() + 1
+	return entry
+}
+
+// prune drops the previous values that fall outside the policy
+func (p HistoryPolicy) prune(history []EntryVersion, now time.Time) []EntryVersion {
+	if p.MaxAgeDays > 0 {
+		cutoff := now.AddDate(0, 0, -p.MaxAgeDays)
+		kept := make([]EntryVersion, 0, len(history))
+		for _, previous := range history {
+			replacedAt, err := time.Parse(time.RFC3339, previous.ReplacedAt)
+			if err == nil && replacedAt.Before(cutoff) {
+				continue
+			}
+			kept = append(kept, previous)
+		}
+		history = kept
+	}
+
+	if len(history) > p.Versions {
+		history = history[len(history)-p.Versions:]
+	}
+	if len(history) == 0 {
+		return nil
+	}
+	return append([]EntryVersion(nil), history...)
+}
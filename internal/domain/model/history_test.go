@@ -0,0 +1,163 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetEntry_RecordsHistory(t *testing.T) {
+	vault := createTestVault(t)
+	vault.SetAuthor("alice")
+
+	for _, value := range []string{"v1", "v2", "v3"} {
+		if err := vault.SetEntry(testKey, value); err != nil {
+			t.Fatalf("SetEntry(%q) failed: %v", value, err)
+		}
+	}
+
+	entry := vault.Entries[testKey]
+	if entry.Value != "v3" || entry.Version != 3 {
+		t.Errorf("expected current value v3 as version 3, got %q as %d", entry.Value, entry.Version)
+	}
+	if entry.UpdatedBy != "alice" {
+		t.Errorf("expected author alice, got %q", entry.UpdatedBy)
+	}
+	if len(entry.History) != 2 {
+		t.Fatalf("expected 2 previous values, got %d", len(entry.History))
+	}
+	for i, want := range []string{"v1", "v2"} {
+		previous := entry.History[i]
+		if previous.Value != want || previous.Version != i+1 {
+			t.Errorf("history[%d] = %q as %d, want %q as %d",
+				i, previous.Value, previous.Version, want, i+1)
+		}
+		if previous.UpdatedBy != "alice" || previous.ReplacedAt == "" {
+			t.Errorf("history[%d] should keep its author and replacement time: %+v", i, previous)
+		}
+	}
+}
+
+func TestSetEntry_LegacyEntryStartsAtVersionOne(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Entries = map[string]Entry{testKey: {Value: "old", UpdatedAt: "2024-01-01T00:00:00Z"}}
+
+	if err := vault.SetEntry(testKey, "new"); err != nil {
+		t.Fatalf("SetEntry() failed: %v", err)
+	}
+
+	entry := vault.Entries[testKey]
+	if entry.Version != 2 || len(entry.History) != 1 || entry.History[0].Version != 1 {
+		t.Errorf("expected the legacy value to become version 1, got %+v", entry)
+	}
+}
+
+func TestSetEntry_HistoryRetention(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.History = &HistoryPolicy{Versions: 2}
+
+	for _, value := range []string{"v1", "v2", "v3", "v4"} {
+		vault.SetEntry(testKey, value)
+	}
+
+	history := vault.Entries[testKey].History
+	if len(history) != 2 || history[0].Value != "v2" || history[1].Value != "v3" {
+		t.Errorf("expected only v2 and v3 to be kept, got %+v", history)
+	}
+
+	vault.Meta.History = &HistoryPolicy{Versions: 0}
+	vault.SetEntry(testKey, "v5")
+	if history := vault.Entries[testKey].History; history != nil {
+		t.Errorf("expected no history to be kept, got %+v", history)
+	}
+}
+
+func TestHistoryPolicy_PruneByAge(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	history := []EntryVersion{
+		{Version: 1, ReplacedAt: "2025-01-01T00:00:00Z"},
+		{Version: 2, ReplacedAt: "2025-06-20T00:00:00Z"},
+		{Version: 3, ReplacedAt: "2025-06-29T00:00:00Z"},
+	}
+
+	kept := HistoryPolicy{Versions: 10, MaxAgeDays: 30}.prune(history, now)
+	if len(kept) != 2 || kept[0].Version != 2 || kept[1].Version != 3 {
+		t.Errorf("expected versions 2 and 3 to be kept, got %+v", kept)
+	}
+
+	kept = HistoryPolicy{Versions: 1, MaxAgeDays: 30}.prune(history, now)
+	if len(kept) != 1 || kept[0].Version != 3 {
+		t.Errorf("expected only version 3 to be kept, got %+v", kept)
+	}
+}
+
+func TestSetHistoryPolicy(t *testing.T) {
+	vault := createTestVault(t)
+	for _, value := range []string{"v1", "v2", "v3"} {
+		vault.SetEntry(testKey, value)
+	}
+
+	if err := vault.SetHistoryPolicy(HistoryPolicy{Versions: -1}); err == nil {
+		t.Error("expected a negative number of versions to be rejected")
+	}
+
+	if err := vault.SetHistoryPolicy(HistoryPolicy{Versions: 1}); err != nil {
+		t.Fatalf("SetHistoryPolicy() failed: %v", err)
+	}
+	if got := vault.Meta.Retention(); got.Versions != 1 {
+		t.Errorf("expected the policy to be stored in the header, got %+v", got)
+	}
+	if history := vault.Entries[testKey].History; len(history) != 1 || history[0].Value != "v2" {
+		t.Errorf("expected only v2 to be kept, got %+v", history)
+	}
+}
+
+func TestMeta_RetentionDefault(t *testing.T) {
+	if got := (Meta{}).Retention(); got.Versions != DefaultHistoryVersions || got.MaxAgeDays != 0 {
+		t.Errorf("expected the default retention, got %+v", got)
+	}
+}
+
+func TestRollbackEntry(t *testing.T) {
+	vault := createTestVault(t)
+	for _, value := range []string{"v1", "v2", "v3"} {
+		vault.SetEntry(testKey, value)
+	}
+
+	if err := vault.RollbackEntry(testKey, 1); err != nil {
+		t.Fatalf("RollbackEntry() failed: %v", err)
+	}
+
+	entry := vault.Entries[testKey]
+	if entry.Value != "v1" || entry.Version != 4 {
+		t.Errorf("expected v1 restored as version 4, got %q as %d", entry.Value, entry.Version)
+	}
+	if last := entry.History[len(entry.History)-1]; last.Value != "v3" || last.Version != 3 {
+		t.Errorf("expected the replaced value to be kept as version 3, got %+v", last)
+	}
+}
+
+func TestRollbackEntry_Errors(t *testing.T) {
+	vault := createTestVault(t)
+	vault.SetEntry(testKey, "v1")
+	vault.SetEntry(testKey, "v2")
+
+	tests := []struct {
+		name    string
+		key     string
+		version int
+		want    string
+	}{
+		{"current version", testKey, 2, "already the current value"},
+		{"unknown version", testKey, 7, "not found"},
+		{"unknown key", "missing", 1, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vault.RollbackEntry(tt.key, tt.version)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RollbackEntry() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
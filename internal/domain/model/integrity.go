@@ -0,0 +1,141 @@
+package model
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IntegrityError lists every inconsistency found while verifying a vault.
+type IntegrityError struct {
+	Problems []string
+}
+
+// Error returns all problems in a single message.
+func (e *IntegrityError) Error() string {
+	return "vault integrity check failed: " + strings.Join(e.Problems, "; ")
+}
+
+// Seal bumps the vault revision and recomputes the manifest and MAC with the session key.
+// Vaults older than FormatVersionVaultMAC only get their revision bumped, and envelope vaults
+// that still carry bcrypt fingerprints are upgraded to FormatVersionKeyCheck.
+func (v *Vault) Seal() error {
+	if v.session == nil {
+		return errors.New("vault is locked")
+	}
+
+	v.Meta.UpgradeKeyCheck()
+	v.Meta.Revision++
+	if v.Meta.FormatVersion < FormatVersionVaultMAC {
+		return nil
+	}
+
+	manifest := make(map[string]string, len(v.Entries))
+	for key, entry := range v.Entries {
+		digest, err := v.entryDigest(key, entry)
+		if err != nil {
+			return err
+		}
+		manifest[key] = digest
+	}
+	v.Meta.Manifest = manifest
+
+	mac, err := v.headerMAC()
+	if err != nil {
+		return err
+	}
+	v.Meta.MAC = mac
+	return nil
+}
+
+// VerifyIntegrity checks the vault MAC and manifest against the entries and returns an
+// *IntegrityError describing every inconsistency. Vaults older than FormatVersionVaultMAC
+// carry no MAC and always pass.
+func (v *Vault) VerifyIntegrity() error {
+	if v.session == nil {
+		return errors.New("vault is locked")
+	}
+	if v.Meta.FormatVersion < FormatVersionVaultMAC {
+		return nil
+	}
+
+	var problems []string
+	if v.Meta.MAC == "" {
+		problems = append(problems, "vault has no integrity MAC")
+	} else {
+		mac, err := v.headerMAC()
+		if err != nil {
+			return err
+		}
+		if !equalMAC(mac, v.Meta.MAC) {
+			problems = append(
+				problems,
+				"vault header MAC does not match: meta, revision or manifest was modified",
+			)
+		}
+	}
+
+	for _, key := range sortedKeys(v.Meta.Manifest) {
+		entry, exists := v.Entries[key]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("entry %q was removed", key))
+			continue
+		}
+		digest, err := v.entryDigest(key, entry)
+		if err != nil {
+			return err
+		}
+		if !equalMAC(digest, v.Meta.Manifest[key]) {
+			problems = append(problems, fmt.Sprintf("entry %q was modified or rolled back", key))
+		}
+	}
+	for _, key := range sortedKeys(v.Entries) {
+		if _, exists := v.Meta.Manifest[key]; !exists {
+			problems = append(problems, fmt.Sprintf("entry %q was added outside lockify", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &IntegrityError{Problems: problems}
+	}
+	return nil
+}
+
+// headerMAC authenticates the meta, including revision and manifest, without the MAC itself.
+func (v *Vault) headerMAC() (string, error) {
+	meta := v.Meta
+	meta.MAC = ""
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode vault header: %w", err)
+	}
+	return v.session.MAC(data)
+}
+
+// entryDigest authenticates a single entry together with its key name.
+func (v *Vault) entryDigest(key string, entry Entry) (string, error) {
+	data, err := json.Marshal(struct {
+		Key   string `json:"key"`
+		Entry Entry  `json:"entry"`
+	}{key, entry})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode entry %q: %w", key, err)
+	}
+	return v.session.MAC(data)
+}
+
+func equalMAC(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
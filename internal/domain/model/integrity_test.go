@@ -0,0 +1,199 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func createSealedTestVault(t *testing.T) *Vault {
+	t.Helper()
+	vault := createTestVault(t)
+	vault.SetSession(&fakeSession{})
+	if err := vault.SetEntry(testKey, testValue); err != nil {
+		t.Fatalf("failed to set entry: %v", err)
+	}
+	if err := vault.SetEntry("OTHER_KEY", "other-value"); err != nil {
+		t.Fatalf("failed to set entry: %v", err)
+	}
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+	return vault
+}
+
+func integrityProblems(t *testing.T, vault *Vault) []string {
+	t.Helper()
+	err := vault.VerifyIntegrity()
+	if err == nil {
+		return nil
+	}
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("expected *IntegrityError, got %v", err)
+	}
+	return integrityErr.Problems
+}
+
+func TestSeal(t *testing.T) {
+	vault := createSealedTestVault(t)
+
+	if vault.Meta.Revision != 1 {
+		t.Errorf("expected revision 1, got %d", vault.Meta.Revision)
+	}
+	if vault.Meta.MAC == "" {
+		t.Error("expected MAC to be set")
+	}
+	if len(vault.Meta.Manifest) != 2 {
+		t.Errorf("expected 2 manifest entries, got %d", len(vault.Meta.Manifest))
+	}
+
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+	if vault.Meta.Revision != 2 {
+		t.Errorf("expected revision 2 after sealing again, got %d", vault.Meta.Revision)
+	}
+}
+
+func TestSealLockedVault(t *testing.T) {
+	vault := createTestVault(t)
+
+	if err := vault.Seal(); err == nil {
+		t.Error("expected error sealing a locked vault")
+	}
+}
+
+func TestSealLegacyFormat(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FormatVersion = FormatVersionVaultMAC - 1
+	vault.SetSession(&fakeSession{})
+
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+	if vault.Meta.MAC != "" || vault.Meta.Manifest != nil {
+		t.Error("expected no MAC or manifest for a legacy format")
+	}
+	if problems := integrityProblems(t, vault); len(problems) != 0 {
+		t.Errorf("expected legacy vault to pass, got %v", problems)
+	}
+}
+
+func TestSealDropsFingerprints(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FormatVersion = FormatVersionRecipients
+	vault.Meta.FingerPrint = testFingerprint
+	vault.Meta.Slots = []KeySlot{{Name: "ci", Salt: testSalt, FingerPrint: testFingerprint}}
+	vault.SetSession(&fakeSession{})
+
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+	if vault.Meta.FormatVersion != FormatVersionKeyCheck {
+		t.Errorf(
+			"expected format version %d, got %d",
+			FormatVersionKeyCheck,
+			vault.Meta.FormatVersion,
+		)
+	}
+	if vault.Meta.FingerPrint != "" || vault.Meta.Slots[0].FingerPrint != "" {
+		t.Error("expected fingerprints to be dropped")
+	}
+	if problems := integrityProblems(t, vault); len(problems) != 0 {
+		t.Errorf("expected upgraded vault to pass, got %v", problems)
+	}
+}
+
+func TestSealKeepsFingerprintOfPreEnvelopeVault(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FormatVersion = FormatVersionEnvelope - 1
+	vault.Meta.FingerPrint = testFingerprint
+	vault.SetSession(&fakeSession{})
+
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+	if vault.Meta.FormatVersion != FormatVersionEnvelope-1 {
+		t.Errorf(
+			"expected format version %d, got %d",
+			FormatVersionEnvelope-1,
+			vault.Meta.FormatVersion,
+		)
+	}
+	if vault.Meta.FingerPrint != testFingerprint {
+		t.Error("expected the fingerprint of a vault without a wrapped key to be kept")
+	}
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(vault *Vault)
+		want   []string
+	}{
+		{
+			name:   "untouched",
+			tamper: func(vault *Vault) {},
+		},
+		{
+			name:   "entry removed",
+			tamper: func(vault *Vault) { delete(vault.Entries, testKey) },
+			want:   []string{`entry "test-key" was removed`},
+		},
+		{
+			name: "entry added",
+			tamper: func(vault *Vault) {
+				vault.Entries["NEW_KEY"] = Entry{Value: "injected"}
+			},
+			want: []string{`entry "NEW_KEY" was added outside lockify`},
+		},
+		{
+			name: "entry rolled back",
+			tamper: func(vault *Vault) {
+				entry := vault.Entries[testKey]
+				entry.UpdatedAt = "2000-01-01T00:00:00Z"
+				vault.Entries[testKey] = entry
+			},
+			want: []string{`entry "test-key" was modified or rolled back`},
+		},
+		{
+			name:   "revision rolled back",
+			tamper: func(vault *Vault) { vault.Meta.Revision-- },
+			want:   []string{"vault header MAC does not match"},
+		},
+		{
+			name: "manifest rewritten with entry",
+			tamper: func(vault *Vault) {
+				delete(vault.Entries, testKey)
+				delete(vault.Meta.Manifest, testKey)
+			},
+			want: []string{"vault header MAC does not match"},
+		},
+		{
+			name:   "mac stripped",
+			tamper: func(vault *Vault) { vault.Meta.MAC = "" },
+			want:   []string{"vault has no integrity MAC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := createSealedTestVault(t)
+			tt.tamper(vault)
+
+			problems := integrityProblems(t, vault)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("expected problems %v, got %v", tt.want, problems)
+			}
+			for _, want := range tt.want {
+				if !slices.ContainsFunc(problems, func(p string) bool {
+					return strings.Contains(p, want)
+				}) {
+					t.Errorf("expected a problem containing %q, got %v", want, problems)
+				}
+			}
+		})
+	}
+}
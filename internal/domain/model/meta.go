@@ -1,8 +1,117 @@
 package model
 
-// Meta contains metadata about the vault including environment, salt, and fingerprint.
+const (
+	// CurrentFormatVersion is the vault file format version written for new vaults.
+	CurrentFormatVersion = 8
+	// FormatVersionEntryAAD is the first format version that authenticates the env,
+	// key name and format version of every entry as associated data.
+	FormatVersionEntryAAD = 2
+	// FormatVersionVaultMAC is the first format version that authenticates the whole
+	// vault with a revision counter, an entry manifest and a MAC.
+	FormatVersionVaultMAC = 3
+	// FormatVersionEnvelope is the first format version that encrypts entries with a random
+	// data key, which is stored wrapped by the passphrase-derived key.
+	FormatVersionEnvelope = 4
+	// FormatVersionKeySlots is the first format version that can wrap the data key for
+	// several named passphrases.
+	FormatVersionKeySlots = 5
+	// FormatVersionRecipients is the first format version that can wrap the data key for
+	// X25519 public-key recipients.
+	FormatVersionRecipients = 6
+	// FormatVersionKeyCheck is the first format version that stores no bcrypt fingerprint of
+	// the passphrases. A passphrase is checked by opening the data key it wraps, so Argon2id
+	// is the only way to test passphrases offline.
+	FormatVersionKeyCheck = 7
+	// FormatVersionAuditRecords is the first format version that seals audit records under
+	// associated data of their own, instead of under a reserved entry key name.
+	FormatVersionAuditRecords = 8
+	// CipherAES256GCM identifies AES-256 in Galois/Counter Mode.
+	CipherAES256GCM = "aes-256-gcm"
+	// KDFArgon2id identifies the Argon2id key derivation function.
+	KDFArgon2id = "argon2id"
+)
+
+// Meta contains metadata about the vault including environment, salt and wrapped data key.
+// FingerPrint is only set for vaults older than FormatVersionKeyCheck.
 type Meta struct {
-	Env         string `json:"env"`
-	Salt        string `json:"salt"`
-	FingerPrint string `json:"fingerprint"`
+	FormatVersion int               `json:"format_version,omitempty"`
+	Env           string            `json:"env"`
+	Salt          string            `json:"salt"`
+	FingerPrint   string            `json:"fingerprint,omitempty"`
+	Cipher        CipherParams      `json:"cipher,omitzero"`
+	KDF           KDFParams         `json:"kdf,omitzero"`
+	WrappedKey    string            `json:"wrapped_key,omitempty"`
+	Slots         []KeySlot         `json:"slots,omitempty"`
+	Recipients    []Recipient       `json:"recipients,omitempty"`
+	History       *HistoryPolicy    `json:"history,omitempty"`
+	PublicMeta    bool              `json:"public_metadata,omitempty"`
+	Rotation      *RotationPolicy   `json:"rotation,omitempty"`
+	Revision      uint64            `json:"revision,omitempty"`
+	Manifest      map[string]string `json:"manifest,omitempty"`
+	MAC           string            `json:"mac,omitempty"`
+}
+
+// CipherParams describes the cipher used to encrypt vault entries.
+type CipherParams struct {
+	Algorithm string `json:"algorithm"`
+	NonceSize int    `json:"nonce_size"`
+}
+
+// KDFParams describes how the vault key is derived from the passphrase.
+type KDFParams struct {
+	Algorithm string `json:"algorithm"`
+	Time      uint32 `json:"time"`
+	MemoryKB  uint32 `json:"memory_kb"`
+	Threads   uint8  `json:"threads"`
+	KeyLength uint32 `json:"key_length"`
+}
+
+// LegacyCipherParams returns the cipher parameters implied by format version 0 vaults,
+// which did not record them. These values must never change.
+func LegacyCipherParams() CipherParams {
+	return CipherParams{Algorithm: CipherAES256GCM, NonceSize: 12}
+}
+
+// LegacyKDFParams returns the KDF parameters implied by format version 0 vaults,
+// which did not record them. These values must never change.
+func LegacyKDFParams() KDFParams {
+	return KDFParams{
+		Algorithm: KDFArgon2id,
+		Time:      3,
+		MemoryKB:  64 * 1024,
+		Threads:   4,
+		KeyLength: 32,
+	}
+}
+
+// EntryFormatVersion returns the version of the entry sealing scheme used by this vault,
+// which is authenticated with every entry. It only changes when entries must be re-sealed,
+// so later vault format versions that keep the scheme keep their entries valid.
+func (m Meta) EntryFormatVersion() int {
+	if m.FormatVersion < FormatVersionEntryAAD {
+		return 0
+	}
+	return FormatVersionEntryAAD
+}
+
+// CryptoParams returns the cipher and KDF parameters that apply to this vault.
+func (m Meta) CryptoParams() (CipherParams, KDFParams) {
+	if m.FormatVersion == 0 {
+		return LegacyCipherParams(), LegacyKDFParams()
+	}
+	return m.Cipher, m.KDF
+}
+
+// UpgradeKeyCheck drops the bcrypt fingerprints of an envelope vault, whose passphrases are
+// checked against the wrapped data key instead, and moves it to FormatVersionKeyCheck. The
+// format versions in between only enabled features, so this needs no passphrase.
+func (m *Meta) UpgradeKeyCheck() {
+	if m.FormatVersion < FormatVersionEnvelope || m.FormatVersion >= FormatVersionKeyCheck {
+		return
+	}
+	m.FingerPrint = ""
+	for i := range m.Slots {
+		m.Slots[i].FingerPrint = ""
+	}
+	m.FormatVersion = FormatVersionKeyCheck
 }
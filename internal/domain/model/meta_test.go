@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCryptoParams_LegacyVault(t *testing.T) {
+	meta := Meta{Env: testEnv, Salt: testSalt, FingerPrint: testFingerprint}
+
+	cipherParams, kdfParams := meta.CryptoParams()
+
+	if cipherParams != LegacyCipherParams() {
+		t.Errorf("expected legacy cipher params %+v, got %+v", LegacyCipherParams(), cipherParams)
+	}
+	if kdfParams != LegacyKDFParams() {
+		t.Errorf("expected legacy kdf params %+v, got %+v", LegacyKDFParams(), kdfParams)
+	}
+}
+
+func TestCryptoParams_StoredParams(t *testing.T) {
+	meta := Meta{
+		FormatVersion: CurrentFormatVersion,
+		Cipher:        CipherParams{Algorithm: CipherAES256GCM, NonceSize: 16},
+		KDF:           KDFParams{Algorithm: KDFArgon2id, Time: 1, MemoryKB: 1024, Threads: 1},
+	}
+
+	cipherParams, kdfParams := meta.CryptoParams()
+
+	if cipherParams != meta.Cipher {
+		t.Errorf("expected stored cipher params %+v, got %+v", meta.Cipher, cipherParams)
+	}
+	if kdfParams != meta.KDF {
+		t.Errorf("expected stored kdf params %+v, got %+v", meta.KDF, kdfParams)
+	}
+}
+
+func TestMeta_UnmarshalLegacyHeader(t *testing.T) {
+	data := `{"env":"test","salt":"test-salt","fingerprint":"test-fingerprint"}`
+
+	var meta Meta
+	if err := json.Unmarshal([]byte(data), &meta); err != nil {
+		t.Fatalf("failed to unmarshal legacy meta: %v", err)
+	}
+
+	if meta.FormatVersion != 0 {
+		t.Errorf("expected format version 0, got %d", meta.FormatVersion)
+	}
+}
+
+func TestMeta_MarshalStoresParams(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.Cipher = LegacyCipherParams()
+	vault.Meta.KDF = LegacyKDFParams()
+
+	data, err := json.Marshal(vault.Meta)
+	if err != nil {
+		t.Fatalf("failed to marshal meta: %v", err)
+	}
+
+	fields := []string{
+		fmt.Sprintf(`"format_version":%d`, CurrentFormatVersion),
+		`"cipher":`,
+		`"kdf":`,
+		`"memory_kb":65536`,
+	}
+	for _, field := range fields {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("expected marshalled meta to contain %s, got %s", field, data)
+		}
+	}
+}
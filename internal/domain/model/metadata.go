@@ -0,0 +1,200 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// metadataRecordPrefix prefixes the key name sealed entry metadata is encrypted under, which
+// keeps it apart from the value of the entry.
+const metadataRecordPrefix = "lockify-metadata:"
+
+// EntryMetadata describes what an entry is for. It is authenticated together with its entry
+// and, unless the vault makes metadata public, encrypted with the vault key.
+type EntryMetadata struct {
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// ExpiresAt is the RFC 3339 time after which the value should no longer be used.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// MaxAgeDays is how many days the value may be used before it has to be rotated,
+	// overriding the rotation policy of the vault; 0 uses that policy.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+}
+
+// IsZero reports whether no metadata is set.
+func (m EntryMetadata) IsZero() bool {
+	return m.Description == "" && m.Owner == "" && len(m.Tags) == 0 && m.ExpiresAt == "" &&
+		m.MaxAgeDays == 0
+}
+
+// HasTag reports whether the metadata carries tag.
+func (m EntryMetadata) HasTag(tag string) bool {
+	return slices.Contains(m.Tags, tag)
+}
+
+// Expired reports whether the entry has an expiry at or before now.
+func (m EntryMetadata) Expired(now time.Time) bool {
+	if m.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, m.ExpiresAt)
+	return err == nil && !expiresAt.After(now)
+}
+
+// Validate checks the expiry format, max age and tag names.
+func (m EntryMetadata) Validate() error {
+	if m.MaxAgeDays < 0 {
+		return fmt.Errorf("max age cannot be negative, got %d days", m.MaxAgeDays)
+	}
+	if m.ExpiresAt != "" {
+		if _, err := time.Parse(time.RFC3339, m.ExpiresAt); err != nil {
+			return fmt.Errorf("invalid expiry %q: it must be an RFC 3339 time", m.ExpiresAt)
+		}
+	}
+	for _, tag := range m.Tags {
+		if tag == "" || strings.ContainsFunc(tag, unicode.IsSpace) || strings.Contains(tag, ",") {
+			return fmt.Errorf("invalid tag %q: tags cannot be empty or contain spaces", tag)
+		}
+	}
+	return nil
+}
+
+// normalize sorts tags and removes duplicates
+func (m EntryMetadata) normalize() EntryMetadata {
+	if len(m.Tags) == 0 {
+		m.Tags = nil
+		return m
+	}
+	m.Tags = slices.Compact(slices.Sorted(slices.Values(m.Tags)))
+	return m
+}
+
+// EntryMetadata returns the metadata of an entry, decrypting it when it is sealed.
+func (v *Vault) EntryMetadata(key string) (EntryMetadata, error) {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return EntryMetadata{}, err
+	}
+	return v.openMetadata(key, entry)
+}
+
+// SetEntryMetadata replaces the metadata of an entry. It is stored as is when the vault
+// makes metadata public and sealed with the vault key otherwise; zero metadata removes it.
+func (v *Vault) SetEntryMetadata(key string, metadata EntryMetadata) error {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return err
+	}
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+
+	entry, err = v.storeMetadata(key, entry, metadata.normalize())
+	if err != nil {
+		return err
+	}
+	v.Entries[key] = entry
+	return nil
+}
+
+// SetPublicMetadata makes the metadata of every entry readable without the vault key, or
+// seals it again. Either way it stays authenticated by the vault MAC.
+func (v *Vault) SetPublicMetadata(public bool) error {
+	if v.session == nil {
+		return errors.New("vault is locked")
+	}
+
+	v.Meta.PublicMeta = public
+	for _, key := range sortedKeys(v.Entries) {
+		if err := v.ApplyMetadataVisibility(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyMetadataVisibility stores the metadata of an entry moved from another vault the way
+// this vault keeps metadata. Sealed metadata must already be sealed for key.
+func (v *Vault) ApplyMetadataVisibility(key string) error {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return err
+	}
+	if (v.Meta.PublicMeta && entry.SealedMetadata == "") ||
+		(!v.Meta.PublicMeta && entry.Metadata == nil) {
+		return nil
+	}
+
+	metadata, err := v.openMetadata(key, entry)
+	if err != nil {
+		return err
+	}
+	entry, err = v.storeMetadata(key, entry, metadata)
+	if err != nil {
+		return err
+	}
+	v.Entries[key] = entry
+	return nil
+}
+
+// openMetadata returns the public or sealed metadata of entry
+func (v *Vault) openMetadata(key string, entry Entry) (EntryMetadata, error) {
+	if entry.SealedMetadata == "" {
+		if entry.Metadata == nil {
+			return EntryMetadata{}, nil
+		}
+		return *entry.Metadata, nil
+	}
+	if v.session == nil {
+		return EntryMetadata{}, errors.New("vault is locked")
+	}
+
+	data, err := v.session.Decrypt(MetadataRecordKey(key), entry.SealedMetadata)
+	if err != nil {
+		return EntryMetadata{}, fmt.Errorf("failed to decrypt metadata of key %q: %w", key, err)
+	}
+	var metadata EntryMetadata
+	err = json.Unmarshal(data, &metadata)
+	clear(data)
+	if err != nil {
+		return EntryMetadata{}, fmt.Errorf("invalid metadata of key %q: %w", key, err)
+	}
+	return metadata, nil
+}
+
+// storeMetadata sets metadata on entry the way the vault keeps metadata
+func (v *Vault) storeMetadata(key string, entry Entry, metadata EntryMetadata) (Entry, error) {
+	entry.Metadata = nil
+	entry.SealedMetadata = ""
+	if metadata.IsZero() {
+		return entry, nil
+	}
+	if v.Meta.PublicMeta {
+		entry.Metadata = &metadata
+		return entry, nil
+	}
+	if v.session == nil {
+		return Entry{}, errors.New("vault is locked")
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode metadata of key %q: %w", key, err)
+	}
+	entry.SealedMetadata, err = v.session.Encrypt(MetadataRecordKey(key), data)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encrypt metadata of key %q: %w", key, err)
+	}
+	return entry, nil
+}
+
+// MetadataRecordKey returns the key name the sealed metadata of key is encrypted under.
+func MetadataRecordKey(key string) string {
+	return metadataRecordPrefix + key
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createMetadataTestVault(t *testing.T) *Vault {
+	t.Helper()
+	vault := createTestVault(t)
+	vault.SetSession(&fakeSession{})
+	if err := vault.SetEntry(testKey, "value"); err != nil {
+		t.Fatalf("SetEntry() failed: %v", err)
+	}
+	return vault
+}
+
+func TestSetEntryMetadata_SealedByDefault(t *testing.T) {
+	vault := createMetadataTestVault(t)
+	metadata := EntryMetadata{
+		Description: "Payment API key",
+		Owner:       "alice",
+		Tags:        []string{"billing", "api", "billing"},
+	}
+
+	if err := vault.SetEntryMetadata(testKey, metadata); err != nil {
+		t.Fatalf("SetEntryMetadata() failed: %v", err)
+	}
+
+	entry := vault.Entries[testKey]
+	if entry.Metadata != nil || entry.SealedMetadata == "" {
+		t.Fatalf("expected sealed metadata only, got %+v", entry)
+	}
+	got, err := vault.EntryMetadata(testKey)
+	if err != nil {
+		t.Fatalf("EntryMetadata() failed: %v", err)
+	}
+	if got.Description != metadata.Description || got.Owner != metadata.Owner {
+		t.Errorf("expected metadata to round-trip, got %+v", got)
+	}
+	if !slices.Equal(got.Tags, []string{"api", "billing"}) {
+		t.Errorf("expected sorted tags without duplicates, got %v", got.Tags)
+	}
+}
+
+func TestSetPublicMetadata(t *testing.T) {
+	vault := createMetadataTestVault(t)
+	vault.SetEntryMetadata(testKey, EntryMetadata{Owner: "alice"})
+
+	if err := vault.SetPublicMetadata(true); err != nil {
+		t.Fatalf("SetPublicMetadata(true) failed: %v", err)
+	}
+	entry := vault.Entries[testKey]
+	if entry.SealedMetadata != "" || entry.Metadata == nil || entry.Metadata.Owner != "alice" {
+		t.Fatalf("expected public metadata only, got %+v", entry)
+	}
+
+	vault.SetEntryMetadata(testKey, EntryMetadata{Owner: "bob"})
+	if entry := vault.Entries[testKey]; entry.Metadata == nil || entry.Metadata.Owner != "bob" {
+		t.Errorf("expected new metadata of a public vault to stay public, got %+v", entry)
+	}
+
+	if err := vault.SetPublicMetadata(false); err != nil {
+		t.Fatalf("SetPublicMetadata(false) failed: %v", err)
+	}
+	entry = vault.Entries[testKey]
+	if entry.Metadata != nil || entry.SealedMetadata == "" {
+		t.Errorf("expected metadata to be sealed again, got %+v", entry)
+	}
+}
+
+func TestSetEntryMetadata_ZeroRemovesMetadata(t *testing.T) {
+	vault := createMetadataTestVault(t)
+	vault.SetEntryMetadata(testKey, EntryMetadata{Owner: "alice"})
+
+	if err := vault.SetEntryMetadata(testKey, EntryMetadata{}); err != nil {
+		t.Fatalf("SetEntryMetadata() failed: %v", err)
+	}
+	entry := vault.Entries[testKey]
+	if entry.Metadata != nil || entry.SealedMetadata != "" {
+		t.Errorf("expected no metadata, got %+v", entry)
+	}
+}
+
+func TestSetEntryMetadata_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata EntryMetadata
+		wantErr  string
+	}{
+		{name: "expiry", metadata: EntryMetadata{ExpiresAt: "tomorrow"}, wantErr: "invalid expiry"},
+		{name: "empty tag", metadata: EntryMetadata{Tags: []string{""}}, wantErr: "invalid tag"},
+		{name: "space", metadata: EntryMetadata{Tags: []string{"a b"}}, wantErr: "invalid tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := createMetadataTestVault(t)
+			err := vault.SetEntryMetadata(testKey, tt.metadata)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEntryMetadata_Expired(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expiresAt string
+		want      bool
+	}{
+		{expiresAt: "", want: false},
+		{expiresAt: "2026-05-31T23:59:59Z", want: true},
+		{expiresAt: "2026-06-01T00:00:00Z", want: true},
+		{expiresAt: "2026-06-01T00:00:01Z", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := (EntryMetadata{ExpiresAt: tt.expiresAt}).Expired(now); got != tt.want {
+			t.Errorf("Expired() with expiry %q = %v, want %v", tt.expiresAt, got, tt.want)
+		}
+	}
+}
+
+func TestSetEntryMetadata_Authenticated(t *testing.T) {
+	vault := createMetadataTestVault(t)
+	vault.SetPublicMetadata(true)
+	vault.SetEntryMetadata(testKey, EntryMetadata{Owner: "alice"})
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("Seal() failed: %v", err)
+	}
+
+	entry := vault.Entries[testKey]
+	entry.Metadata = &EntryMetadata{Owner: "mallory"}
+	vault.Entries[testKey] = entry
+
+	if problems := integrityProblems(t, vault); len(problems) != 1 {
+		t.Errorf("expected changed public metadata to be detected, got %v", problems)
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ScoreTooGuessable is the strength score of a passphrase guessed in under 10^3 guesses.
+	ScoreTooGuessable = iota
+	// ScoreVeryGuessable is the strength score of a passphrase guessed in under 10^6 guesses.
+	ScoreVeryGuessable
+	// ScoreSomewhatGuessable is the strength score of a passphrase guessed in under 10^8
+	// guesses.
+	ScoreSomewhatGuessable
+	// ScoreSafelyUnguessable is the strength score of a passphrase guessed in under 10^10
+	// guesses.
+	ScoreSafelyUnguessable
+	// ScoreVeryUnguessable is the strength score of a passphrase needing more guesses.
+	ScoreVeryUnguessable
+	// MaxPassphraseScore is the highest strength score.
+	MaxPassphraseScore = ScoreVeryUnguessable
+)
+
+// PassphraseStrength estimates how hard a passphrase is to guess.
+type PassphraseStrength struct {
+	// Score ranks the passphrase from ScoreTooGuessable to ScoreVeryUnguessable.
+	Score int
+	// Guesses is the estimated number of guesses needed to find the passphrase.
+	Guesses float64
+	// Warning explains what makes the passphrase easy to guess; empty when nothing does.
+	Warning string
+	// Suggestions tell how to choose a stronger passphrase.
+	Suggestions []string
+}
+
+// WeakPassphraseError is returned when a new passphrase is rejected by the passphrase policy
+// of an environment, either because it is too easy to guess or because it appears in the
+// list of breached passwords.
+type WeakPassphraseError struct {
+	Env      string
+	MinScore int
+	Strength PassphraseStrength
+	Breached bool
+}
+
+// Error tells why the passphrase was rejected
+func (e *WeakPassphraseError) Error() string {
+	if e.Breached {
+		return fmt.Sprintf(
+			"passphrase for environment %s appears in the list of breached passwords",
+			e.Env,
+		)
+	}
+
+	msg := fmt.Sprintf(
+		"passphrase is too weak for environment %s: strength %d of %d, at least %d required",
+		e.Env,
+		e.Strength.Score,
+		MaxPassphraseScore,
+		e.MinScore,
+	)
+	if e.Strength.Warning != "" {
+		msg += " (" + strings.TrimSuffix(e.Strength.Warning, ".") + ")"
+	}
+	return msg
+}
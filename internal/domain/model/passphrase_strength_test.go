@@ -0,0 +1,48 @@
+package model
+
+import "testing"
+
+func TestWeakPassphraseError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *WeakPassphraseError
+		want string
+	}{
+		{
+			name: "weak",
+			err: &WeakPassphraseError{
+				Env:      "prod",
+				MinScore: 4,
+				Strength: PassphraseStrength{
+					Score:   ScoreVeryGuessable,
+					Warning: "Recent years are easy to guess",
+				},
+			},
+			want: "passphrase is too weak for environment prod: strength 1 of 4, " +
+				"at least 4 required (Recent years are easy to guess)",
+		},
+		{
+			name: "weak without warning",
+			err: &WeakPassphraseError{
+				Env:      "dev",
+				MinScore: 3,
+				Strength: PassphraseStrength{Score: ScoreSomewhatGuessable},
+			},
+			want: "passphrase is too weak for environment dev: strength 2 of 4, " +
+				"at least 3 required",
+		},
+		{
+			name: "breached",
+			err:  &WeakPassphraseError{Env: "prod", MinScore: 4, Breached: true},
+			want: "passphrase for environment prod appears in the list of breached passwords",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecipientSlotPrefix prefixes the unlocked slot name of a vault opened with an identity.
+const RecipientSlotPrefix = "recipient:"
+
+// ErrNoIdentity is returned when no local identity file exists.
+var ErrNoIdentity = errors.New("no identity found")
+
+// Identity is a key pair whose private key unlocks the vaults it is a recipient of.
+type Identity struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+// Recipient wraps the vault data key for the public key of an identity.
+type Recipient struct {
+	Name       string `json:"name,omitempty"`
+	PublicKey  string `json:"public_key"`
+	WrappedKey string `json:"wrapped_key"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
+// Label returns the recipient name, or its public key when it has none.
+func (r Recipient) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.PublicKey
+}
+
+// FindRecipient returns the recipient with the given public key
+func (m Meta) FindRecipient(publicKey string) (Recipient, bool) {
+	for _, recipient := range m.Recipients {
+		if recipient.PublicKey == publicKey {
+			return recipient, true
+		}
+	}
+	return Recipient{}, false
+}
+
+// AddRecipient adds a public-key recipient to the vault
+func (v *Vault) AddRecipient(recipient Recipient) error {
+	if v.Meta.FormatVersion < FormatVersionRecipients {
+		return fmt.Errorf(
+			"vault format version %d does not support recipients",
+			v.Meta.FormatVersion,
+		)
+	}
+	if recipient.PublicKey == "" || recipient.WrappedKey == "" {
+		return errors.New("recipient is incomplete")
+	}
+	for _, existing := range v.Meta.Recipients {
+		if existing.PublicKey == recipient.PublicKey {
+			return fmt.Errorf("recipient %q already exists", existing.Label())
+		}
+		if recipient.Name != "" && existing.Name == recipient.Name {
+			return fmt.Errorf("recipient %q already exists", recipient.Name)
+		}
+	}
+
+	if recipient.CreatedAt == "" {
+		recipient.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	v.Meta.Recipients = append(v.Meta.Recipients, recipient)
+	return nil
+}
+
+// RemoveRecipient removes the recipient with the given name or public key
+func (v *Vault) RemoveRecipient(nameOrPublicKey string) error {
+	if nameOrPublicKey == "" {
+		return errors.New("recipient cannot be empty")
+	}
+
+	for i, recipient := range v.Meta.Recipients {
+		if recipient.PublicKey == nameOrPublicKey || recipient.Name == nameOrPublicKey {
+			v.Meta.Recipients = append(v.Meta.Recipients[:i], v.Meta.Recipients[i+1:]...)
+			if len(v.Meta.Recipients) == 0 {
+				v.Meta.Recipients = nil
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("recipient %q not found", nameOrPublicKey)
+}
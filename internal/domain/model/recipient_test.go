@@ -0,0 +1,96 @@
+package model
+
+import "testing"
+
+func createTestRecipient(name string) Recipient {
+	return Recipient{
+		Name:       name,
+		PublicKey:  "lockify1" + name,
+		WrappedKey: name + "-wrapped-key",
+	}
+}
+
+func TestRecipientLabel(t *testing.T) {
+	if label := createTestRecipient("alice").Label(); label != "alice" {
+		t.Errorf("expected label %q, got %q", "alice", label)
+	}
+	if label := (Recipient{PublicKey: "lockify1bob"}).Label(); label != "lockify1bob" {
+		t.Errorf("expected the public key as label, got %q", label)
+	}
+}
+
+func TestAddRecipient(t *testing.T) {
+	vault := createTestVault(t)
+	if err := vault.AddRecipient(createTestRecipient("alice")); err != nil {
+		t.Fatalf("AddRecipient() returned unexpected error: %v", err)
+	}
+
+	recipient, ok := vault.Meta.FindRecipient("lockify1alice")
+	if !ok {
+		t.Fatal("FindRecipient() did not find the added recipient")
+	}
+	if recipient.Name != "alice" || recipient.CreatedAt == "" {
+		t.Errorf("expected alice with a creation time, got %+v", recipient)
+	}
+	if _, ok := vault.Meta.FindRecipient("lockify1bob"); ok {
+		t.Error("FindRecipient() found a recipient that was not added")
+	}
+}
+
+func TestAddRecipient_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient Recipient
+	}{
+		{name: "missing public key", recipient: Recipient{WrappedKey: "wrapped"}},
+		{name: "missing wrapped key", recipient: Recipient{PublicKey: "lockify1carol"}},
+		{name: "duplicate public key", recipient: createTestRecipient("alice")},
+		{
+			name:      "duplicate name",
+			recipient: Recipient{Name: "alice", PublicKey: "lockify1x", WrappedKey: "wrapped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := createTestVault(t)
+			if err := vault.AddRecipient(createTestRecipient("alice")); err != nil {
+				t.Fatalf("AddRecipient() returned unexpected error: %v", err)
+			}
+			if err := vault.AddRecipient(tt.recipient); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestAddRecipient_LegacyFormat(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FormatVersion = FormatVersionKeySlots
+
+	if err := vault.AddRecipient(createTestRecipient("alice")); err == nil {
+		t.Error("AddRecipient() on a vault without recipient support expected error, got nil")
+	}
+}
+
+func TestRemoveRecipient(t *testing.T) {
+	vault := createTestVault(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := vault.AddRecipient(createTestRecipient(name)); err != nil {
+			t.Fatalf("AddRecipient() returned unexpected error: %v", err)
+		}
+	}
+
+	if err := vault.RemoveRecipient("alice"); err != nil {
+		t.Fatalf("RemoveRecipient() by name returned unexpected error: %v", err)
+	}
+	if err := vault.RemoveRecipient("alice"); err == nil {
+		t.Error("RemoveRecipient() of a removed recipient expected error, got nil")
+	}
+	if err := vault.RemoveRecipient("lockify1bob"); err != nil {
+		t.Fatalf("RemoveRecipient() by public key returned unexpected error: %v", err)
+	}
+	if vault.Meta.Recipients != nil {
+		t.Errorf("expected no recipients, got %+v", vault.Meta.Recipients)
+	}
+}
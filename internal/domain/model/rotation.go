@@ -0,0 +1,189 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultRotationWarnDays is how many days before it is due an entry is reported as expiring
+// when the rotation policy sets no warning window.
+const DefaultRotationWarnDays = 14
+
+// RotationStatus tells whether the value of an entry is due for rotation.
+type RotationStatus string
+
+const (
+	// RotationOK is the status of a value that is not due for rotation soon, or at all.
+	RotationOK RotationStatus = "ok"
+	// RotationExpiring is the status of a value due for rotation within the warning window.
+	RotationExpiring RotationStatus = "expiring"
+	// RotationExpired is the status of a value overdue for rotation or past its expiry.
+	RotationExpired RotationStatus = "expired"
+)
+
+// RotationPolicy limits how long entry values are used before they have to be rotated. It is
+// stored in the vault header, so it is authenticated with it.
+type RotationPolicy struct {
+	// MaxAgeDays applies to every entry; 0 sets no limit.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+	// Tags sets the max age of the entries carrying a tag, overriding MaxAgeDays. An entry
+	// with several of these tags uses the shortest max age.
+	Tags map[string]int `json:"tags,omitempty"`
+	// WarnDays is how many days before it is due a rotation is reported; 0 uses
+	// DefaultRotationWarnDays.
+	WarnDays int `json:"warn_days,omitempty"`
+}
+
+// EntryRotation describes when the value of an entry is due for rotation.
+type EntryRotation struct {
+	Key       string
+	UpdatedAt string
+	// MaxAgeDays is the max age that applies to the entry; 0 when none does.
+	MaxAgeDays int
+	// DueAt is the RFC 3339 time the value has to be rotated by, the earlier of its max age
+	// and its expiry; empty when neither is set.
+	DueAt  string
+	Status RotationStatus
+}
+
+// IsZero reports whether the policy sets nothing.
+func (p RotationPolicy) IsZero() bool {
+	return p.MaxAgeDays == 0 && len(p.Tags) == 0 && p.WarnDays == 0
+}
+
+// Validate checks that the limits are not negative and the tag names are valid
+func (p RotationPolicy) Validate() error {
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("rotation max age cannot be negative, got %d days", p.MaxAgeDays)
+	}
+	if p.WarnDays < 0 {
+		return fmt.Errorf("rotation warning cannot be negative, got %d days", p.WarnDays)
+	}
+	for tag, days := range p.Tags {
+		if tag == "" || strings.ContainsFunc(tag, unicode.IsSpace) || strings.Contains(tag, ",") {
+			return fmt.Errorf("invalid tag %q: tags cannot be empty or contain spaces", tag)
+		}
+		if days <= 0 {
+			return fmt.Errorf("rotation max age of tag %q must be positive, got %d days", tag, days)
+		}
+	}
+	return nil
+}
+
+// Warn returns the warning window of the policy in days.
+func (p RotationPolicy) Warn() int {
+	if p.WarnDays == 0 {
+		return DefaultRotationWarnDays
+	}
+	return p.WarnDays
+}
+
+// WithMaxAge returns a copy of the policy with the max age of the entries carrying tag, or
+// of every entry when tag is empty, set to days; 0 removes that limit.
+func (p RotationPolicy) WithMaxAge(tag string, days int) RotationPolicy {
+	p.Tags = maps.Clone(p.Tags)
+	switch {
+	case tag == "":
+		p.MaxAgeDays = days
+	case days == 0:
+		delete(p.Tags, tag)
+	default:
+		if p.Tags == nil {
+			p.Tags = make(map[string]int)
+		}
+		p.Tags[tag] = days
+	}
+	if len(p.Tags) == 0 {
+		p.Tags = nil
+	}
+	return p
+}
+
+// MaxAge returns the max age in days that applies to an entry with metadata: its own max
+// age, else the shortest max age of its tags, else the max age of every entry.
+func (p RotationPolicy) MaxAge(metadata EntryMetadata) int {
+	if metadata.MaxAgeDays > 0 {
+		return metadata.MaxAgeDays
+	}
+	maxAge := 0
+	for _, tag := range metadata.Tags {
+		if days, ok := p.Tags[tag]; ok && (maxAge == 0 || days < maxAge) {
+			maxAge = days
+		}
+	}
+	if maxAge == 0 {
+		return p.MaxAgeDays
+	}
+	return maxAge
+}
+
+// RotationPolicy returns the rotation policy of the vault; its zero value rotates nothing.
+func (m Meta) RotationPolicy() RotationPolicy {
+	if m.Rotation == nil {
+		return RotationPolicy{}
+	}
+	return *m.Rotation
+}
+
+// SetRotationPolicy stores the rotation policy in the vault header; a zero policy removes it.
+func (v *Vault) SetRotationPolicy(policy RotationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if policy.IsZero() {
+		v.Meta.Rotation = nil
+		return nil
+	}
+	policy.Tags = maps.Clone(policy.Tags)
+	v.Meta.Rotation = &policy
+	return nil
+}
+
+// EntryRotation returns when the value of an entry is due for rotation and whether it is due
+// within warnDays of now; warnDays of 0 uses the warning window of the policy.
+func (v *Vault) EntryRotation(key string, now time.Time, warnDays int) (EntryRotation, error) {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return EntryRotation{}, err
+	}
+	metadata, err := v.openMetadata(key, entry)
+	if err != nil {
+		return EntryRotation{}, err
+	}
+
+	policy := v.Meta.RotationPolicy()
+	rotation := EntryRotation{
+		Key:        key,
+		UpdatedAt:  entry.UpdatedAt,
+		MaxAgeDays: policy.MaxAge(metadata),
+		Status:     RotationOK,
+	}
+
+	var due time.Time
+	if updatedAt, err := time.Parse(time.RFC3339, entry.UpdatedAt); err == nil &&
+		rotation.MaxAgeDays > 0 {
+		due = updatedAt.AddDate(0, 0, rotation.MaxAgeDays)
+	}
+	if expiresAt, err := time.Parse(time.RFC3339, metadata.ExpiresAt); err == nil &&
+		(due.IsZero() || expiresAt.Before(due)) {
+		due = expiresAt
+	}
+	if due.IsZero() {
+		return rotation, nil
+	}
+
+	if warnDays == 0 {
+		warnDays = policy.Warn()
+	}
+	rotation.DueAt = due.UTC().Format(time.RFC3339)
+	switch {
+	case !due.After(now):
+		rotation.Status = RotationExpired
+	case !due.After(now.AddDate(0, 0, warnDays)):
+		rotation.Status = RotationExpiring
+	}
+	return rotation, nil
+}
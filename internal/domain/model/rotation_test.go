@@ -0,0 +1,181 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// createRotationTestVault returns a vault whose testKey was last set on updatedAt
+func createRotationTestVault(t *testing.T, updatedAt string) *Vault {
+	t.Helper()
+	vault := createMetadataTestVault(t)
+	entry := vault.Entries[testKey]
+	entry.UpdatedAt = updatedAt
+	vault.Entries[testKey] = entry
+	return vault
+}
+
+func TestRotationPolicy_MaxAge(t *testing.T) {
+	policy := RotationPolicy{MaxAgeDays: 90, Tags: map[string]int{"payments": 30, "pci": 7}}
+	tests := []struct {
+		name     string
+		metadata EntryMetadata
+		want     int
+	}{
+		{name: "default", metadata: EntryMetadata{}, want: 90},
+		{name: "tag", metadata: EntryMetadata{Tags: []string{"payments"}}, want: 30},
+		{name: "shortest tag", metadata: EntryMetadata{Tags: []string{"payments", "pci"}}, want: 7},
+		{name: "untracked tag", metadata: EntryMetadata{Tags: []string{"db"}}, want: 90},
+		{
+			name:     "key",
+			metadata: EntryMetadata{Tags: []string{"pci"}, MaxAgeDays: 365},
+			want:     365,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := policy.MaxAge(tt.metadata); got != tt.want {
+			t.Errorf("%s: MaxAge() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEntryRotation(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		maxAgeDays int
+		updatedAt  string
+		metadata   EntryMetadata
+		warnDays   int
+		wantDue    string
+		want       RotationStatus
+	}{
+		{
+			name:      "no limit",
+			updatedAt: "2020-01-01T00:00:00Z",
+			want:      RotationOK,
+		},
+		{
+			name:       "overdue",
+			maxAgeDays: 90,
+			updatedAt:  "2026-02-01T12:00:00Z",
+			wantDue:    "2026-05-02T12:00:00Z",
+			want:       RotationExpired,
+		},
+		{
+			name:       "within warning window",
+			maxAgeDays: 90,
+			updatedAt:  "2026-03-10T12:00:00Z",
+			wantDue:    "2026-06-08T12:00:00Z",
+			want:       RotationExpiring,
+		},
+		{
+			name:       "wider window",
+			maxAgeDays: 90,
+			updatedAt:  "2026-04-01T12:00:00Z",
+			warnDays:   60,
+			wantDue:    "2026-06-30T12:00:00Z",
+			want:       RotationExpiring,
+		},
+		{
+			name:       "fresh",
+			maxAgeDays: 90,
+			updatedAt:  "2026-05-30T12:00:00Z",
+			wantDue:    "2026-08-28T12:00:00Z",
+			want:       RotationOK,
+		},
+		{
+			name:       "expiry before max age",
+			maxAgeDays: 90,
+			updatedAt:  "2026-05-30T12:00:00Z",
+			metadata:   EntryMetadata{ExpiresAt: "2026-05-31T00:00:00Z"},
+			wantDue:    "2026-05-31T00:00:00Z",
+			want:       RotationExpired,
+		},
+		{
+			name:       "own max age",
+			maxAgeDays: 90,
+			updatedAt:  "2026-05-30T12:00:00Z",
+			metadata:   EntryMetadata{MaxAgeDays: 1},
+			wantDue:    "2026-05-31T12:00:00Z",
+			want:       RotationExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := createRotationTestVault(t, tt.updatedAt)
+			vault.SetRotationPolicy(RotationPolicy{MaxAgeDays: tt.maxAgeDays})
+			vault.SetEntryMetadata(testKey, tt.metadata)
+
+			got, err := vault.EntryRotation(testKey, now, tt.warnDays)
+			if err != nil {
+				t.Fatalf("EntryRotation() failed: %v", err)
+			}
+			if got.DueAt != tt.wantDue || got.Status != tt.want {
+				t.Errorf("EntryRotation() = %+v, want due %q and status %q",
+					got, tt.wantDue, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotationPolicy_WithMaxAge(t *testing.T) {
+	policy := RotationPolicy{}.WithMaxAge("", 90).WithMaxAge("payments", 30)
+	if policy.MaxAgeDays != 90 || policy.Tags["payments"] != 30 {
+		t.Fatalf("expected default and tag max ages, got %+v", policy)
+	}
+
+	removed := policy.WithMaxAge("payments", 0)
+	if removed.Tags != nil {
+		t.Errorf("expected the tag max age to be removed, got %+v", removed)
+	}
+	if policy.Tags["payments"] != 30 {
+		t.Errorf("expected WithMaxAge() to leave the original policy alone, got %+v", policy)
+	}
+}
+
+func TestSetRotationPolicy(t *testing.T) {
+	vault := createTestVault(t)
+
+	invalid := []RotationPolicy{
+		{MaxAgeDays: -1},
+		{WarnDays: -1},
+		{Tags: map[string]int{"payments": 0}},
+		{Tags: map[string]int{"a b": 30}},
+	}
+	for _, policy := range invalid {
+		if err := vault.SetRotationPolicy(policy); err == nil {
+			t.Errorf("expected policy %+v to be rejected", policy)
+		}
+	}
+
+	if err := vault.SetRotationPolicy(RotationPolicy{MaxAgeDays: 90}); err != nil {
+		t.Fatalf("SetRotationPolicy() failed: %v", err)
+	}
+	if got := vault.Meta.RotationPolicy(); got.MaxAgeDays != 90 || got.Warn() != 14 {
+		t.Errorf("expected the policy to be stored in the header, got %+v", got)
+	}
+
+	if err := vault.SetRotationPolicy(RotationPolicy{}); err != nil {
+		t.Fatalf("SetRotationPolicy() failed: %v", err)
+	}
+	if vault.Meta.Rotation != nil {
+		t.Errorf("expected a zero policy to be removed, got %+v", vault.Meta.Rotation)
+	}
+}
+
+func TestSetRotationPolicy_Authenticated(t *testing.T) {
+	vault := createMetadataTestVault(t)
+	vault.SetRotationPolicy(RotationPolicy{MaxAgeDays: 30})
+	if err := vault.Seal(); err != nil {
+		t.Fatalf("Seal() failed: %v", err)
+	}
+
+	vault.Meta.Rotation.MaxAgeDays = 3650
+
+	if problems := integrityProblems(t, vault); len(problems) != 1 {
+		t.Errorf("expected a relaxed rotation policy to be detected, got %v", problems)
+	}
+}
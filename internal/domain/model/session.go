@@ -0,0 +1,39 @@
+package model
+
+import "errors"
+
+// ErrTampered is returned when an entry fails authentication, which means its ciphertext
+// was modified or moved from another key or environment.
+var ErrTampered = errors.New(
+	"entry failed authentication: it was modified or moved from another key or environment",
+)
+
+// ErrWrongPassphrase is returned when a passphrase does not open the data key wrapped for a
+// key slot, which means it is not the passphrase of that slot or the slot was modified.
+var ErrWrongPassphrase = errors.New("wrong passphrase or modified key slot")
+
+// Session seals and opens entry values with a vault key that is derived once
+// when the vault is unlocked and held until the session is closed.
+type Session interface {
+	// Encrypt encrypts the plaintext of an entry and returns base64-encoded ciphertext
+	Encrypt(key string, plaintext []byte) (string, error)
+	// Decrypt decrypts the base64-encoded ciphertext of an entry and returns plaintext
+	Decrypt(key, ciphertext string) ([]byte, error)
+	// SealRecord encrypts a record kept beside the entries, such as an audit event, under
+	// associated data of its own domain, which no entry key can produce
+	SealRecord(domain string, plaintext []byte) (string, error)
+	// OpenRecord decrypts a record of domain sealed with SealRecord and returns plaintext
+	OpenRecord(domain, record string) ([]byte, error)
+	// WrapKey seals the data key of the session under the key-encryption key derived from
+	// passphrase and the salt and KDF parameters in meta
+	WrapKey(meta Meta, passphrase string) (string, error)
+	// WrapKeyForRecipient seals the data key of the session for the holder of the private
+	// key that matches publicKey
+	WrapKeyForRecipient(meta Meta, publicKey string) (string, error)
+	// ExportKey returns a copy of the data key of the session, so that the agent can hold it
+	ExportKey() ([]byte, error)
+	// MAC returns a base64-encoded MAC of data under a key derived from the vault key
+	MAC(data []byte) (string, error)
+	// Close zeroes the derived key; the session cannot be used afterwards
+	Close()
+}
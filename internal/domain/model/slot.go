@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultSlotName names the key slot kept in the salt, fingerprint and wrapped key of the
+// vault meta. It is created with the vault and rotated by rotate-key.
+const DefaultSlotName = "default"
+
+// ErrOtherKeySlots is returned when a new data key is generated for a vault with passphrase
+// slots other than the one that unlocked it, since the key cannot be wrapped for them.
+var ErrOtherKeySlots = errors.New("other key slots would lose access to the vault")
+
+// KeySlot wraps the vault data key for one named passphrase.
+type KeySlot struct {
+	Name        string `json:"name"`
+	Salt        string `json:"salt"`
+	FingerPrint string `json:"fingerprint,omitempty"`
+	WrappedKey  string `json:"wrapped_key"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+// KeySlots returns every key slot of the vault, starting with the default slot.
+func (m Meta) KeySlots() []KeySlot {
+	slots := make([]KeySlot, 0, len(m.Slots)+1)
+	slots = append(slots, KeySlot{
+		Name:        DefaultSlotName,
+		Salt:        m.Salt,
+		FingerPrint: m.FingerPrint,
+		WrappedKey:  m.WrappedKey,
+	})
+	return append(slots, m.Slots...)
+}
+
+// ForSlot returns a copy of the meta whose salt, fingerprint and wrapped key are those of
+// slot, so the data key can be unwrapped or wrapped with the slot passphrase.
+func (m Meta) ForSlot(slot KeySlot) Meta {
+	m.Salt = slot.Salt
+	m.FingerPrint = slot.FingerPrint
+	m.WrappedKey = slot.WrappedKey
+	m.Slots = nil
+	return m
+}
+
+// UnlockedSlot returns the name of the key slot that unlocked the vault
+func (v *Vault) UnlockedSlot() string {
+	return v.unlockedSlot
+}
+
+// SetUnlockedSlot records the name of the key slot that unlocked the vault
+func (v *Vault) SetUnlockedSlot(name string) {
+	v.unlockedSlot = name
+}
+
+// AddSlot adds a named key slot to the vault
+func (v *Vault) AddSlot(slot KeySlot) error {
+	if v.Meta.FormatVersion < FormatVersionKeySlots {
+		return fmt.Errorf(
+			"vault format version %d does not support key slots",
+			v.Meta.FormatVersion,
+		)
+	}
+	if slot.Name == "" {
+		return errors.New("slot name cannot be empty")
+	}
+	if slot.Salt == "" || slot.WrappedKey == "" {
+		return fmt.Errorf("slot %q is incomplete", slot.Name)
+	}
+	if _, exists := v.findSlot(slot.Name); exists {
+		return fmt.Errorf("slot %q already exists", slot.Name)
+	}
+
+	if slot.CreatedAt == "" {
+		slot.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	v.Meta.Slots = append(v.Meta.Slots, slot)
+	return nil
+}
+
+// RewrapSlot replaces the salt and wrapped data key of the named key slot, which drops the
+// fingerprint of vaults older than FormatVersionKeyCheck
+func (v *Vault) RewrapSlot(name, salt, wrappedKey string) error {
+	i, exists := v.findSlot(name)
+	if !exists {
+		return fmt.Errorf("slot %q not found", name)
+	}
+	if i < 0 {
+		v.Meta.Salt, v.Meta.FingerPrint, v.Meta.WrappedKey = salt, "", wrappedKey
+		return nil
+	}
+	slot := &v.Meta.Slots[i]
+	slot.Salt, slot.FingerPrint, slot.WrappedKey = salt, "", wrappedKey
+	return nil
+}
+
+// OtherSlotNames returns the names of the passphrase slots besides the one that unlocked the
+// vault
+func (v *Vault) OtherSlotNames() []string {
+	var names []string
+	for _, slot := range v.Meta.KeySlots() {
+		if slot.Name != v.unlockedSlot {
+			names = append(names, slot.Name)
+		}
+	}
+	return names
+}
+
+// RemoveSlot removes a named key slot. The default slot cannot be removed.
+func (v *Vault) RemoveSlot(name string) error {
+	if name == "" {
+		return errors.New("slot name cannot be empty")
+	}
+	if name == DefaultSlotName {
+		return fmt.Errorf("slot %q cannot be removed, use rotate-key to change it", name)
+	}
+
+	i, exists := v.findSlot(name)
+	if !exists {
+		return fmt.Errorf("slot %q not found", name)
+	}
+	v.Meta.Slots = append(v.Meta.Slots[:i], v.Meta.Slots[i+1:]...)
+	if len(v.Meta.Slots) == 0 {
+		v.Meta.Slots = nil
+	}
+	return nil
+}
+
+// findSlot returns the index in Meta.Slots of the named slot; the default slot is not listed
+func (v *Vault) findSlot(name string) (int, bool) {
+	if name == DefaultSlotName {
+		return -1, true
+	}
+	for i, slot := range v.Meta.Slots {
+		if slot.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
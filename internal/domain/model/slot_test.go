@@ -0,0 +1,184 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func createTestSlot(name string) KeySlot {
+	return KeySlot{
+		Name:        name,
+		Salt:        name + "-salt",
+		FingerPrint: name + "-fingerprint",
+		WrappedKey:  name + "-wrapped-key",
+	}
+}
+
+func TestKeySlots_DefaultFirst(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.WrappedKey = "default-wrapped-key"
+	if err := vault.AddSlot(createTestSlot("ci")); err != nil {
+		t.Fatalf("AddSlot() returned unexpected error: %v", err)
+	}
+
+	slots := vault.Meta.KeySlots()
+	if len(slots) != 2 {
+		t.Fatalf("expected 2 slots, got %d", len(slots))
+	}
+	if slots[0].Name != DefaultSlotName || slots[0].Salt != testSalt ||
+		slots[0].WrappedKey != "default-wrapped-key" {
+		t.Errorf("expected the default slot from the meta, got %+v", slots[0])
+	}
+	if slots[1].Name != "ci" || slots[1].CreatedAt == "" {
+		t.Errorf("expected the ci slot with a creation time, got %+v", slots[1])
+	}
+}
+
+func TestForSlot(t *testing.T) {
+	vault := createTestVault(t)
+	slot := createTestSlot("ci")
+	if err := vault.AddSlot(slot); err != nil {
+		t.Fatalf("AddSlot() returned unexpected error: %v", err)
+	}
+
+	meta := vault.Meta.ForSlot(slot)
+	if meta.Salt != slot.Salt || meta.FingerPrint != slot.FingerPrint ||
+		meta.WrappedKey != slot.WrappedKey {
+		t.Errorf("expected the slot key material, got %+v", meta)
+	}
+	if meta.Env != testEnv || meta.Slots != nil {
+		t.Errorf("expected the vault env without slots, got %+v", meta)
+	}
+	if vault.Meta.Salt != testSalt {
+		t.Error("ForSlot() should not modify the vault meta")
+	}
+}
+
+func TestAddSlot_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		slot    KeySlot
+		wantErr string
+	}{
+		{"empty name", createTestSlot(""), "slot name cannot be empty"},
+		{"default name", createTestSlot(DefaultSlotName), "already exists"},
+		{"incomplete", KeySlot{Name: "ci"}, "incomplete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := createTestVault(t)
+			err := vault.AddSlot(tt.slot)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AddSlot() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddSlot_Duplicate(t *testing.T) {
+	vault := createTestVault(t)
+	if err := vault.AddSlot(createTestSlot("ci")); err != nil {
+		t.Fatalf("AddSlot() returned unexpected error: %v", err)
+	}
+	if err := vault.AddSlot(createTestSlot("ci")); err == nil {
+		t.Error("AddSlot() with a duplicate name expected error, got nil")
+	}
+}
+
+func TestAddSlot_LegacyFormat(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FormatVersion = FormatVersionEnvelope
+
+	if err := vault.AddSlot(createTestSlot("ci")); err == nil {
+		t.Error("AddSlot() on a vault without key slot support expected error, got nil")
+	}
+}
+
+func TestRemoveSlot(t *testing.T) {
+	vault := createTestVault(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := vault.AddSlot(createTestSlot(name)); err != nil {
+			t.Fatalf("AddSlot() returned unexpected error: %v", err)
+		}
+	}
+
+	if err := vault.RemoveSlot("alice"); err != nil {
+		t.Fatalf("RemoveSlot() returned unexpected error: %v", err)
+	}
+	if len(vault.Meta.Slots) != 1 || vault.Meta.Slots[0].Name != "bob" {
+		t.Errorf("expected only bob to remain, got %+v", vault.Meta.Slots)
+	}
+
+	if err := vault.RemoveSlot("alice"); err == nil {
+		t.Error("RemoveSlot() of a removed slot expected error, got nil")
+	}
+	if err := vault.RemoveSlot(DefaultSlotName); err == nil {
+		t.Error("RemoveSlot() of the default slot expected error, got nil")
+	}
+
+	if err := vault.RemoveSlot("bob"); err != nil {
+		t.Fatalf("RemoveSlot() returned unexpected error: %v", err)
+	}
+	if vault.Meta.Slots != nil {
+		t.Errorf("expected no slots, got %+v", vault.Meta.Slots)
+	}
+}
+
+func TestRewrapSlot(t *testing.T) {
+	vault := createTestVault(t)
+	vault.Meta.FingerPrint = "default-fingerprint"
+	if err := vault.AddSlot(createTestSlot("ci")); err != nil {
+		t.Fatalf("AddSlot() returned unexpected error: %v", err)
+	}
+
+	if err := vault.RewrapSlot("ci", "new-salt", "new-wrapped-key"); err != nil {
+		t.Fatalf("RewrapSlot() returned unexpected error: %v", err)
+	}
+	ci := vault.Meta.Slots[0]
+	if ci.Salt != "new-salt" || ci.WrappedKey != "new-wrapped-key" || ci.FingerPrint != "" {
+		t.Errorf("expected the ci slot to be rewrapped, got %+v", ci)
+	}
+	if vault.Meta.Salt != testSalt || vault.Meta.FingerPrint != "default-fingerprint" {
+		t.Errorf("expected the default slot to be kept, got %+v", vault.Meta)
+	}
+
+	if err := vault.RewrapSlot(DefaultSlotName, "default-salt", "default-key"); err != nil {
+		t.Fatalf("RewrapSlot() returned unexpected error: %v", err)
+	}
+	if vault.Meta.Salt != "default-salt" || vault.Meta.WrappedKey != "default-key" ||
+		vault.Meta.FingerPrint != "" {
+		t.Errorf("expected the default slot to be rewrapped, got %+v", vault.Meta)
+	}
+
+	if err := vault.RewrapSlot("unknown", "salt", "key"); err == nil {
+		t.Error("RewrapSlot() of an unknown slot should return an error")
+	}
+}
+
+func TestOtherSlotNames(t *testing.T) {
+	vault := createTestVault(t)
+	for _, name := range []string{"ci", "ops"} {
+		if err := vault.AddSlot(createTestSlot(name)); err != nil {
+			t.Fatalf("AddSlot() returned unexpected error: %v", err)
+		}
+	}
+
+	vault.SetUnlockedSlot("ci")
+	got := strings.Join(vault.OtherSlotNames(), ",")
+	if got != "default,ops" {
+		t.Errorf("OtherSlotNames() = %q, want %q", got, "default,ops")
+	}
+}
+
+func TestLock_ForgetsUnlockedSlot(t *testing.T) {
+	vault := createTestVault(t)
+	vault.SetSession(&fakeSession{})
+	vault.SetUnlockedSlot("ci")
+
+	vault.Lock()
+
+	if vault.UnlockedSlot() != "" {
+		t.Errorf("expected no unlocked slot after Lock(), got %q", vault.UnlockedSlot())
+	}
+}
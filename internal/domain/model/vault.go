@@ -3,24 +3,27 @@ package model
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Vault represents an encrypted vault containing entries for an environment.
 type Vault struct {
-	Meta       Meta             `json:"meta"`
-	Entries    map[string]Entry `json:"entries"`
-	path       string
-	passphrase string
+	Meta         Meta             `json:"meta"`
+	Entries      map[string]Entry `json:"entries"`
+	path         string
+	passphrase   string
+	session      Session
+	unlockedSlot string
+	fileDigest   string
+	release      func()
+	author       string
 }
 
 // NewVault creates a new vault instance
-func NewVault(env, fingerprint, salt string) (*Vault, error) {
-	if env == "" {
-		return nil, errors.New("environment cannot be empty")
-	}
-	if fingerprint == "" {
-		return nil, errors.New("fingerprint cannot be empty")
+func NewVault(env, salt string) (*Vault, error) {
+	if err := ValidateEnvName(env); err != nil {
+		return nil, err
 	}
 	if salt == "" {
 		return nil, errors.New("salt cannot be empty")
@@ -28,9 +31,9 @@ func NewVault(env, fingerprint, salt string) (*Vault, error) {
 
 	vault := &Vault{
 		Meta: Meta{
-			Env:         env,
-			Salt:        salt,
-			FingerPrint: fingerprint,
+			FormatVersion: CurrentFormatVersion,
+			Env:           env,
+			Salt:          salt,
 		},
 		Entries: make(map[string]Entry),
 	}
@@ -38,6 +41,17 @@ func NewVault(env, fingerprint, salt string) (*Vault, error) {
 	return vault, nil
 }
 
+// ValidateEnvName checks that env can name a vault file
+func ValidateEnvName(env string) error {
+	if env == "" {
+		return errors.New("environment cannot be empty")
+	}
+	if env == "." || env == ".." || strings.ContainsAny(env, `/\`) {
+		return fmt.Errorf("invalid environment name %q: it must not contain path separators", env)
+	}
+	return nil
+}
+
 // Path returns the vault file path
 func (v *Vault) Path() string {
 	return v.path
@@ -48,6 +62,21 @@ func (v *Vault) SetPath(path string) {
 	v.path = path
 }
 
+// FileDigest returns the digest of the stored vault file this vault was loaded from
+func (v *Vault) FileDigest() string {
+	return v.fileDigest
+}
+
+// SetFileDigest sets the digest of the stored vault file this vault was loaded from
+func (v *Vault) SetFileDigest(digest string) {
+	v.fileDigest = digest
+}
+
+// SetRelease sets the function that releases the vault file lock when the vault is locked
+func (v *Vault) SetRelease(release func()) {
+	v.release = release
+}
+
 // Passphrase returns the vault passphrase
 func (v *Vault) Passphrase() string {
 	return v.passphrase
@@ -58,6 +87,30 @@ func (v *Vault) SetPassphrase(passphrase string) {
 	v.passphrase = passphrase
 }
 
+// Session returns the session of an unlocked vault, or nil when the vault is locked
+func (v *Vault) Session() Session {
+	return v.session
+}
+
+// SetSession sets the session holding the derived vault key
+func (v *Vault) SetSession(session Session) {
+	v.session = session
+}
+
+// Lock closes the vault session, forgets the passphrase and releases the vault file lock
+func (v *Vault) Lock() {
+	if v.session != nil {
+		v.session.Close()
+		v.session = nil
+	}
+	v.passphrase = ""
+	v.unlockedSlot = ""
+	if v.release != nil {
+		v.release()
+		v.release = nil
+	}
+}
+
 // GetEntry retrieves an entry by key
 func (v *Vault) GetEntry(key string) (Entry, error) {
 	if key == "" {
@@ -79,19 +132,61 @@ func (v *Vault) SetEntry(key, encryptedValue string) error {
 		return errors.New("encrypted value cannot be empty")
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := time.Now().UTC()
 	entry, exists := v.Entries[key]
 
 	if exists {
+		entry = v.recordVersion(entry, now)
 		entry.Value = encryptedValue
-		entry.UpdatedAt = now
+		entry.UpdatedAt = now.Format(time.RFC3339)
 	} else {
 		entry = Entry{
 			Value:     encryptedValue,
-			CreatedAt: now,
-			UpdatedAt: now,
+			CreatedAt: now.Format(time.RFC3339),
+			UpdatedAt: now.Format(time.RFC3339),
+			Version:   1,
 		}
 	}
+	entry.UpdatedBy = v.author
+
+	if v.Entries == nil {
+		v.Entries = make(map[string]Entry)
+	}
+	v.Entries[key] = entry
+	return nil
+}
+
+// MarkSecret marks an entry as holding a secret value
+func (v *Vault) MarkSecret(key string) error {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return err
+	}
+	entry.Secret = true
+	v.Entries[key] = entry
+	return nil
+}
+
+// UnmarkSecret clears the secret marker of an entry
+func (v *Vault) UnmarkSecret(key string) error {
+	entry, err := v.GetEntry(key)
+	if err != nil {
+		return err
+	}
+	entry.Secret = false
+	v.Entries[key] = entry
+	return nil
+}
+
+// PutEntry stores an entry moved from another key or vault as is, keeping its timestamps
+// and markers. Its value must already be encrypted for key.
+func (v *Vault) PutEntry(key string, entry Entry) error {
+	if key == "" {
+		return errors.New("key cannot be empty")
+	}
+	if entry.Value == "" {
+		return errors.New("encrypted value cannot be empty")
+	}
 
 	if v.Entries == nil {
 		v.Entries = make(map[string]Entry)
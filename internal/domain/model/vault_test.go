@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
@@ -19,7 +20,7 @@ const (
 
 func createTestVault(t *testing.T) *Vault {
 	t.Helper()
-	vault, err := NewVault(testEnv, testFingerprint, testSalt)
+	vault, err := NewVault(testEnv, testSalt)
 	if err != nil {
 		t.Fatalf("failed to create test vault: %v", err)
 	}
@@ -36,8 +37,8 @@ func TestNewVault(t *testing.T) {
 	if vault.Meta.Env != testEnv {
 		t.Errorf("expected env %q, got %q", testEnv, vault.Meta.Env)
 	}
-	if vault.Meta.FingerPrint != testFingerprint {
-		t.Errorf("expected fingerprint %q, got %q", testFingerprint, vault.Meta.FingerPrint)
+	if vault.Meta.FingerPrint != "" {
+		t.Errorf("expected no fingerprint, got %q", vault.Meta.FingerPrint)
 	}
 	if vault.Meta.Salt != testSalt {
 		t.Errorf("expected salt %q, got %q", testSalt, vault.Meta.Salt)
@@ -140,6 +141,51 @@ func TestGetEntryWithEmptyKey(t *testing.T) {
 	}
 }
 
+func TestMarkSecret(t *testing.T) {
+	vault := createTestVault(t)
+	vault.SetEntry(testKey, testValue)
+
+	if err := vault.MarkSecret(testKey); err != nil {
+		t.Fatalf("MarkSecret() returned unexpected error: %v", err)
+	}
+	if !vault.Entries[testKey].Secret {
+		t.Error("expected entry to be marked as secret")
+	}
+
+	vault.SetEntry(testKey, "new-value")
+	if !vault.Entries[testKey].Secret {
+		t.Error("expected updating the value to keep the secret marker")
+	}
+
+	if err := vault.MarkSecret("non_existent_key"); err == nil {
+		t.Error("expected error for a missing key, got nil")
+	}
+}
+
+func TestPutEntry(t *testing.T) {
+	vault := createTestVault(t)
+	entry := Entry{
+		Value:     testValue,
+		CreatedAt: "2026-01-01T00:00:00Z",
+		UpdatedAt: "2026-02-01T00:00:00Z",
+		Secret:    true,
+	}
+
+	if err := vault.PutEntry(testKey, entry); err != nil {
+		t.Fatalf("PutEntry() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(vault.Entries[testKey], entry) {
+		t.Errorf("expected entry %+v to be stored as is, got %+v", entry, vault.Entries[testKey])
+	}
+
+	if err := vault.PutEntry("", entry); err == nil {
+		t.Error("expected error for an empty key, got nil")
+	}
+	if err := vault.PutEntry(testKey, Entry{}); err == nil {
+		t.Error("expected error for an empty value, got nil")
+	}
+}
+
 func TestDeleteEntry(t *testing.T) {
 	vault := createTestVault(t)
 
@@ -262,38 +308,34 @@ func TestSetPath(t *testing.T) {
 
 func TestNewVault_ValidationErrors(t *testing.T) {
 	tests := []struct {
-		name        string
-		env         string
-		fingerprint string
-		salt        string
-		wantErr     string
+		name    string
+		env     string
+		salt    string
+		wantErr string
 	}{
 		{
-			name:        "empty env",
-			env:         "",
-			fingerprint: "test",
-			salt:        "test",
-			wantErr:     "environment cannot be empty",
+			name:    "empty env",
+			env:     "",
+			salt:    "test",
+			wantErr: "environment cannot be empty",
 		},
 		{
-			name:        "empty fingerprint",
-			env:         "test",
-			fingerprint: "",
-			salt:        "test",
-			wantErr:     "fingerprint cannot be empty",
+			name:    "env with path separator",
+			env:     "../prod",
+			salt:    "test",
+			wantErr: "invalid environment name",
 		},
 		{
-			name:        "empty salt",
-			env:         "test",
-			fingerprint: "test",
-			salt:        "",
-			wantErr:     "salt cannot be empty",
+			name:    "empty salt",
+			env:     "test",
+			salt:    "",
+			wantErr: "salt cannot be empty",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := NewVault(tt.env, tt.fingerprint, tt.salt)
+			_, err := NewVault(tt.env, tt.salt)
 			if err == nil {
 				t.Fatal("expected error, got nil")
 			}
@@ -303,3 +345,87 @@ func TestNewVault_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+type fakeSession struct {
+	closed bool
+}
+
+func (s *fakeSession) Encrypt(key string, plaintext []byte) (string, error) {
+	return string(plaintext), nil
+}
+
+func (s *fakeSession) Decrypt(key, ciphertext string) ([]byte, error) {
+	return []byte(ciphertext), nil
+}
+
+func (s *fakeSession) SealRecord(domain string, plaintext []byte) (string, error) {
+	return domain + ":" + string(plaintext), nil
+}
+
+func (s *fakeSession) OpenRecord(domain, record string) ([]byte, error) {
+	plaintext, ok := strings.CutPrefix(record, domain+":")
+	if !ok {
+		return nil, ErrTampered
+	}
+	return []byte(plaintext), nil
+}
+
+func (s *fakeSession) WrapKey(meta Meta, passphrase string) (string, error) {
+	return "wrapped", nil
+}
+
+func (s *fakeSession) WrapKeyForRecipient(meta Meta, publicKey string) (string, error) {
+	return "wrapped-for-" + publicKey, nil
+}
+
+func (s *fakeSession) ExportKey() ([]byte, error) {
+	return []byte("data-key"), nil
+}
+
+func (s *fakeSession) MAC(data []byte) (string, error) {
+	return fmt.Sprintf("mac(%s)", data), nil
+}
+
+func (s *fakeSession) Close() { s.closed = true }
+
+func TestLock(t *testing.T) {
+	vault := createTestVault(t)
+	session := &fakeSession{}
+	vault.SetPassphrase(testPassphrase)
+	vault.SetSession(session)
+
+	vault.Lock()
+
+	if !session.closed {
+		t.Error("expected Lock() to close the session")
+	}
+	if vault.Session() != nil {
+		t.Error("expected session to be nil after Lock()")
+	}
+	if vault.Passphrase() != "" {
+		t.Errorf("expected passphrase to be cleared, got %q", vault.Passphrase())
+	}
+}
+
+func TestLockWithoutSession(t *testing.T) {
+	vault := createTestVault(t)
+
+	vault.Lock()
+
+	if vault.Session() != nil {
+		t.Error("expected session to stay nil after Lock()")
+	}
+}
+
+func TestLockReleasesFileLock(t *testing.T) {
+	vault := createTestVault(t)
+	released := 0
+	vault.SetRelease(func() { released++ })
+
+	vault.Lock()
+	vault.Lock()
+
+	if released != 1 {
+		t.Errorf("expected Lock() to release the file lock once, released %d times", released)
+	}
+}
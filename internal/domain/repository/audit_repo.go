@@ -0,0 +1,15 @@
+package repository
+
+import "context"
+
+// AuditRepository stores the audit log of each environment as a list of sealed records
+type AuditRepository interface {
+	// Load returns the records of the audit log of an environment, oldest first, or none
+	// when it has no audit log
+	Load(ctx context.Context, env string) ([]string, error)
+	// Append locks the audit log of an environment, passes its newest record ("" when it is
+	// empty) and number of records to seal, and appends the record seal returns
+	Append(ctx context.Context, env string, seal func(last string, count int) (string, error)) error
+	// Replace replaces all records of the audit log of an environment; no records remove it
+	Replace(ctx context.Context, env string, records []string) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+// IdentityRepository provides operations for managing the local identity
+type IdentityRepository interface {
+	// Load loads the local identity, returning model.ErrNoIdentity when there is none
+	Load(ctx context.Context) (model.Identity, error)
+	// Save stores the local identity and returns its path; an existing identity is only
+	// replaced when overwrite is set
+	Save(ctx context.Context, identity model.Identity, overwrite bool) (string, error)
+}
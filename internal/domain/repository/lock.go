@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrVaultChanged is returned when a vault file was modified by another process between
+// loading and saving it
+var ErrVaultChanged = errors.New("vault file changed on disk since it was loaded")
+
+type lockTimeoutKey struct{}
+
+// WithLockTimeout returns a context that bounds how long repositories wait for a file lock
+func WithLockTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, lockTimeoutKey{}, timeout)
+}
+
+// LockTimeout returns the lock timeout stored in ctx, if any
+func LockTimeout(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(lockTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
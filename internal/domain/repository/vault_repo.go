@@ -12,8 +12,23 @@ type VaultRepository interface {
 	Create(ctx context.Context, vault *model.Vault) error
 	// Load loads a vault from the storage
 	Load(ctx context.Context, env string) (*model.Vault, error)
-	// Save saves a vault to the storage
+	// Save saves a vault to the storage, failing with ErrVaultChanged when the stored vault
+	// is no longer the one it was loaded from
 	Save(ctx context.Context, vault *model.Vault) error
 	// Exists checks if a vault exists for an environment
 	Exists(ctx context.Context, env string) (bool, error)
+	// List returns the environments that have a vault
+	List(ctx context.Context) ([]string, error)
+	// Backup copies the current vault file of an environment into the newest backup
+	// generation and returns its path
+	Backup(ctx context.Context, env string) (string, error)
+	// Restore replaces the vault of an environment with one of its backup generations,
+	// 1 being the newest, and returns the path it was restored from
+	Restore(ctx context.Context, env string, generation int) (string, error)
+	// Delete removes the vault of an environment after copying it into the newest backup
+	// generation, whose path it returns. Callers must hold the exclusive lock of the vault.
+	Delete(ctx context.Context, env string) (string, error)
+	// Lock takes a shared or exclusive lock on the vault of an environment, waiting at most
+	// the lock timeout of ctx, and returns the function that releases it
+	Lock(ctx context.Context, env string, exclusive bool) (func(), error)
 }
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+)
+
+// AuditLog defines the interface through which use cases record what they do with vaults.
+type AuditLog interface {
+	// Record appends an event for an operation on keys of an unlocked vault to the audit
+	// log of its environment.
+	Record(
+		ctx context.Context,
+		vault *model.Vault,
+		operation model.AuditOperation,
+		keys ...string,
+	) error
+	// Events returns the events of the audit log of an unlocked vault. When the chain is
+	// broken it returns the events before the break with a *model.AuditChainError.
+	Events(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error)
+	// Rewrite replaces the audit log of the environment of an unlocked vault with events,
+	// chained and sealed with its current key. It is used when a vault gets a new key or
+	// environment name; no events start an empty log.
+	Rewrite(ctx context.Context, vault *model.Vault, events []model.AuditEvent) error
+}
+
+// AuditService implements AuditLog on top of an audit repository, sealing every record with
+// the vault key.
+type AuditService struct {
+	auditRepo     repository.AuditRepository
+	authorService AuthorService
+}
+
+// NewAuditService creates a new AuditService instance.
+func NewAuditService(
+	auditRepo repository.AuditRepository,
+	authorService AuthorService,
+) *AuditService {
+	return &AuditService{auditRepo, authorService}
+}
+
+// Record appends an event with the current time, actor, host and unlocked slot, chained to
+// the newest record of the log.
+func (as *AuditService) Record(
+	ctx context.Context,
+	vault *model.Vault,
+	operation model.AuditOperation,
+	keys ...string,
+) error {
+	session := vault.Session()
+	if session == nil {
+		return fmt.Errorf("cannot record %s: vault is locked", operation)
+	}
+
+	keys = slices.Clone(keys)
+	slices.Sort(keys)
+	event := model.AuditEvent{
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Operation: operation,
+		Keys:      keys,
+		Actor:     as.authorService.Author(),
+		Host:      as.authorService.Host(),
+		Slot:      vault.UnlockedSlot(),
+	}
+
+	err := as.auditRepo.Append(ctx, vault.Meta.Env, func(last string, count int) (string, error) {
+		event.Seq = count + 1
+		event.PrevHash = model.AuditRecordHash(last)
+		return model.SealAuditEvent(session, vault.Meta.FormatVersion, event)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s in audit log: %w", operation, err)
+	}
+	return nil
+}
+
+// Events decrypts the audit log of the environment of vault and verifies its chain.
+func (as *AuditService) Events(
+	ctx context.Context,
+	vault *model.Vault,
+) ([]model.AuditEvent, error) {
+	session := vault.Session()
+	if session == nil {
+		return nil, fmt.Errorf("cannot read audit log: vault is locked")
+	}
+
+	records, err := as.auditRepo.Load(ctx, vault.Meta.Env)
+	if err != nil {
+		return nil, err
+	}
+	return model.OpenAuditLog(session, vault.Meta.FormatVersion, records)
+}
+
+// Rewrite seals events again with the current key of vault and replaces its audit log.
+func (as *AuditService) Rewrite(
+	ctx context.Context,
+	vault *model.Vault,
+	events []model.AuditEvent,
+) error {
+	var records []string
+	if len(events) > 0 {
+		session := vault.Session()
+		if session == nil {
+			return fmt.Errorf("cannot rewrite audit log: vault is locked")
+		}
+
+		var err error
+		records, err = model.ChainAuditEvents(session, vault.Meta.FormatVersion, events)
+		if err != nil {
+			return fmt.Errorf("failed to seal audit log: %w", err)
+		}
+	}
+
+	if err := as.auditRepo.Replace(ctx, vault.Meta.Env, records); err != nil {
+		return fmt.Errorf("failed to rewrite audit log: %w", err)
+	}
+	return nil
+}
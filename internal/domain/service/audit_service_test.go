@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+)
+
+func createAuditTestVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, "test-salt")
+	vault.SetSession(&test.MockSession{
+		SealRecordFunc: func(domain string, plaintext []byte) (string, error) {
+			return string(plaintext), nil
+		},
+		OpenRecordFunc: func(domain, record string) ([]byte, error) {
+			return []byte(record), nil
+		},
+	})
+	vault.SetUnlockedSlot("ci")
+	return vault
+}
+
+func TestAuditRecord_ChainsEvents(t *testing.T) {
+	repo := &test.MockAuditRepository{}
+	auditLog := NewAuditService(repo, &test.MockAuthorService{})
+	vault := createAuditTestVault("prod")
+	ctx := context.Background()
+
+	if err := auditLog.Record(ctx, vault, model.AuditSet, "B", "A"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+	if err := auditLog.Record(ctx, vault, model.AuditGet, "A"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	events, err := auditLog.Events(ctx, vault)
+	if err != nil {
+		t.Fatalf("Events() failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	first := events[0]
+	if first.Operation != model.AuditSet || !slices.Equal(first.Keys, []string{"A", "B"}) {
+		t.Errorf("expected set of sorted keys A, B, got %s of %v", first.Operation, first.Keys)
+	}
+	if first.Actor != "test-author" || first.Host != "test-host" || first.Slot != "ci" {
+		t.Errorf("expected actor, host and slot to be recorded, got %+v", first)
+	}
+	if events[1].PrevHash != model.AuditRecordHash(repo.Records["prod"][0]) {
+		t.Error("expected the second event to be chained to the first")
+	}
+}
+
+func TestAuditRecord_LockedVault(t *testing.T) {
+	auditLog := NewAuditService(&test.MockAuditRepository{}, &test.MockAuthorService{})
+	vault, _ := model.NewVault("prod", "test-salt")
+
+	err := auditLog.Record(context.Background(), vault, model.AuditGet, "A")
+	if err == nil || !strings.Contains(err.Error(), "vault is locked") {
+		t.Errorf("expected a locked vault error, got %v", err)
+	}
+}
+
+func TestAuditRecord_RepositoryError(t *testing.T) {
+	repo := &test.MockAuditRepository{
+		AppendFunc: func(ctx context.Context, env string, record string) error {
+			return errors.New("disk full")
+		},
+	}
+	auditLog := NewAuditService(repo, &test.MockAuthorService{})
+
+	err := auditLog.Record(context.Background(), createAuditTestVault("prod"), model.AuditExport)
+	if err == nil || !strings.Contains(err.Error(), "failed to record export in audit log") {
+		t.Errorf("expected a record error, got %v", err)
+	}
+}
+
+func TestAuditEvents_BrokenChain(t *testing.T) {
+	repo := &test.MockAuditRepository{}
+	auditLog := NewAuditService(repo, &test.MockAuthorService{})
+	vault := createAuditTestVault("prod")
+	ctx := context.Background()
+	for _, key := range []string{"A", "B", "C"} {
+		auditLog.Record(ctx, vault, model.AuditGet, key)
+	}
+	repo.Records["prod"] = slices.Delete(repo.Records["prod"], 1, 2)
+
+	events, err := auditLog.Events(ctx, vault)
+	var chainErr *model.AuditChainError
+	if !errors.As(err, &chainErr) || chainErr.Seq != 2 {
+		t.Fatalf("expected the chain to break at record 2, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the event before the break, got %d events", len(events))
+	}
+}
+
+func TestAuditRewrite(t *testing.T) {
+	repo := &test.MockAuditRepository{}
+	auditLog := NewAuditService(repo, &test.MockAuthorService{})
+	vault := createAuditTestVault("staging")
+	ctx := context.Background()
+
+	events := []model.AuditEvent{
+		{Seq: 4, Operation: model.AuditOpen},
+		{Seq: 5, Operation: model.AuditRotate},
+	}
+	if err := auditLog.Rewrite(ctx, vault, events); err != nil {
+		t.Fatalf("Rewrite() failed: %v", err)
+	}
+	rewritten, err := auditLog.Events(ctx, vault)
+	if err != nil {
+		t.Fatalf("Events() failed after Rewrite(): %v", err)
+	}
+	if len(rewritten) != 2 || rewritten[0].Seq != 1 || rewritten[1].Operation != model.AuditRotate {
+		t.Errorf("expected the events to be renumbered and kept in order, got %+v", rewritten)
+	}
+
+	locked, _ := model.NewVault("staging", "test-salt")
+	if err := auditLog.Rewrite(ctx, locked, nil); err != nil {
+		t.Fatalf("Rewrite() without events should not need a session: %v", err)
+	}
+	if len(repo.Records["staging"]) != 0 {
+		t.Errorf("expected an empty audit log, got %d records", len(repo.Records["staging"]))
+	}
+}
@@ -0,0 +1,9 @@
+package service
+
+// AuthorService defines the interface for identifying who changes vault entries and where.
+type AuthorService interface {
+	// Author returns the name recorded with changed entries, or "" when it is unknown.
+	Author() string
+	// Host returns the name of the machine lockify runs on, or "" when it is unknown.
+	Host() string
+}
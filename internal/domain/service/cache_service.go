@@ -1,10 +1,18 @@
 package service
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned when the cache holds no value for a key, or its value expired.
+var ErrCacheMiss = errors.New("no cached value")
+
 // Cache defines the interface for caching operations.
 type Cache interface {
-	// Set stores a value in cache
-	Set(key, value string) error
-	// Get retrieves a value from cache
+	// Set stores a value in cache for ttl, or until it is deleted when ttl is 0
+	Set(key, value string, ttl time.Duration) error
+	// Get retrieves a value from cache, or ErrCacheMiss when there is none
 	Get(key string) (string, error)
 	// Delete removes a value from cache
 	Delete(key string) error
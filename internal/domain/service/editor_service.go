@@ -0,0 +1,9 @@
+package service
+
+// EditorService defines the interface for letting the user edit content in an editor.
+type EditorService interface {
+	// Edit opens content in the user's editor and returns the edited content. name is a file
+	// name hint, so that the editor can pick the right syntax. No copy of the content may
+	// remain on disk once Edit returns.
+	Edit(name string, content []byte) ([]byte, error)
+}
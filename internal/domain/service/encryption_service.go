@@ -1,9 +1,30 @@
 package service
 
+import "github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+
 // EncryptionService provides encryption and decryption operations for vault entries
 type EncryptionService interface {
-	// Encrypt encrypts plaintext and returns base64-encoded ciphertext
-	Encrypt(plaintext []byte, encodedSalt, passphrase string) (string, error)
-	// Decrypt decrypts base64-encoded ciphertext and returns plaintext
-	Decrypt(ciphertext, encodedSalt, passphrase string) ([]byte, error)
+	// NewSession derives the vault key once, using the cipher and KDF parameters
+	// recorded in the vault meta, and returns a session that encrypts and decrypts
+	// entries with it until it is closed
+	NewSession(meta model.Meta, passphrase string) (model.Session, error)
+	// NewDataKey generates a random data key and returns a session bound to it
+	NewDataKey(meta model.Meta) (model.Session, error)
+	// NewKeySession returns a session bound to a data key exported by another session
+	NewKeySession(meta model.Meta, key []byte) (model.Session, error)
+	// NewRecipientSession unwraps the data key of a recipient with the private key of
+	// identity and returns a session bound to it
+	NewRecipientSession(
+		meta model.Meta,
+		recipient model.Recipient,
+		identity model.Identity,
+	) (model.Session, error)
+	// GenerateIdentity creates a new key pair for unlocking vaults without a passphrase
+	GenerateIdentity() (model.Identity, error)
+	// IdentityPublicKey derives the public key of an identity from its private key
+	IdentityPublicKey(privateKey string) (string, error)
+	// NewSalt generates a random salt for deriving the key-encryption key of a passphrase
+	NewSalt() (string, error)
+	// DefaultParams returns the cipher and KDF parameters stamped on new vaults
+	DefaultParams() (model.CipherParams, model.KDFParams)
 }
@@ -1,8 +1,9 @@
 package service
 
 // HashService provides cryptographic utility operations:
-// - Passphrase hashing and verification (for fingerprints)
-// - Salt generation
+//   - Passphrase hashing and verification (for the fingerprints of vaults older than
+//     model.FormatVersionEnvelope; newer vaults check passphrases against their wrapped key)
+//   - Salt generation
 type HashService interface {
 	// Hash creates a hash of the passphrase (for fingerprinting)
 	Hash(passphrase string) (string, error)
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+// ErrAgentNotRunning is returned when no lockify agent listens on the agent socket.
+var ErrAgentNotRunning = errors.New("no lockify agent is running")
+
+// KeyAgent defines the interface for talking to a running lockify agent, which holds
+// unlocked vault keys in memory so that commands do not ask for passphrases again.
+type KeyAgent interface {
+	// Key returns the key held for the vault with id, or model.ErrAgentNoKey
+	Key(ctx context.Context, id string) (model.AgentKey, error)
+	// Add hands an unlocked vault key to the agent
+	Add(ctx context.Context, key model.AgentKey) error
+	// Forget drops the keys held for env and returns how many were dropped
+	Forget(ctx context.Context, env string) (int, error)
+	// Lock drops every key and returns how many were dropped
+	Lock(ctx context.Context) (int, error)
+	// Status describes the agent and the keys it holds
+	Status(ctx context.Context) (model.AgentStatus, error)
+}
+
+// AgentServer defines the interface for running the lockify agent itself.
+type AgentServer interface {
+	// Serve holds keys under policy until ctx is done; ready is called once the agent
+	// listens. Every key is wiped when it returns.
+	Serve(ctx context.Context, policy model.AgentPolicy, ready func(socket string)) error
+}
@@ -0,0 +1,11 @@
+package service
+
+import "context"
+
+// PassphrasePolicy decides whether a passphrase is strong enough to protect a vault.
+type PassphrasePolicy interface {
+	// Check returns a *model.WeakPassphraseError telling why the passphrase is rejected for
+	// env, nil when it is accepted, or another error when the policy cannot be applied
+	// because it is misconfigured
+	Check(ctx context.Context, env, passphrase string) error
+}
@@ -10,10 +10,14 @@ import (
 type PassphraseService interface {
 	// Get retrieves a passphrase from environment variable, cache, or user input
 	Get(ctx context.Context, env string) (string, error)
+	// GetNew retrieves the passphrase of a new vault like Get, asking for it twice at the
+	// prompt, and checks it against the passphrase policy of env
+	GetNew(ctx context.Context, env string) (string, error)
 	// Clear clears a cached passphrase for an environment
 	Clear(ctx context.Context, env string) error
 	// ClearAll clears all cached passphrases
 	ClearAll(ctx context.Context) error
-	// Validate validates a passphrase against a vault's fingerprint
-	Validate(ctx context.Context, vault *model.Vault, passphrase string) error
+	// Validate validates a passphrase against the key slots of a vault and returns the
+	// slot it unlocks
+	Validate(ctx context.Context, vault *model.Vault, passphrase string) (model.KeySlot, error)
 }
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoPassphrase is returned by a passphrase source that has no passphrase for an
+// environment, so the next source is tried.
+var ErrNoPassphrase = errors.New("no passphrase found")
+
+// PassphraseSource is one place the passphrase of a vault can come from, such as an
+// environment variable, a file or the OS keyring.
+type PassphraseSource interface {
+	// Name identifies the source in a configured order, such as "env" or "keyring".
+	Name() string
+	// Passphrase returns the passphrase of env, or ErrNoPassphrase when the source has none.
+	Passphrase(ctx context.Context, env string) (string, error)
+}
+
+// PassphraseOptions configures the passphrase sources of a single command.
+type PassphraseOptions struct {
+	// File is the path of a file holding the passphrase; {env} is replaced by the
+	// environment name.
+	File string
+	// FD is a file descriptor to read the passphrase from, or -1 for none.
+	FD int
+	// Command is a shell command printing the passphrase; it finds the environment name in
+	// $LOCKIFY_ENV.
+	Command string
+	// Sources lists the names of the sources to try, in order; empty uses the configured
+	// order.
+	Sources []string
+	// CacheTTL is how long a prompted passphrase stays cached, 0 for no expiry; nil uses the
+	// per-env or configured TTL.
+	CacheTTL *time.Duration
+	// Confirm asks for a prompted passphrase twice, for passphrases that protect a new vault.
+	Confirm bool
+}
+
+type passphraseOptionsKey struct{}
+
+// WithPassphraseOptions returns a context that configures the passphrase sources
+func WithPassphraseOptions(ctx context.Context, options PassphraseOptions) context.Context {
+	return context.WithValue(ctx, passphraseOptionsKey{}, options)
+}
+
+// PassphraseOptionsFrom returns the passphrase options stored in ctx; without any, no file,
+// file descriptor or command is set.
+func PassphraseOptionsFrom(ctx context.Context) PassphraseOptions {
+	options, ok := ctx.Value(passphraseOptionsKey{}).(PassphraseOptions)
+	if !ok {
+		return PassphraseOptions{FD: -1}
+	}
+	return options
+}
@@ -0,0 +1,10 @@
+package service
+
+import "context"
+
+// ProcessRunner defines the interface for running child processes.
+type ProcessRunner interface {
+	// Run runs name with args and env, connected to the terminal of lockify, forwarding the
+	// signals lockify receives. It returns the exit code of the process.
+	Run(ctx context.Context, name string, args, env []string) (int, error)
+}
@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
-	"github.com/ahmed-abdelgawad92/lockify/internal/config"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
 )
@@ -12,24 +12,47 @@ import (
 // VaultServiceInterface defines the interface for vault operations.
 type VaultServiceInterface interface {
 	Open(ctx context.Context, env string) (*model.Vault, error)
+	OpenUnverified(ctx context.Context, env string) (*model.Vault, error)
+	OpenForUpdate(ctx context.Context, env string) (*model.Vault, error)
 	Save(ctx context.Context, vault *model.Vault) error
 	Create(ctx context.Context, env string) (*model.Vault, error)
+	New(ctx context.Context, env, passphrase string) (*model.Vault, error)
+	SaveNew(ctx context.Context, vault *model.Vault) error
 }
 
 // VaultService implements vault operations including create, open, and save.
 type VaultService struct {
 	vaultRepo         repository.VaultRepository
 	passphraseService PassphraseService
-	hashService       HashService
+	encryptionService EncryptionService
+	identityRepo      repository.IdentityRepository
+	authorService     AuthorService
+	keyAgent          KeyAgent
+	upgrader          VaultUpgrader
+	auditLog          AuditLog
 }
 
 // NewVaultService creates a new VaultService instance.
 func NewVaultService(
 	vaultRepo repository.VaultRepository,
 	passphraseService PassphraseService,
-	hashService HashService,
+	encryptionService EncryptionService,
+	identityRepo repository.IdentityRepository,
+	authorService AuthorService,
+	keyAgent KeyAgent,
+	upgrader VaultUpgrader,
+	auditLog AuditLog,
 ) *VaultService {
-	return &VaultService{vaultRepo, passphraseService, hashService}
+	return &VaultService{
+		vaultRepo,
+		passphraseService,
+		encryptionService,
+		identityRepo,
+		authorService,
+		keyAgent,
+		upgrader,
+		auditLog,
+	}
 }
 
 // Create creates a new vault for the specified environment.
@@ -42,62 +65,341 @@ func (vs *VaultService) Create(ctx context.Context, env string) (*model.Vault, e
 		return nil, fmt.Errorf("vault already exists for environment %q", env)
 	}
 
-	passphrase, err := vs.passphraseService.Get(ctx, env)
+	passphrase, err := vs.passphraseService.GetNew(ctx, env)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get passphrase: %w", err)
 	}
 
-	fingerprint, err := vs.hashService.Hash(passphrase)
+	vault, err := vs.New(ctx, env, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash passphrase: %w", err)
+		return nil, err
+	}
+	defer vault.Lock()
+
+	if err := vs.SaveNew(ctx, vault); err != nil {
+		return nil, err
 	}
 
-	salt, err := vs.hashService.GenerateSalt(config.DefaultSaltSize)
+	return vault, nil
+}
+
+// New returns an unlocked vault for env with a fresh data key wrapped by passphrase. The
+// vault is not stored until it is passed to SaveNew; callers must Lock it when done.
+func (vs *VaultService) New(ctx context.Context, env, passphrase string) (*model.Vault, error) {
+	salt, err := vs.encryptionService.NewSalt()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate salt: %w", err)
 	}
 
-	vault, err := model.NewVault(env, fingerprint, salt)
+	vault, err := model.NewVault(env, salt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault: %w", err)
 	}
+	vault.Meta.Cipher, vault.Meta.KDF = vs.encryptionService.DefaultParams()
 
-	if err := vs.vaultRepo.Create(ctx, vault); err != nil {
-		return nil, fmt.Errorf("failed to save vault: %w", err)
+	session, err := vs.encryptionService.NewDataKey(vault.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	vault.SetSession(session)
+
+	vault.Meta.WrappedKey, err = session.WrapKey(vault.Meta, passphrase)
+	if err != nil {
+		vault.Lock()
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
 	}
+	vault.SetPassphrase(passphrase)
+	vault.SetUnlockedSlot(model.DefaultSlotName)
+	vault.SetAuthor(vs.authorService.Author())
 
 	return vault, nil
 }
 
-// Open opens an existing vault for the specified environment.
+// SaveNew seals a vault returned by New and stores it, failing if the environment already
+// has a vault.
+func (vs *VaultService) SaveNew(ctx context.Context, vault *model.Vault) error {
+	if err := vault.Seal(); err != nil {
+		return fmt.Errorf("failed to seal vault: %w", err)
+	}
+	if err := vs.vaultRepo.Create(ctx, vault); err != nil {
+		return fmt.Errorf("failed to save vault: %w", err)
+	}
+	return nil
+}
+
+// Open opens an existing vault for the specified environment, unlocks it and verifies
+// its integrity. The vault key is derived once here; callers must Lock the vault when done.
+// The vault file stays share-locked until then, so no other process can change it meanwhile.
 func (vs *VaultService) Open(ctx context.Context, env string) (*model.Vault, error) {
+	return vs.open(ctx, env, false)
+}
+
+// OpenForUpdate opens a vault like Open, but holds an exclusive lock on the vault file
+// until the vault is locked, so that other processes wait for the changes to be saved.
+func (vs *VaultService) OpenForUpdate(ctx context.Context, env string) (*model.Vault, error) {
+	return vs.open(ctx, env, true)
+}
+
+func (vs *VaultService) open(
+	ctx context.Context,
+	env string,
+	exclusive bool,
+) (*model.Vault, error) {
+	vault, err := vs.openUnverified(ctx, env, exclusive)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vault.VerifyIntegrity(); err != nil {
+		vault.Lock()
+		return nil, err
+	}
+
+	return vault, nil
+}
+
+// OpenUnverified opens and unlocks a vault without checking its integrity MAC.
+// It is meant for commands that report integrity problems themselves. A vault that lists
+// the local identity as a recipient is unlocked with it instead of prompting.
+func (vs *VaultService) OpenUnverified(ctx context.Context, env string) (*model.Vault, error) {
+	return vs.openUnverified(ctx, env, false)
+}
+
+func (vs *VaultService) openUnverified(
+	ctx context.Context,
+	env string,
+	exclusive bool,
+) (*model.Vault, error) {
 	if exists, err := vs.vaultRepo.Exists(ctx, env); !exists || err != nil {
 		return nil, fmt.Errorf("vault for env %s does not exist %w", env, err)
 	}
 
-	passphrase, err := vs.passphraseService.Get(ctx, env)
+	release, err := vs.vaultRepo.Lock(ctx, env, exclusive)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve passphrase: %w", err)
+		return nil, err
+	}
+
+	vault, err := vs.unlock(ctx, env)
+	if err != nil {
+		release()
+		return nil, err
 	}
+	vault.SetRelease(release)
+	vault.SetAuthor(vs.authorService.Author())
+
+	return vault, nil
+}
 
+// unlock loads the vault of an environment and unlocks it with the local identity, a key
+// held by the agent or a passphrase, in that order.
+func (vs *VaultService) unlock(ctx context.Context, env string) (*model.Vault, error) {
 	vault, err := vs.vaultRepo.Load(ctx, env)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open vault for environment %s: %w", env, err)
 	}
 
-	if err = vs.passphraseService.Validate(ctx, vault, passphrase); err != nil {
-		if err = vs.passphraseService.Clear(ctx, env); err != nil {
-			return nil, fmt.Errorf("failed to clear passphrase: %w", err)
+	if vault.Meta.FormatVersion > model.CurrentFormatVersion {
+		return nil, fmt.Errorf(
+			"vault for environment %s uses format version %d, this lockify supports up to %d",
+			env,
+			vault.Meta.FormatVersion,
+			model.CurrentFormatVersion,
+		)
+	}
+
+	unlocked, err := vs.unlockWithIdentity(ctx, vault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault for environment %s: %w", env, err)
+	}
+	if unlocked || vs.unlockWithAgent(ctx, vault) {
+		return vault, nil
+	}
+
+	passphrase, err := vs.passphraseService.Get(ctx, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve passphrase: %w", err)
+	}
+
+	slot, session, err := vs.unlockWithPassphrase(ctx, vault, passphrase)
+	if errors.Is(err, model.ErrWrongPassphrase) {
+		if clearErr := vs.passphraseService.Clear(ctx, env); clearErr != nil {
+			return nil, fmt.Errorf("failed to clear passphrase: %w", clearErr)
 		}
 		return nil, fmt.Errorf("invalid credentials: %w", err)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault for environment %s: %w", env, err)
+	}
 
 	vault.SetPassphrase(passphrase)
+	vault.SetSession(session)
+	vault.SetUnlockedSlot(slot.Name)
+	vs.addToAgent(ctx, vault)
 
 	return vault, nil
 }
 
-// Save saves the vault to persistent storage.
+// unlockWithPassphrase derives the key of every key slot from passphrase until one opens the
+// wrapped data key, and returns that slot with a session bound to the data key. A passphrase
+// that opens no slot fails with model.ErrWrongPassphrase. Vaults older than
+// model.FormatVersionEnvelope have no wrapped key and are checked against their bcrypt
+// fingerprint instead.
+func (vs *VaultService) unlockWithPassphrase(
+	ctx context.Context,
+	vault *model.Vault,
+	passphrase string,
+) (model.KeySlot, model.Session, error) {
+	if vault.Meta.FormatVersion < model.FormatVersionEnvelope {
+		slot, err := vs.passphraseService.Validate(ctx, vault, passphrase)
+		if err != nil {
+			return model.KeySlot{}, nil, fmt.Errorf("%w: %w", model.ErrWrongPassphrase, err)
+		}
+		session, err := vs.encryptionService.NewSession(vault.Meta, passphrase)
+		return slot, session, err
+	}
+
+	for _, slot := range vault.Meta.KeySlots() {
+		session, err := vs.encryptionService.NewSession(vault.Meta.ForSlot(slot), passphrase)
+		if errors.Is(err, model.ErrWrongPassphrase) {
+			continue
+		}
+		if err != nil {
+			return model.KeySlot{}, nil, err
+		}
+		return slot, session, nil
+	}
+	return model.KeySlot{}, nil, fmt.Errorf(
+		"passphrase does not match any key slot: %w",
+		model.ErrWrongPassphrase,
+	)
+}
+
+// unlockWithIdentity unlocks the vault with the local identity when it is one of the vault
+// recipients. It reports false, without error, when there is no identity or it is not a
+// recipient, so that the caller falls back to the passphrase.
+func (vs *VaultService) unlockWithIdentity(ctx context.Context, vault *model.Vault) (bool, error) {
+	if len(vault.Meta.Recipients) == 0 {
+		return false, nil
+	}
+
+	identity, err := vs.identityRepo.Load(ctx)
+	if errors.Is(err, model.ErrNoIdentity) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	recipient, ok := vault.Meta.FindRecipient(identity.PublicKey)
+	if !ok {
+		return false, nil
+	}
+
+	session, err := vs.encryptionService.NewRecipientSession(vault.Meta, recipient, identity)
+	if err != nil {
+		return false, err
+	}
+
+	vault.SetSession(session)
+	vault.SetUnlockedSlot(model.RecipientSlotPrefix + recipient.Label())
+	return true, nil
+}
+
+// unlockWithAgent unlocks the vault with the data key the agent holds for it. It reports
+// false when no agent runs, the agent holds no key for the vault or the key does not match
+// it, so that the caller falls back to the passphrase. Vaults older than
+// model.FormatVersionEnvelope have no data key and are never unlocked by the agent.
+func (vs *VaultService) unlockWithAgent(ctx context.Context, vault *model.Vault) bool {
+	if vault.Meta.FormatVersion < model.FormatVersionEnvelope {
+		return false
+	}
+
+	held, err := vs.keyAgent.Key(ctx, vault.Meta.AgentKeyID())
+	if err != nil {
+		return false
+	}
+	session, err := vs.encryptionService.NewKeySession(vault.Meta, held.Key)
+	clear(held.Key)
+	if err != nil {
+		return false
+	}
+
+	vault.SetSession(session)
+	if err := vault.VerifyIntegrity(); err != nil {
+		// The passphrase unlock reports whether the vault itself was tampered with.
+		session.Close()
+		vault.SetSession(nil)
+		return false
+	}
+	vault.SetUnlockedSlot(held.Slot)
+	return true
+}
+
+// addToAgent hands the data key of a vault unlocked with a passphrase to the agent, when one
+// runs and the user opted in to it, so that the next commands find it there.
+func (vs *VaultService) addToAgent(ctx context.Context, vault *model.Vault) {
+	if vault.Meta.FormatVersion < model.FormatVersionEnvelope {
+		return
+	}
+
+	key, err := vault.Session().ExportKey()
+	if err != nil {
+		return
+	}
+	defer clear(key)
+
+	//nolint:errcheck // Without an agent the passphrase is simply asked for again
+	vs.keyAgent.Add(ctx, model.AgentKey{
+		ID:   vault.Meta.AgentKeyID(),
+		Env:  vault.Meta.Env,
+		Slot: vault.UnlockedSlot(),
+		Key:  key,
+	})
+}
+
+// Save seals the vault with a new revision and MAC and writes it to persistent storage.
+// Vaults older than model.FormatVersionEnvelope, whose passphrases are still checked against
+// a bcrypt fingerprint, are upgraded to the current format first, together with their audit
+// log.
 func (vs *VaultService) Save(ctx context.Context, vault *model.Vault) error {
+	if vault.Meta.FormatVersion < model.FormatVersionEnvelope {
+		return vs.saveUpgraded(ctx, vault)
+	}
+	if err := vault.Seal(); err != nil {
+		return fmt.Errorf("failed to seal vault: %w", err)
+	}
 	return vs.vaultRepo.Save(ctx, vault)
 }
+
+// saveUpgraded upgrades vault to the current format, saves it and seals its audit log again
+// with the new data key.
+func (vs *VaultService) saveUpgraded(ctx context.Context, vault *model.Vault) error {
+	env := vault.Meta.Env
+	// The audit log is sealed with the vault key, so it is read before the key changes.
+	events, err := vs.auditLog.Events(ctx, vault)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot upgrade the audit log of environment %s, inspect it with "+
+				"`lockify audit --env %s`: %w",
+			env,
+			env,
+			err,
+		)
+	}
+	if err := vs.upgrader.Upgrade(vault); err != nil {
+		return err
+	}
+
+	if err := vault.Seal(); err != nil {
+		return fmt.Errorf("failed to seal vault: %w", err)
+	}
+	if err := vs.vaultRepo.Save(ctx, vault); err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		if err := vs.auditLog.Rewrite(ctx, vault, events); err != nil {
+			return fmt.Errorf("vault was upgraded but its audit log was not: %w", err)
+		}
+	}
+	return nil
+}
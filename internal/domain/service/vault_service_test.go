@@ -14,17 +14,34 @@ import (
 // Helpers
 // ============================================================================
 func createTestVault(env string) *model.Vault {
-	vault, _ := model.NewVault(env, "test-fingerprint", "test-salt")
+	vault := createUnlockedTestVault(env)
+	vault.Seal()
+	vault.SetSession(nil)
+	return vault
+}
+
+func createUnlockedTestVault(env string) *model.Vault {
+	vault, _ := model.NewVault(env, "test-salt")
 	vault.SetEntry("test-entry", "test-value")
+	vault.SetSession(&test.MockSession{})
 	return vault
 }
 
 func createVaultServiceWithMocks(
 	repo *test.MockVaultRepository,
 	passphrase *test.MockPassphraseService,
-	hash *test.MockHashService,
+	encryption *test.MockEncryptionService,
 ) VaultServiceInterface {
-	return NewVaultService(repo, passphrase, hash)
+	return NewVaultService(
+		repo,
+		passphrase,
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
 }
 
 // ============================================================================
@@ -34,7 +51,7 @@ func TestCreate_Success(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		&test.MockVaultRepository{},
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 	vault, err := vaultService.Create(context.Background(), "test")
 	if err != nil {
@@ -46,8 +63,8 @@ func TestCreate_Success(t *testing.T) {
 	if vault.Meta.Env != "test" {
 		t.Errorf("Create() vault.Meta.Env = %q, want %q", vault.Meta.Env, "test")
 	}
-	if vault.Meta.FingerPrint == "" {
-		t.Error("Create() vault.Meta.FingerPrint is empty")
+	if vault.Meta.FingerPrint != "" {
+		t.Errorf("Create() vault.Meta.FingerPrint = %q, want none", vault.Meta.FingerPrint)
 	}
 	if vault.Meta.Salt == "" {
 		t.Error("Create() vault.Meta.Salt is empty")
@@ -55,6 +72,107 @@ func TestCreate_Success(t *testing.T) {
 	if len(vault.Entries) != 0 {
 		t.Errorf("Create() vault.Entries length = %d, want 0", len(vault.Entries))
 	}
+	if vault.Meta.FormatVersion != model.CurrentFormatVersion {
+		t.Errorf(
+			"Create() vault.Meta.FormatVersion = %d, want %d",
+			vault.Meta.FormatVersion,
+			model.CurrentFormatVersion,
+		)
+	}
+	if vault.Meta.Cipher.Algorithm == "" || vault.Meta.KDF.Algorithm == "" {
+		t.Error("Create() should record the cipher and KDF parameters")
+	}
+}
+
+func TestCreate_WrapsDataKey(t *testing.T) {
+	var wrappedPassphrase string
+	session := &test.MockSession{
+		WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+			wrappedPassphrase = passphrase
+			return "wrapped-data-key", nil
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			t.Error("Create() should generate a data key instead of deriving the vault key")
+			return &test.MockSession{}, nil
+		},
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return session, nil
+		},
+	}
+	vaultService := NewVaultService(
+		&test.MockVaultRepository{},
+		&test.MockPassphraseService{
+			GetFunc: func(ctx context.Context, env string) (string, error) {
+				return "test-passphrase", nil
+			},
+		},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Create(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+	if vault.Meta.WrappedKey != "wrapped-data-key" {
+		t.Errorf(
+			"Create() vault.Meta.WrappedKey = %q, want %q",
+			vault.Meta.WrappedKey,
+			"wrapped-data-key",
+		)
+	}
+	if wrappedPassphrase != "test-passphrase" {
+		t.Errorf(
+			"Create() wrapped the data key with %q, want %q",
+			wrappedPassphrase,
+			"test-passphrase",
+		)
+	}
+	if !session.Closed {
+		t.Error("Create() should lock the vault when done")
+	}
+}
+
+func TestCreate_WrapKeyError(t *testing.T) {
+	encryption := &test.MockEncryptionService{
+		NewDataKeyFunc: func(meta model.Meta) (model.Session, error) {
+			return &test.MockSession{
+				WrapKeyFunc: func(meta model.Meta, passphrase string) (string, error) {
+					return "", errors.New("wrap error")
+				},
+			}, nil
+		},
+	}
+	repo := &test.MockVaultRepository{
+		CreateFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Create() should not write a vault whose data key could not be wrapped")
+			return nil
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := vaultService.Create(context.Background(), "test")
+	if err == nil {
+		t.Fatal("Create() with wrap error expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to wrap data key") {
+		t.Errorf("Create() error = %q, want to contain 'failed to wrap data key'", err.Error())
+	}
 }
 
 func TestCreate_VaultAlreadyExists(t *testing.T) {
@@ -66,7 +184,7 @@ func TestCreate_VaultAlreadyExists(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
@@ -87,7 +205,7 @@ func TestCreate_RepositoryExistsError(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
@@ -111,7 +229,7 @@ func TestCreate_PassphraseGetError(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		&test.MockVaultRepository{},
 		passphrase,
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
@@ -123,37 +241,39 @@ func TestCreate_PassphraseGetError(t *testing.T) {
 	}
 }
 
-func TestCreate_HashError(t *testing.T) {
-	hash := &test.MockHashService{
-		HashFunc: func(passphrase string) (string, error) {
-			return "", errors.New("hash error")
+func TestCreate_WeakPassphrase(t *testing.T) {
+	passphrase := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Create() should get a new passphrase, not an existing one")
+			return "", errors.New("unexpected get")
+		},
+		GetNewFunc: func(ctx context.Context, env string) (string, error) {
+			return "", &model.WeakPassphraseError{Env: env, MinScore: 3}
 		},
 	}
 	vaultService := createVaultServiceWithMocks(
 		&test.MockVaultRepository{},
-		&test.MockPassphraseService{},
-		hash,
+		passphrase,
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
-	if err == nil {
-		t.Fatal("Create() with hash error expected error, got nil")
-	}
-	if !strings.Contains(err.Error(), "failed to hash passphrase") {
-		t.Errorf("Create() error = %q, want to contain 'failed to hash passphrase'", err.Error())
+	var weak *model.WeakPassphraseError
+	if !errors.As(err, &weak) {
+		t.Errorf("Create() error = %v, want a WeakPassphraseError", err)
 	}
 }
 
 func TestCreate_GenerateSaltError(t *testing.T) {
-	hash := &test.MockHashService{
-		GenerateSaltFunc: func(length int) (string, error) {
+	encryption := &test.MockEncryptionService{
+		NewSaltFunc: func() (string, error) {
 			return "", errors.New("salt error")
 		},
 	}
 	vaultService := createVaultServiceWithMocks(
 		&test.MockVaultRepository{},
 		&test.MockPassphraseService{},
-		hash,
+		encryption,
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
@@ -174,7 +294,7 @@ func TestCreate_RepositoryCreateError(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Create(context.Background(), "test")
@@ -199,7 +319,7 @@ func TestOpen_Success(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	vault, err := vaultService.Open(context.Background(), "test")
@@ -215,6 +335,170 @@ func TestOpen_Success(t *testing.T) {
 	if vault.Passphrase() != "test-passphrase" {
 		t.Errorf("Open() vault.Passphrase() = %q, want %q", vault.Passphrase(), "test-passphrase")
 	}
+	if vault.Session() == nil {
+		t.Error("Open() should unlock the vault with a session, got nil")
+	}
+}
+
+func TestOpen_UnlocksWithMatchingSlot(t *testing.T) {
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := createUnlockedTestVault(env)
+			vault.Meta.Slots = []model.KeySlot{
+				{Name: "ci", Salt: "ci-salt", WrappedKey: "ci-key"},
+			}
+			vault.Seal()
+			vault.SetSession(nil)
+			return vault, nil
+		},
+	}
+	var triedSalts []string
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			triedSalts = append(triedSalts, meta.Salt)
+			if meta.Salt != "ci-salt" || meta.WrappedKey != "ci-key" {
+				return nil, model.ErrWrongPassphrase
+			}
+			return &test.MockSession{}, nil
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if vault.UnlockedSlot() != "ci" {
+		t.Errorf("Open() vault.UnlockedSlot() = %q, want %q", vault.UnlockedSlot(), "ci")
+	}
+	if vault.Meta.Salt != "test-salt" {
+		t.Error("Open() should not replace the default slot in the vault meta")
+	}
+	if strings.Join(triedSalts, ",") != "test-salt,ci-salt" {
+		t.Errorf("Open() tried slots with salts %v, want the default slot first", triedSalts)
+	}
+}
+
+func TestOpen_NewSessionError(t *testing.T) {
+	testVault := createTestVault("test")
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return testVault, nil
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return nil, errors.New("kdf error")
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := vaultService.Open(context.Background(), "test")
+	if err == nil {
+		t.Fatal("Open() with session error expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to unlock vault") {
+		t.Errorf("Open() error = %q, want to contain 'failed to unlock vault'", err.Error())
+	}
+}
+
+func TestOpen_UnsupportedFormatVersion(t *testing.T) {
+	testVault := createTestVault("test")
+	testVault.Meta.FormatVersion = model.CurrentFormatVersion + 1
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return testVault, nil
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	_, err := vaultService.Open(context.Background(), "test")
+	if err == nil {
+		t.Fatal("Open() with newer format version expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "uses format version") {
+		t.Errorf("Open() error = %q, want to contain 'uses format version'", err.Error())
+	}
+}
+
+func TestOpen_IntegrityError(t *testing.T) {
+	testVault := createTestVault("test")
+	delete(testVault.Entries, "test-entry")
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return testVault, nil
+		},
+	}
+	session := &test.MockSession{}
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return session, nil
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	_, err := vaultService.Open(context.Background(), "test")
+	var integrityErr *model.IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("Open() error = %v, want *model.IntegrityError", err)
+	}
+	if !strings.Contains(err.Error(), `entry "test-entry" was removed`) {
+		t.Errorf("Open() error = %q, want to name the removed entry", err.Error())
+	}
+	if !session.Closed {
+		t.Error("Open() should lock the vault when the integrity check fails")
+	}
+
+	vault, err := vaultService.OpenUnverified(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("OpenUnverified() returned unexpected error: %v", err)
+	}
+	if vault.Session() == nil {
+		t.Error("OpenUnverified() should return an unlocked vault")
+	}
 }
 
 func TestOpen_VaultDoesNotExist(t *testing.T) {
@@ -226,7 +510,7 @@ func TestOpen_VaultDoesNotExist(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Open(context.Background(), "test")
@@ -253,7 +537,7 @@ func TestOpen_PassphraseGetError(t *testing.T) {
 			return "", errors.New("passphrase error")
 		},
 	}
-	vaultService := createVaultServiceWithMocks(repo, passphrase, &test.MockHashService{})
+	vaultService := createVaultServiceWithMocks(repo, passphrase, &test.MockEncryptionService{})
 
 	_, err := vaultService.Open(context.Background(), "test")
 	if err == nil {
@@ -276,7 +560,7 @@ func TestOpen_RepositoryLoadError(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	_, err := vaultService.Open(context.Background(), "test")
@@ -288,6 +572,131 @@ func TestOpen_RepositoryLoadError(t *testing.T) {
 	}
 }
 
+func TestOpen_TakesSharedLockUntilVaultIsLocked(t *testing.T) {
+	testVault := createTestVault("test")
+	locked, released := false, false
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			if exclusive {
+				t.Error("Open() should take a shared lock")
+			}
+			locked = true
+			return func() { released = true }, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			if !locked {
+				t.Error("Open() should lock the vault before loading it")
+			}
+			return testVault, nil
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if released {
+		t.Fatal("Open() released the lock before the vault was locked")
+	}
+
+	vault.Lock()
+	if !released {
+		t.Error("Lock() should release the vault file lock")
+	}
+}
+
+func TestOpenForUpdate_TakesExclusiveLock(t *testing.T) {
+	testVault := createTestVault("test")
+	exclusiveLock := false
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			exclusiveLock = exclusive
+			return func() {}, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return testVault, nil
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	vault, err := vaultService.OpenForUpdate(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("OpenForUpdate() returned unexpected error: %v", err)
+	}
+	defer vault.Lock()
+
+	if !exclusiveLock {
+		t.Error("OpenForUpdate() should take an exclusive lock")
+	}
+}
+
+func TestOpen_LockError(t *testing.T) {
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			return nil, errors.New("vault is in use")
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			t.Error("Open() should not load a vault it could not lock")
+			return nil, errors.New("unexpected load")
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	_, err := vaultService.Open(context.Background(), "test")
+	if err == nil || !strings.Contains(err.Error(), "vault is in use") {
+		t.Errorf("Open() error = %v, want to contain 'vault is in use'", err)
+	}
+}
+
+func TestOpen_ReleasesLockOnError(t *testing.T) {
+	released := false
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LockFunc: func(ctx context.Context, env string, exclusive bool) (func(), error) {
+			return func() { released = true }, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return nil, errors.New("load error")
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	if _, err := vaultService.Open(context.Background(), "test"); err == nil {
+		t.Fatal("Open() with load error expected error, got nil")
+	}
+	if !released {
+		t.Error("Open() should release the vault file lock when it fails")
+	}
+}
+
 func TestOpen_InvalidPassphrase(t *testing.T) {
 	testVault := createTestVault("test")
 	clearCalled := false
@@ -300,30 +709,79 @@ func TestOpen_InvalidPassphrase(t *testing.T) {
 		},
 	}
 	passphrase := &test.MockPassphraseService{
-		ValidateFunc: func(ctx context.Context, vault *model.Vault, passphrase string) error {
-			return errors.New("invalid passphrase")
+		ValidateFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			passphrase string,
+		) (model.KeySlot, error) {
+			t.Error("Validate() should only be called for vaults with a bcrypt fingerprint")
+			return model.KeySlot{}, nil
 		},
 		ClearFunc: func(ctx context.Context, env string) error {
 			clearCalled = true
 			return nil
 		},
 	}
-	vaultService := createVaultServiceWithMocks(repo, passphrase, &test.MockHashService{})
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			return nil, model.ErrWrongPassphrase
+		},
+	}
+	vaultService := createVaultServiceWithMocks(repo, passphrase, encryption)
 
 	_, err := vaultService.Open(context.Background(), "test")
 	if err == nil {
 		t.Fatal("Open() with invalid passphrase expected error, got nil")
 	}
-	if !strings.Contains(err.Error(), "invalid credentials") {
-		t.Errorf("Open() error = %q, want to contain 'invalid credentials'", err.Error())
+	if !strings.Contains(err.Error(), "invalid credentials") ||
+		!errors.Is(err, model.ErrWrongPassphrase) {
+		t.Errorf("Open() error = %q, want invalid credentials", err.Error())
 	}
 	if !clearCalled {
 		t.Error("Open() with invalid passphrase should call Clear(), but it didn't")
 	}
 }
 
+func TestOpen_LegacyVaultChecksFingerprint(t *testing.T) {
+	testVault := createTestVault("test")
+	testVault.Meta.FormatVersion = model.FormatVersionVaultMAC
+	testVault.Meta.FingerPrint = "test-fingerprint"
+	repo := &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return testVault, nil
+		},
+	}
+	passphrase := &test.MockPassphraseService{
+		ValidateFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			passphrase string,
+		) (model.KeySlot, error) {
+			return model.KeySlot{}, errors.New("passphrase does not match any key slot")
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewSessionFunc: func(meta model.Meta, passphrase string) (model.Session, error) {
+			t.Error("NewSession() should not be called before the fingerprint matches")
+			return &test.MockSession{}, nil
+		},
+	}
+	vaultService := createVaultServiceWithMocks(repo, passphrase, encryption)
+
+	_, err := vaultService.OpenUnverified(context.Background(), "test")
+	if err == nil {
+		t.Fatal("OpenUnverified() with invalid passphrase expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "invalid credentials") {
+		t.Errorf("OpenUnverified() error = %q, want invalid credentials", err.Error())
+	}
+}
+
 func TestSave_Success(t *testing.T) {
-	vault := createTestVault("test")
+	vault := createUnlockedTestVault("test")
 	saveCalled := false
 	repo := &test.MockVaultRepository{
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
@@ -337,7 +795,7 @@ func TestSave_Success(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	err := vaultService.Save(context.Background(), vault)
@@ -347,10 +805,38 @@ func TestSave_Success(t *testing.T) {
 	if !saveCalled {
 		t.Error("Save() should call repository.Save(), but it didn't")
 	}
+	if vault.Meta.Revision != 1 {
+		t.Errorf("Save() vault.Meta.Revision = %d, want 1", vault.Meta.Revision)
+	}
+	if vault.Meta.MAC == "" {
+		t.Error("Save() should write the vault MAC")
+	}
+}
+
+func TestSave_LockedVault(t *testing.T) {
+	repo := &test.MockVaultRepository{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not write a vault it cannot seal")
+			return nil
+		},
+	}
+	vaultService := createVaultServiceWithMocks(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+	)
+
+	err := vaultService.Save(context.Background(), createTestVault("test"))
+	if err == nil {
+		t.Fatal("Save() with a locked vault expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to seal vault") {
+		t.Errorf("Save() error = %q, want to contain 'failed to seal vault'", err.Error())
+	}
 }
 
 func TestSave_RepositoryError(t *testing.T) {
-	vault := createTestVault("test")
+	vault := createUnlockedTestVault("test")
 	repo := &test.MockVaultRepository{
 		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
 			return errors.New("save error")
@@ -359,7 +845,7 @@ func TestSave_RepositoryError(t *testing.T) {
 	vaultService := createVaultServiceWithMocks(
 		repo,
 		&test.MockPassphraseService{},
-		&test.MockHashService{},
+		&test.MockEncryptionService{},
 	)
 
 	err := vaultService.Save(context.Background(), vault)
@@ -370,3 +856,410 @@ func TestSave_RepositoryError(t *testing.T) {
 		t.Errorf("Save() error = %q, want %q", err.Error(), "save error")
 	}
 }
+
+func TestSave_UpgradesLegacyVault(t *testing.T) {
+	vault := createUnlockedTestVault("test")
+	vault.Meta.FormatVersion = model.FormatVersionVaultMAC
+	vault.Meta.FingerPrint = "bcrypt-fingerprint"
+	events := []model.AuditEvent{{Operation: model.AuditSet, Keys: []string{"test-entry"}}}
+	var saved bool
+	var rewritten []model.AuditEvent
+	repo := &test.MockVaultRepository{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			saved = true
+			if vault.Meta.FormatVersion != model.CurrentFormatVersion {
+				t.Errorf("Save() wrote format version %d, want %d",
+					vault.Meta.FormatVersion, model.CurrentFormatVersion)
+			}
+			return nil
+		},
+	}
+	auditLog := &test.MockAuditLog{
+		EventsFunc: func(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error) {
+			if vault.Meta.FormatVersion != model.FormatVersionVaultMAC {
+				t.Error("Save() should read the audit log before upgrading the vault")
+			}
+			return events, nil
+		},
+		RewriteFunc: func(
+			ctx context.Context,
+			vault *model.Vault,
+			events []model.AuditEvent,
+		) error {
+			if !saved {
+				t.Error("Save() should rewrite the audit log after saving the vault")
+			}
+			rewritten = events
+			return nil
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		auditLog,
+	)
+
+	if err := vaultService.Save(context.Background(), vault); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+	if !saved {
+		t.Error("Save() should call repository.Save(), but it didn't")
+	}
+	if len(rewritten) != len(events) {
+		t.Errorf("Save() rewrote %d audit events, want %d", len(rewritten), len(events))
+	}
+}
+
+func TestSave_LegacyVaultWithUnreadableAuditLog(t *testing.T) {
+	vault := createUnlockedTestVault("test")
+	vault.Meta.FormatVersion = model.FormatVersionVaultMAC
+	repo := &test.MockVaultRepository{
+		SaveFunc: func(ctx context.Context, vault *model.Vault) error {
+			t.Error("Save() should not upgrade a vault whose audit log cannot be read")
+			return nil
+		},
+	}
+	auditLog := &test.MockAuditLog{
+		EventsFunc: func(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error) {
+			return nil, errors.New("audit chain broken")
+		},
+	}
+	vaultService := NewVaultService(
+		repo,
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		auditLog,
+	)
+
+	err := vaultService.Save(context.Background(), vault)
+	if err == nil || !strings.Contains(err.Error(), "audit chain broken") {
+		t.Errorf("Save() error = %v, want the audit log error", err)
+	}
+	if vault.Meta.FormatVersion != model.FormatVersionVaultMAC {
+		t.Error("Save() should leave the vault at its format version")
+	}
+}
+
+func createRecipientVaultRepository() *test.MockVaultRepository {
+	return &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			vault := createUnlockedTestVault(env)
+			vault.Meta.Recipients = []model.Recipient{
+				{Name: "alice", PublicKey: "lockify1alice", WrappedKey: "alice-key"},
+			}
+			vault.Seal()
+			vault.SetSession(nil)
+			return vault, nil
+		},
+	}
+}
+
+func TestOpen_UnlocksWithIdentity(t *testing.T) {
+	passphrase := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Open() should not ask for a passphrase when the identity is a recipient")
+			return "", nil
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewRecipientSessionFunc: func(
+			meta model.Meta,
+			recipient model.Recipient,
+			identity model.Identity,
+		) (model.Session, error) {
+			if recipient.WrappedKey != "alice-key" {
+				t.Errorf("NewRecipientSession() called with %+v, want alice", recipient)
+			}
+			return &test.MockSession{}, nil
+		},
+	}
+	identities := &test.MockIdentityRepository{
+		LoadFunc: func(ctx context.Context) (model.Identity, error) {
+			return model.Identity{PublicKey: "lockify1alice", PrivateKey: "secret"}, nil
+		},
+	}
+	vaultService := NewVaultService(
+		createRecipientVaultRepository(),
+		passphrase,
+		encryption,
+		identities,
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if vault.UnlockedSlot() != model.RecipientSlotPrefix+"alice" {
+		t.Errorf("Open() vault.UnlockedSlot() = %q, want the alice recipient", vault.UnlockedSlot())
+	}
+	if vault.Passphrase() != "" {
+		t.Error("Open() with an identity should not set a passphrase")
+	}
+}
+
+func TestOpen_IdentityNotRecipientFallsBackToPassphrase(t *testing.T) {
+	encryption := &test.MockEncryptionService{
+		NewRecipientSessionFunc: func(
+			meta model.Meta,
+			recipient model.Recipient,
+			identity model.Identity,
+		) (model.Session, error) {
+			t.Error("NewRecipientSession() should not be called for an unknown identity")
+			return nil, errors.New("unexpected call")
+		},
+	}
+	identities := &test.MockIdentityRepository{
+		LoadFunc: func(ctx context.Context) (model.Identity, error) {
+			return model.Identity{PublicKey: "lockify1bob", PrivateKey: "secret"}, nil
+		},
+	}
+	vaultService := NewVaultService(
+		createRecipientVaultRepository(),
+		&test.MockPassphraseService{},
+		encryption,
+		identities,
+		&test.MockAuthorService{},
+		&test.MockKeyAgent{},
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if vault.UnlockedSlot() != model.DefaultSlotName {
+		t.Errorf("Open() vault.UnlockedSlot() = %q, want the default slot", vault.UnlockedSlot())
+	}
+}
+
+func TestOpen_IdentityErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		loadErr    error
+		sessionErr error
+		contains   string
+	}{
+		{
+			name:     "unreadable identity",
+			loadErr:  errors.New("permission denied"),
+			contains: "failed to load identity",
+		},
+		{
+			name:       "unwrap failure",
+			sessionErr: errors.New("failed to unwrap data key"),
+			contains:   "failed to unwrap data key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encryption := &test.MockEncryptionService{
+				NewRecipientSessionFunc: func(
+					meta model.Meta,
+					recipient model.Recipient,
+					identity model.Identity,
+				) (model.Session, error) {
+					return &test.MockSession{}, tt.sessionErr
+				},
+			}
+			identities := &test.MockIdentityRepository{
+				LoadFunc: func(ctx context.Context) (model.Identity, error) {
+					return model.Identity{PublicKey: "lockify1alice"}, tt.loadErr
+				},
+			}
+			vaultService := NewVaultService(
+				createRecipientVaultRepository(),
+				&test.MockPassphraseService{},
+				encryption,
+				identities,
+				&test.MockAuthorService{},
+				&test.MockKeyAgent{},
+				&test.MockVaultUpgrader{},
+				&test.MockAuditLog{},
+			)
+
+			_, err := vaultService.Open(context.Background(), "test")
+			if err == nil {
+				t.Fatal("Open() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Open() error = %q, want to contain %q", err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func createAgentVaultRepository(vault *model.Vault) *test.MockVaultRepository {
+	return &test.MockVaultRepository{
+		ExistsFunc: func(ctx context.Context, env string) (bool, error) {
+			return true, nil
+		},
+		LoadFunc: func(ctx context.Context, env string) (*model.Vault, error) {
+			return vault, nil
+		},
+	}
+}
+
+func TestOpen_UnlocksWithAgentKey(t *testing.T) {
+	testVault := createTestVault("test")
+	agent := &test.MockKeyAgent{
+		KeyFunc: func(ctx context.Context, id string) (model.AgentKey, error) {
+			if id != testVault.Meta.AgentKeyID() {
+				t.Errorf("Key() called with %q, want the id of the vault", id)
+			}
+			return model.AgentKey{ID: id, Env: "test", Slot: "ci", Key: []byte("held-key")}, nil
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewKeySessionFunc: func(meta model.Meta, key []byte) (model.Session, error) {
+			if string(key) != "held-key" {
+				t.Errorf("NewKeySession() called with %q, want the held key", key)
+			}
+			return &test.MockSession{}, nil
+		},
+	}
+	passphrase := &test.MockPassphraseService{
+		GetFunc: func(ctx context.Context, env string) (string, error) {
+			t.Error("Open() should not ask for a passphrase when the agent holds the key")
+			return "", errors.New("unexpected call")
+		},
+	}
+	vaultService := NewVaultService(
+		createAgentVaultRepository(testVault),
+		passphrase,
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		agent,
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if vault.UnlockedSlot() != "ci" {
+		t.Errorf("Open() unlocked slot %q, want the slot of the held key", vault.UnlockedSlot())
+	}
+	if vault.Passphrase() != "" {
+		t.Error("Open() with an agent key should not set a passphrase")
+	}
+	if len(agent.Added) != 0 {
+		t.Errorf("Open() added %d key(s) to the agent, want none", len(agent.Added))
+	}
+}
+
+func TestOpen_AddsPassphraseUnlockedKeyToAgent(t *testing.T) {
+	testVault := createTestVault("test")
+	agent := &test.MockKeyAgent{}
+	vaultService := NewVaultService(
+		createAgentVaultRepository(testVault),
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		agent,
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	if _, err := vaultService.Open(context.Background(), "test"); err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if len(agent.Added) != 1 {
+		t.Fatalf("Open() added %d key(s) to the agent, want 1", len(agent.Added))
+	}
+	added := agent.Added[0]
+	if added.ID != testVault.Meta.AgentKeyID() || added.Env != "test" ||
+		added.Slot != model.DefaultSlotName {
+		t.Errorf("Open() added %+v, want the default slot key of the vault", added)
+	}
+}
+
+func TestOpen_MismatchedAgentKeyFallsBackToPassphrase(t *testing.T) {
+	agentSession := &test.MockSession{
+		MACFunc: func(data []byte) (string, error) {
+			return "mac-of-another-key", nil
+		},
+	}
+	encryption := &test.MockEncryptionService{
+		NewKeySessionFunc: func(meta model.Meta, key []byte) (model.Session, error) {
+			return agentSession, nil
+		},
+	}
+	agent := &test.MockKeyAgent{
+		KeyFunc: func(ctx context.Context, id string) (model.AgentKey, error) {
+			return model.AgentKey{ID: id, Env: "test", Slot: "ci", Key: []byte("old-key")}, nil
+		},
+	}
+	vaultService := NewVaultService(
+		createAgentVaultRepository(createTestVault("test")),
+		&test.MockPassphraseService{},
+		encryption,
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		agent,
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	vault, err := vaultService.Open(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	if !agentSession.Closed {
+		t.Error("Open() should close the session of a key that does not match the vault")
+	}
+	if vault.UnlockedSlot() != model.DefaultSlotName || vault.Passphrase() != "test-passphrase" {
+		t.Errorf("Open() unlocked slot %q, want the default slot", vault.UnlockedSlot())
+	}
+	if len(agent.Added) != 1 {
+		t.Errorf("Open() added %d key(s) to the agent, want the new key", len(agent.Added))
+	}
+}
+
+func TestOpen_OlderFormatSkipsAgent(t *testing.T) {
+	testVault := createTestVault("test")
+	testVault.Meta.FormatVersion = model.FormatVersionEnvelope - 1
+	agent := &test.MockKeyAgent{
+		KeyFunc: func(ctx context.Context, id string) (model.AgentKey, error) {
+			t.Error("Open() should not ask the agent for the key of an older vault")
+			return model.AgentKey{}, model.ErrAgentNoKey
+		},
+	}
+	vaultService := NewVaultService(
+		createAgentVaultRepository(testVault),
+		&test.MockPassphraseService{},
+		&test.MockEncryptionService{},
+		&test.MockIdentityRepository{},
+		&test.MockAuthorService{},
+		agent,
+		&test.MockVaultUpgrader{},
+		&test.MockAuditLog{},
+	)
+
+	if _, err := vaultService.OpenUnverified(context.Background(), "test"); err != nil {
+		t.Fatalf("OpenUnverified() returned unexpected error: %v", err)
+	}
+	if len(agent.Added) != 0 {
+		t.Errorf("OpenUnverified() added %d key(s) to the agent, want none", len(agent.Added))
+	}
+}
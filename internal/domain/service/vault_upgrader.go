@@ -0,0 +1,10 @@
+package service
+
+import "github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+
+// VaultUpgrader defines the interface for bringing an unlocked vault to the current format
+// version, as `lockify migrate` does. Vaults older than model.FormatVersionEnvelope need their
+// passphrase set.
+type VaultUpgrader interface {
+	Upgrade(vault *model.Vault) error
+}
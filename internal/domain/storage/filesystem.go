@@ -1,15 +1,46 @@
 package storage
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrLockTimeout is returned when a file lock is not acquired before the timeout
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
 // FileSystem abstracts file operations for testing
 type FileSystem interface {
 	// MkdirAll creates a directory and all parent directories
 	MkdirAll(path string, perm uint32) error
 	// WriteFile writes data to a file
 	WriteFile(path string, data []byte, perm uint32) error
+	// AppendFile appends data to a file, creating it if needed, and flushes it to disk
+	AppendFile(path string, data []byte, perm uint32) error
 	// ReadFile reads a file
 	ReadFile(path string) ([]byte, error)
 	// Stat returns file information
 	Stat(path string) (FileInfo, error)
+	// ReadDir returns the names of the entries in a directory
+	ReadDir(path string) ([]string, error)
+	// CreateTemp creates a new file with the given permissions in dir, named by pattern
+	// as in os.CreateTemp
+	CreateTemp(dir, pattern string, perm uint32) (File, error)
+	// Rename replaces newPath with oldPath and makes the rename durable
+	Rename(oldPath, newPath string) error
+	// Remove removes a file
+	Remove(path string) error
+	// Lock takes an advisory lock on path, creating the file if needed, waiting at most
+	// timeout for other processes to release it. Shared locks can be held by several
+	// processes at once, exclusive locks only by one. The returned function releases it.
+	Lock(path string, exclusive bool, perm uint32, timeout time.Duration) (func() error, error)
+}
+
+// File is an open file that can be flushed to stable storage
+type File interface {
+	Write(data []byte) (int, error)
+	Sync() error
+	Close() error
+	Name() string
 }
 
 // FileInfo represents file metadata
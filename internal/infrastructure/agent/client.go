@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// Client implements service.KeyAgent by talking to the agent over its unix socket
+type Client struct {
+	cfg config.VaultConfig
+}
+
+// NewClient creates a client for the agent socket configured in cfg
+func NewClient(cfg config.VaultConfig) service.KeyAgent {
+	return &Client{cfg}
+}
+
+// Key returns the key the agent holds for the vault with id
+func (c *Client) Key(ctx context.Context, id string) (model.AgentKey, error) {
+	resp, err := c.call(ctx, request{Op: opKey, ID: id})
+	if err != nil {
+		return model.AgentKey{}, err
+	}
+	if resp.Key == nil {
+		return model.AgentKey{}, errors.New("agent returned no key")
+	}
+	return *resp.Key, nil
+}
+
+// Add hands an unlocked vault key to the agent. Keys are only handed over when the user
+// opted in with the config or its environment variable; otherwise Add does nothing.
+func (c *Client) Add(ctx context.Context, key model.AgentKey) error {
+	if !useAgent(c.cfg) {
+		return nil
+	}
+	_, err := c.call(ctx, request{Op: opAdd, Key: &key})
+	return err
+}
+
+// Forget drops the keys the agent holds for env
+func (c *Client) Forget(ctx context.Context, env string) (int, error) {
+	resp, err := c.call(ctx, request{Op: opForget, Env: env})
+	return resp.Count, err
+}
+
+// Lock drops every key the agent holds
+func (c *Client) Lock(ctx context.Context) (int, error) {
+	resp, err := c.call(ctx, request{Op: opLock})
+	return resp.Count, err
+}
+
+// Status describes the agent and the keys it holds
+func (c *Client) Status(ctx context.Context) (model.AgentStatus, error) {
+	resp, err := c.call(ctx, request{Op: opStatus})
+	if err != nil {
+		return model.AgentStatus{}, err
+	}
+	if resp.Status == nil {
+		return model.AgentStatus{}, errors.New("agent returned no status")
+	}
+	return *resp.Status, nil
+}
+
+// call sends req on a new connection to the agent and returns its response, after making
+// sure that the socket belongs to the current user
+func (c *Client) call(ctx context.Context, req request) (response, error) {
+	socket := socketPath(c.cfg)
+	err := checkSocket(socket, os.FileMode(c.cfg.DirMode), os.FileMode(c.cfg.FileMode))
+	if errors.Is(err, os.ErrNotExist) {
+		return response{}, fmt.Errorf("%w on %s", service.ErrAgentNotRunning, socket)
+	}
+	if err != nil {
+		return response{}, fmt.Errorf("refusing to talk to agent: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return response{}, fmt.Errorf("%w on %s", service.ErrAgentNotRunning, socket)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(ioTimeout)); err != nil {
+		return response{}, fmt.Errorf("failed to talk to agent: %w", err)
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, fmt.Errorf("failed to send request to agent: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, fmt.Errorf("failed to read response of agent: %w", err)
+	}
+	if resp.NoKey {
+		return response{}, model.ErrAgentNoKey
+	}
+	if resp.Error != "" {
+		return response{}, fmt.Errorf("agent: %s", resp.Error)
+	}
+	return resp, nil
+}
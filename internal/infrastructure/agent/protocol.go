@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+const (
+	// opKey asks for the key of a vault.
+	opKey = "key"
+	// opAdd hands a key to the agent.
+	opAdd = "add"
+	// opForget drops the keys of an environment.
+	opForget = "forget"
+	// opLock drops every key.
+	opLock = "lock"
+	// opStatus describes the agent.
+	opStatus = "status"
+	// dialTimeout bounds how long clients try to reach the agent.
+	dialTimeout = time.Second
+	// ioTimeout bounds a whole request and response.
+	ioTimeout = 5 * time.Second
+	// maxRequestSize bounds the size of a request the agent reads.
+	maxRequestSize = 64 * 1024
+)
+
+// request is sent by a client as a single JSON object on a fresh connection.
+type request struct {
+	Op  string          `json:"op"`
+	ID  string          `json:"id,omitempty"`
+	Env string          `json:"env,omitempty"`
+	Key *model.AgentKey `json:"key,omitempty"`
+}
+
+// response answers a request; NoKey reports that the agent holds no key for the vault.
+type response struct {
+	Error  string             `json:"error,omitempty"`
+	NoKey  bool               `json:"no_key,omitempty"`
+	Key    *model.AgentKey    `json:"key,omitempty"`
+	Count  int                `json:"count,omitempty"`
+	Status *model.AgentStatus `json:"status,omitempty"`
+}
+
+// socketPath returns the agent socket from the environment or the config, else in the
+// runtime directory of the user, else in a private directory under the temp directory
+func socketPath(cfg config.VaultConfig) string {
+	if path := os.Getenv(cfg.AgentSocketEnv); cfg.AgentSocketEnv != "" && path != "" {
+		return path
+	}
+	if cfg.AgentSocket != "" {
+		return cfg.AgentSocket
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "lockify", config.AgentSocketFileName)
+	}
+	return filepath.Join(
+		os.TempDir(),
+		fmt.Sprintf("lockify-%d", os.Getuid()),
+		config.AgentSocketFileName,
+	)
+}
+
+// useAgent reports whether unlocked keys are handed to the agent, as set by the environment
+// variable of the config, else by the config itself
+func useAgent(cfg config.VaultConfig) bool {
+	if value := os.Getenv(cfg.UseAgentEnv); cfg.UseAgentEnv != "" && value != "" {
+		enabled, err := strconv.ParseBool(value)
+		return err == nil && enabled
+	}
+	return cfg.UseAgent
+}
@@ -0,0 +1,282 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// sweepInterval is how often the agent drops expired keys.
+const sweepInterval = time.Second
+
+// Server implements service.AgentServer, holding vault keys in memory and serving them on
+// a unix socket that only the current user can use
+type Server struct {
+	cfg       config.VaultConfig
+	now       func() time.Time
+	mu        sync.Mutex
+	keys      map[string]*heldKey
+	policy    model.AgentPolicy
+	socket    string
+	startedAt time.Time
+}
+
+// heldKey is a key held by the agent with the times its expiry is computed from
+type heldKey struct {
+	key        model.AgentKey
+	addedAt    time.Time
+	lastUsedAt time.Time
+}
+
+// NewServer creates an agent listening on the socket configured in cfg
+func NewServer(cfg config.VaultConfig) service.AgentServer {
+	return &Server{cfg: cfg, now: time.Now, keys: make(map[string]*heldKey)}
+}
+
+// Serve listens on the agent socket and answers requests until ctx is done. Keys are
+// dropped once they were idle for the idle timeout or held for the max lifetime, and all
+// of them are wiped when Serve returns.
+func (s *Server) Serve(
+	ctx context.Context,
+	policy model.AgentPolicy,
+	ready func(socket string),
+) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	s.mu.Lock()
+	s.policy, s.socket, s.startedAt = policy, listener.Addr().String(), s.now()
+	s.mu.Unlock()
+	defer s.lock()
+
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+	go s.sweep(ctx)
+
+	if ready != nil {
+		ready(listener.Addr().String())
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("agent stopped accepting connections: %w", err)
+		}
+		go s.handle(conn)
+	}
+}
+
+// listen creates the socket, readable and writable only by the current user, in a directory
+// only the current user owns and can use, replacing the socket of an agent that is no longer
+// running
+func (s *Server) listen() (net.Listener, error) {
+	socket := socketPath(s.cfg)
+	dir := filepath.Dir(socket)
+	if err := os.MkdirAll(dir, os.FileMode(s.cfg.DirMode)); err != nil {
+		return nil, fmt.Errorf("failed to create agent directory: %w", err)
+	}
+	// Anyone who can write to the directory could replace the socket with their own.
+	if err := checkDir(dir, os.FileMode(s.cfg.DirMode)); err != nil {
+		return nil, err
+	}
+
+	if conn, err := net.DialTimeout("unix", socket, dialTimeout); err == nil {
+		//nolint:errcheck // The connection only probed for a running agent
+		conn.Close()
+		return nil, fmt.Errorf("an agent is already running on %s", socket)
+	}
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale agent socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	if err := os.Chmod(socket, os.FileMode(s.cfg.FileMode)); err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("failed to restrict agent socket: %w", err),
+			listener.Close(),
+		)
+	}
+	return listener, nil
+}
+
+// handle answers the single request of a connection
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(ioTimeout)); err != nil {
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(io.LimitReader(conn, maxRequestSize)).Decode(&req); err != nil {
+		return
+	}
+	resp := s.dispatch(req)
+	//nolint:errcheck // The client may already have gone away
+	json.NewEncoder(conn).Encode(resp)
+	if resp.Key != nil {
+		clear(resp.Key.Key)
+	}
+	if req.Key != nil {
+		clear(req.Key.Key)
+	}
+}
+
+// dispatch runs a request against the held keys
+func (s *Server) dispatch(req request) response {
+	switch req.Op {
+	case opKey:
+		return s.get(req.ID)
+	case opAdd:
+		return s.add(req.Key)
+	case opForget:
+		if req.Env == "" {
+			return response{Error: "forget needs an environment"}
+		}
+		return response{Count: s.drop(func(key model.AgentKey) bool { return key.Env == req.Env })}
+	case opLock:
+		return response{Count: s.lock()}
+	case opStatus:
+		status := s.status()
+		return response{Status: &status}
+	default:
+		return response{Error: fmt.Sprintf("unknown request %q", req.Op)}
+	}
+}
+
+// get returns a copy of the key held for id and marks it as used
+func (s *Server) get(id string) response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	held, ok := s.keys[id]
+	now := s.now()
+	if !ok || !now.Before(s.policy.ExpiresAt(held.addedAt, held.lastUsedAt)) {
+		return response{NoKey: true}
+	}
+	held.lastUsedAt = now
+
+	key := held.key
+	key.Key = append([]byte(nil), held.key.Key...)
+	return response{Key: &key}
+}
+
+// add holds key, replacing the key held for the same vault
+func (s *Server) add(key *model.AgentKey) response {
+	if key == nil || key.ID == "" || key.Env == "" || len(key.Key) == 0 {
+		return response{Error: "incomplete key"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.keys[key.ID]; ok {
+		clear(old.key.Key)
+	}
+	now := s.now()
+	held := &heldKey{key: *key, addedAt: now, lastUsedAt: now}
+	held.key.Key = append([]byte(nil), key.Key...)
+	s.keys[key.ID] = held
+	return response{}
+}
+
+// drop wipes the keys that match and returns how many there were
+func (s *Server) drop(match func(key model.AgentKey) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := 0
+	for id, held := range s.keys {
+		if match(held.key) {
+			clear(held.key.Key)
+			delete(s.keys, id)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// lock wipes every key and returns how many there were
+func (s *Server) lock() int {
+	return s.drop(func(model.AgentKey) bool { return true })
+}
+
+// sweep drops expired keys until ctx is done
+func (s *Server) sweep(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.expire()
+		}
+	}
+}
+
+// expire drops the keys whose idle timeout or max lifetime has passed
+func (s *Server) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, held := range s.keys {
+		if !now.Before(s.policy.ExpiresAt(held.addedAt, held.lastUsedAt)) {
+			clear(held.key.Key)
+			delete(s.keys, id)
+		}
+	}
+}
+
+// status describes the agent and its keys, sorted by environment and slot
+func (s *Server) status() model.AgentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]model.AgentKeyInfo, 0, len(s.keys))
+	for _, held := range s.keys {
+		keys = append(keys, model.AgentKeyInfo{
+			Env:        held.key.Env,
+			Slot:       held.key.Slot,
+			AddedAt:    held.addedAt,
+			LastUsedAt: held.lastUsedAt,
+			ExpiresAt:  s.policy.ExpiresAt(held.addedAt, held.lastUsedAt),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Env != keys[j].Env {
+			return keys[i].Env < keys[j].Env
+		}
+		return keys[i].Slot < keys[j].Slot
+	})
+
+	return model.AgentStatus{
+		Socket:    s.socket,
+		PID:       os.Getpid(),
+		StartedAt: s.startedAt,
+		Policy:    s.policy,
+		Keys:      keys,
+	}
+}
@@ -0,0 +1,219 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// testClock is a settable clock shared by the test and the agent
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// startTestAgent runs an agent on a socket in a temp directory until the test ends and
+// returns a client for it
+func startTestAgent(t *testing.T, clock *testClock) (service.KeyAgent, config.VaultConfig) {
+	t.Helper()
+	cfg := config.DefaultVaultConfig()
+	cfg.AgentSocketEnv = ""
+	cfg.AgentSocket = filepath.Join(t.TempDir(), "lockify", config.AgentSocketFileName)
+	cfg.UseAgentEnv = ""
+	cfg.UseAgent = true
+
+	server := NewServer(cfg).(*Server)
+	server.now = clock.Now
+	policy := model.AgentPolicy{IdleTimeout: 10 * time.Minute, MaxLifetime: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, policy, func(string) { close(ready) })
+	}()
+	select {
+	case <-ready:
+	case err := <-done:
+		cancel()
+		t.Fatalf("Serve() returned early: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() returned unexpected error: %v", err)
+		}
+	})
+	return NewClient(cfg), cfg
+}
+
+func testAgentKey(env, id string) model.AgentKey {
+	return model.AgentKey{ID: id, Env: env, Slot: model.DefaultSlotName, Key: []byte("0123456789")}
+}
+
+func TestServer_KeyLifecycle(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)}
+	client, cfg := startTestAgent(t, clock)
+	ctx := context.Background()
+
+	info, err := os.Stat(cfg.AgentSocket)
+	if err != nil {
+		t.Fatalf("failed to stat agent socket: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("agent socket mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if _, err := client.Key(ctx, "prod-id"); !errors.Is(err, model.ErrAgentNoKey) {
+		t.Fatalf("Key() before Add() = %v, want ErrAgentNoKey", err)
+	}
+	for _, key := range []model.AgentKey{
+		testAgentKey("prod", "prod-id"),
+		testAgentKey("prod", "other-prod-id"),
+		testAgentKey("staging", "staging-id"),
+	} {
+		if err := client.Add(ctx, key); err != nil {
+			t.Fatalf("Add() returned unexpected error: %v", err)
+		}
+	}
+
+	key, err := client.Key(ctx, "prod-id")
+	if err != nil {
+		t.Fatalf("Key() returned unexpected error: %v", err)
+	}
+	if key.Env != "prod" || key.Slot != model.DefaultSlotName || string(key.Key) != "0123456789" {
+		t.Errorf("Key() = %+v, want the added prod key", key)
+	}
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() returned unexpected error: %v", err)
+	}
+	if len(status.Keys) != 3 || status.Keys[0].Env != "prod" || status.Keys[2].Env != "staging" {
+		t.Errorf("Status() keys = %+v, want three keys sorted by env", status.Keys)
+	}
+	if status.Socket != cfg.AgentSocket || status.PID != os.Getpid() {
+		t.Errorf("Status() = %+v, want socket %s and pid %d", status, cfg.AgentSocket, os.Getpid())
+	}
+
+	if dropped, err := client.Forget(ctx, "prod"); err != nil || dropped != 2 {
+		t.Errorf("Forget() = %d, %v, want both prod keys dropped", dropped, err)
+	}
+	if dropped, err := client.Lock(ctx); err != nil || dropped != 1 {
+		t.Errorf("Lock() = %d, %v, want the staging key dropped", dropped, err)
+	}
+	if _, err := client.Key(ctx, "staging-id"); !errors.Is(err, model.ErrAgentNoKey) {
+		t.Errorf("Key() after Lock() = %v, want ErrAgentNoKey", err)
+	}
+}
+
+func TestServer_Expiry(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)}
+	client, _ := startTestAgent(t, clock)
+	ctx := context.Background()
+
+	if err := client.Add(ctx, testAgentKey("prod", "prod-id")); err != nil {
+		t.Fatalf("Add() returned unexpected error: %v", err)
+	}
+
+	// Using the key within the idle timeout keeps it, but only up to the max lifetime.
+	for range 6 {
+		clock.Advance(9 * time.Minute)
+		if _, err := client.Key(ctx, "prod-id"); err != nil {
+			t.Fatalf("Key() within the idle timeout returned unexpected error: %v", err)
+		}
+	}
+	clock.Advance(7 * time.Minute)
+	if _, err := client.Key(ctx, "prod-id"); !errors.Is(err, model.ErrAgentNoKey) {
+		t.Errorf("Key() after the max lifetime = %v, want ErrAgentNoKey", err)
+	}
+
+	if err := client.Add(ctx, testAgentKey("prod", "prod-id")); err != nil {
+		t.Fatalf("Add() returned unexpected error: %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	if _, err := client.Key(ctx, "prod-id"); !errors.Is(err, model.ErrAgentNoKey) {
+		t.Errorf("Key() after the idle timeout = %v, want ErrAgentNoKey", err)
+	}
+}
+
+func TestServer_RejectsSecondAgent(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	_, cfg := startTestAgent(t, clock)
+
+	err := NewServer(cfg).Serve(context.Background(), model.AgentPolicy{}, nil)
+	if err == nil {
+		t.Fatal("Serve() with an agent already running expected error, got nil")
+	}
+}
+
+func TestClient_NotRunning(t *testing.T) {
+	cfg := config.DefaultVaultConfig()
+	cfg.AgentSocketEnv = ""
+	cfg.AgentSocket = filepath.Join(t.TempDir(), "lockify", config.AgentSocketFileName)
+
+	_, err := NewClient(cfg).Key(context.Background(), "prod-id")
+	if !errors.Is(err, service.ErrAgentNotRunning) {
+		t.Errorf("Key() without an agent = %v, want ErrAgentNotRunning", err)
+	}
+}
+
+func TestClient_AddOnlyWhenOptedIn(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	_, cfg := startTestAgent(t, clock)
+	ctx := context.Background()
+	cfg.UseAgent = false
+	cfg.UseAgentEnv = "LOCKIFY_AGENT"
+	client := NewClient(cfg)
+
+	if err := client.Add(ctx, testAgentKey("prod", "prod-id")); err != nil {
+		t.Fatalf("Add() returned unexpected error: %v", err)
+	}
+	if _, err := client.Key(ctx, "prod-id"); !errors.Is(err, model.ErrAgentNoKey) {
+		t.Fatalf("Key() after Add() without opting in = %v, want ErrAgentNoKey", err)
+	}
+
+	t.Setenv("LOCKIFY_AGENT", "1")
+	if err := client.Add(ctx, testAgentKey("prod", "prod-id")); err != nil {
+		t.Fatalf("Add() returned unexpected error: %v", err)
+	}
+	if _, err := client.Key(ctx, "prod-id"); err != nil {
+		t.Errorf("Key() after Add() with LOCKIFY_AGENT=1 returned unexpected error: %v", err)
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	cfg := config.DefaultVaultConfig()
+
+	t.Setenv(cfg.AgentSocketEnv, "/run/custom.sock")
+	if got := socketPath(cfg); got != "/run/custom.sock" {
+		t.Errorf("socketPath() = %q, want the socket from the environment", got)
+	}
+
+	t.Setenv(cfg.AgentSocketEnv, "")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	want := filepath.Join("/run/user/1000", "lockify", config.AgentSocketFileName)
+	if got := socketPath(cfg); got != want {
+		t.Errorf("socketPath() = %q, want %q", got, want)
+	}
+}
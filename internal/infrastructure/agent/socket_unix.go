@@ -0,0 +1,54 @@
+//go:build unix
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// checkSocket makes sure that socket is a socket with fileMode in a directory with dirMode,
+// both owned by the current user, so that no one else can have put it there to collect the
+// keys handed to the agent
+func checkSocket(socket string, dirMode, fileMode os.FileMode) error {
+	if err := checkDir(filepath.Dir(socket), dirMode); err != nil {
+		return err
+	}
+	if err := checkOwned(socket, os.ModeSocket, fileMode); err != nil {
+		return fmt.Errorf("agent socket %s %w", socket, err)
+	}
+	return nil
+}
+
+// checkDir makes sure that dir is a directory with mode, owned by the current user
+func checkDir(dir string, mode os.FileMode) error {
+	if err := checkOwned(dir, os.ModeDir, mode); err != nil {
+		return fmt.Errorf("agent directory %s %w", dir, err)
+	}
+	return nil
+}
+
+// checkOwned checks the type, owner and mode of path without following a symlink
+func checkOwned(path string, kind, mode os.FileMode) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("cannot be checked: %w", err)
+	}
+	if info.Mode().Type() != kind {
+		return fmt.Errorf("has type %s, want %s", info.Mode().Type(), kind)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return errors.New("has no owner")
+	}
+	if uid := os.Getuid(); int(stat.Uid) != uid {
+		return fmt.Errorf("is owned by uid %d, not by you (uid %d)", stat.Uid, uid)
+	}
+	if info.Mode().Perm() != mode {
+		return fmt.Errorf("has mode %s, want %s", info.Mode().Perm(), mode)
+	}
+	return nil
+}
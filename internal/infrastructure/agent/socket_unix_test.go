@@ -0,0 +1,98 @@
+//go:build unix
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+func TestClient_RefusesSocketOthersCanUse(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   func(cfg config.VaultConfig) string
+		mode   os.FileMode
+		reason string
+	}{
+		{
+			name:   "socket readable by others",
+			path:   func(cfg config.VaultConfig) string { return cfg.AgentSocket },
+			mode:   0o666,
+			reason: "agent socket",
+		},
+		{
+			name:   "directory usable by others",
+			path:   func(cfg config.VaultConfig) string { return filepath.Dir(cfg.AgentSocket) },
+			mode:   0o755,
+			reason: "agent directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cfg := startTestAgent(t, &testClock{now: time.Now()})
+			if err := os.Chmod(tt.path(cfg), tt.mode); err != nil {
+				t.Fatalf("failed to chmod: %v", err)
+			}
+
+			for name, call := range map[string]func() error{
+				"Add": func() error {
+					return client.Add(context.Background(), testAgentKey("prod", "prod-id"))
+				},
+				"Key": func() error {
+					_, err := client.Key(context.Background(), "prod-id")
+					return err
+				},
+				"Status": func() error {
+					_, err := client.Status(context.Background())
+					return err
+				},
+			} {
+				err := call()
+				if err == nil || !strings.Contains(err.Error(), tt.reason) {
+					t.Errorf("%s() = %v, want an error about the %s", name, err, tt.reason)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RefusesSymlinkedSocket(t *testing.T) {
+	_, cfg := startTestAgent(t, &testClock{now: time.Now()})
+	link := filepath.Join(filepath.Dir(cfg.AgentSocket), "link.sock")
+	if err := os.Symlink(cfg.AgentSocket, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	cfg.AgentSocket = link
+
+	_, err := NewClient(cfg).Key(context.Background(), "prod-id")
+	if err == nil || errors.Is(err, model.ErrAgentNoKey) {
+		t.Errorf("Key() through a symlink = %v, want an error", err)
+	}
+}
+
+func TestServer_RejectsDirectoryOthersCanUse(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lockify")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatalf("failed to chmod directory: %v", err)
+	}
+	cfg := config.DefaultVaultConfig()
+	cfg.AgentSocketEnv = ""
+	cfg.AgentSocket = filepath.Join(dir, config.AgentSocketFileName)
+
+	err := NewServer(cfg).Serve(context.Background(), model.AgentPolicy{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "has mode") {
+		t.Errorf("Serve() in a directory with mode 0755 = %v, want a mode error", err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package agent
+
+import "os"
+
+// checkSocket accepts any socket, as Windows files have no unix owner and mode to check;
+// the socket lives in the temp directory of the user
+func checkSocket(socket string, dirMode, fileMode os.FileMode) error {
+	return nil
+}
+
+// checkDir accepts any directory, see checkSocket
+func checkDir(dir string, mode os.FileMode) error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// New creates the cache backend named by the backend variable of cfg, or else by its
+// configured backend. An unknown backend fails every call, so the mistake is reported
+// when the cache is used rather than by every command.
+func New(cfg config.VaultConfig, serviceName string) service.Cache {
+	backend := cfg.CacheBackend
+	if cfg.CacheBackendEnv != "" {
+		if value := os.Getenv(cfg.CacheBackendEnv); value != "" {
+			backend = value
+		}
+	}
+
+	switch backend {
+	case config.CacheBackendKeyring, "":
+		return NewOSKeyring(serviceName)
+	case config.CacheBackendFile:
+		return NewFileCache(cfg)
+	case config.CacheBackendNone:
+		return NewNoneCache()
+	default:
+		return &unavailableCache{fmt.Errorf(
+			"unknown cache backend %q: use %s, %s or %s",
+			backend,
+			config.CacheBackendKeyring,
+			config.CacheBackendFile,
+			config.CacheBackendNone,
+		)}
+	}
+}
+
+// entry is a cached value with the unix time it expires at, 0 for never
+type entry struct {
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// newEntry returns an entry holding value for ttl from now
+func newEntry(value string, ttl time.Duration, now time.Time) (entry, error) {
+	if ttl < 0 {
+		return entry{}, fmt.Errorf("cache ttl cannot be negative: %s", ttl)
+	}
+	e := entry{Value: value}
+	if ttl > 0 {
+		e.ExpiresAt = now.Add(ttl).Unix()
+	}
+	return e, nil
+}
+
+// expired reports whether the entry is no longer valid at now
+func (e entry) expired(now time.Time) bool {
+	return e.ExpiresAt != 0 && now.Unix() >= e.ExpiresAt
+}
+
+// encode returns the JSON form of the entry
+func (e entry) encode() ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return data, nil
+}
+
+// decodeEntry parses the JSON form of an entry
+func decodeEntry(data []byte) (entry, error) {
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return entry{}, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+	return e, nil
+}
+
+// unavailableCache fails every call with err
+type unavailableCache struct {
+	err error
+}
+
+func (c *unavailableCache) Set(key, value string, ttl time.Duration) error { return c.err }
+func (c *unavailableCache) Get(key string) (string, error)                 { return "", c.err }
+func (c *unavailableCache) Delete(key string) error                        { return c.err }
+func (c *unavailableCache) DeleteAll() error                               { return c.err }
@@ -0,0 +1,328 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+const (
+	// fileCacheKeyName is the file holding the random key of the cache directory.
+	fileCacheKeyName = ".key"
+	// fileCacheKeySize is the size of the random key and of the keys derived from it.
+	fileCacheKeySize = 32
+	// fileCacheEntrySuffix is the file name suffix of cached values.
+	fileCacheEntrySuffix = ".enc"
+	// bootIDPath holds an identifier that changes with every boot on Linux.
+	bootIDPath = "/proc/sys/kernel/random/boot_id"
+)
+
+// errNoRuntimeDir is returned when the file cache has no directory to live in.
+var errNoRuntimeDir = errors.New("the file cache needs $XDG_RUNTIME_DIR")
+
+// FileCache implements Cache with AES-GCM encrypted files in the runtime directory of the
+// user, which is removed when the user logs out. Values are encrypted with a key derived
+// from a random key stored next to them and the login session, so a value cached in one
+// session cannot be read in another one. The encryption keeps copies of the files useless
+// outside the session, but it does not protect against the user: any process of the user in
+// the same session reads the key as easily as the values, so for those processes the cache
+// is as good as plaintext.
+type FileCache struct {
+	cfg     config.VaultConfig
+	now     func() time.Time
+	session func() string
+}
+
+// NewFileCache creates a file cache in the directory configured in cfg, else in
+// $XDG_RUNTIME_DIR
+func NewFileCache(cfg config.VaultConfig) service.Cache {
+	return &FileCache{cfg: cfg, now: time.Now, session: loginSession}
+}
+
+// Set encrypts value into a file of the cache directory
+func (c *FileCache) Set(key, value string, ttl time.Duration) error {
+	e, err := newEntry(value, ttl, c.now())
+	if err != nil {
+		return err
+	}
+	data, err := e.encode()
+	if err != nil {
+		return err
+	}
+	defer clear(data)
+
+	dir, err := c.createDir()
+	if err != nil {
+		return err
+	}
+	sessionKey, err := c.sessionKey(dir, true)
+	if err != nil {
+		return err
+	}
+	defer clear(sessionKey)
+
+	gcm, err := newGCM(sessionKey)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, data, []byte(key))
+	return c.writeFile(dir, entryFileName(sessionKey, key), sealed)
+}
+
+// Get decrypts the value of key, removing it once it expired or cannot be decrypted
+func (c *FileCache) Get(key string) (string, error) {
+	dir, err := c.dir()
+	if err != nil {
+		return "", err
+	}
+	sessionKey, err := c.sessionKey(dir, false)
+	if err != nil {
+		return "", err
+	}
+	defer clear(sessionKey)
+
+	path := filepath.Join(dir, entryFileName(sessionKey, key))
+	sealed, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", service.ErrCacheMiss
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached value: %w", err)
+	}
+
+	gcm, err := newGCM(sessionKey)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return discard(path)
+	}
+	data, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(key))
+	if err != nil {
+		return discard(path)
+	}
+	defer clear(data)
+
+	e, err := decodeEntry(data)
+	if err != nil {
+		return "", err
+	}
+	if e.expired(c.now()) {
+		return discard(path)
+	}
+	return e.Value, nil
+}
+
+// discard removes the cache file at path, whose value expired or cannot be decrypted, and
+// reports a cache miss, or why the file could not be removed
+func discard(path string) (string, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to remove unusable cached value: %w", err)
+	}
+	return "", service.ErrCacheMiss
+}
+
+// Delete removes the cached value of key, if there is one
+func (c *FileCache) Delete(key string) error {
+	dir, err := c.dir()
+	if err != nil {
+		return err
+	}
+	sessionKey, err := c.sessionKey(dir, false)
+	if errors.Is(err, service.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer clear(sessionKey)
+
+	err = os.Remove(filepath.Join(dir, entryFileName(sessionKey, key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove cached value: %w", err)
+	}
+	return nil
+}
+
+// DeleteAll removes the cache directory with every cached value and its key
+func (c *FileCache) DeleteAll() error {
+	dir, err := c.dir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove file cache: %w", err)
+	}
+	return nil
+}
+
+// dir returns the cache directory
+func (c *FileCache) dir() (string, error) {
+	if c.cfg.CacheDir != "" {
+		return c.cfg.CacheDir, nil
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return "", errNoRuntimeDir
+	}
+	return filepath.Join(runtimeDir, "lockify", config.CacheDirName), nil
+}
+
+// createDir creates the cache directory, readable only by the current user, and checks
+// that no one else can write to it
+func (c *FileCache) createDir() (string, error) {
+	dir, err := c.dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, os.FileMode(c.cfg.DirMode)); err != nil {
+		return "", fmt.Errorf("failed to create file cache directory: %w", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to check file cache directory: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf(
+			"file cache directory %s is accessible by other users (mode %s)",
+			dir,
+			info.Mode().Perm(),
+		)
+	}
+	return dir, nil
+}
+
+// sessionKey derives the key of the current login session from the key of the cache
+// directory, creating that key when create is set. Without it, the cache is empty.
+func (c *FileCache) sessionKey(dir string, create bool) ([]byte, error) {
+	path := filepath.Join(dir, fileCacheKeyName)
+	dirKey, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && create {
+		dirKey, err = c.createKey(path)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, service.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file cache key: %w", err)
+	}
+	defer clear(dirKey)
+	if len(dirKey) != fileCacheKeySize {
+		return nil, fmt.Errorf("file cache key %s is corrupted", path)
+	}
+
+	mac := hmac.New(sha256.New, dirKey)
+	mac.Write([]byte("lockify file cache\x00" + c.session()))
+	return mac.Sum(nil), nil
+}
+
+// createKey writes a new random key to path, or reads the key another lockify process
+// created first
+func (c *FileCache) createKey(path string) ([]byte, error) {
+	key := make([]byte, fileCacheKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate file cache key: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.FileMode(c.cfg.FileMode))
+	if errors.Is(err, os.ErrExist) {
+		clear(key)
+		return os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(key); err != nil {
+		//nolint:errcheck // The write error is the one to report
+		file.Close()
+		//nolint:errcheck // A partly written key is rejected by its size on the next read
+		os.Remove(path)
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		//nolint:errcheck // A partly written key is rejected by its size on the next read
+		os.Remove(path)
+		return nil, err
+	}
+	return key, nil
+}
+
+// writeFile writes data to a temporary file in dir and renames it to name, so readers never
+// see a partly written value
+func (c *FileCache) writeFile(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if err := tmp.Chmod(os.FileMode(c.cfg.FileMode)); err != nil && runtime.GOOS != "windows" {
+		//nolint:errcheck // The chmod error is the one to report
+		tmp.Close()
+		//nolint:errcheck // The file was only just created and holds nothing yet
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to restrict cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		//nolint:errcheck // The write error is the one to report
+		tmp.Close()
+		//nolint:errcheck // A leftover temporary file is never read as a cached value
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		//nolint:errcheck // A leftover temporary file is never read as a cached value
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, name)); err != nil {
+		//nolint:errcheck // A leftover temporary file is never read as a cached value
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
+
+// entryFileName names the file of key after a MAC of it, so the names of cached keys are
+// not visible and values of other sessions are kept apart
+func entryFileName(sessionKey []byte, key string) string {
+	mac := hmac.New(sha256.New, sessionKey)
+	mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil)) + fileCacheEntrySuffix
+}
+
+// newGCM returns AES-GCM keyed with key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// loginSession identifies the login session of the user: the systemd-logind session and
+// the boot it belongs to
+func loginSession() string {
+	bootID, err := os.ReadFile(bootIDPath)
+	if err != nil {
+		bootID = nil
+	}
+	return os.Getenv("XDG_SESSION_ID") + "\x00" + strings.TrimSpace(string(bootID))
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// newTestFileCache returns a file cache in a temp directory for session, with a clock the
+// test can move
+func newTestFileCache(t *testing.T, dir string, session string, now *time.Time) *FileCache {
+	t.Helper()
+	cfg := config.DefaultVaultConfig()
+	cfg.CacheDir = dir
+	cache := NewFileCache(cfg).(*FileCache)
+	cache.now = func() time.Time { return *now }
+	cache.session = func() string { return session }
+	return cache
+}
+
+func TestFileCache_SetGet(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), config.CacheDirName)
+	cache := newTestFileCache(t, dir, "session-1", &now)
+
+	if _, err := cache.Get("env:prod"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Fatalf("Get() of an empty cache = %v, want ErrCacheMiss", err)
+	}
+	if err := cache.Set("env:prod", "secret", 15*time.Minute); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+	if got, err := cache.Get("env:prod"); err != nil || got != "secret" {
+		t.Errorf("Get() = %q, %v, want the cached value", got, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read cache directory: %v", err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read cache file: %v", err)
+		}
+		if bytes.Contains(data, []byte("secret")) || strings.Contains(entry.Name(), "prod") {
+			t.Errorf("cache file %s holds the value or the key in plaintext", entry.Name())
+		}
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("failed to stat cache file: %v", err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+			t.Errorf("cache file %s mode = %v, want 0600", entry.Name(), info.Mode().Perm())
+		}
+	}
+
+	now = now.Add(15 * time.Minute)
+	if _, err := cache.Get("env:prod"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() after expiry = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestFileCache_ExpiredValueNotRemoved(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions do not keep this user from removing files")
+	}
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), config.CacheDirName)
+	cache := newTestFileCache(t, dir, "session-1", &now)
+
+	if err := cache.Set("env:prod", "secret", time.Minute); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+	if err := os.Chmod(dir, 0o500); err != nil {
+		t.Fatalf("failed to make cache directory read-only: %v", err)
+	}
+	t.Cleanup(func() {
+		//nolint:errcheck // Removing the temp directory reports any failure
+		os.Chmod(dir, 0o700)
+	})
+
+	now = now.Add(time.Minute)
+	_, err := cache.Get("env:prod")
+	if err == nil || errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() of an expired value that cannot be removed = %v, want an error", err)
+	}
+}
+
+func TestFileCache_BoundToSession(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), config.CacheDirName)
+	first := newTestFileCache(t, dir, "session-1", &now)
+	second := newTestFileCache(t, dir, "session-2", &now)
+
+	if err := first.Set("env:prod", "secret", 0); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+	if _, err := second.Get("env:prod"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() from another session = %v, want ErrCacheMiss", err)
+	}
+	if got, err := first.Get("env:prod"); err != nil || got != "secret" {
+		t.Errorf("Get() from the same session = %q, %v, want the cached value", got, err)
+	}
+}
+
+func TestFileCache_Delete(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), config.CacheDirName)
+	cache := newTestFileCache(t, dir, "session-1", &now)
+
+	if err := cache.Delete("env:prod"); err != nil {
+		t.Errorf("Delete() of an empty cache returned unexpected error: %v", err)
+	}
+	for _, key := range []string{"env:prod", "env:dev"} {
+		if err := cache.Set(key, "secret", 0); err != nil {
+			t.Fatalf("Set() returned unexpected error: %v", err)
+		}
+	}
+
+	if err := cache.Delete("env:prod"); err != nil {
+		t.Fatalf("Delete() returned unexpected error: %v", err)
+	}
+	if _, err := cache.Get("env:prod"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() after Delete() = %v, want ErrCacheMiss", err)
+	}
+	if _, err := cache.Get("env:dev"); err != nil {
+		t.Errorf("Delete() should keep the other values, Get() = %v", err)
+	}
+
+	if err := cache.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll() returned unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("DeleteAll() should remove the cache directory, got %v", err)
+	}
+}
+
+func TestFileCache_NeedsRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	cache := NewFileCache(config.DefaultVaultConfig())
+
+	if err := cache.Set("env:prod", "secret", 0); !errors.Is(err, errNoRuntimeDir) {
+		t.Errorf("Set() without $XDG_RUNTIME_DIR = %v, want errNoRuntimeDir", err)
+	}
+}
+
+func TestNew_Backend(t *testing.T) {
+	cfg := config.DefaultVaultConfig()
+	cfg.CacheBackendEnv = "LOCKIFY_TEST_CACHE"
+
+	tests := []struct {
+		backend string
+		check   func(service.Cache) bool
+	}{
+		{backend: "", check: func(c service.Cache) bool { _, ok := c.(*OSKeyring); return ok }},
+		{backend: "file", check: func(c service.Cache) bool { _, ok := c.(*FileCache); return ok }},
+		{backend: "none", check: func(c service.Cache) bool { _, ok := c.(*NoneCache); return ok }},
+	}
+	for _, tt := range tests {
+		t.Setenv("LOCKIFY_TEST_CACHE", tt.backend)
+		if cache := New(cfg, "lockify-test"); !tt.check(cache) {
+			t.Errorf("New() with backend %q = %T", tt.backend, cache)
+		}
+	}
+
+	t.Setenv("LOCKIFY_TEST_CACHE", "redis")
+	if _, err := New(cfg, "lockify-test").Get("env:prod"); err == nil {
+		t.Error("Get() of an unknown backend expected error, got nil")
+	}
+}
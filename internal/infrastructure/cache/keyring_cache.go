@@ -1,28 +1,76 @@
 package cache
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 	"github.com/zalando/go-keyring"
 )
 
+// keyringEntryPrefix marks keyring values stored with their expiry; values without it were
+// cached by older versions without one, so they are treated as expired
+const keyringEntryPrefix = "lockify-cache:"
+
 // OSKeyring implements Cache using the OS keyring
 type OSKeyring struct {
 	service string
+	now     func() time.Time
 }
 
 // NewOSKeyring creates a new OS keyring implementation
 func NewOSKeyring(s string) service.Cache {
-	return &OSKeyring{service: s}
+	return &OSKeyring{service: s, now: time.Now}
 }
 
-// Set stores a value in the keyring
-func (k *OSKeyring) Set(key, value string) error {
-	return keyring.Set(k.service, key, value)
+// Set stores a value in the keyring together with its expiry
+func (k *OSKeyring) Set(key, value string, ttl time.Duration) error {
+	e, err := newEntry(value, ttl, k.now())
+	if err != nil {
+		return err
+	}
+	data, err := e.encode()
+	if err != nil {
+		return err
+	}
+	return keyring.Set(k.service, key, keyringEntryPrefix+string(data))
 }
 
-// Get retrieves a value from the keyring
+// Get retrieves a value from the keyring, removing it once it expired or when it was stored
+// without an expiry
 func (k *OSKeyring) Get(key string) (string, error) {
-	return keyring.Get(k.service, key)
+	stored, err := keyring.Get(k.service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", service.ErrCacheMiss
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, ok := strings.CutPrefix(stored, keyringEntryPrefix)
+	if !ok {
+		return k.discard(key)
+	}
+	e, err := decodeEntry([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	if e.expired(k.now()) {
+		return k.discard(key)
+	}
+	return e.Value, nil
+}
+
+// discard removes the expired value of key from the keyring and reports a cache miss, or
+// why the value could not be removed
+func (k *OSKeyring) discard(key string) (string, error) {
+	err := keyring.Delete(k.service, key)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("failed to remove expired cached value: %w", err)
+	}
+	return "", service.ErrCacheMiss
 }
 
 // Delete removes a value from the keyring
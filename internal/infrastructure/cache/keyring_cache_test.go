@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/zalando/go-keyring"
+)
+
+func TestOSKeyring_TTL(t *testing.T) {
+	keyring.MockInit()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := &OSKeyring{service: "lockify-test", now: func() time.Time { return now }}
+
+	if err := cache.Set("env:prod", "secret", 15*time.Minute); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+	if err := cache.Set("env:dev", "forever", 0); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+
+	now = now.Add(14 * time.Minute)
+	if got, err := cache.Get("env:prod"); err != nil || got != "secret" {
+		t.Errorf("Get() before expiry = %q, %v, want the cached value", got, err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := cache.Get("env:prod"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() after expiry = %v, want ErrCacheMiss", err)
+	}
+	if _, err := keyring.Get("lockify-test", "env:prod"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("expired value should be removed from the keyring, got %v", err)
+	}
+	if got, err := cache.Get("env:dev"); err != nil || got != "forever" {
+		t.Errorf("Get() without ttl = %q, %v, want the cached value", got, err)
+	}
+}
+
+func TestOSKeyring_ExpiresValuesOfOlderVersions(t *testing.T) {
+	keyring.MockInit()
+	if err := keyring.Set("lockify-test", "env:prod", "plain-passphrase"); err != nil {
+		t.Fatalf("failed to set keyring value: %v", err)
+	}
+
+	_, err := NewOSKeyring("lockify-test").Get("env:prod")
+	if !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() of a value stored without expiry = %v, want ErrCacheMiss", err)
+	}
+	if _, err := keyring.Get("lockify-test", "env:prod"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("value stored without expiry should be removed from the keyring, got %v", err)
+	}
+	if _, err := NewOSKeyring("lockify-test").Get("env:qa"); !errors.Is(err, service.ErrCacheMiss) {
+		t.Errorf("Get() of a missing key = %v, want ErrCacheMiss", err)
+	}
+}
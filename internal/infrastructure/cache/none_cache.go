@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// NoneCache implements Cache without storing anything, for machines that should never keep
+// passphrases
+type NoneCache struct{}
+
+// NewNoneCache creates a cache that stores nothing
+func NewNoneCache() service.Cache {
+	return &NoneCache{}
+}
+
+// Set discards the value
+func (c *NoneCache) Set(key, value string, ttl time.Duration) error {
+	return nil
+}
+
+// Get always misses
+func (c *NoneCache) Get(key string) (string, error) {
+	return "", service.ErrCacheMiss
+}
+
+// Delete has nothing to remove
+func (c *NoneCache) Delete(key string) error {
+	return nil
+}
+
+// DeleteAll has nothing to remove
+func (c *NoneCache) DeleteAll() error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package fs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+)
+
+// writeFileAtomic writes data to a temporary file next to path, flushes it to disk and
+// renames it over path, so that path holds either its old or its new content even if
+// lockify is interrupted
+func writeFileAtomic(fs storage.FileSystem, path string, data []byte, perm uint32) error {
+	tmp, err := fs.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*", perm)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		//nolint:errcheck // The write error is the one to report
+		tmp.Close()
+		//nolint:errcheck // A leftover temporary file does not affect path
+		fs.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		//nolint:errcheck // The sync error is the one to report
+		tmp.Close()
+		//nolint:errcheck // A leftover temporary file does not affect path
+		fs.Remove(tmpPath)
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		//nolint:errcheck // A leftover temporary file does not affect path
+		fs.Remove(tmpPath)
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := fs.Rename(tmpPath, path); err != nil {
+		//nolint:errcheck // A leftover temporary file does not affect path
+		fs.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
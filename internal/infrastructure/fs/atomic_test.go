@@ -0,0 +1,50 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// renameFailingFileSystem is the OS filesystem with a Rename that always fails
+type renameFailingFileSystem struct {
+	OSFileSystem
+}
+
+func (f *renameFailingFileSystem) Rename(oldPath, newPath string) error {
+	return errors.New("rename failed")
+}
+
+func TestWriteFileAtomic_Success(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err := writeFileAtomic(NewOSFileSystem(), path, []byte("new"), 0o600); err != nil {
+		t.Fatalf("writeFileAtomic() returned unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("file content = %q, want %q", data, "new")
+	}
+}
+
+func TestWriteFileAtomic_RenameFailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	err := writeFileAtomic(&renameFailingFileSystem{}, path, []byte("new"), 0o600)
+	if err == nil {
+		t.Fatal("writeFileAtomic() with a failing rename expected error, got nil")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("file content = %q, want the original %q", data, "old")
+	}
+	if names, _ := os.ReadDir(dir); len(names) != 1 {
+		t.Errorf("writeFileAtomic() should remove its temporary file, found %d files", len(names))
+	}
+}
@@ -0,0 +1,149 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+)
+
+// FileAuditRepository implements AuditRepository with one record per line in a file next to
+// the vault
+type FileAuditRepository struct {
+	fs  storage.FileSystem
+	cfg config.VaultConfig
+}
+
+// NewFileAuditRepository creates a new file-based audit repository
+func NewFileAuditRepository(
+	fs storage.FileSystem,
+	cfg config.VaultConfig,
+) repository.AuditRepository {
+	return &FileAuditRepository{fs, cfg}
+}
+
+// Load reads the records of the audit log of an environment
+func (repo *FileAuditRepository) Load(ctx context.Context, env string) ([]string, error) {
+	if env == "" {
+		return nil, fmt.Errorf("environment cannot be empty")
+	}
+	return repo.read(repo.cfg.GetAuditPath(env))
+}
+
+// Append seals a new record from the newest one and appends it while holding the lock of
+// the audit log, so that records appended by parallel processes still form one chain
+func (repo *FileAuditRepository) Append(
+	ctx context.Context,
+	env string,
+	seal func(last string, count int) (string, error),
+) error {
+	if env == "" {
+		return fmt.Errorf("environment cannot be empty")
+	}
+
+	auditPath := repo.cfg.GetAuditPath(env)
+	release, err := repo.lock(ctx, auditPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	records, err := repo.read(auditPath)
+	if err != nil {
+		return err
+	}
+	last := ""
+	if len(records) > 0 {
+		last = records[len(records)-1]
+	}
+
+	record, err := seal(last, len(records))
+	if err != nil {
+		return err
+	}
+	if err := repo.fs.AppendFile(auditPath, []byte(record+"\n"), repo.cfg.FileMode); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Replace atomically rewrites the audit log of an environment
+func (repo *FileAuditRepository) Replace(ctx context.Context, env string, records []string) error {
+	if env == "" {
+		return fmt.Errorf("environment cannot be empty")
+	}
+
+	auditPath := repo.cfg.GetAuditPath(env)
+	release, err := repo.lock(ctx, auditPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if len(records) == 0 {
+		if err := repo.fs.Remove(auditPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove audit log: %w", err)
+		}
+		return nil
+	}
+
+	data := []byte(strings.Join(records, "\n") + "\n")
+	if err := writeFileAtomic(repo.fs, auditPath, data, repo.cfg.FileMode); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// read returns the records of the audit log at path, or none when it does not exist
+func (repo *FileAuditRepository) read(path string) ([]string, error) {
+	data, err := repo.fs.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var records []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			records = append(records, line)
+		}
+	}
+	return records, nil
+}
+
+// lock takes the exclusive lock of the audit log at path
+func (repo *FileAuditRepository) lock(ctx context.Context, path string) (func(), error) {
+	timeout, ok := repository.LockTimeout(ctx)
+	if !ok {
+		timeout = repo.cfg.LockTimeout
+	}
+
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := repo.fs.MkdirAll(dir, repo.cfg.DirMode); err != nil {
+			return nil, fmt.Errorf("failed to create vault directory: %w", err)
+		}
+	}
+
+	unlock, err := repo.fs.Lock(path+config.LockFileSuffix, true, repo.cfg.FileMode, timeout)
+	if errors.Is(err, storage.ErrLockTimeout) {
+		return nil, fmt.Errorf(
+			"audit log %s is in use by another lockify process (waited %s): %w",
+			path,
+			timeout,
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock audit log %s: %w", path, err)
+	}
+
+	return func() { unlock() }, nil
+}
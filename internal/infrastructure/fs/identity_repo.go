@@ -0,0 +1,128 @@
+package fs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+)
+
+// publicKeyComment introduces the public key line of an identity file, which is only there
+// for people to read
+const publicKeyComment = "# public key: "
+
+// FileIdentityRepository implements IdentityRepository using a file in the user config directory
+type FileIdentityRepository struct {
+	fs                storage.FileSystem
+	encryptionService service.EncryptionService
+	cfg               config.VaultConfig
+}
+
+// NewFileIdentityRepository creates a new file-based identity repository; encryptionService
+// derives the public key of the identity it loads
+func NewFileIdentityRepository(
+	fs storage.FileSystem,
+	encryptionService service.EncryptionService,
+	cfg config.VaultConfig,
+) repository.IdentityRepository {
+	return &FileIdentityRepository{fs, encryptionService, cfg}
+}
+
+// Load reads the identity file and derives its public key from the private key, so an
+// edited public key comment cannot make it disagree with the key that unwraps vaults
+func (repo *FileIdentityRepository) Load(ctx context.Context) (model.Identity, error) {
+	path, err := repo.path()
+	if err != nil {
+		return model.Identity{}, err
+	}
+
+	data, err := repo.fs.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.Identity{}, model.ErrNoIdentity
+		}
+		return model.Identity{}, fmt.Errorf("failed to read identity file: %w", err)
+	}
+
+	var identity model.Identity
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if identity.PrivateKey != "" {
+			return model.Identity{}, fmt.Errorf("identity file %s holds more than one key", path)
+		}
+		identity.PrivateKey = line
+	}
+
+	if identity.PrivateKey == "" {
+		return model.Identity{}, fmt.Errorf("identity file %s is incomplete", path)
+	}
+	identity.PublicKey, err = repo.encryptionService.IdentityPublicKey(identity.PrivateKey)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("identity file %s: %w", path, err)
+	}
+	return identity, nil
+}
+
+// Save writes the identity file, readable only by the current user
+func (repo *FileIdentityRepository) Save(
+	ctx context.Context,
+	identity model.Identity,
+	overwrite bool,
+) (string, error) {
+	path, err := repo.path()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := repo.fs.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("identity already exists at %s", path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to check identity file: %w", err)
+	}
+
+	if err := repo.fs.MkdirAll(filepath.Dir(path), repo.cfg.DirMode); err != nil {
+		return "", fmt.Errorf("failed to create identity directory: %w", err)
+	}
+
+	var data strings.Builder
+	fmt.Fprintf(&data, "# created: %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&data, "%s%s\n", publicKeyComment, identity.PublicKey)
+	fmt.Fprintf(&data, "%s\n", identity.PrivateKey)
+
+	if err := writeFileAtomic(repo.fs, path, []byte(data.String()), repo.cfg.FileMode); err != nil {
+		return "", fmt.Errorf("failed to write identity file: %w", err)
+	}
+
+	return path, nil
+}
+
+// path returns the identity file path from the environment, the config or the user
+// config directory, in that order
+func (repo *FileIdentityRepository) path() (string, error) {
+	if path := os.Getenv(repo.cfg.IdentityEnv); repo.cfg.IdentityEnv != "" && path != "" {
+		return path, nil
+	}
+	if repo.cfg.IdentityFile != "" {
+		return repo.cfg.IdentityFile, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate identity file: %w", err)
+	}
+	return filepath.Join(dir, config.IdentityFileName), nil
+}
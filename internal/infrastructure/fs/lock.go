@@ -0,0 +1,51 @@
+package fs
+
+import (
+	"os"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+)
+
+// lockRetryInterval is how long Lock waits between attempts to take a busy lock
+const lockRetryInterval = 50 * time.Millisecond
+
+// Lock takes an advisory lock on path, retrying until timeout while another process holds it
+func (f *OSFileSystem) Lock(
+	path string,
+	exclusive bool,
+	perm uint32,
+	timeout time.Duration,
+) (func() error, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.FileMode(perm))
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(file, exclusive)
+		if err != nil {
+			//nolint:errcheck // The locking error is the one to report
+			file.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			//nolint:errcheck // The file holds no lock, so there is nothing to release
+			file.Close()
+			return nil, storage.ErrLockTimeout
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	return func() error {
+		unlockErr := unlockFile(file)
+		if err := file.Close(); err != nil && unlockErr == nil {
+			return err
+		}
+		return unlockErr
+	}, nil
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package fs
+
+import (
+	"errors"
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile takes a LockFileEx lock on file without blocking and reports whether it
+// succeeded
+func tryLockFile(file *os.File, exclusive bool) (bool, error) {
+	flags := uint32(windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	err := windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		flags,
+		0,
+		math.MaxUint32,
+		math.MaxUint32,
+		new(windows.Overlapped),
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the LockFileEx lock on file
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(
+		windows.Handle(file.Fd()),
+		0,
+		math.MaxUint32,
+		math.MaxUint32,
+		new(windows.Overlapped),
+	)
+}
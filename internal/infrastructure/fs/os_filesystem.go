@@ -2,6 +2,8 @@ package fs
 
 import (
 	"os"
+	"path/filepath"
+	"runtime"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
 )
@@ -24,6 +26,25 @@ func (f *OSFileSystem) WriteFile(path string, data []byte, perm uint32) error {
 	return os.WriteFile(path, data, os.FileMode(perm))
 }
 
+// AppendFile appends data to a file, creating it if needed, and syncs it
+func (f *OSFileSystem) AppendFile(path string, data []byte, perm uint32) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(perm))
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		//nolint:errcheck // The write error is the one to report
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		//nolint:errcheck // The sync error is the one to report
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 // ReadFile reads a file
 func (f *OSFileSystem) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
@@ -38,6 +59,63 @@ func (f *OSFileSystem) Stat(path string) (storage.FileInfo, error) {
 	return &fileInfo{info}, nil
 }
 
+// ReadDir returns the names of the entries in a directory
+func (f *OSFileSystem) ReadDir(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// CreateTemp creates a new file with the given permissions in dir
+func (f *OSFileSystem) CreateTemp(dir, pattern string, perm uint32) (storage.File, error) {
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Chmod(os.FileMode(perm)); err != nil {
+		//nolint:errcheck // The chmod error is the one to report
+		file.Close()
+		//nolint:errcheck // The file was only just created and holds nothing yet
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// Rename replaces newPath with oldPath, then syncs the parent directory so that the
+// rename survives a crash
+func (f *OSFileSystem) Rename(oldPath, newPath string) error {
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+	// Directories cannot be opened for syncing on Windows, where renames are durable.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(filepath.Dir(newPath))
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		//nolint:errcheck // The sync error is the one to report
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+// Remove removes a file
+func (f *OSFileSystem) Remove(path string) error {
+	return os.Remove(path)
+}
+
 // fileInfo wraps os.FileInfo
 type fileInfo struct {
 	info os.FileInfo
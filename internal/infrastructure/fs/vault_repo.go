@@ -1,11 +1,18 @@
 package fs
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/config"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
@@ -40,6 +47,12 @@ func (repo *FileVaultRepository) Create(ctx context.Context, vault *model.Vault)
 		return fmt.Errorf("failed to create vault directory: %w", err)
 	}
 
+	release, err := repo.Lock(ctx, vault.Meta.Env, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	exists, err := repo.Exists(ctx, vault.Meta.Env)
 	if err != nil {
 		return fmt.Errorf("failed to check vault existence: %w", err)
@@ -67,22 +80,14 @@ func (repo *FileVaultRepository) Load(ctx context.Context, env string) (*model.V
 		return nil, fmt.Errorf("failed to read vault file: %w", err)
 	}
 
-	var vault model.Vault
-	if err := json.Unmarshal(data, &vault); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal vault: %w", err)
+	vault, err := decodeVault(data, env)
+	if err != nil {
+		return nil, err
 	}
-
 	vault.SetPath(vaultPath)
+	vault.SetFileDigest(fileDigest(data))
 
-	if vault.Meta.Env != env {
-		return nil, fmt.Errorf(
-			"vault environment mismatch: expected %q, got %q",
-			env,
-			vault.Meta.Env,
-		)
-	}
-
-	return &vault, nil
+	return vault, nil
 }
 
 // Save saves a vault to the filesystem
@@ -110,9 +115,28 @@ func (repo *FileVaultRepository) Save(ctx context.Context, vault *model.Vault) e
 		return fmt.Errorf("failed to marshal vault: %w", err)
 	}
 
-	if err := repo.fs.WriteFile(vaultPath, data, repo.cfg.FileMode); err != nil {
+	current, err := repo.readIfExists(vaultPath)
+	if err != nil {
+		return fmt.Errorf("failed to read vault file: %w", err)
+	}
+	if fileDigest(current) != vault.FileDigest() {
+		return fmt.Errorf(
+			"refusing to overwrite vault for environment %q: %w",
+			vault.Meta.Env,
+			repository.ErrVaultChanged,
+		)
+	}
+
+	if generations := repo.backupGenerations(); generations > 0 {
+		if _, err := repo.pushBackup(vaultPath, current, generations); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFileAtomic(repo.fs, vaultPath, data, repo.cfg.FileMode); err != nil {
 		return fmt.Errorf("failed to write vault file: %w", err)
 	}
+	vault.SetFileDigest(fileDigest(data))
 
 	return nil
 }
@@ -133,3 +157,257 @@ func (repo *FileVaultRepository) Exists(ctx context.Context, env string) (bool,
 	}
 	return true, nil
 }
+
+// List returns the environments that have a vault in the base directory
+func (repo *FileVaultRepository) List(ctx context.Context) ([]string, error) {
+	dir := repo.cfg.BaseDir
+	if dir == "" {
+		dir = "."
+	}
+
+	names, err := repo.fs.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read vault directory: %w", err)
+	}
+
+	envs := make([]string, 0, len(names))
+	for _, name := range names {
+		env, ok := strings.CutSuffix(name, config.VaultFileSuffix)
+		if ok && env != "" {
+			envs = append(envs, env)
+		}
+	}
+	sort.Strings(envs)
+
+	return envs, nil
+}
+
+// Backup copies the current vault file of an environment into the newest backup generation,
+// keeping at least one generation even when rolling backups are disabled
+func (repo *FileVaultRepository) Backup(ctx context.Context, env string) (string, error) {
+	if env == "" {
+		return "", fmt.Errorf("environment cannot be empty")
+	}
+
+	vaultPath := repo.cfg.GetVaultPath(env)
+	current, err := repo.fs.ReadFile(vaultPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault file: %w", err)
+	}
+
+	return repo.pushBackup(vaultPath, current, max(repo.backupGenerations(), 1))
+}
+
+// Restore replaces the vault of an environment with one of its backup generations. The
+// current vault file becomes the newest backup, so a restore can itself be undone.
+func (repo *FileVaultRepository) Restore(
+	ctx context.Context,
+	env string,
+	generation int,
+) (string, error) {
+	if env == "" {
+		return "", fmt.Errorf("environment cannot be empty")
+	}
+	if generation < 1 {
+		return "", fmt.Errorf("backup generation must be at least 1, got %d", generation)
+	}
+
+	release, err := repo.Lock(ctx, env, true)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	vaultPath := repo.cfg.GetVaultPath(env)
+	restorePath := backupPath(vaultPath, generation)
+	data, err := repo.fs.ReadFile(restorePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf(
+				"backup generation %d not found for environment %q",
+				generation,
+				env,
+			)
+		}
+		return "", fmt.Errorf("failed to read vault backup: %w", err)
+	}
+	if _, err := decodeVault(data, env); err != nil {
+		return "", fmt.Errorf("backup %s is not a valid vault: %w", restorePath, err)
+	}
+
+	current, err := repo.readIfExists(vaultPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault file: %w", err)
+	}
+	if _, err := repo.pushBackup(vaultPath, current, max(repo.backupGenerations(), 1)); err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(repo.fs, vaultPath, data, repo.cfg.FileMode); err != nil {
+		return "", fmt.Errorf("failed to write vault file: %w", err)
+	}
+
+	return restorePath, nil
+}
+
+// Delete removes the vault file of an environment, keeping it as the newest backup so that
+// "lockify restore" can bring it back
+func (repo *FileVaultRepository) Delete(ctx context.Context, env string) (string, error) {
+	if env == "" {
+		return "", fmt.Errorf("environment cannot be empty")
+	}
+
+	vaultPath := repo.cfg.GetVaultPath(env)
+	current, err := repo.fs.ReadFile(vaultPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("vault not found for environment %q: %w", env, err)
+		}
+		return "", fmt.Errorf("failed to read vault file: %w", err)
+	}
+
+	backup, err := repo.pushBackup(vaultPath, current, max(repo.backupGenerations(), 1))
+	if err != nil {
+		return "", err
+	}
+	if err := repo.fs.Remove(vaultPath); err != nil {
+		return "", fmt.Errorf("failed to remove vault file: %w", err)
+	}
+
+	return backup, nil
+}
+
+// pushBackup stores current, the content of the vault file at vaultPath, as backup
+// generation 1, shifting older generations up and dropping those beyond generations. Nothing
+// is done when there is no vault file yet or the newest backup already holds the same content.
+// Backups are exact copies, so the backup of a vault older than model.FormatVersionKeyCheck
+// keeps its bcrypt fingerprint until it is dropped.
+func (repo *FileVaultRepository) pushBackup(
+	vaultPath string,
+	current []byte,
+	generations int,
+) (string, error) {
+	if current == nil {
+		return "", nil
+	}
+
+	newest := backupPath(vaultPath, 1)
+	if previous, err := repo.fs.ReadFile(newest); err == nil && bytes.Equal(previous, current) {
+		return newest, nil
+	}
+
+	oldest := backupPath(vaultPath, generations)
+	if err := repo.fs.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to remove oldest vault backup: %w", err)
+	}
+	for generation := generations - 1; generation >= 1; generation-- {
+		err := repo.fs.Rename(
+			backupPath(vaultPath, generation),
+			backupPath(vaultPath, generation+1),
+		)
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to rotate vault backups: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(repo.fs, newest, current, repo.cfg.FileMode); err != nil {
+		return "", fmt.Errorf("failed to write vault backup: %w", err)
+	}
+
+	return newest, nil
+}
+
+// Lock takes an advisory lock on the lock file of the vault of an environment. The vault file
+// itself is replaced on every save, so it cannot carry the lock.
+func (repo *FileVaultRepository) Lock(
+	ctx context.Context,
+	env string,
+	exclusive bool,
+) (func(), error) {
+	if env == "" {
+		return nil, fmt.Errorf("environment cannot be empty")
+	}
+
+	timeout, ok := repository.LockTimeout(ctx)
+	if !ok {
+		timeout = repo.cfg.LockTimeout
+	}
+
+	vaultPath := repo.cfg.GetVaultPath(env)
+	if dir := filepath.Dir(vaultPath); dir != "." && dir != "" {
+		if err := repo.fs.MkdirAll(dir, repo.cfg.DirMode); err != nil {
+			return nil, fmt.Errorf("failed to create vault directory: %w", err)
+		}
+	}
+
+	lockPath := vaultPath + config.LockFileSuffix
+	unlock, err := repo.fs.Lock(lockPath, exclusive, repo.cfg.FileMode, timeout)
+	if errors.Is(err, storage.ErrLockTimeout) {
+		return nil, fmt.Errorf(
+			"vault for environment %q is in use by another lockify process (waited %s): %w",
+			env,
+			timeout,
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock vault for environment %q: %w", env, err)
+	}
+
+	return func() { unlock() }, nil
+}
+
+// readIfExists reads a file, returning nil without error when it does not exist
+func (repo *FileVaultRepository) readIfExists(path string) ([]byte, error) {
+	data, err := repo.fs.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// fileDigest returns the digest of a stored vault file, or an empty string when there is none
+func fileDigest(data []byte) string {
+	if data == nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// backupGenerations returns the number of backups to keep, from the environment or config
+func (repo *FileVaultRepository) backupGenerations() int {
+	if repo.cfg.BackupGenerationsEnv != "" {
+		if value := os.Getenv(repo.cfg.BackupGenerationsEnv); value != "" {
+			if generations, err := strconv.Atoi(value); err == nil && generations >= 0 {
+				return generations
+			}
+		}
+	}
+	return repo.cfg.BackupGenerations
+}
+
+// backupPath returns the path of a backup generation of the vault file at vaultPath
+func backupPath(vaultPath string, generation int) string {
+	return fmt.Sprintf("%s%s.%d", vaultPath, config.BackupFileSuffix, generation)
+}
+
+// decodeVault parses a vault file and checks that it belongs to env
+func decodeVault(data []byte, env string) (*model.Vault, error) {
+	var vault model.Vault
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vault: %w", err)
+	}
+
+	if vault.Meta.Env != env {
+		return nil, fmt.Errorf(
+			"vault environment mismatch: expected %q, got %q",
+			env,
+			vault.Meta.Env,
+		)
+	}
+
+	return &vault, nil
+}
@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/repository"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/storage"
+)
+
+const envTest = "test"
+
+// newTestVaultRepo returns a vault repository in a temp directory that keeps generations
+// backups
+func newTestVaultRepo(t *testing.T, generations int) (*FileVaultRepository, config.VaultConfig) {
+	t.Helper()
+	cfg := config.DefaultVaultConfig()
+	cfg.BaseDir = t.TempDir()
+	cfg.BackupGenerations = generations
+	cfg.BackupGenerationsEnv = ""
+	return NewFileVaultRepository(NewOSFileSystem(), cfg).(*FileVaultRepository), cfg
+}
+
+// saveVersions creates the vault of envTest and saves it once more for each further version,
+// storing the version as the value of its "version" entry
+func saveVersions(t *testing.T, repo *FileVaultRepository, versions int) {
+	t.Helper()
+	ctx := context.Background()
+	vault, _ := model.NewVault(envTest, "salt")
+	vault.SetEntry("version", "1")
+	if err := repo.Create(ctx, vault); err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+	for version := 2; version <= versions; version++ {
+		vault.SetEntry("version", fmt.Sprint(version))
+		if err := repo.Save(ctx, vault); err != nil {
+			t.Fatalf("Save() returned unexpected error: %v", err)
+		}
+	}
+}
+
+// storedVersion returns the "version" entry of the vault file at path
+func storedVersion(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	vault, err := decodeVault(data, envTest)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", path, err)
+	}
+	return vault.Entries["version"].Value
+}
+
+func TestFileVaultRepository_Save_RotatesBackups(t *testing.T) {
+	repo, cfg := newTestVaultRepo(t, 2)
+	saveVersions(t, repo, 4)
+
+	vaultPath := cfg.GetVaultPath(envTest)
+	if got := storedVersion(t, vaultPath); got != "4" {
+		t.Errorf("vault holds version %s, want 4", got)
+	}
+	if got := storedVersion(t, backupPath(vaultPath, 1)); got != "3" {
+		t.Errorf("backup generation 1 holds version %s, want 3", got)
+	}
+	if got := storedVersion(t, backupPath(vaultPath, 2)); got != "2" {
+		t.Errorf("backup generation 2 holds version %s, want 2", got)
+	}
+	if _, err := os.Stat(backupPath(vaultPath, 3)); !os.IsNotExist(err) {
+		t.Errorf("Save() should drop backups beyond 2 generations, stat error: %v", err)
+	}
+}
+
+func TestFileVaultRepository_Restore_Generation(t *testing.T) {
+	repo, cfg := newTestVaultRepo(t, 3)
+	saveVersions(t, repo, 4)
+
+	vaultPath := cfg.GetVaultPath(envTest)
+	restored, err := repo.Restore(context.Background(), envTest, 2)
+	if err != nil {
+		t.Fatalf("Restore() returned unexpected error: %v", err)
+	}
+	if restored != backupPath(vaultPath, 2) {
+		t.Errorf("Restore() = %q, want %q", restored, backupPath(vaultPath, 2))
+	}
+	if got := storedVersion(t, vaultPath); got != "2" {
+		t.Errorf("vault holds version %s after restoring generation 2, want 2", got)
+	}
+	if got := storedVersion(t, backupPath(vaultPath, 1)); got != "4" {
+		t.Errorf("backup generation 1 holds version %s, want the replaced version 4", got)
+	}
+}
+
+func TestFileVaultRepository_Restore_MissingGeneration(t *testing.T) {
+	repo, cfg := newTestVaultRepo(t, 3)
+	saveVersions(t, repo, 2)
+
+	_, err := repo.Restore(context.Background(), envTest, 5)
+	if err == nil {
+		t.Fatal("Restore() of a missing generation expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "backup generation 5 not found") {
+		t.Errorf("Restore() error = %v, want backup generation 5 not found", err)
+	}
+	if got := storedVersion(t, cfg.GetVaultPath(envTest)); got != "2" {
+		t.Errorf("vault holds version %s after a failed restore, want 2", got)
+	}
+}
+
+func TestFileVaultRepository_Lock_TimesOut(t *testing.T) {
+	repo, _ := newTestVaultRepo(t, 0)
+	repo.cfg.LockTimeout = 200 * time.Millisecond
+	ctx := context.Background()
+
+	release, err := repo.Lock(ctx, envTest, true)
+	if err != nil {
+		t.Fatalf("first Lock() returned unexpected error: %v", err)
+	}
+
+	started := time.Now()
+	_, err = repo.Lock(ctx, envTest, true)
+	if !errors.Is(err, storage.ErrLockTimeout) {
+		t.Fatalf("second Lock() error = %v, want ErrLockTimeout", err)
+	}
+	if waited := time.Since(started); waited < repo.cfg.LockTimeout {
+		t.Errorf("second Lock() gave up after %s, want at least %s", waited, repo.cfg.LockTimeout)
+	}
+
+	release()
+	releaseAgain, err := repo.Lock(ctx, envTest, true)
+	if err != nil {
+		t.Fatalf("Lock() after release returned unexpected error: %v", err)
+	}
+	releaseAgain()
+}
+
+func TestFileVaultRepository_Save_VaultChanged(t *testing.T) {
+	repo, cfg := newTestVaultRepo(t, 0)
+	saveVersions(t, repo, 1)
+	ctx := context.Background()
+
+	vault, err := repo.Load(ctx, envTest)
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	other, _ := repo.Load(ctx, envTest)
+	other.SetEntry("version", "2")
+	if err := repo.Save(ctx, other); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+
+	vault.SetEntry("version", "3")
+	err = repo.Save(ctx, vault)
+	if !errors.Is(err, repository.ErrVaultChanged) {
+		t.Fatalf("Save() of a stale vault error = %v, want ErrVaultChanged", err)
+	}
+	if got := storedVersion(t, cfg.GetVaultPath(envTest)); got != "2" {
+		t.Errorf("vault holds version %s, want the concurrent version 2", got)
+	}
+}
@@ -0,0 +1,62 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// GitAuthor implements AuthorService with the git identity of the user, falling back to
+// the login name
+type GitAuthor struct {
+	once   sync.Once
+	author string
+}
+
+// NewGitAuthor creates a new author service
+func NewGitAuthor() service.AuthorService {
+	return &GitAuthor{}
+}
+
+// Author returns "Name <email>" from git config, or $USER when git has no user name. The
+// result is looked up once per process.
+func (a *GitAuthor) Author() string {
+	a.once.Do(func() {
+		a.author = lookupAuthor()
+	})
+	return a.author
+}
+
+// Host returns the host name reported by the operating system
+func (a *GitAuthor) Host() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+func lookupAuthor() string {
+	if name := gitConfig("user.name"); name != "" {
+		if email := gitConfig("user.email"); email != "" {
+			return name + " <" + email + ">"
+		}
+		return name
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return os.Getenv("USERNAME")
+}
+
+// gitConfig returns a git config value, or "" when git is missing or the value is unset
+func gitConfig(name string) string {
+	output, err := exec.Command("git", "config", "--get", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
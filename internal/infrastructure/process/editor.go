@@ -0,0 +1,130 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// Editor implements EditorService by running $VISUAL or $EDITOR on a private temp file
+type Editor struct{}
+
+// NewEditor creates a new editor service
+func NewEditor() service.EditorService {
+	return &Editor{}
+}
+
+// Edit writes content to a 0600 temp file, preferably on tmpfs, opens it in the editor and
+// returns what the editor saved. The file is overwritten and removed before Edit returns,
+// also when the editor fails, lockify receives a signal or a panic unwinds through Edit.
+func (e *Editor) Edit(name string, content []byte) ([]byte, error) {
+	file, err := createPrivateTemp("lockify-*-" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := file.Name()
+	defer wipeFile(path)
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		return nil, err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return edited, nil
+}
+
+// runEditor runs the editor on path. Signals are relayed to the editor instead of
+// terminating lockify, so that the temp file is always wiped afterwards.
+func runEditor(path string) error {
+	command := editorCommand()
+	cmd := exec.Command(command[0], append(command[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start editor %s: %w", command[0], err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				//nolint:errcheck // The editor may already have exited
+				cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", command[0], err)
+	}
+	return nil
+}
+
+// editorCommand returns the editor to run, taken from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if command := strings.Fields(os.Getenv(name)); len(command) > 0 {
+			return command
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// createPrivateTemp creates a temp file only the current user can read, preferring
+// memory-backed directories so that the plaintext never reaches a disk
+func createPrivateTemp(pattern string) (*os.File, error) {
+	for _, dir := range []string{os.Getenv("XDG_RUNTIME_DIR"), "/dev/shm"} {
+		if dir == "" {
+			continue
+		}
+		if file, err := os.CreateTemp(dir, pattern); err == nil {
+			return file, nil
+		}
+	}
+	return os.CreateTemp("", pattern)
+}
+
+// wipeFile overwrites a file with zeros before removing it
+func wipeFile(path string) {
+	if info, err := os.Stat(path); err == nil {
+		if file, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			//nolint:errcheck // Removing the file below is all that is left to try
+			file.Write(make([]byte, info.Size()))
+			//nolint:errcheck // Removing the file below is all that is left to try
+			file.Sync()
+			//nolint:errcheck // Removing the file below is all that is left to try
+			file.Close()
+		}
+	}
+	//nolint:errcheck // The file may never have been created
+	os.Remove(path)
+}
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// ExecRunner implements ProcessRunner using os/exec
+type ExecRunner struct{}
+
+// NewExecRunner creates a new process runner
+func NewExecRunner() service.ProcessRunner {
+	return &ExecRunner{}
+}
+
+// Run starts the process, relays signals to it until it exits and returns its exit code
+func (r *ExecRunner) Run(ctx context.Context, name string, args, env []string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Subscribe before starting so that a signal can never terminate lockify and orphan
+	// the child.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				//nolint:errcheck // The process may already have exited
+				cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return 0, fmt.Errorf("failed to wait for %s: %w", name, err)
+	}
+
+	return exitCode(cmd.ProcessState), nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package process
+
+import "os"
+
+// forwardedSignals are relayed to the child process. The console already delivers Ctrl+C
+// to the child, so lockify only has to survive it.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// exitCode returns the exit code of the process
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -50,3 +50,14 @@ func (p *Service) GetPassphraseInput(message string) (string, error) {
 	}
 	return passphrase, nil
 }
+
+// Confirm asks the user a yes/no question, defaulting to no.
+func (p *Service) Confirm(message string) (bool, error) {
+	var confirmed bool
+	prompt := &survey.Confirm{Message: message}
+	err := survey.AskOne(prompt, &confirmed)
+	if err != nil {
+		return false, fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	return confirmed, nil
+}
@@ -3,17 +3,45 @@ package security
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"runtime"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 	"golang.org/x/crypto/argon2"
 )
 
+const (
+	// aes256KeyLength is the key length in bytes required by AES-256.
+	aes256KeyLength uint32 = 32
+	// maxArgonTime bounds the Argon2id passes a vault header may ask for. The header is
+	// checked before its MAC, so without a bound an edited header could make unlocking spin.
+	maxArgonTime uint32 = 64
+	// maxArgonMemoryKB bounds the Argon2id memory a vault header may ask for, 4 GiB.
+	maxArgonMemoryKB uint32 = 4 * 1024 * 1024
+	// aadPrefix is the domain separator at the start of every entry's associated data.
+	aadPrefix = "lockify-entry"
+	// aadFieldHeader is the size in bytes of each fixed-width field in the associated data.
+	aadFieldHeader = 4
+	// macKeyInfo separates the vault MAC key from the encryption key in HKDF.
+	macKeyInfo = "lockify-vault-mac"
+	// recordAADPrefix is the domain separator at the start of the associated data of records
+	// kept beside the entries. It differs from aadPrefix, so no entry key can produce it.
+	recordAADPrefix = "lockify-record"
+	// dataKeyAADPrefix is the domain separator at the start of the wrapped data key's
+	// associated data.
+	dataKeyAADPrefix = "lockify-data-key"
+)
+
 // AESEncryptionService implements domain.EncryptionService using AES-GCM encryption.
 type AESEncryptionService struct {
 	cfg config.EncryptionConfig
@@ -24,59 +52,169 @@ func NewAESEncryptionService(cfg config.EncryptionConfig) service.EncryptionServ
 	return &AESEncryptionService{cfg}
 }
 
-func (e *AESEncryptionService) getAEAD(encodedSalt, passphrase string) (cipher.AEAD, error) {
-	if encodedSalt == "" {
-		return nil, fmt.Errorf("salt cannot be empty")
+// DefaultParams returns the cipher and KDF parameters from the encryption config
+func (e *AESEncryptionService) DefaultParams() (model.CipherParams, model.KDFParams) {
+	return model.CipherParams{
+		Algorithm: model.CipherAES256GCM,
+		NonceSize: e.cfg.NonceSize,
+	}, model.KDFParams{
+		Algorithm: model.KDFArgon2id,
+		Time:      e.cfg.ArgonTime,
+		MemoryKB:  e.cfg.ArgonMemory,
+		Threads:   e.cfg.ArgonThreads,
+		KeyLength: e.cfg.KeyLength,
 	}
-	if passphrase == "" {
-		return nil, fmt.Errorf("passphrase cannot be empty")
+}
+
+// NewSalt generates config.DefaultSaltSize random bytes for key derivation
+func (e *AESEncryptionService) NewSalt() (string, error) {
+	salt := make([]byte, config.DefaultSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
 	}
+	return base64.StdEncoding.EncodeToString(salt), nil
+}
 
-	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+// NewSession derives the key-encryption key with the parameters stored in the vault meta
+// and returns an AES-GCM session bound to the vault data key. Vaults older than
+// model.FormatVersionEnvelope encrypt entries with the derived key directly.
+func (e *AESEncryptionService) NewSession(
+	meta model.Meta,
+	passphrase string,
+) (model.Session, error) {
+	kek, cipherParams, err := deriveKEK(meta, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("invalid salt encoding: %w", err)
+		return nil, err
 	}
-	if len(salt) == 0 {
-		return nil, fmt.Errorf("salt cannot be empty")
+	if meta.FormatVersion < model.FormatVersionEnvelope {
+		return newAESSession(kek, cipherParams, meta, false)
 	}
+	defer clearBytes(kek)
 
-	key := deriveKey([]byte(passphrase), salt, e.cfg)
+	dataKey, err := unwrapKey(kek, cipherParams.NonceSize, meta)
+	if err != nil {
+		return nil, err
+	}
+	return newAESSession(dataKey, cipherParams, meta, true)
+}
 
-	block, err := aes.NewCipher(key)
+// NewDataKey generates a random vault data key and returns an AES-GCM session bound to it
+func (e *AESEncryptionService) NewDataKey(meta model.Meta) (model.Session, error) {
+	cipherParams, kdfParams := meta.CryptoParams()
+	if err := validateParams(cipherParams, kdfParams); err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, aes256KeyLength)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return newAESSession(dataKey, cipherParams, meta, true)
+}
+
+// NewKeySession returns an AES-GCM session bound to a data key exported by another session,
+// such as one held by the agent. The session takes a copy of key.
+func (e *AESEncryptionService) NewKeySession(meta model.Meta, key []byte) (model.Session, error) {
+	if meta.FormatVersion < model.FormatVersionEnvelope {
+		return nil, fmt.Errorf("vault format version %d has no data key", meta.FormatVersion)
+	}
+	cipherParams, kdfParams := meta.CryptoParams()
+	if err := validateParams(cipherParams, kdfParams); err != nil {
+		return nil, err
+	}
+	if len(key) != int(aes256KeyLength) {
+		return nil, fmt.Errorf("invalid data key length %d", len(key))
+	}
+
+	return newAESSession(append([]byte(nil), key...), cipherParams, meta, true)
+}
+
+// aesSession implements model.Session with an AES-GCM key unlocked once per unlock
+type aesSession struct {
+	key       []byte
+	macKey    []byte
+	aead      cipher.AEAD
+	nonceSize int
+	env       string
+	version   int
+	dataKey   bool
+}
+
+// newAESSession creates a session that encrypts entries with key and takes ownership of it
+func newAESSession(
+	key []byte,
+	cipherParams model.CipherParams,
+	meta model.Meta,
+	dataKey bool,
+) (model.Session, error) {
+	aead, err := newAEAD(key, cipherParams.NonceSize)
 	if err != nil {
-		clearBytes(key, salt)
-		return nil, fmt.Errorf("failed to create cipher: %w", err)
+		clearBytes(key)
+		return nil, err
 	}
 
-	aead, err := cipher.NewGCM(block)
+	macKey, err := hkdf.Key(sha256.New, key, nil, macKeyInfo, sha256.Size)
 	if err != nil {
-		clearBytes(key, salt)
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
+		clearBytes(key)
+		return nil, fmt.Errorf("failed to derive MAC key: %w", err)
 	}
 
-	return aead, nil
+	return &aesSession{
+		key:       key,
+		macKey:    macKey,
+		aead:      aead,
+		nonceSize: cipherParams.NonceSize,
+		env:       meta.Env,
+		version:   meta.EntryFormatVersion(),
+		dataKey:   dataKey,
+	}, nil
+}
+
+// Encrypt encrypts the plaintext of an entry and returns base64-encoded ciphertext
+func (s *aesSession) Encrypt(key string, plaintext []byte) (string, error) {
+	return s.seal(plaintext, s.associatedData(key))
+}
+
+// Decrypt decrypts the base64-encoded ciphertext of an entry and returns plaintext
+func (s *aesSession) Decrypt(key, ciphertext string) ([]byte, error) {
+	plaintext, err := s.open(ciphertext, s.associatedData(key))
+	if errors.Is(err, model.ErrTampered) {
+		return nil, fmt.Errorf("decryption failed for key %q: %w", key, err)
+	}
+	return plaintext, err
+}
+
+// SealRecord encrypts a record of domain kept beside the entries and returns base64-encoded
+// ciphertext
+func (s *aesSession) SealRecord(domain string, plaintext []byte) (string, error) {
+	return s.seal(plaintext, s.recordAssociatedData(domain))
 }
 
-// Encrypt encrypts plaintext and returns base64-encoded ciphertext
-func (e *AESEncryptionService) Encrypt(
-	plaintext []byte,
-	encodedSalt, passphrase string,
-) (string, error) {
+// OpenRecord decrypts a record of domain sealed with SealRecord and returns plaintext
+func (s *aesSession) OpenRecord(domain, record string) ([]byte, error) {
+	plaintext, err := s.open(record, s.recordAssociatedData(domain))
+	if errors.Is(err, model.ErrTampered) {
+		return nil, fmt.Errorf("decryption failed for %s record: %w", domain, err)
+	}
+	return plaintext, err
+}
+
+// seal encrypts plaintext with a random nonce and aad and returns the base64-encoded nonce
+// and ciphertext
+func (s *aesSession) seal(plaintext, aad []byte) (string, error) {
+	if s.aead == nil {
+		return "", fmt.Errorf("session is closed")
+	}
 	if plaintext == nil {
 		return "", fmt.Errorf("plaintext cannot be nil")
 	}
 
-	nonce := make([]byte, e.cfg.NonceSize)
+	nonce := make([]byte, s.nonceSize)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	aead, err := e.getAEAD(encodedSalt, passphrase)
-	if err != nil {
-		return "", err
-	}
-
-	ciphertext := aead.Seal(nil, nonce, plaintext, nil)
+	ciphertext := s.aead.Seal(nil, nonce, plaintext, aad)
 	result := make([]byte, 0, len(nonce)+len(ciphertext))
 	result = append(result, nonce...)
 	result = append(result, ciphertext...)
@@ -88,8 +226,12 @@ func (e *AESEncryptionService) Encrypt(
 	return encoded, nil
 }
 
-// Decrypt decrypts base64-encoded ciphertext and returns plaintext
-func (e *AESEncryptionService) Decrypt(ciphertext, encodedSalt, passphrase string) ([]byte, error) {
+// open decrypts the base64-encoded output of seal with aad, returning model.ErrTampered when
+// it fails authentication
+func (s *aesSession) open(ciphertext string, aad []byte) ([]byte, error) {
+	if s.aead == nil {
+		return nil, fmt.Errorf("session is closed")
+	}
 	if ciphertext == "" {
 		return nil, fmt.Errorf("ciphertext cannot be empty")
 	}
@@ -99,26 +241,19 @@ func (e *AESEncryptionService) Decrypt(ciphertext, encodedSalt, passphrase strin
 		return nil, fmt.Errorf("invalid ciphertext encoding: %w", err)
 	}
 
-	aead, err := e.getAEAD(encodedSalt, passphrase)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := e.validateCiphertextLength(raw, aead.Overhead()); err != nil {
+	if err := s.validateCiphertextLength(raw); err != nil {
 		return nil, err
 	}
 
 	// Extract nonce and ciphertext
-	nonce := raw[:e.cfg.NonceSize]
-	ciphertextBytes := raw[e.cfg.NonceSize:]
-	plaintext, err := aead.Open(nil, nonce, ciphertextBytes, nil)
+	nonce := raw[:s.nonceSize]
+	ciphertextBytes := raw[s.nonceSize:]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertextBytes, aad)
+	clearBytes(nonce, ciphertextBytes)
 	if err != nil {
-		clearBytes(nonce, ciphertextBytes)
-		return nil, fmt.Errorf("decryption failed: %w", err)
+		return nil, model.ErrTampered
 	}
 
-	clearBytes(nonce, ciphertextBytes)
-
 	if plaintext == nil {
 		return []byte{}, nil
 	}
@@ -126,10 +261,100 @@ func (e *AESEncryptionService) Decrypt(ciphertext, encodedSalt, passphrase strin
 	return plaintext, nil
 }
 
+// WrapKey seals the session data key under the key-encryption key derived from passphrase
+// and the salt and KDF parameters in meta, and returns it base64-encoded
+func (s *aesSession) WrapKey(meta model.Meta, passphrase string) (string, error) {
+	if s.aead == nil {
+		return "", fmt.Errorf("session is closed")
+	}
+	if !s.dataKey {
+		return "", fmt.Errorf("session is not bound to a data key")
+	}
+
+	kek, cipherParams, err := deriveKEK(meta, passphrase)
+	if err != nil {
+		return "", err
+	}
+	defer clearBytes(kek)
+
+	wrapped, err := sealDataKey(kek, cipherParams.NonceSize, meta.Env, s.key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+// ExportKey returns a copy of the session data key
+func (s *aesSession) ExportKey() ([]byte, error) {
+	if s.aead == nil {
+		return nil, fmt.Errorf("session is closed")
+	}
+	if !s.dataKey {
+		return nil, fmt.Errorf("session is not bound to a data key")
+	}
+	return append([]byte(nil), s.key...), nil
+}
+
+// MAC returns a base64-encoded HMAC-SHA256 of data under the vault MAC key
+func (s *aesSession) MAC(data []byte) (string, error) {
+	if s.aead == nil {
+		return "", fmt.Errorf("session is closed")
+	}
+
+	mac := hmac.New(sha256.New, s.macKey)
+	mac.Write(data)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Close zeroes the derived keys and drops the AEAD
+func (s *aesSession) Close() {
+	clearBytes(s.key, s.macKey)
+	s.key = nil
+	s.macKey = nil
+	s.aead = nil
+}
+
+// associatedData binds an entry to its env, key name and entry format version. Vaults
+// older than model.FormatVersionEntryAAD were sealed without associated data.
+func (s *aesSession) associatedData(key string) []byte {
+	if s.version == 0 {
+		return nil
+	}
+
+	aad := make([]byte, 0, len(aadPrefix)+aadFieldHeader*3+len(s.env)+len(key))
+	aad = append(aad, aadPrefix...)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(s.version))
+	aad = binary.BigEndian.AppendUint32(aad, uint32(len(s.env)))
+	aad = append(aad, s.env...)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(len(key)))
+	aad = append(aad, key...)
+	return aad
+}
+
+// recordAssociatedData binds a record to its env and domain, such as the audit log
+func (s *aesSession) recordAssociatedData(domain string) []byte {
+	aad := make([]byte, 0, len(recordAADPrefix)+aadFieldHeader*2+len(s.env)+len(domain))
+	aad = append(aad, recordAADPrefix...)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(len(s.env)))
+	aad = append(aad, s.env...)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(len(domain)))
+	aad = append(aad, domain...)
+	return aad
+}
+
+// dataKeyAssociatedData binds the wrapped data key to the env of its vault
+func dataKeyAssociatedData(env string) []byte {
+	aad := make([]byte, 0, len(dataKeyAADPrefix)+aadFieldHeader+len(env))
+	aad = append(aad, dataKeyAADPrefix...)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(len(env)))
+	aad = append(aad, env...)
+	return aad
+}
+
 // validateCiphertextLength checks if the ciphertext meets the minimum length requirement
 // The minimum length is nonce size + AEAD overhead (authentication tag)
-func (e *AESEncryptionService) validateCiphertextLength(ciphertext []byte, overhead int) error {
-	minLen := e.cfg.NonceSize + overhead
+func (s *aesSession) validateCiphertextLength(ciphertext []byte) error {
+	minLen := s.nonceSize + s.aead.Overhead()
 	if len(ciphertext) < minLen {
 		return fmt.Errorf(
 			"ciphertext too short: expected at least %d bytes, got %d",
@@ -140,15 +365,148 @@ func (e *AESEncryptionService) validateCiphertextLength(ciphertext []byte, overh
 	return nil
 }
 
+// deriveKEK validates the vault parameters and derives the key-encryption key from passphrase
+func deriveKEK(meta model.Meta, passphrase string) ([]byte, model.CipherParams, error) {
+	if meta.Salt == "" {
+		return nil, model.CipherParams{}, fmt.Errorf("salt cannot be empty")
+	}
+	if passphrase == "" {
+		return nil, model.CipherParams{}, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	cipherParams, kdfParams := meta.CryptoParams()
+	if err := validateParams(cipherParams, kdfParams); err != nil {
+		return nil, model.CipherParams{}, err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(meta.Salt)
+	if err != nil {
+		return nil, model.CipherParams{}, fmt.Errorf("invalid salt encoding: %w", err)
+	}
+	if len(salt) == 0 {
+		return nil, model.CipherParams{}, fmt.Errorf("salt cannot be empty")
+	}
+
+	key := deriveKey([]byte(passphrase), salt, kdfParams)
+	clearBytes(salt)
+	return key, cipherParams, nil
+}
+
+// unwrapKey opens the wrapped data key stored in meta with the key-encryption key. The AEAD
+// tag of the wrapped key is what checks the passphrase, so a wrong one fails with
+// model.ErrWrongPassphrase.
+func unwrapKey(kek []byte, nonceSize int, meta model.Meta) ([]byte, error) {
+	if meta.WrappedKey == "" {
+		return nil, fmt.Errorf("vault has no wrapped data key")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(meta.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped data key encoding: %w", err)
+	}
+
+	dataKey, err := openDataKey(kek, nonceSize, meta.Env, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", model.ErrWrongPassphrase)
+	}
+	return dataKey, nil
+}
+
+// sealDataKey encrypts a data key under kek and returns the nonce followed by the ciphertext
+func sealDataKey(kek []byte, nonceSize int, env string, dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(kek, nonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, dataKey, dataKeyAssociatedData(env)), nil
+}
+
+// openDataKey decrypts a data key sealed by sealDataKey
+func openDataKey(kek []byte, nonceSize int, env string, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(kek, nonceSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < nonceSize+aead.Overhead() {
+		return nil, fmt.Errorf("wrapped data key too short")
+	}
+
+	aad := dataKeyAssociatedData(env)
+	dataKey, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], aad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataKey) != int(aes256KeyLength) {
+		clearBytes(dataKey)
+		return nil, fmt.Errorf("invalid data key length %d", len(dataKey))
+	}
+	return dataKey, nil
+}
+
+// validateParams checks that the vault parameters describe a supported cipher and KDF
+func validateParams(cipherParams model.CipherParams, kdfParams model.KDFParams) error {
+	if cipherParams.Algorithm != model.CipherAES256GCM {
+		return fmt.Errorf("unsupported cipher %q", cipherParams.Algorithm)
+	}
+	if cipherParams.NonceSize <= 0 {
+		return fmt.Errorf("invalid nonce size %d", cipherParams.NonceSize)
+	}
+	if kdfParams.Algorithm != model.KDFArgon2id {
+		return fmt.Errorf("unsupported key derivation function %q", kdfParams.Algorithm)
+	}
+	if kdfParams.Time == 0 || kdfParams.MemoryKB == 0 || kdfParams.Threads == 0 {
+		return fmt.Errorf("invalid key derivation parameters")
+	}
+	if kdfParams.Time > maxArgonTime || kdfParams.MemoryKB > maxArgonMemoryKB {
+		return fmt.Errorf(
+			"key derivation parameters exceed the supported maximum: time %d (max %d), "+
+				"memory %d KiB (max %d KiB)",
+			kdfParams.Time,
+			maxArgonTime,
+			kdfParams.MemoryKB,
+			maxArgonMemoryKB,
+		)
+	}
+	if kdfParams.KeyLength != aes256KeyLength {
+		return fmt.Errorf(
+			"invalid key length %d for %s",
+			kdfParams.KeyLength,
+			model.CipherAES256GCM,
+		)
+	}
+	return nil
+}
+
+// newAEAD creates an AES-GCM AEAD for the given key and nonce size
+func newAEAD(key []byte, nonceSize int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
 // deriveKey derives a key from a passphrase using Argon2id
-func deriveKey(passphrase, salt []byte, cfg config.EncryptionConfig) []byte {
+func deriveKey(passphrase, salt []byte, params model.KDFParams) []byte {
 	return argon2.IDKey(
 		passphrase,
 		salt,
-		cfg.ArgonTime,
-		cfg.ArgonMemory,
-		cfg.ArgonThreads,
-		cfg.KeyLength,
+		params.Time,
+		params.MemoryKB,
+		params.Threads,
+		params.KeyLength,
 	)
 }
 
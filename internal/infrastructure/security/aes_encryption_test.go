@@ -3,16 +3,20 @@ package security
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 )
 
 const (
 	testPassphrase = "test-passphrase"
 	testPlaintext  = "test-plaintext"
 	testSalt       = "test-salt"
+	testEnv        = "test"
+	testKey        = "TEST_KEY"
 )
 
 // createTestEncryptionService creates a test encryption service with default config
@@ -27,13 +31,56 @@ func createTestSalt(t *testing.T) string {
 	return base64.StdEncoding.EncodeToString([]byte("test salt"))
 }
 
+// createTestMeta creates vault meta with the given salt and the default parameters, using
+// the last format version that encrypts entries with the passphrase-derived key
+func createTestMeta(t *testing.T, encodedSalt string) model.Meta {
+	t.Helper()
+	meta := model.Meta{
+		FormatVersion: model.FormatVersionVaultMAC,
+		Env:           testEnv,
+		Salt:          encodedSalt,
+	}
+	meta.Cipher, meta.KDF = createTestEncryptionService(t).DefaultParams()
+	return meta
+}
+
+// createEnvelopeMeta creates envelope vault meta with a new data key wrapped by passphrase
+func createEnvelopeMeta(t *testing.T, encodedSalt, passphrase string) model.Meta {
+	t.Helper()
+	meta := createTestMeta(t, encodedSalt)
+	meta.FormatVersion = model.FormatVersionEnvelope
+	dataKey, err := createTestEncryptionService(t).NewDataKey(meta)
+	if err != nil {
+		t.Fatalf("NewDataKey() returned unexpected error: %v", err)
+	}
+	defer dataKey.Close()
+
+	meta.WrappedKey, err = dataKey.WrapKey(meta, passphrase)
+	if err != nil {
+		t.Fatalf("WrapKey() returned unexpected error: %v", err)
+	}
+	return meta
+}
+
+// createTestSession unlocks a test session with the given salt and passphrase
+func createTestSession(t *testing.T, encodedSalt, passphrase string) model.Session {
+	t.Helper()
+	session, err := createTestEncryptionService(t).NewSession(
+		createTestMeta(t, encodedSalt),
+		passphrase,
+	)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	t.Cleanup(session.Close)
+	return session
+}
+
 func TestEncrypt_Success(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 	plaintext := []byte(testPlaintext)
 
-	ciphertext, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext, err := session.Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() returned unexpected error: %v", err)
 	}
@@ -50,16 +97,14 @@ func TestEncrypt_Success(t *testing.T) {
 }
 
 func TestEncrypt_SamePlaintextProducesDifferentCiphertexts(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 	plaintext := []byte(testPlaintext)
 
-	ciphertext1, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext1, err := session.Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() first call returned unexpected error: %v", err)
 	}
-	ciphertext2, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext2, err := session.Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() second call returned unexpected error: %v", err)
 	}
@@ -72,17 +117,34 @@ func TestEncrypt_SamePlaintextProducesDifferentCiphertexts(t *testing.T) {
 }
 
 func TestEncryptDecrypt_RoundTrip(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+	plaintext := []byte(testPlaintext)
+
+	ciphertext, err := session.Encrypt(testKey, plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	decrypted, err := session.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() returned unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestEncryptDecrypt_SeparateSessionsSameKey(t *testing.T) {
 	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
 	plaintext := []byte(testPlaintext)
 
-	ciphertext, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext, err := createTestSession(t, encodedSalt, testPassphrase).Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() returned unexpected error: %v", err)
 	}
 
-	decrypted, err := encryptionService.Decrypt(ciphertext, encodedSalt, passphrase)
+	decrypted, err := createTestSession(t, encodedSalt, testPassphrase).Decrypt(testKey, ciphertext)
 	if err != nil {
 		t.Fatalf("Decrypt() returned unexpected error: %v", err)
 	}
@@ -93,17 +155,15 @@ func TestEncryptDecrypt_RoundTrip(t *testing.T) {
 }
 
 func TestEncryptDecrypt_EmptyPlaintext(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 	plaintext := []byte("")
 
-	ciphertext, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext, err := session.Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() with empty plaintext returned unexpected error: %v", err)
 	}
 
-	decrypted, err := encryptionService.Decrypt(ciphertext, encodedSalt, passphrase)
+	decrypted, err := session.Decrypt(testKey, ciphertext)
 	if err != nil {
 		t.Fatalf("Decrypt() returned unexpected error: %v", err)
 	}
@@ -114,20 +174,18 @@ func TestEncryptDecrypt_EmptyPlaintext(t *testing.T) {
 }
 
 func TestDecrypt_WrongPassphrase(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
 	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
 	plaintext := []byte(testPlaintext)
 
-	ciphertext, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	ciphertext, err := createTestSession(t, encodedSalt, testPassphrase).Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() returned unexpected error: %v", err)
 	}
 
-	wrongPassphrase := "wrong passphrase"
-	_, err = encryptionService.Decrypt(ciphertext, encodedSalt, wrongPassphrase)
+	wrongSession := createTestSession(t, encodedSalt, "wrong passphrase")
+	_, err = wrongSession.Decrypt(testKey, ciphertext)
 	if err == nil {
-		t.Error("Decrypt() with wrong passphrase expected error, got nil")
+		t.Fatal("Decrypt() with wrong passphrase expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "decryption failed") {
 		t.Errorf("Decrypt() with wrong passphrase returned unexpected error: %v", err)
@@ -135,20 +193,18 @@ func TestDecrypt_WrongPassphrase(t *testing.T) {
 }
 
 func TestDecrypt_WrongSalt(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
 	plaintext := []byte(testPlaintext)
 
-	ciphertext, err := encryptionService.Encrypt(plaintext, encodedSalt, passphrase)
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+	ciphertext, err := session.Encrypt(testKey, plaintext)
 	if err != nil {
 		t.Fatalf("Encrypt() returned unexpected error: %v", err)
 	}
 
 	wrongSalt := base64.StdEncoding.EncodeToString([]byte("wrong salt"))
-	_, err = encryptionService.Decrypt(ciphertext, wrongSalt, passphrase)
+	_, err = createTestSession(t, wrongSalt, testPassphrase).Decrypt(testKey, ciphertext)
 	if err == nil {
-		t.Error("Decrypt() with wrong salt expected error, got nil")
+		t.Fatal("Decrypt() with wrong salt expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "decryption failed") {
 		t.Errorf("Decrypt() with wrong salt returned unexpected error: %v", err)
@@ -156,13 +212,11 @@ func TestDecrypt_WrongSalt(t *testing.T) {
 }
 
 func TestDecrypt_EmptyCiphertext(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 
-	_, err := encryptionService.Decrypt("", encodedSalt, passphrase)
+	_, err := session.Decrypt(testKey, "")
 	if err == nil {
-		t.Error("Decrypt() with empty ciphertext expected error, got nil")
+		t.Fatal("Decrypt() with empty ciphertext expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "ciphertext cannot be empty") {
 		t.Errorf("Decrypt() with empty ciphertext returned unexpected error: %v", err)
@@ -170,13 +224,11 @@ func TestDecrypt_EmptyCiphertext(t *testing.T) {
 }
 
 func TestDecrypt_InvalidCiphertext(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 
-	_, err := encryptionService.Decrypt("invalid", encodedSalt, passphrase)
+	_, err := session.Decrypt(testKey, "invalid")
 	if err == nil {
-		t.Error("Decrypt() with invalid ciphertext expected error, got nil")
+		t.Fatal("Decrypt() with invalid ciphertext expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "invalid ciphertext encoding") {
 		t.Errorf("Decrypt() with invalid ciphertext returned unexpected error: %v", err)
@@ -184,59 +236,623 @@ func TestDecrypt_InvalidCiphertext(t *testing.T) {
 }
 
 func TestEncrypt_NilPlaintext(t *testing.T) {
-	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 
-	_, err := encryptionService.Encrypt(nil, encodedSalt, passphrase)
+	_, err := session.Encrypt(testKey, nil)
 	if err == nil {
-		t.Error("Encrypt() with nil plaintext expected error, got nil")
+		t.Fatal("Encrypt() with nil plaintext expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "plaintext cannot be nil") {
 		t.Errorf("Encrypt() with nil plaintext returned unexpected error: %v", err)
 	}
 }
 
-func TestEncrypt_EmptySalt(t *testing.T) {
+func TestNewSession_EmptySalt(t *testing.T) {
 	encryptionService := createTestEncryptionService(t)
-	passphrase := testPassphrase
-	plaintext := []byte(testPlaintext)
 
-	_, err := encryptionService.Encrypt(plaintext, "", passphrase)
+	_, err := encryptionService.NewSession(createTestMeta(t, ""), testPassphrase)
 	if err == nil {
-		t.Error("Encrypt() with empty salt expected error, got nil")
+		t.Fatal("NewSession() with empty salt expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "salt cannot be empty") {
-		t.Errorf("Encrypt() with empty salt returned unexpected error: %v", err)
+		t.Errorf("NewSession() with empty salt returned unexpected error: %v", err)
 	}
 }
 
-func TestEncrypt_EmptyPassphrase(t *testing.T) {
+func TestNewSession_EmptyPassphrase(t *testing.T) {
 	encryptionService := createTestEncryptionService(t)
-	encodedSalt := createTestSalt(t)
-	plaintext := []byte(testPlaintext)
 
-	_, err := encryptionService.Encrypt(plaintext, encodedSalt, "")
+	_, err := encryptionService.NewSession(createTestMeta(t, createTestSalt(t)), "")
 	if err == nil {
-		t.Error("Encrypt() with empty passphrase expected error, got nil")
+		t.Fatal("NewSession() with empty passphrase expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "passphrase cannot be empty") {
-		t.Errorf("Encrypt() with empty passphrase returned unexpected error: %v", err)
+		t.Errorf("NewSession() with empty passphrase returned unexpected error: %v", err)
 	}
 }
 
-func TestDecrypt_CiphertextTooShort(t *testing.T) {
+func TestNewSession_LegacyMetaMatchesLegacyParams(t *testing.T) {
 	encryptionService := createTestEncryptionService(t)
+	legacy := model.Meta{Salt: createTestSalt(t)}
+	explicit := model.Meta{
+		FormatVersion: 1,
+		Salt:          legacy.Salt,
+		Cipher:        model.LegacyCipherParams(),
+		KDF:           model.LegacyKDFParams(),
+	}
+
+	legacySession, err := encryptionService.NewSession(legacy, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() with legacy meta returned unexpected error: %v", err)
+	}
+	defer legacySession.Close()
+	ciphertext, err := legacySession.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	explicitSession, err := encryptionService.NewSession(explicit, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() with explicit meta returned unexpected error: %v", err)
+	}
+	defer explicitSession.Close()
+	decrypted, err := explicitSession.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() returned unexpected error: %v", err)
+	}
+	if string(decrypted) != testPlaintext {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, testPlaintext)
+	}
+}
+
+func TestNewSession_UsesStoredParams(t *testing.T) {
+	encryptionService := NewAESEncryptionService(config.EncryptionConfig{
+		ArgonTime:    1,
+		ArgonMemory:  8 * 1024,
+		ArgonThreads: 1,
+		KeyLength:    32,
+		NonceSize:    12,
+	})
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.KDF.Time = 1
+	meta.KDF.MemoryKB = 8 * 1024
+	meta.Cipher.NonceSize = 16
+
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	ciphertext, err := session.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(ciphertext)
+	if want := 16 + len(testPlaintext) + 16; len(raw) != want {
+		t.Errorf("Encrypt() produced %d bytes, want %d with the stored nonce size", len(raw), want)
+	}
+
+	// A vault written with other KDF parameters must not open with the defaults.
+	defaultMeta := createTestMeta(t, meta.Salt)
+	defaultMeta.Cipher.NonceSize = 16
+	defaultSession, err := encryptionService.NewSession(defaultMeta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer defaultSession.Close()
+	if _, err := defaultSession.Decrypt(testKey, ciphertext); err == nil {
+		t.Error("Decrypt() with default parameters expected error, got nil")
+	}
+}
+
+func TestNewSession_UnsupportedParams(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	tests := []struct {
+		name    string
+		mutate  func(meta *model.Meta)
+		wantErr string
+	}{
+		{
+			name:    "unknown cipher",
+			mutate:  func(meta *model.Meta) { meta.Cipher.Algorithm = "chacha20" },
+			wantErr: "unsupported cipher",
+		},
+		{
+			name:    "unknown kdf",
+			mutate:  func(meta *model.Meta) { meta.KDF.Algorithm = "scrypt" },
+			wantErr: "unsupported key derivation function",
+		},
+		{
+			name:    "zero nonce size",
+			mutate:  func(meta *model.Meta) { meta.Cipher.NonceSize = 0 },
+			wantErr: "invalid nonce size",
+		},
+		{
+			name:    "wrong key length",
+			mutate:  func(meta *model.Meta) { meta.KDF.KeyLength = 16 },
+			wantErr: "invalid key length",
+		},
+		{
+			name:    "zero kdf time",
+			mutate:  func(meta *model.Meta) { meta.KDF.Time = 0 },
+			wantErr: "invalid key derivation parameters",
+		},
+		{
+			name:    "kdf time above maximum",
+			mutate:  func(meta *model.Meta) { meta.KDF.Time = 1 << 20 },
+			wantErr: "exceed the supported maximum",
+		},
+		{
+			name:    "kdf memory above maximum",
+			mutate:  func(meta *model.Meta) { meta.KDF.MemoryKB = 4294967295 },
+			wantErr: "exceed the supported maximum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := createTestMeta(t, createTestSalt(t))
+			tt.mutate(&meta)
+
+			_, err := encryptionService.NewSession(meta, testPassphrase)
+			if err == nil {
+				t.Fatalf("NewSession() expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewSession() error = %q, want to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultParams(t *testing.T) {
+	cipherParams, kdfParams := createTestEncryptionService(t).DefaultParams()
+
+	if cipherParams != model.LegacyCipherParams() {
+		t.Errorf("DefaultParams() cipher = %+v, want %+v", cipherParams, model.LegacyCipherParams())
+	}
+	if kdfParams != model.LegacyKDFParams() {
+		t.Errorf("DefaultParams() kdf = %+v, want %+v", kdfParams, model.LegacyKDFParams())
+	}
+}
+
+func TestDecrypt_SwappedKeyIsTampered(t *testing.T) {
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+
+	ciphertext, err := session.Encrypt("DB_PASSWORD", []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	_, err = session.Decrypt("DB_USER", ciphertext)
+	if !errors.Is(err, model.ErrTampered) {
+		t.Fatalf("Decrypt() under another key error = %v, want %v", err, model.ErrTampered)
+	}
+	if !strings.Contains(err.Error(), `"DB_USER"`) {
+		t.Errorf("Decrypt() error = %q, want to name the key", err.Error())
+	}
+}
+
+func TestDecrypt_MovedEnvIsTampered(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	staging := createTestMeta(t, createTestSalt(t))
+	staging.Env = "staging"
+	prod := staging
+	prod.Env = "prod"
+
+	stagingSession, err := encryptionService.NewSession(staging, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer stagingSession.Close()
+	ciphertext, err := stagingSession.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	prodSession, err := encryptionService.NewSession(prod, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer prodSession.Close()
+	if _, err := prodSession.Decrypt(testKey, ciphertext); !errors.Is(err, model.ErrTampered) {
+		t.Errorf("Decrypt() in another env error = %v, want %v", err, model.ErrTampered)
+	}
+}
+
+func TestDecrypt_LegacyFormatIgnoresKey(t *testing.T) {
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.FormatVersion = model.FormatVersionEntryAAD - 1
+	session, err := createTestEncryptionService(t).NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	ciphertext, err := session.Encrypt("DB_PASSWORD", []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+	if _, err := session.Decrypt("DB_USER", ciphertext); err != nil {
+		t.Errorf("Decrypt() of a legacy entry returned unexpected error: %v", err)
+	}
+}
+
+func TestDecrypt_EntriesSurviveVaultFormatBump(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.FormatVersion = model.FormatVersionEntryAAD
+
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+	ciphertext, err := session.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	meta.FormatVersion = model.FormatVersionVaultMAC
+	current, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer current.Close()
+	if _, err := current.Decrypt(testKey, ciphertext); err != nil {
+		t.Errorf("Decrypt() after a vault format bump returned unexpected error: %v", err)
+	}
+}
+
+func TestAssociatedData_Unambiguous(t *testing.T) {
+	a := (&aesSession{env: "a:b", version: model.FormatVersionEntryAAD}).associatedData("c")
+	b := (&aesSession{env: "a", version: model.FormatVersionEntryAAD}).associatedData("b:c")
+
+	if bytes.Equal(a, b) {
+		t.Error("associatedData() should differ when the env/key boundary moves")
+	}
+}
+
+func TestOpenRecord_SeparatedFromEntries(t *testing.T) {
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+
+	record, err := session.SealRecord("audit", []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("SealRecord() returned unexpected error: %v", err)
+	}
+	plaintext, err := session.OpenRecord("audit", record)
+	if err != nil || string(plaintext) != testPlaintext {
+		t.Fatalf("OpenRecord() = %q, %v, want %q", plaintext, err, testPlaintext)
+	}
+	if _, err := session.Decrypt("audit", record); !errors.Is(err, model.ErrTampered) {
+		t.Errorf("Decrypt() of a record error = %v, want %v", err, model.ErrTampered)
+	}
+
+	entry, err := session.Encrypt("lockify-audit", []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+	for _, domain := range []string{"audit", "lockify-audit"} {
+		_, err := session.OpenRecord(domain, entry)
+		if !errors.Is(err, model.ErrTampered) {
+			t.Errorf("OpenRecord(%q) of an entry error = %v, want tampered", domain, err)
+		}
+	}
+}
+
+func TestSession_MAC(t *testing.T) {
 	encodedSalt := createTestSalt(t)
-	passphrase := testPassphrase
+	session := createTestSession(t, encodedSalt, testPassphrase)
+
+	mac1, err := session.MAC([]byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("MAC() returned unexpected error: %v", err)
+	}
+	mac2, err := createTestSession(t, encodedSalt, testPassphrase).MAC([]byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("MAC() returned unexpected error: %v", err)
+	}
+	if mac1 != mac2 {
+		t.Error("MAC() should be deterministic for the same vault key")
+	}
+
+	other, err := createTestSession(t, encodedSalt, "wrong passphrase").MAC([]byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("MAC() returned unexpected error: %v", err)
+	}
+	if mac1 == other {
+		t.Error("MAC() should depend on the vault key")
+	}
+
+	aesSess := session.(*aesSession)
+	if bytes.Equal(aesSess.macKey, aesSess.key) {
+		t.Error("MAC key should be derived separately from the encryption key")
+	}
+}
+
+func TestDecrypt_CiphertextTooShort(t *testing.T) {
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
 
 	shortCiphertext := base64.StdEncoding.EncodeToString([]byte("short"))
 
-	_, err := encryptionService.Decrypt(shortCiphertext, encodedSalt, passphrase)
+	_, err := session.Decrypt(testKey, shortCiphertext)
 	if err == nil {
-		t.Error("Decrypt() with too short ciphertext expected error, got nil")
+		t.Fatal("Decrypt() with too short ciphertext expected error, got nil")
 	}
 	if !strings.Contains(err.Error(), "ciphertext too short") {
 		t.Errorf("Decrypt() with too short ciphertext returned unexpected error: %v", err)
 	}
 }
+
+func TestSession_CloseZeroesKey(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createTestMeta(t, createTestSalt(t))
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+
+	key := session.(*aesSession).key
+	macKey := session.(*aesSession).macKey
+	session.Close()
+
+	if !bytes.Equal(key, make([]byte, len(key))) {
+		t.Error("Close() should zero the derived key")
+	}
+	if !bytes.Equal(macKey, make([]byte, len(macKey))) {
+		t.Error("Close() should zero the MAC key")
+	}
+	if _, err := session.MAC([]byte(testPlaintext)); err == nil {
+		t.Error("MAC() on a closed session expected error, got nil")
+	}
+	if _, err := session.Encrypt(testKey, []byte(testPlaintext)); err == nil {
+		t.Error("Encrypt() on a closed session expected error, got nil")
+	}
+	if _, err := session.Decrypt(testKey, "c2hvcnQ="); err == nil {
+		t.Error("Decrypt() on a closed session expected error, got nil")
+	}
+}
+
+func TestNewSession_UnwrapsDataKey(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.FormatVersion = model.FormatVersionEnvelope
+
+	dataKey, err := encryptionService.NewDataKey(meta)
+	if err != nil {
+		t.Fatalf("NewDataKey() returned unexpected error: %v", err)
+	}
+	defer dataKey.Close()
+	ciphertext, err := dataKey.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+	meta.WrappedKey, err = dataKey.WrapKey(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("WrapKey() returned unexpected error: %v", err)
+	}
+
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+	decrypted, err := session.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() returned unexpected error: %v", err)
+	}
+	if string(decrypted) != testPlaintext {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, testPlaintext)
+	}
+}
+
+func TestNewSession_WrongPassphraseCannotUnwrap(t *testing.T) {
+	meta := createEnvelopeMeta(t, createTestSalt(t), testPassphrase)
+
+	_, err := createTestEncryptionService(t).NewSession(meta, "wrong passphrase")
+	if err == nil {
+		t.Fatal("NewSession() with wrong passphrase expected error, got nil")
+	}
+	if !errors.Is(err, model.ErrWrongPassphrase) {
+		t.Errorf("NewSession() with wrong passphrase returned unexpected error: %v", err)
+	}
+}
+
+func TestNewSession_LongPassphraseSharingPrefixCannotUnwrap(t *testing.T) {
+	// bcrypt ignores everything after 72 bytes, the derived key does not.
+	prefix := strings.Repeat("p", 72)
+	meta := createEnvelopeMeta(t, createTestSalt(t), prefix+"-right")
+
+	_, err := createTestEncryptionService(t).NewSession(meta, prefix+"-wrong")
+	if !errors.Is(err, model.ErrWrongPassphrase) {
+		t.Errorf(
+			"NewSession() with a passphrase sharing a 72-byte prefix = %v, want %v",
+			err,
+			model.ErrWrongPassphrase,
+		)
+	}
+}
+
+func TestNewSalt(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+
+	salt, err := encryptionService.NewSalt()
+	if err != nil {
+		t.Fatalf("NewSalt() returned unexpected error: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		t.Fatalf("NewSalt() returned invalid base64 %q: %v", salt, err)
+	}
+	if len(decoded) != config.DefaultSaltSize {
+		t.Errorf("NewSalt() returned %d bytes, want %d", len(decoded), config.DefaultSaltSize)
+	}
+	if other, _ := encryptionService.NewSalt(); other == salt {
+		t.Error("NewSalt() returned the same salt twice")
+	}
+}
+
+func TestNewSession_MissingWrappedKey(t *testing.T) {
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.FormatVersion = model.FormatVersionEnvelope
+
+	_, err := createTestEncryptionService(t).NewSession(meta, testPassphrase)
+	if err == nil {
+		t.Fatal("NewSession() without a wrapped key expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "no wrapped data key") {
+		t.Errorf("NewSession() without a wrapped key returned unexpected error: %v", err)
+	}
+}
+
+func TestNewSession_WrappedKeyBoundToEnv(t *testing.T) {
+	meta := createEnvelopeMeta(t, createTestSalt(t), testPassphrase)
+	meta.Env = "prod"
+
+	if _, err := createTestEncryptionService(t).NewSession(meta, testPassphrase); err == nil {
+		t.Error("NewSession() with a data key wrapped for another env expected error, got nil")
+	}
+}
+
+func TestWrapKey_RotationKeepsCiphertexts(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createEnvelopeMeta(t, createTestSalt(t), testPassphrase)
+
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+	ciphertext, err := session.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	rotated := meta
+	rotated.Salt = base64.StdEncoding.EncodeToString([]byte("rotated salt"))
+	rotated.WrappedKey, err = session.WrapKey(rotated, "new passphrase")
+	if err != nil {
+		t.Fatalf("WrapKey() returned unexpected error: %v", err)
+	}
+
+	if _, err := encryptionService.NewSession(rotated, testPassphrase); err == nil {
+		t.Error("NewSession() with the old passphrase after rotation expected error, got nil")
+	}
+	rotatedSession, err := encryptionService.NewSession(rotated, "new passphrase")
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer rotatedSession.Close()
+	decrypted, err := rotatedSession.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() after rotation returned unexpected error: %v", err)
+	}
+	if string(decrypted) != testPlaintext {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, testPlaintext)
+	}
+}
+
+func TestWrapKey_LegacySession(t *testing.T) {
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+
+	_, err := session.WrapKey(createTestMeta(t, createTestSalt(t)), testPassphrase)
+	if err == nil {
+		t.Fatal("WrapKey() on a passphrase-derived session expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "not bound to a data key") {
+		t.Errorf("WrapKey() returned unexpected error: %v", err)
+	}
+}
+
+func TestNewKeySession_ExportedKeyDecrypts(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createEnvelopeMeta(t, createTestSalt(t), testPassphrase)
+
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		t.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+	ciphertext, err := session.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+	key, err := session.ExportKey()
+	if err != nil {
+		t.Fatalf("ExportKey() returned unexpected error: %v", err)
+	}
+
+	keySession, err := encryptionService.NewKeySession(meta, key)
+	if err != nil {
+		t.Fatalf("NewKeySession() returned unexpected error: %v", err)
+	}
+	keySession.Close()
+	if bytes.Equal(key, make([]byte, len(key))) {
+		t.Fatal("closing the key session zeroed the exported key")
+	}
+
+	keySession, err = encryptionService.NewKeySession(meta, key)
+	if err != nil {
+		t.Fatalf("NewKeySession() returned unexpected error: %v", err)
+	}
+	defer keySession.Close()
+	decrypted, err := keySession.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() returned unexpected error: %v", err)
+	}
+	if string(decrypted) != testPlaintext {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, testPlaintext)
+	}
+}
+
+func TestNewKeySession_Errors(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta := createEnvelopeMeta(t, createTestSalt(t), testPassphrase)
+
+	if _, err := encryptionService.NewKeySession(meta, []byte("short")); err == nil {
+		t.Error("NewKeySession() with a short key expected error, got nil")
+	}
+	legacy := createTestMeta(t, createTestSalt(t))
+	if _, err := encryptionService.NewKeySession(legacy, make([]byte, 32)); err == nil {
+		t.Error("NewKeySession() for a vault without data key expected error, got nil")
+	}
+	if _, err := createTestSession(t, createTestSalt(t), testPassphrase).ExportKey(); err == nil {
+		t.Error("ExportKey() on a passphrase-derived session expected error, got nil")
+	}
+}
+
+func BenchmarkNewSession(b *testing.B) {
+	encryptionService := NewAESEncryptionService(config.DefaultEncryptionConfig())
+	meta := model.Meta{Salt: base64.StdEncoding.EncodeToString([]byte("test salt"))}
+
+	for i := 0; i < b.N; i++ {
+		session, err := encryptionService.NewSession(meta, testPassphrase)
+		if err != nil {
+			b.Fatalf("NewSession() returned unexpected error: %v", err)
+		}
+		session.Close()
+	}
+}
+
+func BenchmarkSession_Decrypt(b *testing.B) {
+	encryptionService := NewAESEncryptionService(config.DefaultEncryptionConfig())
+	meta := model.Meta{Salt: base64.StdEncoding.EncodeToString([]byte("test salt"))}
+	session, err := encryptionService.NewSession(meta, testPassphrase)
+	if err != nil {
+		b.Fatalf("NewSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	ciphertext, err := session.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		b.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := session.Decrypt(testKey, ciphertext); err != nil {
+			b.Fatalf("Decrypt() returned unexpected error: %v", err)
+		}
+	}
+}
@@ -2,10 +2,12 @@ package security
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
 )
@@ -14,39 +16,119 @@ import (
 type PassphraseService struct {
 	cache      service.Cache
 	cryptoUtil service.HashService
-	envVar     string
+	policy     service.PassphrasePolicy
+	sourcesEnv string
+	sources    []service.PassphraseSource
 }
 
-// NewPassphraseService creates a new passphrase service
+// NewPassphraseService creates a new passphrase service that tries sources in the given
+// order, unless the command or sourcesEnv configures another one. cryptoUtil is only needed
+// to validate passphrases of vaults older than model.FormatVersionEnvelope and may be nil.
+// policy checks the passphrases of new vaults; nil accepts any passphrase.
 func NewPassphraseService(
 	cache service.Cache,
 	cryptoUtil service.HashService,
-	envVar string,
+	policy service.PassphrasePolicy,
+	sourcesEnv string,
+	sources ...service.PassphraseSource,
 ) service.PassphraseService {
-	if envVar == "" {
-		envVar = "LOCKIFY_PASSPHRASE"
+	if sourcesEnv == "" {
+		sourcesEnv = "LOCKIFY_PASSPHRASE_SOURCES"
 	}
 
-	return &PassphraseService{cache, cryptoUtil, envVar}
+	return &PassphraseService{cache, cryptoUtil, policy, sourcesEnv, sources}
 }
 
-// Get retrieves a passphrase from environment variable, keyring cache, or user input
+// newPassphraseSources names the sources a new passphrase may come from. The keyring is left
+// out: it caches the passphrases of existing vaults, which would otherwise become the
+// passphrase of a new one without the user noticing.
+var newPassphraseSources = []string{
+	SourceEnv,
+	SourceFile,
+	SourceFD,
+	SourceCommand,
+	SourcePrompt,
+}
+
+// Get retrieves a passphrase from the first source that has one for env
 func (s *PassphraseService) Get(ctx context.Context, env string) (string, error) {
 	if env == "" {
 		return "", fmt.Errorf("environment cannot be empty")
 	}
 
-	if passphrase := os.Getenv(s.envVar); passphrase != "" {
+	sources, err := s.order(ctx, env)
+	if err != nil {
+		return "", err
+	}
+	return s.first(ctx, env, sources)
+}
+
+// GetNew retrieves the passphrase of a new vault for env like Get, but never from the
+// keyring, asking for it twice when it is prompted for, and checks it against the passphrase
+// policy. A rejected passphrase is dropped from the cache, so it is
+// not used again.
+func (s *PassphraseService) GetNew(ctx context.Context, env string) (string, error) {
+	if env == "" {
+		return "", fmt.Errorf("environment cannot be empty")
+	}
+
+	sources, err := s.order(ctx, env)
+	if err != nil {
+		return "", err
+	}
+	sources = slices.DeleteFunc(slices.Clone(sources), func(source service.PassphraseSource) bool {
+		return !slices.Contains(newPassphraseSources, source.Name())
+	})
+	if len(sources) == 0 {
+		return "", fmt.Errorf(
+			"no source for a new passphrase of environment %q: use %s",
+			env,
+			strings.Join(newPassphraseSources, ", "),
+		)
+	}
+
+	options := service.PassphraseOptionsFrom(ctx)
+	options.Confirm = true
+	passphrase, err := s.first(service.WithPassphraseOptions(ctx, options), env, sources)
+	if err != nil {
+		return "", err
+	}
+	if s.policy == nil {
 		return passphrase, nil
 	}
 
-	key := s.getKeyringKey(env)
-	passphrase, err := s.cache.Get(key)
-	if err == nil && passphrase != "" {
+	if err := s.policy.Check(ctx, env, passphrase); err != nil {
+		//nolint:errcheck // The rejection is the error to report
+		s.Clear(ctx, env)
+		return "", err
+	}
+	return passphrase, nil
+}
+
+// first returns the passphrase of the first of sources that has one for env
+func (s *PassphraseService) first(
+	ctx context.Context,
+	env string,
+	sources []service.PassphraseSource,
+) (string, error) {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		passphrase, err := source.Passphrase(ctx, env)
+		if errors.Is(err, service.ErrNoPassphrase) {
+			names = append(names, source.Name())
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("passphrase source %s: %w", source.Name(), err)
+		}
 		return passphrase, nil
 	}
 
-	return s.getFromUser(ctx, env)
+	return "", fmt.Errorf(
+		"no passphrase for environment %q from sources %s",
+		env,
+		strings.Join(names, ", "),
+	)
 }
 
 // Clear clears a cached passphrase for an environment
@@ -54,9 +136,7 @@ func (s *PassphraseService) Clear(ctx context.Context, env string) error {
 	if env == "" {
 		return fmt.Errorf("environment cannot be empty")
 	}
-	key := s.getKeyringKey(env)
-
-	return s.cache.Delete(key)
+	return s.cache.Delete(keyringKey(env))
 }
 
 // ClearAll clears all cached passphrases
@@ -64,49 +144,84 @@ func (s *PassphraseService) ClearAll(ctx context.Context) error {
 	return s.cache.DeleteAll()
 }
 
-// Validate validates a passphrase against a vault's fingerprint
+// Validate validates a passphrase against the bcrypt fingerprint of every key slot of a
+// vault and returns the first slot it matches. Only vaults older than
+// model.FormatVersionEnvelope are checked this way; newer ones are checked by opening their
+// wrapped data key.
 func (s *PassphraseService) Validate(
 	ctx context.Context,
 	vault *model.Vault,
 	passphrase string,
-) error {
+) (model.KeySlot, error) {
 	if vault == nil {
-		return fmt.Errorf("vault cannot be nil")
+		return model.KeySlot{}, fmt.Errorf("vault cannot be nil")
 	}
 	if vault.Meta.FingerPrint == "" {
-		return fmt.Errorf("fingerprint cannot be empty")
+		return model.KeySlot{}, fmt.Errorf("fingerprint cannot be empty")
+	}
+	if s.cryptoUtil == nil {
+		return model.KeySlot{}, fmt.Errorf("no hash service to check the vault fingerprint")
 	}
 	if passphrase == "" {
-		return fmt.Errorf("passphrase cannot be empty")
+		return model.KeySlot{}, fmt.Errorf("passphrase cannot be empty")
 	}
 
-	return s.cryptoUtil.Verify(vault.Meta.FingerPrint, passphrase)
+	var err error
+	for _, slot := range vault.Meta.KeySlots() {
+		if err = s.cryptoUtil.Verify(slot.FingerPrint, passphrase); err == nil {
+			return slot, nil
+		}
+	}
+	return model.KeySlot{}, fmt.Errorf("passphrase does not match any key slot: %w", err)
 }
 
-// getFromUser prompts the user for a passphrase
-func (s *PassphraseService) getFromUser(ctx context.Context, env string) (string, error) {
-	var passphrase string
-	prompt := &survey.Password{
-		Message: fmt.Sprintf("Enter passphrase for environment %q:", env),
+// order returns the sources to try for env: the ones named by the command, else by the
+// per-env or shared sources variable, else every source in the configured order
+func (s *PassphraseService) order(
+	ctx context.Context,
+	env string,
+) ([]service.PassphraseSource, error) {
+	names := service.PassphraseOptionsFrom(ctx).Sources
+	for _, variable := range []string{envVariable(s.sourcesEnv, env), s.sourcesEnv} {
+		if len(names) > 0 {
+			break
+		}
+		if value := os.Getenv(variable); value != "" {
+			names = strings.Split(value, ",")
+		}
 	}
-
-	if err := survey.AskOne(prompt, &passphrase); err != nil {
-		return "", fmt.Errorf("failed to get passphrase: %w", err)
+	if len(names) == 0 {
+		return s.sources, nil
 	}
 
-	if passphrase == "" {
-		return "", fmt.Errorf("passphrase cannot be empty")
+	sources := make([]service.PassphraseSource, 0, len(names))
+	for _, name := range names {
+		source, err := s.source(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
 	}
+	return sources, nil
+}
 
-	// Cache passphrase in keyring (best effort, ignore errors)
-	key := s.getKeyringKey(env)
-	//nolint:errcheck // We don't want to return an error here
-	s.cache.Set(key, passphrase)
-
-	return passphrase, nil
+// source returns the source called name
+func (s *PassphraseService) source(name string) (service.PassphraseSource, error) {
+	known := make([]string, 0, len(s.sources))
+	for _, source := range s.sources {
+		if source.Name() == name {
+			return source, nil
+		}
+		known = append(known, source.Name())
+	}
+	return nil, fmt.Errorf(
+		"unknown passphrase source %q: use %s",
+		name,
+		strings.Join(known, ", "),
+	)
 }
 
-// getKeyringKey returns the keyring key for an environment
-func (s *PassphraseService) getKeyringKey(env string) string {
+// keyringKey returns the keyring key for an environment
+func keyringKey(env string) string {
 	return fmt.Sprintf("env:%s", env)
 }
@@ -0,0 +1,147 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+// toolName is assumed to be tried by attackers along with the environment name.
+const toolName = "lockify"
+
+// PassphrasePolicy implements service.PassphrasePolicy, rejecting passphrases that are
+// estimated to be too easy to guess or that appear in a list of breached passwords
+type PassphrasePolicy struct {
+	cfg config.VaultConfig
+}
+
+// NewPassphrasePolicy creates a passphrase policy with the scores and breached passwords
+// file configured in cfg, unless their variables set others
+func NewPassphrasePolicy(cfg config.VaultConfig) service.PassphrasePolicy {
+	return &PassphrasePolicy{cfg}
+}
+
+// Check rejects a passphrase scoring below the minimum score of env, then one listed in the
+// breached passwords file of env
+func (p *PassphrasePolicy) Check(ctx context.Context, env, passphrase string) error {
+	minScore, err := p.minScore(env)
+	if err != nil {
+		return err
+	}
+
+	strength := EstimateStrength(passphrase, env, toolName)
+	if strength.Score < minScore {
+		return &model.WeakPassphraseError{Env: env, MinScore: minScore, Strength: strength}
+	}
+
+	path := p.breachedFile(env)
+	if path == "" {
+		return nil
+	}
+	breached, err := isBreached(path, passphrase)
+	if err != nil {
+		return err
+	}
+	if breached {
+		return &model.WeakPassphraseError{
+			Env:      env,
+			MinScore: minScore,
+			Strength: strength,
+			Breached: true,
+		}
+	}
+	return nil
+}
+
+// minScore returns the strength score new passphrases of env need: the per-env score
+// variable, else the score configured for env, else the shared score variable, else the
+// configured score
+func (p *PassphrasePolicy) minScore(env string) (int, error) {
+	variable := p.cfg.PassphraseScoreEnv
+	if variable != "" {
+		if score, ok, err := scoreVariable(envVariable(variable, env)); ok || err != nil {
+			return score, err
+		}
+	}
+	if score, ok := p.cfg.MinPassphraseScores[env]; ok {
+		return score, nil
+	}
+	if variable != "" {
+		if score, ok, err := scoreVariable(variable); ok || err != nil {
+			return score, err
+		}
+	}
+	return p.cfg.MinPassphraseScore, nil
+}
+
+// scoreVariable returns the score set in variable, if it is set
+func scoreVariable(variable string) (int, bool, error) {
+	value := os.Getenv(variable)
+	if value == "" {
+		return 0, false, nil
+	}
+	score, err := strconv.Atoi(value)
+	if err != nil || score < model.ScoreTooGuessable || score > model.MaxPassphraseScore {
+		return 0, false, fmt.Errorf(
+			"invalid %s %q: must be a score from %d to %d",
+			variable,
+			value,
+			model.ScoreTooGuessable,
+			model.MaxPassphraseScore,
+		)
+	}
+	return score, true, nil
+}
+
+// breachedFile returns the breached passwords file of env: the per-env or shared variable,
+// else the configured file
+func (p *PassphrasePolicy) breachedFile(env string) string {
+	if variable := p.cfg.BreachedFileEnv; variable != "" {
+		for _, name := range []string{envVariable(variable, env), variable} {
+			if path := os.Getenv(name); path != "" {
+				return path
+			}
+		}
+	}
+	return p.cfg.BreachedFile
+}
+
+// isBreached reports whether passphrase is listed in the file at path, which holds one
+// password per line, either as is or as the hex SHA-1 hash of the Have I Been Pwned
+// downloads, optionally followed by :count
+func isBreached(path, passphrase string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open breached passwords file: %w", err)
+	}
+	defer file.Close()
+
+	// SHA-1 only matches the published hashes here, it protects nothing.
+	sum := sha1.Sum([]byte(passphrase))
+	hash := hex.EncodeToString(sum[:])
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == passphrase {
+			return true, nil
+		}
+		candidate, _, _ := strings.Cut(line, ":")
+		if len(candidate) == len(hash) && strings.EqualFold(candidate, hash) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breached passwords file: %w", err)
+	}
+	return false, nil
+}
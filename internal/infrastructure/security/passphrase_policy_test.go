@@ -0,0 +1,164 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/config"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+// strongPassphrase scores model.ScoreVeryUnguessable.
+const strongPassphrase = "correct horse battery staple"
+
+func newTestPassphrasePolicy(breachedFile string) *PassphrasePolicy {
+	return &PassphrasePolicy{config.VaultConfig{
+		MinPassphraseScore:  config.DefaultMinPassphraseScore,
+		MinPassphraseScores: map[string]int{"prod": config.StrictMinPassphraseScore},
+		PassphraseScoreEnv:  "LOCKIFY_TEST_MIN_SCORE",
+		BreachedFile:        breachedFile,
+		BreachedFileEnv:     "LOCKIFY_TEST_BREACHED",
+	}}
+}
+
+func TestPassphrasePolicy_MinScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		vars    map[string]string
+		want    int
+		wantErr bool
+	}{
+		{name: "configured", env: "dev", want: config.DefaultMinPassphraseScore},
+		{name: "configured for env", env: "prod", want: config.StrictMinPassphraseScore},
+		{
+			name: "shared variable",
+			env:  "dev",
+			vars: map[string]string{"LOCKIFY_TEST_MIN_SCORE": "1"},
+			want: 1,
+		},
+		{
+			name: "configured for env over shared variable",
+			env:  "prod",
+			vars: map[string]string{"LOCKIFY_TEST_MIN_SCORE": "1"},
+			want: config.StrictMinPassphraseScore,
+		},
+		{
+			name: "per-env variable",
+			env:  "prod",
+			vars: map[string]string{
+				"LOCKIFY_TEST_MIN_SCORE":      "1",
+				"LOCKIFY_TEST_MIN_SCORE_PROD": "2",
+			},
+			want: 2,
+		},
+		{
+			name:    "invalid variable",
+			env:     "dev",
+			vars:    map[string]string{"LOCKIFY_TEST_MIN_SCORE": "strong"},
+			wantErr: true,
+		},
+		{
+			name:    "score out of range",
+			env:     "prod",
+			vars:    map[string]string{"LOCKIFY_TEST_MIN_SCORE_PROD": "5"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.vars {
+				t.Setenv(name, value)
+			}
+
+			got, err := newTestPassphrasePolicy("").minScore(tt.env)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("minScore() = %d, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("minScore() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPassphrasePolicy_Check_Weak(t *testing.T) {
+	policy := newTestPassphrasePolicy("")
+	ctx := context.Background()
+
+	err := policy.Check(ctx, "prod", "monkey123")
+	var weak *model.WeakPassphraseError
+	if !errors.As(err, &weak) {
+		t.Fatalf("Check() = %v, want a WeakPassphraseError", err)
+	}
+	if weak.Breached || weak.MinScore != config.StrictMinPassphraseScore {
+		t.Errorf("Check() = %+v, want too weak for the prod score", weak)
+	}
+	if weak.Strength.Warning == "" {
+		t.Error("Check() should explain why the passphrase is weak")
+	}
+
+	if err := policy.Check(ctx, "prod", strongPassphrase); err != nil {
+		t.Errorf("Check() of a strong passphrase = %v, want nil", err)
+	}
+}
+
+func TestPassphrasePolicy_Check_Breached(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.txt")
+	// The uppercase SHA-1 hash of strongPassphrase with a count, as downloaded from HIBP.
+	hashed := filepath.Join(dir, "hashed.txt")
+	files := map[string]string{
+		plain: "123456\r\n" + strongPassphrase + "\r\n",
+		hashed: "7C4A8D09CA3762AF61E59520943DC26494F8941B:37\n" +
+			"ABF7AAD6438836DBE526AA231ABDE2D0EEF74D42:2\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write breached passwords file: %v", err)
+		}
+	}
+	ctx := context.Background()
+
+	for _, path := range []string{plain, hashed} {
+		err := newTestPassphrasePolicy(path).Check(ctx, "dev", strongPassphrase)
+		var weak *model.WeakPassphraseError
+		if !errors.As(err, &weak) || !weak.Breached {
+			t.Errorf("Check() with %s = %v, want a breached passphrase", filepath.Base(path), err)
+		}
+	}
+
+	err := newTestPassphrasePolicy(plain).Check(ctx, "dev", "quokka marble lantern")
+	if err != nil {
+		t.Errorf("Check() of an unlisted passphrase = %v, want nil", err)
+	}
+}
+
+func TestPassphrasePolicy_Check_BreachedFileVariable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prod.txt")
+	if err := os.WriteFile(path, []byte(strongPassphrase+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write breached passwords file: %v", err)
+	}
+	t.Setenv("LOCKIFY_TEST_BREACHED", filepath.Join(dir, "missing.txt"))
+	t.Setenv("LOCKIFY_TEST_BREACHED_PROD", path)
+	policy := newTestPassphrasePolicy("")
+	ctx := context.Background()
+
+	var weak *model.WeakPassphraseError
+	if err := policy.Check(ctx, "prod", strongPassphrase); !errors.As(err, &weak) {
+		t.Errorf("Check() = %v, want the per-env file to list the passphrase", err)
+	}
+
+	err := policy.Check(ctx, "dev", strongPassphrase)
+	if err == nil || errors.As(err, &weak) {
+		t.Errorf("Check() with a missing file = %v, want an error opening it", err)
+	}
+}
@@ -0,0 +1,342 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+)
+
+const (
+	// SourceEnv names the source reading environment variables.
+	SourceEnv = "env"
+	// SourceFile names the source reading --passphrase-file.
+	SourceFile = "file"
+	// SourceFD names the source reading --passphrase-fd.
+	SourceFD = "fd"
+	// SourceCommand names the source running --passphrase-cmd.
+	SourceCommand = "cmd"
+	// SourceKeyring names the source reading cached passphrases.
+	SourceKeyring = "keyring"
+	// SourcePrompt names the source asking the user.
+	SourcePrompt = "prompt"
+	// envPlaceholder is replaced by the environment name in the passphrase file path.
+	envPlaceholder = "{env}"
+	// commandEnvVar passes the environment name to the passphrase command.
+	commandEnvVar = "LOCKIFY_ENV"
+)
+
+// EnvPassphraseSource reads the passphrase from a variable of the environment, such as
+// LOCKIFY_PASSPHRASE_PROD for prod, falling back to the variable shared by every
+// environment, such as LOCKIFY_PASSPHRASE.
+type EnvPassphraseSource struct {
+	variable string
+}
+
+// NewEnvPassphraseSource creates a source reading variable and its per-env variants.
+func NewEnvPassphraseSource(variable string) service.PassphraseSource {
+	return &EnvPassphraseSource{variable}
+}
+
+// Name returns the name of the source.
+func (s *EnvPassphraseSource) Name() string {
+	return SourceEnv
+}
+
+// Passphrase returns the per-env variable of env, or else the shared variable.
+func (s *EnvPassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	for _, name := range []string{envVariable(s.variable, env), s.variable} {
+		if passphrase := os.Getenv(name); passphrase != "" {
+			return passphrase, nil
+		}
+	}
+	return "", service.ErrNoPassphrase
+}
+
+// FilePassphraseSource reads the passphrase from the file given with --passphrase-file.
+type FilePassphraseSource struct{}
+
+// NewFilePassphraseSource creates a source reading the passphrase file of a command.
+func NewFilePassphraseSource() service.PassphraseSource {
+	return &FilePassphraseSource{}
+}
+
+// Name returns the name of the source.
+func (s *FilePassphraseSource) Name() string {
+	return SourceFile
+}
+
+// Passphrase returns the content of the passphrase file of env without its final newline.
+func (s *FilePassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	path := service.PassphraseOptionsFrom(ctx).File
+	if path == "" {
+		return "", service.ErrNoPassphrase
+	}
+	path = strings.ReplaceAll(path, envPlaceholder, env)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	passphrase := trimNewline(string(data))
+	clear(data)
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", path)
+	}
+	return passphrase, nil
+}
+
+// FDPassphraseSource reads the passphrase from the first line of the file descriptor given
+// with --passphrase-fd. The line is read once and used for every environment.
+type FDPassphraseSource struct {
+	mu         sync.Mutex
+	read       bool
+	passphrase string
+}
+
+// NewFDPassphraseSource creates a source reading the passphrase file descriptor of a command.
+func NewFDPassphraseSource() service.PassphraseSource {
+	return &FDPassphraseSource{}
+}
+
+// Name returns the name of the source.
+func (s *FDPassphraseSource) Name() string {
+	return SourceFD
+}
+
+// Passphrase returns the first line of the passphrase file descriptor.
+func (s *FDPassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	fd := service.PassphraseOptionsFrom(ctx).FD
+	if fd < 0 {
+		return "", service.ErrNoPassphrase
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.read {
+		passphrase, err := readLine(os.NewFile(uintptr(fd), "passphrase-fd"))
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase from file descriptor %d: %w", fd, err)
+		}
+		s.read, s.passphrase = true, passphrase
+	}
+	if s.passphrase == "" {
+		return "", fmt.Errorf("file descriptor %d holds no passphrase", fd)
+	}
+	return s.passphrase, nil
+}
+
+// CommandPassphraseSource prints the passphrase with the shell command given with
+// --passphrase-cmd, such as a password manager. The command finds the environment name in
+// $LOCKIFY_ENV.
+type CommandPassphraseSource struct{}
+
+// NewCommandPassphraseSource creates a source running the passphrase command of a command.
+func NewCommandPassphraseSource() service.PassphraseSource {
+	return &CommandPassphraseSource{}
+}
+
+// Name returns the name of the source.
+func (s *CommandPassphraseSource) Name() string {
+	return SourceCommand
+}
+
+// Passphrase runs the passphrase command for env and returns its output without its final
+// newline. The command shares the terminal of lockify, so it can ask for a PIN.
+func (s *CommandPassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	command := service.PassphraseOptionsFrom(ctx).Command
+	if command == "" {
+		return "", service.ErrNoPassphrase
+	}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, command)
+	cmd.Env = append(os.Environ(), commandEnvVar+"="+env)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("passphrase command failed: %w", err)
+	}
+	passphrase := trimNewline(stdout.String())
+	if passphrase == "" {
+		return "", errors.New("passphrase command printed no passphrase")
+	}
+	return passphrase, nil
+}
+
+// KeyringPassphraseSource reads passphrases cached after they were entered at the prompt,
+// in the OS keyring or whichever cache backend is configured.
+type KeyringPassphraseSource struct {
+	cache service.Cache
+}
+
+// NewKeyringPassphraseSource creates a source reading passphrases from cache.
+func NewKeyringPassphraseSource(cache service.Cache) service.PassphraseSource {
+	return &KeyringPassphraseSource{cache}
+}
+
+// Name returns the name of the source.
+func (s *KeyringPassphraseSource) Name() string {
+	return SourceKeyring
+}
+
+// Passphrase returns the cached passphrase of env. A cache that cannot be used is treated
+// as holding no passphrase.
+func (s *KeyringPassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	passphrase, err := s.cache.Get(keyringKey(env))
+	if err != nil || passphrase == "" {
+		return "", service.ErrNoPassphrase
+	}
+	return passphrase, nil
+}
+
+// PromptPassphraseSource asks the user for the passphrase and caches it for a TTL taken
+// from --cache-ttl, else from the per-env or shared TTL variable, else from the config.
+type PromptPassphraseSource struct {
+	prompt service.PromptService
+	cache  service.Cache
+	ttlEnv string
+	ttl    time.Duration
+}
+
+// NewPromptPassphraseSource creates a source asking for passphrases through prompt and
+// caching them in cache for ttl, unless the command or ttlEnv configures another TTL.
+func NewPromptPassphraseSource(
+	prompt service.PromptService,
+	cache service.Cache,
+	ttlEnv string,
+	ttl time.Duration,
+) service.PassphraseSource {
+	return &PromptPassphraseSource{prompt, cache, ttlEnv, ttl}
+}
+
+// Name returns the name of the source.
+func (s *PromptPassphraseSource) Name() string {
+	return SourcePrompt
+}
+
+// Passphrase asks the user for the passphrase of env, twice when the command confirms new
+// passphrases, so a typo does not lock the user out of a new vault.
+func (s *PromptPassphraseSource) Passphrase(ctx context.Context, env string) (string, error) {
+	ttl, err := s.cacheTTL(ctx, env)
+	if err != nil {
+		return "", err
+	}
+
+	passphrase, err := s.prompt.GetPassphraseInput(
+		fmt.Sprintf("Enter passphrase for environment %q:", env),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get passphrase: %w", err)
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	if service.PassphraseOptionsFrom(ctx).Confirm {
+		confirmation, err := s.prompt.GetPassphraseInput(
+			fmt.Sprintf("Confirm passphrase for environment %q:", env),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to get passphrase: %w", err)
+		}
+		if confirmation != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+
+	// Cache passphrase (best effort, ignore errors)
+	//nolint:errcheck // We don't want to return an error here
+	s.cache.Set(keyringKey(env), passphrase, ttl)
+
+	return passphrase, nil
+}
+
+// cacheTTL returns how long to cache the passphrase of env, checked before prompting so a
+// mistyped TTL does not cost a passphrase
+func (s *PromptPassphraseSource) cacheTTL(ctx context.Context, env string) (time.Duration, error) {
+	if ttl := service.PassphraseOptionsFrom(ctx).CacheTTL; ttl != nil {
+		if *ttl < 0 {
+			return 0, fmt.Errorf("cache ttl cannot be negative: %s", *ttl)
+		}
+		return *ttl, nil
+	}
+	if s.ttlEnv == "" {
+		return s.ttl, nil
+	}
+
+	for _, variable := range []string{envVariable(s.ttlEnv, env), s.ttlEnv} {
+		value := os.Getenv(variable)
+		if value == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return 0, fmt.Errorf(
+				"invalid %s %q: must be a duration such as 15m or 0",
+				variable,
+				value,
+			)
+		}
+		return ttl, nil
+	}
+	return s.ttl, nil
+}
+
+// envVariable returns the per-env variant of variable, such as LOCKIFY_PASSPHRASE_PROD_EU
+// for prod-eu
+func envVariable(variable, env string) string {
+	return variable + "_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, env)
+}
+
+// readLine reads up to the first newline of r one byte at a time, so nothing after it is
+// consumed
+func readLine(r *os.File) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", err
+		}
+	}
+	return trimNewline(string(line)), nil
+}
+
+// trimNewline removes one final line ending, keeping any other whitespace of a passphrase
+func trimNewline(value string) string {
+	value = strings.TrimSuffix(value, "\n")
+	return strings.TrimSuffix(value, "\r")
+}
@@ -0,0 +1,258 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+)
+
+// withOptions returns a context holding options, with no file descriptor unless one is set
+func withOptions(options service.PassphraseOptions) context.Context {
+	if options.FD == 0 {
+		options.FD = -1
+	}
+	return service.WithPassphraseOptions(context.Background(), options)
+}
+
+func TestEnvPassphraseSource(t *testing.T) {
+	source := NewEnvPassphraseSource("LOCKIFY_TEST_PASSPHRASE")
+	ctx := context.Background()
+
+	if _, err := source.Passphrase(ctx, "prod"); !errors.Is(err, service.ErrNoPassphrase) {
+		t.Fatalf("Passphrase() without variables = %v, want ErrNoPassphrase", err)
+	}
+
+	t.Setenv("LOCKIFY_TEST_PASSPHRASE", "shared")
+	t.Setenv("LOCKIFY_TEST_PASSPHRASE_PROD_EU", "prod-eu")
+	tests := map[string]string{"prod-eu": "prod-eu", "staging": "shared"}
+	for env, want := range tests {
+		got, err := source.Passphrase(ctx, env)
+		if err != nil {
+			t.Fatalf("Passphrase(%q) returned unexpected error: %v", env, err)
+		}
+		if got != want {
+			t.Errorf("Passphrase(%q) = %q, want %q", env, got, want)
+		}
+	}
+}
+
+func TestFilePassphraseSource(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "prod.pass"), []byte("pass phrase \n"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write passphrase file: %v", err)
+	}
+	source := NewFilePassphraseSource()
+
+	ctx := withOptions(service.PassphraseOptions{File: filepath.Join(dir, "{env}.pass")})
+	got, err := source.Passphrase(ctx, "prod")
+	if err != nil {
+		t.Fatalf("Passphrase() returned unexpected error: %v", err)
+	}
+	if got != "pass phrase " {
+		t.Errorf("Passphrase() = %q, want only the final newline removed", got)
+	}
+
+	_, err = source.Passphrase(ctx, "staging")
+	if err == nil || errors.Is(err, service.ErrNoPassphrase) {
+		t.Errorf("Passphrase() with a missing file = %v, want a read error", err)
+	}
+	_, err = source.Passphrase(withOptions(service.PassphraseOptions{}), "prod")
+	if !errors.Is(err, service.ErrNoPassphrase) {
+		t.Errorf("Passphrase() without a file = %v, want ErrNoPassphrase", err)
+	}
+}
+
+func TestFDPassphraseSource(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer reader.Close()
+	writer.WriteString("from-fd\r\nnot read\n")
+	writer.Close()
+
+	source := NewFDPassphraseSource()
+	ctx := withOptions(service.PassphraseOptions{FD: int(reader.Fd())})
+	for _, env := range []string{"prod", "staging"} {
+		got, err := source.Passphrase(ctx, env)
+		if err != nil {
+			t.Fatalf("Passphrase(%q) returned unexpected error: %v", env, err)
+		}
+		if got != "from-fd" {
+			t.Errorf("Passphrase(%q) = %q, want the first line for every environment", env, got)
+		}
+	}
+}
+
+func TestCommandPassphraseSource(t *testing.T) {
+	command := "echo secret-$LOCKIFY_ENV"
+	if runtime.GOOS == "windows" {
+		command = "echo secret-%LOCKIFY_ENV%"
+	}
+	source := NewCommandPassphraseSource()
+
+	got, err := source.Passphrase(withOptions(service.PassphraseOptions{Command: command}), "prod")
+	if err != nil {
+		t.Fatalf("Passphrase() returned unexpected error: %v", err)
+	}
+	if got != "secret-prod" {
+		t.Errorf("Passphrase() = %q, want %q", got, "secret-prod")
+	}
+
+	_, err = source.Passphrase(withOptions(service.PassphraseOptions{Command: "exit 3"}), "prod")
+	if err == nil || errors.Is(err, service.ErrNoPassphrase) {
+		t.Errorf("Passphrase() with a failing command = %v, want an error", err)
+	}
+}
+
+func TestPromptPassphraseSource_CachesPassphrase(t *testing.T) {
+	cache := &test.MockCache{}
+	prompt := &test.MockPromptService{
+		GetPassphraseInputFunc: func(message string) (string, error) {
+			return "typed", nil
+		},
+	}
+	ctx := context.Background()
+
+	got, err := NewPromptPassphraseSource(prompt, cache, "", 0).Passphrase(ctx, "prod")
+	if err != nil || got != "typed" {
+		t.Fatalf("Passphrase() = %q, %v, want the typed passphrase", got, err)
+	}
+
+	got, err = NewKeyringPassphraseSource(cache).Passphrase(ctx, "prod")
+	if err != nil || got != "typed" {
+		t.Errorf("keyring Passphrase() = %q, %v, want the cached passphrase", got, err)
+	}
+	_, err = NewKeyringPassphraseSource(cache).Passphrase(ctx, "staging")
+	if !errors.Is(err, service.ErrNoPassphrase) {
+		t.Errorf("keyring Passphrase() of another env = %v, want ErrNoPassphrase", err)
+	}
+}
+
+func TestPromptPassphraseSource_Confirm(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirmation string
+		wantErr      bool
+	}{
+		{name: "match", confirmation: "typed"},
+		{name: "mismatch", confirmation: "typo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &test.MockCache{}
+			var messages []string
+			prompt := &test.MockPromptService{
+				GetPassphraseInputFunc: func(message string) (string, error) {
+					messages = append(messages, message)
+					if len(messages) == 1 {
+						return "typed", nil
+					}
+					return tt.confirmation, nil
+				},
+			}
+			ctx := withOptions(service.PassphraseOptions{Confirm: true})
+
+			got, err := NewPromptPassphraseSource(prompt, cache, "", 0).Passphrase(ctx, "prod")
+			if len(messages) != 2 {
+				t.Fatalf("Passphrase() prompted %d times, want 2", len(messages))
+			}
+			_, cacheErr := NewKeyringPassphraseSource(cache).Passphrase(ctx, "prod")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Passphrase() = %q, want a mismatch error", got)
+				}
+				if !errors.Is(cacheErr, service.ErrNoPassphrase) {
+					t.Errorf("keyring Passphrase() = %v, want no cached mismatch", cacheErr)
+				}
+				return
+			}
+			if err != nil || got != "typed" {
+				t.Fatalf("Passphrase() = %q, %v, want the typed passphrase", got, err)
+			}
+			if cacheErr != nil {
+				t.Errorf("keyring Passphrase() = %v, want the confirmed passphrase", cacheErr)
+			}
+		})
+	}
+}
+
+func TestPromptPassphraseSource_CacheTTL(t *testing.T) {
+	flagTTL := 5 * time.Minute
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		vars    map[string]string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "configured", ctx: context.Background(), want: time.Hour},
+		{
+			name: "shared variable",
+			ctx:  context.Background(),
+			vars: map[string]string{"LOCKIFY_TEST_CACHE_TTL": "30m"},
+			want: 30 * time.Minute,
+		},
+		{
+			name: "per-env variable",
+			ctx:  context.Background(),
+			vars: map[string]string{
+				"LOCKIFY_TEST_CACHE_TTL":      "30m",
+				"LOCKIFY_TEST_CACHE_TTL_PROD": "0",
+			},
+			want: 0,
+		},
+		{
+			name: "flag",
+			ctx:  withOptions(service.PassphraseOptions{CacheTTL: &flagTTL}),
+			vars: map[string]string{"LOCKIFY_TEST_CACHE_TTL_PROD": "30m"},
+			want: flagTTL,
+		},
+		{
+			name:    "invalid variable",
+			ctx:     context.Background(),
+			vars:    map[string]string{"LOCKIFY_TEST_CACHE_TTL_PROD": "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.vars {
+				t.Setenv(name, value)
+			}
+			prompted := false
+			prompt := &test.MockPromptService{
+				GetPassphraseInputFunc: func(message string) (string, error) {
+					prompted = true
+					return "typed", nil
+				},
+			}
+			cache := &test.MockCache{}
+			source := NewPromptPassphraseSource(prompt, cache, "LOCKIFY_TEST_CACHE_TTL", time.Hour)
+
+			_, err := source.Passphrase(tt.ctx, "prod")
+			if tt.wantErr {
+				if err == nil || prompted {
+					t.Errorf("Passphrase() = %v, want an error before prompting", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Passphrase() returned unexpected error: %v", err)
+			}
+			if got := cache.TTLs[keyringKey("prod")]; got != tt.want {
+				t.Errorf("cached for %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/service"
+	"github.com/ahmed-abdelgawad92/lockify/test"
+)
+
+// createSlottedVault creates a vault with a bcrypt fingerprint for a default slot and a ci slot
+func createSlottedVault(t *testing.T) *model.Vault {
+	t.Helper()
+	hashService := NewBcryptHashService()
+	fingerprint, err := hashService.Hash(testPassphrase)
+	if err != nil {
+		t.Fatalf("Hash() returned unexpected error: %v", err)
+	}
+	vault, _ := model.NewVault(testEnv, testSalt)
+	vault.Meta.FingerPrint = fingerprint
+
+	ciFingerprint, err := hashService.Hash("ci-passphrase")
+	if err != nil {
+		t.Fatalf("Hash() returned unexpected error: %v", err)
+	}
+	err = vault.AddSlot(model.KeySlot{
+		Name:        "ci",
+		Salt:        "ci-salt",
+		FingerPrint: ciFingerprint,
+		WrappedKey:  "ci-wrapped-key",
+	})
+	if err != nil {
+		t.Fatalf("AddSlot() returned unexpected error: %v", err)
+	}
+	return vault
+}
+
+func TestPassphraseService_Validate_Slots(t *testing.T) {
+	passphraseService := NewPassphraseService(nil, NewBcryptHashService(), nil, "")
+	vault := createSlottedVault(t)
+
+	tests := []struct {
+		passphrase string
+		wantSlot   string
+	}{
+		{testPassphrase, model.DefaultSlotName},
+		{"ci-passphrase", "ci"},
+	}
+	for _, tt := range tests {
+		slot, err := passphraseService.Validate(context.Background(), vault, tt.passphrase)
+		if err != nil {
+			t.Fatalf("Validate() returned unexpected error: %v", err)
+		}
+		if slot.Name != tt.wantSlot {
+			t.Errorf("Validate() unlocked slot %q, want %q", slot.Name, tt.wantSlot)
+		}
+	}
+}
+
+func TestPassphraseService_Validate_NoMatchingSlot(t *testing.T) {
+	passphraseService := NewPassphraseService(nil, NewBcryptHashService(), nil, "")
+
+	_, err := passphraseService.Validate(context.Background(), createSlottedVault(t), "wrong")
+	if err == nil {
+		t.Fatal("Validate() with wrong passphrase expected error, got nil")
+	}
+}
+
+func TestPassphraseService_Validate_EmptyPassphrase(t *testing.T) {
+	passphraseService := NewPassphraseService(nil, NewBcryptHashService(), nil, "")
+
+	_, err := passphraseService.Validate(context.Background(), createSlottedVault(t), "")
+	if err == nil {
+		t.Fatal("Validate() with empty passphrase expected error, got nil")
+	}
+}
+
+func TestPassphraseService_Validate_NoHashService(t *testing.T) {
+	passphraseService := NewPassphraseService(nil, nil, nil, "")
+
+	vault := createSlottedVault(t)
+	_, err := passphraseService.Validate(context.Background(), vault, testPassphrase)
+	if err == nil {
+		t.Fatal("Validate() without a hash service expected error, got nil")
+	}
+}
+
+// fakeSource is a passphrase source that has a passphrase for the environments in values
+type fakeSource struct {
+	name   string
+	values map[string]string
+	err    error
+}
+
+func (s *fakeSource) Name() string {
+	return s.name
+}
+
+func (s *fakeSource) Passphrase(ctx context.Context, env string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if passphrase, ok := s.values[env]; ok {
+		return passphrase, nil
+	}
+	return "", service.ErrNoPassphrase
+}
+
+func newChainedPassphraseService() service.PassphraseService {
+	return NewPassphraseService(
+		&test.MockCache{},
+		NewBcryptHashService(),
+		nil,
+		"LOCKIFY_TEST_SOURCES",
+		&fakeSource{name: "env", values: map[string]string{"prod": "from-env"}},
+		&fakeSource{name: "cmd", values: map[string]string{"prod": "from-cmd", "qa": "qa-cmd"}},
+	)
+}
+
+func TestPassphraseService_Get_Order(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		vars    map[string]string
+		env     string
+		want    string
+		wantErr string
+	}{
+		{name: "default order", ctx: context.Background(), env: "prod", want: "from-env"},
+		{name: "next source", ctx: context.Background(), env: "qa", want: "qa-cmd"},
+		{
+			name: "shared variable",
+			ctx:  context.Background(),
+			vars: map[string]string{"LOCKIFY_TEST_SOURCES": "cmd,env"},
+			env:  "prod",
+			want: "from-cmd",
+		},
+		{
+			name: "per-env variable",
+			ctx:  context.Background(),
+			vars: map[string]string{
+				"LOCKIFY_TEST_SOURCES":      "cmd",
+				"LOCKIFY_TEST_SOURCES_PROD": "env",
+			},
+			env:  "prod",
+			want: "from-env",
+		},
+		{
+			name: "flag",
+			ctx: service.WithPassphraseOptions(context.Background(), service.PassphraseOptions{
+				FD:      -1,
+				Sources: []string{"cmd"},
+			}),
+			vars: map[string]string{"LOCKIFY_TEST_SOURCES_PROD": "env"},
+			env:  "prod",
+			want: "from-cmd",
+		},
+		{
+			name:    "exhausted",
+			ctx:     context.Background(),
+			vars:    map[string]string{"LOCKIFY_TEST_SOURCES": "env"},
+			env:     "qa",
+			wantErr: "no passphrase for environment \"qa\" from sources env",
+		},
+		{
+			name:    "unknown source",
+			ctx:     context.Background(),
+			vars:    map[string]string{"LOCKIFY_TEST_SOURCES": "env,vault"},
+			env:     "prod",
+			wantErr: "unknown passphrase source \"vault\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.vars {
+				t.Setenv(name, value)
+			}
+			got, err := newChainedPassphraseService().Get(tt.ctx, tt.env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Get() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPassphraseService_Get_SourceError(t *testing.T) {
+	passphraseService := NewPassphraseService(
+		&test.MockCache{},
+		NewBcryptHashService(),
+		nil,
+		"LOCKIFY_TEST_SOURCES",
+		&fakeSource{name: "file", err: errors.New("permission denied")},
+		&fakeSource{name: "env", values: map[string]string{"prod": "from-env"}},
+	)
+
+	_, err := passphraseService.Get(context.Background(), "prod")
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Get() error = %v, want the failing source not to be skipped", err)
+	}
+}
+
+func TestPassphraseService_GetNew(t *testing.T) {
+	weak := &model.WeakPassphraseError{Env: "prod", MinScore: 3}
+	tests := []struct {
+		name    string
+		policy  service.PassphrasePolicy
+		wantErr error
+	}{
+		{name: "no policy"},
+		{name: "accepted", policy: &test.MockPassphrasePolicy{}},
+		{
+			name: "rejected",
+			policy: &test.MockPassphrasePolicy{
+				CheckFunc: func(ctx context.Context, env, passphrase string) error {
+					return weak
+				},
+			},
+			wantErr: weak,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirmed bool
+			cache := &test.MockCache{}
+			prompt := &test.MockPromptService{
+				GetPassphraseInputFunc: func(message string) (string, error) {
+					confirmed = strings.HasPrefix(message, "Confirm")
+					return "typed", nil
+				},
+			}
+			s := NewPassphraseService(
+				cache,
+				nil,
+				tt.policy,
+				"LOCKIFY_TEST_SOURCES",
+				NewPromptPassphraseSource(prompt, cache, "", 0),
+			)
+			ctx := withOptions(service.PassphraseOptions{})
+
+			got, err := s.GetNew(ctx, "prod")
+			if !confirmed {
+				t.Error("GetNew() should ask to confirm the passphrase")
+			}
+			_, cacheErr := NewKeyringPassphraseSource(cache).Passphrase(ctx, "prod")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetNew() = %q, %v, want %v", got, err, tt.wantErr)
+				}
+				if cacheErr == nil {
+					t.Error("GetNew() should clear a rejected passphrase from the cache")
+				}
+				return
+			}
+			if err != nil || got != "typed" {
+				t.Fatalf("GetNew() = %q, %v, want the typed passphrase", got, err)
+			}
+			if cacheErr != nil {
+				t.Errorf("keyring Passphrase() = %v, want the accepted passphrase cached", cacheErr)
+			}
+		})
+	}
+}
+
+func TestPassphraseService_GetNew_NeverFromKeyring(t *testing.T) {
+	var checked []string
+	policy := &test.MockPassphrasePolicy{
+		CheckFunc: func(ctx context.Context, env, passphrase string) error {
+			checked = append(checked, passphrase)
+			return nil
+		},
+	}
+	passphraseService := NewPassphraseService(
+		&test.MockCache{},
+		nil,
+		policy,
+		"LOCKIFY_TEST_SOURCES",
+		&fakeSource{name: "keyring", values: map[string]string{"prod": "from-keyring"}},
+		&fakeSource{name: "env", values: map[string]string{"prod": "from-env"}},
+		&fakeSource{name: "cmd", values: map[string]string{"prod": "from-cmd"}},
+	)
+
+	got, err := passphraseService.GetNew(context.Background(), "prod")
+	if err != nil || got != "from-env" {
+		t.Fatalf("GetNew() = %q, %v, want the passphrase of the env source", got, err)
+	}
+	if !slices.Equal(checked, []string{"from-env"}) {
+		t.Errorf("GetNew() checked %v against the policy, want the env passphrase", checked)
+	}
+
+	t.Setenv("LOCKIFY_TEST_SOURCES", "keyring")
+	_, err = passphraseService.GetNew(context.Background(), "prod")
+	if err == nil || !strings.Contains(err.Error(), "no source for a new passphrase") {
+		t.Errorf("GetNew() with only the keyring error = %v, want no source", err)
+	}
+	if got, err := passphraseService.Get(context.Background(), "prod"); got != "from-keyring" {
+		t.Errorf("Get() = %q, %v, want the keyring to still be used", got, err)
+	}
+}
@@ -0,0 +1,352 @@
+package security
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+const (
+	// maxStrengthRunes is how much of a passphrase is estimated, which bounds the time an
+	// estimate takes. Longer passphrases are rated by their start, so they are never rated
+	// stronger than they are.
+	maxStrengthRunes = 100
+	// bruteforceCardinality is the number of guesses counted per character no pattern
+	// matches.
+	bruteforceCardinality = 10
+	// minGuessesBeforeGrowingSequence makes a sequence of several matches cost at least
+	// this much more per match, so a passphrase is not split into many short matches.
+	minGuessesBeforeGrowingSequence = 10000
+	// minSubmatchGuessesSingleChar is the fewest guesses a single character match of a
+	// longer passphrase counts for.
+	minSubmatchGuessesSingleChar = 10
+	// minSubmatchGuessesMultiChar is the fewest guesses a longer match of a longer
+	// passphrase counts for.
+	minSubmatchGuessesMultiChar = 50
+	// scoreDelta keeps passphrases right at a threshold in the lower score.
+	scoreDelta = 5
+)
+
+// addWordsSuggestion is suggested for every passphrase that is not very unguessable.
+const addWordsSuggestion = "Add another word or two. Uncommon words are better."
+
+// scoreThresholds are the guesses from which a passphrase reaches each score above
+// model.ScoreTooGuessable.
+var scoreThresholds = []float64{1e3, 1e6, 1e8, 1e10}
+
+// EstimateStrength estimates how many guesses an attacker who knows common passwords, words,
+// names, keyboard patterns, sequences, repeats and dates needs to find passphrase, and rates
+// it from model.ScoreTooGuessable to model.ScoreVeryUnguessable, in the way of zxcvbn.
+// userInputs are words the attacker is assumed to try first, such as the environment name.
+func EstimateStrength(passphrase string, userInputs ...string) model.PassphraseStrength {
+	password := []rune(passphrase)
+	if len(password) > maxStrengthRunes {
+		password = password[:maxStrengthRunes]
+	}
+
+	inputs := make([]string, 0, len(userInputs))
+	for _, input := range userInputs {
+		inputs = append(inputs, strings.ToLower(input))
+	}
+	estimator := &strengthEstimator{newRankedDictionary(dictionaryUserInputs, inputs)}
+	guesses, sequence := estimator.mostGuessable(password)
+
+	strength := model.PassphraseStrength{Score: guessesToScore(guesses), Guesses: guesses}
+	strength.Warning, strength.Suggestions = strengthFeedback(strength.Score, sequence)
+	return strength
+}
+
+// strengthEstimator finds the least guessable way to build a passphrase out of matches
+type strengthEstimator struct {
+	userInputs rankedDictionary
+}
+
+// mostGuessable returns the fewest guesses needed for password and the sequence of matches
+// it is made of, where the characters no pattern matches are guessed by brute force
+func (e *strengthEstimator) mostGuessable(password []rune) (float64, []strengthMatch) {
+	n := len(password)
+	if n == 0 {
+		return 1, nil
+	}
+
+	byEnd := make([][]strengthMatch, n)
+	for _, m := range e.matches(password) {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	table := newSequenceTable(n)
+	for k := 0; k < n; k++ {
+		for _, m := range byEnd[k] {
+			table.extend(m)
+		}
+		for i := 0; i <= k; i++ {
+			table.extend(bruteforceMatch(password, i, k))
+		}
+	}
+	return table.best()
+}
+
+// sequenceCandidate is the best sequence of matches of a given length ending at a position:
+// its last match, the product of the guesses of its matches, and its total guesses.
+type sequenceCandidate struct {
+	set     bool
+	last    strengthMatch
+	product float64
+	guesses float64
+}
+
+// sequenceTable holds the best sequence of every length covering each start of a password
+// n runes long.
+type sequenceTable struct {
+	n     int
+	cells [][]sequenceCandidate
+}
+
+// newSequenceTable returns an empty table for a password n runes long
+func newSequenceTable(n int) *sequenceTable {
+	cells := make([][]sequenceCandidate, n)
+	for k := range cells {
+		cells[k] = make([]sequenceCandidate, n+1)
+	}
+	return &sequenceTable{n, cells}
+}
+
+// extend appends m to every sequence ending right before it
+func (t *sequenceTable) extend(m strengthMatch) {
+	if m.i == 0 {
+		t.update(m, 1)
+		return
+	}
+	for l, previous := range t.cells[m.i-1] {
+		// Two brute forced runs in a row are one longer run.
+		if !previous.set || (m.pattern == patternBruteforce && previous.last.pattern == m.pattern) {
+			continue
+		}
+		t.update(m, l+1)
+	}
+}
+
+// update keeps the sequence of l matches ending with m if no sequence of at most l matches
+// ending at the same position takes as few guesses
+func (t *sequenceTable) update(m strengthMatch, l int) {
+	product := matchGuesses(m, t.n)
+	if l > 1 {
+		product *= t.cells[m.i-1][l-1].product
+	}
+	guesses := factorial(l)*product + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+	for _, competing := range t.cells[m.j][:l+1] {
+		if competing.set && competing.guesses <= guesses {
+			return
+		}
+	}
+	t.cells[m.j][l] = sequenceCandidate{true, m, product, guesses}
+}
+
+// best returns the guesses and matches of the best sequence covering the whole password
+func (t *sequenceTable) best() (float64, []strengthMatch) {
+	bestL, best := 0, math.Inf(1)
+	for l, c := range t.cells[t.n-1] {
+		if c.set && c.guesses < best {
+			bestL, best = l, c.guesses
+		}
+	}
+
+	sequence := make([]strengthMatch, bestL)
+	for k, l := t.n-1, bestL; l > 0; l-- {
+		m := t.cells[k][l].last
+		sequence[l-1] = m
+		k = m.i - 1
+	}
+	return best, sequence
+}
+
+// matchGuesses returns the guesses of a match of a password n runes long, counting short
+// matches of longer passwords as at least a few guesses
+func matchGuesses(m strengthMatch, n int) float64 {
+	length := m.j - m.i + 1
+	if length == n {
+		return math.Max(m.guesses, 1)
+	}
+	if length == 1 {
+		return math.Max(m.guesses, minSubmatchGuessesSingleChar)
+	}
+	return math.Max(m.guesses, minSubmatchGuessesMultiChar)
+}
+
+// bruteforceMatch returns the match guessing password[i:j+1] character by character
+func bruteforceMatch(password []rune, i, j int) strengthMatch {
+	length := j - i + 1
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+	if length == 1 {
+		guesses = math.Max(guesses, minSubmatchGuessesSingleChar+1)
+	} else {
+		guesses = math.Max(guesses, minSubmatchGuessesMultiChar+1)
+	}
+	return strengthMatch{
+		pattern: patternBruteforce,
+		i:       i,
+		j:       j,
+		token:   string(password[i : j+1]),
+		guesses: guesses,
+	}
+}
+
+// guessesToScore rates guesses from model.ScoreTooGuessable to model.ScoreVeryUnguessable
+func guessesToScore(guesses float64) int {
+	for score, threshold := range scoreThresholds {
+		if guesses < threshold+scoreDelta {
+			return score
+		}
+	}
+	return model.ScoreVeryUnguessable
+}
+
+// strengthFeedback explains what makes a weak passphrase easy to guess, going by the longest
+// match it is made of, and how to choose a stronger one
+func strengthFeedback(score int, sequence []strengthMatch) (string, []string) {
+	if len(sequence) == 0 {
+		return "", []string{
+			"Use a few words, avoid common phrases",
+			"No need for symbols, digits, or uppercase letters",
+		}
+	}
+	if score == model.MaxPassphraseScore {
+		return "", nil
+	}
+	if score > model.ScoreSomewhatGuessable {
+		return "", []string{addWordsSuggestion}
+	}
+
+	longest := sequence[0]
+	for _, m := range sequence[1:] {
+		if len([]rune(m.token)) > len([]rune(longest.token)) {
+			longest = m
+		}
+	}
+	warning, suggestions := matchFeedback(longest, len(sequence) == 1)
+	return warning, append([]string{addWordsSuggestion}, suggestions...)
+}
+
+// matchFeedback explains what makes a match easy to guess; sole tells whether the match is
+// the whole passphrase
+func matchFeedback(m strengthMatch, sole bool) (string, []string) {
+	switch m.pattern {
+	case patternDictionary:
+		return dictionaryFeedback(m, sole)
+	case patternSpatial:
+		if m.turns == 1 {
+			return "Straight rows of keys are easy to guess",
+				[]string{"Use a longer keyboard pattern with more turns"}
+		}
+		return "Short keyboard patterns are easy to guess",
+			[]string{"Use a longer keyboard pattern with more turns"}
+	case patternRepeat:
+		if len([]rune(m.baseToken)) == 1 {
+			return `Repeats like "aaa" are easy to guess`,
+				[]string{"Avoid repeated words and characters"}
+		}
+		return `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`,
+			[]string{"Avoid repeated words and characters"}
+	case patternSequence:
+		return "Sequences like abc or 6543 are easy to guess", []string{"Avoid sequences"}
+	case patternYear:
+		return "Recent years are easy to guess",
+			[]string{"Avoid recent years", "Avoid years that are associated with you"}
+	case patternDate:
+		return "Dates are often easy to guess",
+			[]string{"Avoid dates and years that are associated with you"}
+	default:
+		return "", nil
+	}
+}
+
+// dictionaryFeedback explains what makes a match of a word list easy to guess
+func dictionaryFeedback(m strengthMatch, sole bool) (string, []string) {
+	return dictionaryWarning(m, sole), dictionarySuggestions(m)
+}
+
+// dictionaryWarning tells which kind of word a match of a word list is
+func dictionaryWarning(m strengthMatch, sole bool) string {
+	switch m.dictionary {
+	case dictionaryPasswords:
+		return passwordWarning(m, sole)
+	case dictionaryEnglish:
+		if sole {
+			return "A word by itself is easy to guess"
+		}
+	case dictionaryNames:
+		if sole {
+			return "Names and surnames by themselves are easy to guess"
+		}
+		return "Common names and surnames are easy to guess"
+	case dictionaryUserInputs:
+		return "Environment names and the name of the tool are easy to guess"
+	}
+	return ""
+}
+
+// passwordWarning tells how common the password a match of the password list is
+func passwordWarning(m strengthMatch, sole bool) string {
+	verbatim := sole && !m.l33t && !m.reversed
+	switch {
+	case verbatim && m.rank <= 10:
+		return "This is a top-10 common password"
+	case verbatim && m.rank <= 100:
+		return "This is a top-100 common password"
+	case verbatim:
+		return "This is a very common password"
+	case m.guesses <= 1e4:
+		return "This is similar to a commonly used password"
+	default:
+		return ""
+	}
+}
+
+// dictionarySuggestions tells why the capitalization, reversal or l33t characters of a
+// match of a word list do not make it much harder to guess
+func dictionarySuggestions(m strengthMatch) []string {
+	var suggestions []string
+	runes := []rune(m.token)
+	rest := string(runes[1:])
+	switch {
+	case len(runes) > 1 && unicode.IsUpper(runes[0]) && strings.ToLower(rest) == rest:
+		suggestions = append(suggestions, "Capitalization doesn't help very much")
+	case strings.ToUpper(m.token) == m.token && strings.ToLower(m.token) != m.token:
+		suggestions = append(suggestions,
+			"All-uppercase is almost as easy to guess as all-lowercase")
+	}
+	if m.reversed && len(runes) >= 4 {
+		suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+	}
+	if m.l33t {
+		suggestions = append(suggestions,
+			"Predictable substitutions like '@' instead of 'a' don't help very much")
+	}
+	return suggestions
+}
+
+// factorial returns n!
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
+
+// binomial returns the number of ways to choose k of n
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
@@ -0,0 +1,773 @@
+package security
+
+import (
+	"embed"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	patternDictionary = "dictionary"
+	patternSpatial    = "spatial"
+	patternRepeat     = "repeat"
+	patternSequence   = "sequence"
+	patternYear       = "year"
+	patternDate       = "date"
+	patternBruteforce = "bruteforce"
+
+	dictionaryPasswords  = "passwords"
+	dictionaryEnglish    = "english"
+	dictionaryNames      = "names"
+	dictionaryUserInputs = "user_inputs"
+
+	// maxL33tSubstitutions bounds how many ways of reading ambiguous l33t characters, such
+	// as 1 for i or l, are tried.
+	maxL33tSubstitutions = 64
+	// maxSequenceDelta is the largest step between characters of a sequence, as in 2468.
+	maxSequenceDelta = 5
+	// obviousSequenceStarts are the characters sequences are guessed from first, before
+	// other digits and then letters.
+	obviousSequenceStarts  = "aAzZ019"
+	obviousSequenceGuesses = 4
+	digitSequenceGuesses   = 10
+	letterSequenceGuesses  = 26
+	// yearDigits is the length of the years looked for.
+	yearDigits = 4
+	// minYearSpace is the fewest years counted between a year or date and the current year.
+	minYearSpace = 20
+	// minDateYear and maxDateYear bound the four-digit years of dates.
+	minDateYear = 1000
+	maxDateYear = 2050
+	// twoDigitYearPivot is the last two-digit year read as 20xx rather than 19xx.
+	twoDigitYearPivot = 50
+	// minDateDigits and maxDateLen bound the length of dates, as in 1990 and 1990-05-12.
+	minDateDigits = 4
+	maxDateLen    = 10
+	maxDay        = 31
+	maxMonth      = 12
+	daysPerYear   = 365
+	// separatorVariations counts the separators a date may be written with.
+	separatorVariations = 4
+)
+
+// wordlists holds the ranked word lists attackers try first, most common first: passwords
+// and English words and names, taken from the frequency lists of zxcvbn under its MIT
+// license; see wordlists/NOTICE.
+//
+//go:embed wordlists/*.txt
+var wordlists embed.FS
+
+// referenceYear is the year recent years and dates are counted from.
+var referenceYear = time.Now().Year()
+
+// strengthMatch is a part of a passphrase that an attacker guesses as a whole, such as a
+// common word or a keyboard pattern.
+type strengthMatch struct {
+	pattern string
+	// i and j are the runes of the passphrase the match spans, j included.
+	i, j    int
+	token   string
+	guesses float64
+
+	// dictionary, rank, reversed and l33t describe dictionary matches.
+	dictionary string
+	rank       int
+	reversed   bool
+	l33t       bool
+	// turns is the number of changes of direction of spatial matches.
+	turns int
+	// baseToken is the repeated part of repeat matches.
+	baseToken string
+}
+
+// rankedDictionary maps each word of a word list to its rank.
+type rankedDictionary struct {
+	name   string
+	ranks  map[string]int
+	maxLen int
+}
+
+// loadDictionaries reads the embedded word lists the first time a passphrase is estimated,
+// so other commands do not pay for it
+var loadDictionaries = sync.OnceValue(func() []rankedDictionary {
+	names := []string{dictionaryPasswords, dictionaryEnglish, dictionaryNames}
+	dictionaries := make([]rankedDictionary, 0, len(names))
+	for _, name := range names {
+		data, err := wordlists.ReadFile("wordlists/" + name + ".txt")
+		if err != nil {
+			panic(fmt.Sprintf("embedded word list %s is missing: %v", name, err))
+		}
+		dictionaries = append(dictionaries, newRankedDictionary(name, strings.Fields(string(data))))
+	}
+	return dictionaries
+})
+
+// newRankedDictionary ranks words by their position in the list
+func newRankedDictionary(name string, words []string) rankedDictionary {
+	d := rankedDictionary{name: name, ranks: rankWords(words)}
+	for word := range d.ranks {
+		d.maxLen = max(d.maxLen, len([]rune(word)))
+	}
+	return d
+}
+
+// rankWords maps each word to its position in words, counted from 1, keeping the first one
+// of duplicates
+func rankWords(words []string) map[string]int {
+	ranks := make(map[string]int, len(words))
+	for i, word := range words {
+		if _, ok := ranks[word]; !ok && word != "" {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}
+
+// matches returns every match found in password
+func (e *strengthEstimator) matches(password []rune) []strengthMatch {
+	dictionaries := append(slices.Clip(loadDictionaries()), e.userInputs)
+
+	var matches []strengthMatch
+	matches = append(matches, dictionaryMatches(password, dictionaries)...)
+	matches = append(matches, reversedDictionaryMatches(password, dictionaries)...)
+	matches = append(matches, l33tMatches(password, dictionaries)...)
+	matches = append(matches, spatialMatches(password)...)
+	matches = append(matches, e.repeatMatches(password)...)
+	matches = append(matches, sequenceMatches(password)...)
+	matches = append(matches, yearMatches(password)...)
+	matches = append(matches, dateMatches(password)...)
+	return matches
+}
+
+// dictionaryMatches returns the words of the dictionaries found in password, ignoring case
+func dictionaryMatches(password []rune, dictionaries []rankedDictionary) []strengthMatch {
+	lower := toLower(password)
+	var matches []strengthMatch
+	for _, d := range dictionaries {
+		for i := range lower {
+			for j := i; j < len(lower) && j-i < d.maxLen; j++ {
+				rank, ok := d.ranks[string(lower[i:j+1])]
+				if !ok {
+					continue
+				}
+				token := string(password[i : j+1])
+				matches = append(matches, strengthMatch{
+					pattern:    patternDictionary,
+					i:          i,
+					j:          j,
+					token:      token,
+					guesses:    float64(rank) * uppercaseVariations(token),
+					dictionary: d.name,
+					rank:       rank,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// reversedDictionaryMatches returns the words of the dictionaries found written backwards
+// in password, which take twice as many guesses
+func reversedDictionaryMatches(password []rune, dictionaries []rankedDictionary) []strengthMatch {
+	n := len(password)
+	reversed := make([]rune, n)
+	for i, r := range password {
+		reversed[n-1-i] = r
+	}
+
+	var matches []strengthMatch
+	for _, m := range dictionaryMatches(reversed, dictionaries) {
+		m.i, m.j = n-1-m.j, n-1-m.i
+		token := string(password[m.i : m.j+1])
+		if strings.EqualFold(token, m.token) {
+			// Palindromes are found forwards already.
+			continue
+		}
+		m.token, m.reversed = token, true
+		m.guesses *= 2
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// l33tTable maps characters to the letters they stand for in l33t speak.
+var l33tTable = map[rune][]rune{
+	'4': {'a'}, '@': {'a'}, '8': {'b'}, '(': {'c'}, '{': {'c'}, '[': {'c'}, '<': {'c'},
+	'3': {'e'}, '6': {'g'}, '9': {'g'}, '1': {'i', 'l'}, '!': {'i'}, '|': {'i', 'l'},
+	'7': {'l', 't'}, '0': {'o'}, '$': {'s'}, '5': {'s'}, '+': {'t'}, '%': {'x'}, '2': {'z'},
+}
+
+// l33tMatches returns the words of the dictionaries found in password once its l33t
+// characters are read as letters, such as p@ssw0rd
+func l33tMatches(password []rune, dictionaries []rankedDictionary) []strengthMatch {
+	lower := toLower(password)
+	var matches []strengthMatch
+	for _, sub := range l33tSubstitutions(lower) {
+		translated := make([]rune, len(lower))
+		for i, r := range lower {
+			if letter, ok := sub[r]; ok {
+				translated[i] = letter
+			} else {
+				translated[i] = r
+			}
+		}
+
+		for _, m := range dictionaryMatches(translated, dictionaries) {
+			used := make(map[rune]rune)
+			for _, r := range lower[m.i : m.j+1] {
+				if letter, ok := sub[r]; ok {
+					used[r] = letter
+				}
+			}
+			// A single l33t character is more likely a digit or symbol than a word.
+			if len(used) == 0 || m.j == m.i {
+				continue
+			}
+
+			token := string(password[m.i : m.j+1])
+			m.token, m.l33t = token, true
+			m.guesses = float64(m.rank) * uppercaseVariations(token) *
+				l33tVariations(toLower([]rune(token)), used)
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// l33tSubstitutions returns the ways of reading the l33t characters of password as letters
+func l33tSubstitutions(password []rune) []map[rune]rune {
+	var present []rune
+	seen := make(map[rune]bool)
+	for _, r := range password {
+		if _, ok := l33tTable[r]; ok && !seen[r] {
+			seen[r] = true
+			present = append(present, r)
+		}
+	}
+	if len(present) == 0 {
+		return nil
+	}
+
+	subs := []map[rune]rune{{}}
+	for _, r := range present {
+		var next []map[rune]rune
+		for _, sub := range subs {
+			for _, letter := range l33tTable[r] {
+				if len(next) == maxL33tSubstitutions {
+					break
+				}
+				extended := make(map[rune]rune, len(sub)+1)
+				for k, v := range sub {
+					extended[k] = v
+				}
+				extended[r] = letter
+				next = append(next, extended)
+			}
+		}
+		subs = next
+	}
+	return subs
+}
+
+// uppercaseVariations returns how many ways of capitalizing token an attacker tries before
+// finding it: only the first or last letter or every letter are tried first
+func uppercaseVariations(token string) float64 {
+	runes := []rune(token)
+	var upper, lower int
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 {
+		return 2
+	}
+	if upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1])) {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+// l33tVariations returns how many ways of substituting letters of token an attacker tries
+// before finding it, given the l33t characters it uses
+func l33tVariations(token []rune, used map[rune]rune) float64 {
+	variations := 1.0
+	for l33t, letter := range used {
+		var subbed, unsubbed int
+		for _, r := range token {
+			switch r {
+			case l33t:
+				subbed++
+			case letter:
+				unsubbed++
+			}
+		}
+		if subbed == 0 || unsubbed == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for i := 1; i <= min(subbed, unsubbed); i++ {
+			possibilities += binomial(subbed+unsubbed, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+// keyboardRows are the rows of a qwerty keyboard, unshifted and shifted, and the column
+// their first key is in; every row sits half a key to the right of the one above.
+var keyboardRows = []struct {
+	keys, shifted string
+	start         int
+}{
+	{"`1234567890-=", "~!@#$%^&*()_+", -1},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|", 0},
+	{"asdfghjkl;'", "ASDFGHJKL:\"", 0},
+	{"zxcvbnm,./", "ZXCVBNM<>?", 0},
+}
+
+// keyPosition is where a key is on the keyboard.
+type keyPosition struct {
+	x, y int
+}
+
+// keyboardNeighbors are the directions of the keys around a key: left, upper left, upper
+// right, right, lower right and lower left.
+var keyboardNeighbors = []keyPosition{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+
+// keyboard maps characters to their key and tells whether they need shift
+type keyboard struct {
+	positions map[rune]keyPosition
+	shifted   map[rune]bool
+	keys      map[keyPosition]bool
+	// averageDegree is the average number of neighbors of a key.
+	averageDegree float64
+}
+
+// qwerty is the keyboard keyboard patterns are looked for on
+var qwerty = sync.OnceValue(func() keyboard {
+	kb := keyboard{
+		positions: make(map[rune]keyPosition),
+		shifted:   make(map[rune]bool),
+		keys:      make(map[keyPosition]bool),
+	}
+	for y, row := range keyboardRows {
+		shifted := []rune(row.shifted)
+		for i, r := range []rune(row.keys) {
+			pos := keyPosition{row.start + i, y}
+			kb.positions[r], kb.positions[shifted[i]] = pos, pos
+			kb.shifted[shifted[i]] = true
+			kb.keys[pos] = true
+		}
+	}
+
+	neighbors := 0
+	for pos := range kb.keys {
+		for _, d := range keyboardNeighbors {
+			if kb.keys[keyPosition{pos.x + d.x, pos.y + d.y}] {
+				neighbors++
+			}
+		}
+	}
+	kb.averageDegree = float64(neighbors) / float64(len(kb.keys))
+	return kb
+})
+
+// direction returns the direction of the key of b from the key of a, or -1 when they are
+// not neighbors
+func (kb keyboard) direction(a, b rune) int {
+	from, ok := kb.positions[a]
+	if !ok {
+		return -1
+	}
+	to, ok := kb.positions[b]
+	if !ok {
+		return -1
+	}
+	for i, d := range keyboardNeighbors {
+		if from.x+d.x == to.x && from.y+d.y == to.y {
+			return i
+		}
+	}
+	return -1
+}
+
+// spatialMatches returns the runs of at least three neighboring keys in password, such as
+// qwerty or zxcvfr
+func spatialMatches(password []rune) []strengthMatch {
+	kb := qwerty()
+	var matches []strengthMatch
+	for i := 0; i < len(password)-1; {
+		j, turns, shifted, lastDirection := i+1, 0, 0, -1
+		if kb.shifted[password[i]] {
+			shifted++
+		}
+		for ; j < len(password); j++ {
+			direction := kb.direction(password[j-1], password[j])
+			if direction < 0 {
+				break
+			}
+			if kb.shifted[password[j]] {
+				shifted++
+			}
+			if direction != lastDirection {
+				turns++
+				lastDirection = direction
+			}
+		}
+		if j-i > 2 {
+			matches = append(matches, strengthMatch{
+				pattern: patternSpatial,
+				i:       i,
+				j:       j - 1,
+				token:   string(password[i:j]),
+				guesses: kb.guesses(j-i, turns, shifted),
+				turns:   turns,
+			})
+		}
+		i = j
+	}
+	return matches
+}
+
+// guesses returns how many keyboard patterns of up to length keys with up to turns changes
+// of direction an attacker tries, times the ways of pressing shift on some of the keys
+func (kb keyboard) guesses(length, turns, shifted int) float64 {
+	starts := float64(len(kb.keys))
+	guesses := 0.0
+	for l := 2; l <= length; l++ {
+		for t := 1; t <= min(turns, l-1); t++ {
+			guesses += binomial(l-1, t-1) * starts * math.Pow(kb.averageDegree, float64(t))
+		}
+	}
+
+	if shifted > 0 {
+		unshifted := length - shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			variations := 0.0
+			for i := 1; i <= min(shifted, unshifted); i++ {
+				variations += binomial(shifted+unshifted, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+// repeatMatches returns the runs of a repeated part of password, such as aaa or abcabc,
+// which take as many guesses as their part times the number of repeats
+func (e *strengthEstimator) repeatMatches(password []rune) []strengthMatch {
+	var matches []strengthMatch
+	for i := 0; i < len(password); {
+		baseLen, count := longestRepeat(password[i:])
+		if count < 2 {
+			i++
+			continue
+		}
+
+		j := i + baseLen*count - 1
+		base := password[i : i+baseLen]
+		baseGuesses, _ := e.mostGuessable(base)
+		matches = append(matches, strengthMatch{
+			pattern:   patternRepeat,
+			i:         i,
+			j:         j,
+			token:     string(password[i : j+1]),
+			guesses:   baseGuesses * float64(count),
+			baseToken: string(base),
+		})
+		i = j + 1
+	}
+	return matches
+}
+
+// longestRepeat returns the length of the shortest part repeated from the start of password
+// over the most runes, and how many times it is repeated
+func longestRepeat(password []rune) (int, int) {
+	bestLen, bestCount := 0, 0
+	for baseLen := 1; baseLen*2 <= len(password); baseLen++ {
+		count := 1
+		for start := baseLen; start+baseLen <= len(password); start += baseLen {
+			if string(password[start:start+baseLen]) != string(password[:baseLen]) {
+				break
+			}
+			count++
+		}
+		if count >= 2 && baseLen*count > bestLen*bestCount {
+			bestLen, bestCount = baseLen, count
+		}
+	}
+	return bestLen, bestCount
+}
+
+// sequenceMatches returns the runs of characters that step by the same small amount, such
+// as abc, 6543 or 2468
+func sequenceMatches(password []rune) []strengthMatch {
+	if len(password) < 3 {
+		return nil
+	}
+
+	var matches []strengthMatch
+	add := func(i, j, delta int) {
+		if j-i < 2 || delta == 0 || delta > maxSequenceDelta || delta < -maxSequenceDelta {
+			return
+		}
+		matches = append(matches, strengthMatch{
+			pattern: patternSequence,
+			i:       i,
+			j:       j,
+			token:   string(password[i : j+1]),
+			guesses: sequenceGuesses(password[i:j+1], delta > 0),
+		})
+	}
+
+	i, lastDelta := 0, int(password[1]-password[0])
+	for k := 2; k < len(password); k++ {
+		delta := int(password[k] - password[k-1])
+		if delta == lastDelta {
+			continue
+		}
+		add(i, k-1, lastDelta)
+		i, lastDelta = k-1, delta
+	}
+	add(i, len(password)-1, lastDelta)
+	return matches
+}
+
+// sequenceGuesses returns how many sequences an attacker tries before token: the obvious
+// starts first, then digits, then letters, going up before going down
+func sequenceGuesses(token []rune, ascending bool) float64 {
+	var base float64
+	switch first := token[0]; {
+	case strings.ContainsRune(obviousSequenceStarts, first):
+		base = obviousSequenceGuesses
+	case unicode.IsDigit(first):
+		base = digitSequenceGuesses
+	default:
+		base = letterSequenceGuesses
+	}
+	if !ascending {
+		base *= 2
+	}
+	return base * float64(len(token))
+}
+
+// yearMatches returns the years from 1900 to 2099 in password
+func yearMatches(password []rune) []strengthMatch {
+	var matches []strengthMatch
+	for i := 0; i+yearDigits <= len(password); i++ {
+		token := string(password[i : i+yearDigits])
+		if !isDigits(password[i:i+yearDigits]) ||
+			(!strings.HasPrefix(token, "19") && !strings.HasPrefix(token, "20")) {
+			continue
+		}
+		year, _ := strconv.Atoi(token)
+		matches = append(matches, strengthMatch{
+			pattern: patternYear,
+			i:       i,
+			j:       i + yearDigits - 1,
+			token:   token,
+			guesses: yearSpace(year),
+		})
+	}
+	return matches
+}
+
+// dateSeparators are the characters dates are written with, as in 1990-05-12.
+const dateSeparators = " -/\\_."
+
+// dateSplits are the ways of splitting 4 to 8 digits into a day, a month and a year, as the
+// positions the second and third part start at.
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+// dateMatches returns the dates in password, written with digits only, such as 12051990, or
+// with separators, such as 12/5/90
+func dateMatches(password []rune) []strengthMatch {
+	var matches []strengthMatch
+	for i := range password {
+		for j := i + minDateDigits - 1; j < len(password) && j-i < maxDateLen; j++ {
+			token := password[i : j+1]
+			year, ok := digitsDateYear(token)
+			guesses := yearSpace(year) * daysPerYear
+			if !ok {
+				year, ok = separatedDateYear(token)
+				guesses = yearSpace(year) * daysPerYear * separatorVariations
+			}
+			if !ok {
+				continue
+			}
+			matches = append(matches, strengthMatch{
+				pattern: patternDate,
+				i:       i,
+				j:       j,
+				token:   string(token),
+				guesses: guesses,
+			})
+		}
+	}
+	return matches
+}
+
+// digitsDateYear returns the year of the date written with digits only that token is,
+// choosing the reading whose year is closest to referenceYear
+func digitsDateYear(token []rune) (int, bool) {
+	splits, ok := dateSplits[len(token)]
+	if !ok || !isDigits(token) {
+		return 0, false
+	}
+
+	best, found := 0, false
+	for _, split := range splits {
+		a, _ := strconv.Atoi(string(token[:split[0]]))
+		b, _ := strconv.Atoi(string(token[split[0]:split[1]]))
+		c, _ := strconv.Atoi(string(token[split[1]:]))
+		year, ok := dateYear(a, b, c)
+		if ok && (!found || yearSpace(year) < yearSpace(best)) {
+			best, found = year, true
+		}
+	}
+	return best, found
+}
+
+// separatedDateYear returns the year of the date written with separators that token is,
+// such as 1990-05-12: up to four digits, one or two digits and up to four digits, with the
+// same separator twice
+func separatedDateYear(token []rune) (int, bool) {
+	sep := firstNonDigit(token)
+	if sep == len(token) || !strings.ContainsRune(dateSeparators, token[sep]) {
+		return 0, false
+	}
+	parts := strings.Split(string(token), string(token[sep]))
+	if len(parts) != 3 || len(parts[1]) > 2 {
+		return 0, false
+	}
+
+	var ints [3]int
+	for i, part := range parts {
+		if part == "" || len(part) > 4 || !isDigits([]rune(part)) {
+			return 0, false
+		}
+		ints[i], _ = strconv.Atoi(part)
+	}
+	return dateYear(ints[0], ints[1], ints[2])
+}
+
+// dateYear returns the year of the date made of three numbers, in either day, month and year
+// order or year, month and day order, if they make one
+func dateYear(a, b, c int) (int, bool) {
+	if !plausibleDate(a, b, c) {
+		return 0, false
+	}
+
+	readings := [][3]int{{c, a, b}, {a, b, c}}
+	for _, r := range readings {
+		if r[0] >= minDateYear && r[0] <= maxDateYear {
+			return r[0], isDayMonth(r[1], r[2])
+		}
+	}
+	for _, r := range readings {
+		if !isDayMonth(r[1], r[2]) {
+			continue
+		}
+		if r[0] > twoDigitYearPivot {
+			return r[0] + 1900, true
+		}
+		return r[0] + 2000, true
+	}
+	return 0, false
+}
+
+// plausibleDate reports whether three numbers could be a day, a month and a year: the middle
+// one is a day or a month, none is a year out of range, and at most one is above 31
+func plausibleDate(a, b, c int) bool {
+	if b > maxDay || b <= 0 {
+		return false
+	}
+	var over12, over31, under1 int
+	for _, n := range []int{a, b, c} {
+		if (n > 99 && n < minDateYear) || n > maxDateYear {
+			return false
+		}
+		if n > maxDay {
+			over31++
+		}
+		if n > maxMonth {
+			over12++
+		}
+		if n <= 0 {
+			under1++
+		}
+	}
+	return over31 < 2 && over12 < 3 && under1 < 2
+}
+
+// isDayMonth reports whether a and b are a day and a month, in either order
+func isDayMonth(a, b int) bool {
+	isDay := func(n int) bool { return n >= 1 && n <= maxDay }
+	isMonth := func(n int) bool { return n >= 1 && n <= maxMonth }
+	return (isDay(a) && isMonth(b)) || (isDay(b) && isMonth(a))
+}
+
+// yearSpace returns how many years an attacker tries before year
+func yearSpace(year int) float64 {
+	space := year - referenceYear
+	if space < 0 {
+		space = -space
+	}
+	return float64(max(space, minYearSpace))
+}
+
+// isDigits reports whether runes holds only ASCII digits
+func isDigits(runes []rune) bool {
+	for _, r := range runes {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// firstNonDigit returns the index of the first rune of runes that is not an ASCII digit
+func firstNonDigit(runes []rune) int {
+	for i, r := range runes {
+		if r < '0' || r > '9' {
+			return i
+		}
+	}
+	return len(runes)
+}
+
+// toLower returns runes in lower case
+func toLower(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
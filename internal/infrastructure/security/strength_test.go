@@ -0,0 +1,94 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+func TestEstimateStrength(t *testing.T) {
+	tests := []struct {
+		passphrase  string
+		wantScore   int
+		wantWarning string
+	}{
+		{"password", model.ScoreTooGuessable, "This is a top-10 common password"},
+		{"purple", model.ScoreTooGuessable, "This is a top-100 common password"},
+		{"P@ssw0rd", model.ScoreTooGuessable, "This is similar to a commonly used password"},
+		{"drowssap", model.ScoreTooGuessable, "This is similar to a commonly used password"},
+		{"Jessica", model.ScoreTooGuessable, "Names and surnames by themselves are easy to guess"},
+		{"aaaaaaaa", model.ScoreTooGuessable, `Repeats like "aaa" are easy to guess`},
+		{"abcdefgh", model.ScoreTooGuessable, "Sequences like abc or 6543 are easy to guess"},
+		{"987654", model.ScoreTooGuessable, "Sequences like abc or 6543 are easy to guess"},
+		{"1994", model.ScoreTooGuessable, "Recent years are easy to guess"},
+		{"13/05/1994", model.ScoreVeryGuessable, "Dates are often easy to guess"},
+		{"monkey123", model.ScoreVeryGuessable, "This is similar to a commonly used password"},
+		{"correct horse battery staple", model.ScoreVeryUnguessable, ""},
+		{"eT6#qa9!Lk2vZp", model.ScoreVeryUnguessable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.passphrase, func(t *testing.T) {
+			got := EstimateStrength(tt.passphrase)
+			if got.Score != tt.wantScore {
+				t.Errorf("EstimateStrength() score = %d, want %d", got.Score, tt.wantScore)
+			}
+			if got.Warning != tt.wantWarning {
+				t.Errorf("EstimateStrength() warning = %q, want %q", got.Warning, tt.wantWarning)
+			}
+			if tt.wantScore < model.ScoreSafelyUnguessable && len(got.Suggestions) == 0 {
+				t.Error("EstimateStrength() of a weak passphrase should suggest a stronger one")
+			}
+		})
+	}
+}
+
+func TestEstimateStrength_KeyboardPattern(t *testing.T) {
+	got := EstimateStrength("1qaz2wsx3edc")
+	if got.Score > model.ScoreVeryGuessable {
+		t.Errorf("EstimateStrength() score = %d, want a guessable keyboard pattern", got.Score)
+	}
+}
+
+func TestEstimateStrength_UserInputs(t *testing.T) {
+	without := EstimateStrength("Staging")
+	with := EstimateStrength("Staging", "staging", toolName)
+	if with.Guesses >= without.Guesses {
+		t.Errorf("EstimateStrength() with user inputs = %g guesses, want fewer than %g",
+			with.Guesses, without.Guesses)
+	}
+	want := "Environment names and the name of the tool are easy to guess"
+	if with.Warning != want {
+		t.Errorf("EstimateStrength() warning = %q, want %q", with.Warning, want)
+	}
+}
+
+func TestEstimateStrength_LongPassphrase(t *testing.T) {
+	start := strings.Repeat("eT6#qa9!Lk", maxStrengthRunes/10)
+	got := EstimateStrength(start + "and some more words")
+	want := EstimateStrength(start)
+	if got.Guesses != want.Guesses {
+		t.Errorf("EstimateStrength() = %g guesses, want only the first %d runes rated (%g)",
+			got.Guesses, maxStrengthRunes, want.Guesses)
+	}
+}
+
+func TestGuessesToScore(t *testing.T) {
+	tests := []struct {
+		guesses float64
+		want    int
+	}{
+		{1, model.ScoreTooGuessable},
+		{1e3 + scoreDelta, model.ScoreVeryGuessable},
+		{1e6 + scoreDelta, model.ScoreSomewhatGuessable},
+		{1e8 + scoreDelta, model.ScoreSafelyUnguessable},
+		{1e10 + scoreDelta, model.ScoreVeryUnguessable},
+	}
+
+	for _, tt := range tests {
+		if got := guessesToScore(tt.guesses); got != tt.want {
+			t.Errorf("guessesToScore(%g) = %d, want %d", tt.guesses, got, tt.want)
+		}
+	}
+}
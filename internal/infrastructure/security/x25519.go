@@ -0,0 +1,183 @@
+package security
+
+import (
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+const (
+	// publicKeyPrefix starts every encoded X25519 recipient public key.
+	publicKeyPrefix = "lockify1"
+	// privateKeyPrefix starts every encoded X25519 identity private key.
+	privateKeyPrefix = "LOCKIFY-SECRET-KEY-1"
+	// recipientKeyInfo separates the recipient wrapping key from other keys in HKDF.
+	recipientKeyInfo = "lockify-x25519-recipient"
+	// x25519KeySize is the size in bytes of X25519 public and private keys.
+	x25519KeySize = 32
+)
+
+// GenerateIdentity creates a new X25519 key pair
+func (e *AESEncryptionService) GenerateIdentity() (model.Identity, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("failed to generate identity: %w", err)
+	}
+
+	return model.Identity{
+		PublicKey:  encodePublicKey(privateKey.PublicKey()),
+		PrivateKey: privateKeyPrefix + base64.RawURLEncoding.EncodeToString(privateKey.Bytes()),
+	}, nil
+}
+
+// IdentityPublicKey derives the recipient public key of an identity private key
+func (e *AESEncryptionService) IdentityPublicKey(privateKey string) (string, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return encodePublicKey(key.PublicKey()), nil
+}
+
+// NewRecipientSession unwraps the data key of a recipient with the identity private key
+// and returns an AES-GCM session bound to it
+func (e *AESEncryptionService) NewRecipientSession(
+	meta model.Meta,
+	recipient model.Recipient,
+	identity model.Identity,
+) (model.Session, error) {
+	privateKey, err := parsePrivateKey(identity.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if encodePublicKey(privateKey.PublicKey()) != recipient.PublicKey {
+		return nil, fmt.Errorf("identity does not match recipient %q", recipient.Label())
+	}
+
+	cipherParams, kdfParams := meta.CryptoParams()
+	if err := validateParams(cipherParams, kdfParams); err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(recipient.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped data key encoding: %w", err)
+	}
+	if len(raw) < x25519KeySize {
+		return nil, fmt.Errorf("wrapped data key too short")
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(raw[:x25519KeySize])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %w", err)
+	}
+	kek, err := recipientKEK(privateKey, ephemeral, ephemeral, privateKey.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	defer clearBytes(kek)
+
+	dataKey, err := openDataKey(kek, cipherParams.NonceSize, meta.Env, raw[x25519KeySize:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key for recipient %q", recipient.Label())
+	}
+	return newAESSession(dataKey, cipherParams, meta, true)
+}
+
+// WrapKeyForRecipient seals the session data key with a key agreed between a new ephemeral
+// key pair and the recipient public key. The ephemeral public key is stored in front.
+func (s *aesSession) WrapKeyForRecipient(meta model.Meta, publicKey string) (string, error) {
+	if s.aead == nil {
+		return "", fmt.Errorf("session is closed")
+	}
+	if !s.dataKey {
+		return "", fmt.Errorf("session is not bound to a data key")
+	}
+
+	recipientKey, err := parsePublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+
+	kek, err := recipientKEK(ephemeral, recipientKey, ephemeral.PublicKey(), recipientKey)
+	if err != nil {
+		return "", err
+	}
+	defer clearBytes(kek)
+
+	cipherParams, _ := meta.CryptoParams()
+	sealed, err := sealDataKey(kek, cipherParams.NonceSize, meta.Env, s.key)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped := append(ephemeral.PublicKey().Bytes(), sealed...)
+	return base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+// recipientKEK derives the key that wraps the data key for a recipient from the X25519
+// shared secret of privateKey and peer, bound to the ephemeral and recipient public keys
+func recipientKEK(
+	privateKey *ecdh.PrivateKey,
+	peer, ephemeral, recipient *ecdh.PublicKey,
+) ([]byte, error) {
+	shared, err := privateKey.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to agree on recipient key: %w", err)
+	}
+	defer clearBytes(shared)
+
+	salt := append(ephemeral.Bytes(), recipient.Bytes()...)
+
+	kek, err := hkdf.Key(sha256.New, shared, salt, recipientKeyInfo, int(aes256KeyLength))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive recipient key: %w", err)
+	}
+	return kek, nil
+}
+
+// encodePublicKey encodes an X25519 public key as a recipient string
+func encodePublicKey(publicKey *ecdh.PublicKey) string {
+	return publicKeyPrefix + base64.RawURLEncoding.EncodeToString(publicKey.Bytes())
+}
+
+// parsePublicKey decodes a recipient string into an X25519 public key
+func parsePublicKey(publicKey string) (*ecdh.PublicKey, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(publicKey), publicKeyPrefix)
+	if !ok {
+		return nil, fmt.Errorf(
+			"invalid recipient %q: expected a %s... public key",
+			publicKey,
+			publicKeyPrefix,
+		)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != x25519KeySize {
+		return nil, fmt.Errorf("invalid recipient %q: malformed public key", publicKey)
+	}
+	return ecdh.X25519().NewPublicKey(raw)
+}
+
+// parsePrivateKey decodes an identity private key
+func parsePrivateKey(privateKey string) (*ecdh.PrivateKey, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(privateKey), privateKeyPrefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid identity: expected a %s... private key", privateKeyPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != x25519KeySize {
+		return nil, fmt.Errorf("invalid identity: malformed private key")
+	}
+	defer clearBytes(raw)
+	return ecdh.X25519().NewPrivateKey(raw)
+}
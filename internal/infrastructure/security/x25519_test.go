@@ -0,0 +1,209 @@
+package security
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
+)
+
+// createRecipientMeta creates recipient vault meta, a data key session for it and a
+// recipient wrapping that data key for a new identity
+func createRecipientMeta(
+	t *testing.T,
+) (model.Meta, model.Session, model.Recipient, model.Identity) {
+	t.Helper()
+	encryptionService := createTestEncryptionService(t)
+	meta := createTestMeta(t, createTestSalt(t))
+	meta.FormatVersion = model.FormatVersionRecipients
+
+	dataKey, err := encryptionService.NewDataKey(meta)
+	if err != nil {
+		t.Fatalf("NewDataKey() returned unexpected error: %v", err)
+	}
+	t.Cleanup(dataKey.Close)
+
+	identity, err := encryptionService.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+	recipient := model.Recipient{Name: "alice", PublicKey: identity.PublicKey}
+	recipient.WrappedKey, err = dataKey.WrapKeyForRecipient(meta, identity.PublicKey)
+	if err != nil {
+		t.Fatalf("WrapKeyForRecipient() returned unexpected error: %v", err)
+	}
+	return meta, dataKey, recipient, identity
+}
+
+func TestGenerateIdentity(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+
+	identity, err := encryptionService.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(identity.PublicKey, publicKeyPrefix) {
+		t.Errorf("expected public key prefix %q, got %q", publicKeyPrefix, identity.PublicKey)
+	}
+	if !strings.HasPrefix(identity.PrivateKey, privateKeyPrefix) {
+		t.Errorf("expected private key to start with %q", privateKeyPrefix)
+	}
+
+	other, err := encryptionService.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+	if other.PublicKey == identity.PublicKey {
+		t.Error("GenerateIdentity() returned the same key pair twice")
+	}
+}
+
+func TestIdentityPublicKey(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	identity, err := encryptionService.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+
+	publicKey, err := encryptionService.IdentityPublicKey(identity.PrivateKey)
+	if err != nil {
+		t.Fatalf("IdentityPublicKey() returned unexpected error: %v", err)
+	}
+	if publicKey != identity.PublicKey {
+		t.Errorf("IdentityPublicKey() = %q, want %q", publicKey, identity.PublicKey)
+	}
+
+	if _, err := encryptionService.IdentityPublicKey("not-a-key"); err == nil {
+		t.Error("IdentityPublicKey() of a malformed private key should return an error")
+	}
+}
+
+func TestNewRecipientSession_UnwrapsDataKey(t *testing.T) {
+	meta, dataKey, recipient, identity := createRecipientMeta(t)
+	ciphertext, err := dataKey.Encrypt(testKey, []byte(testPlaintext))
+	if err != nil {
+		t.Fatalf("Encrypt() returned unexpected error: %v", err)
+	}
+
+	session, err := createTestEncryptionService(t).NewRecipientSession(meta, recipient, identity)
+	if err != nil {
+		t.Fatalf("NewRecipientSession() returned unexpected error: %v", err)
+	}
+	defer session.Close()
+	decrypted, err := session.Decrypt(testKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() returned unexpected error: %v", err)
+	}
+	if string(decrypted) != testPlaintext {
+		t.Errorf("Decrypt() returned %q, want %q", decrypted, testPlaintext)
+	}
+}
+
+func TestNewRecipientSession_OtherIdentity(t *testing.T) {
+	encryptionService := createTestEncryptionService(t)
+	meta, _, recipient, _ := createRecipientMeta(t)
+	other, err := encryptionService.GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+
+	_, err = encryptionService.NewRecipientSession(meta, recipient, other)
+	if err == nil {
+		t.Fatal("NewRecipientSession() with another identity expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "does not match recipient") {
+		t.Errorf("NewRecipientSession() returned unexpected error: %v", err)
+	}
+}
+
+func TestNewRecipientSession_WrappedKeyBoundToEnv(t *testing.T) {
+	meta, _, recipient, identity := createRecipientMeta(t)
+	meta.Env = "prod"
+
+	_, err := createTestEncryptionService(t).NewRecipientSession(meta, recipient, identity)
+	if err == nil {
+		t.Error("NewRecipientSession() with a key wrapped for another env expected error, got nil")
+	}
+}
+
+func TestNewRecipientSession_TamperedWrappedKey(t *testing.T) {
+	meta, _, recipient, identity := createRecipientMeta(t)
+	raw, _ := base64.StdEncoding.DecodeString(recipient.WrappedKey)
+	raw[len(raw)-1] ^= 0xff
+	recipient.WrappedKey = base64.StdEncoding.EncodeToString(raw)
+
+	_, err := createTestEncryptionService(t).NewRecipientSession(meta, recipient, identity)
+	if err == nil {
+		t.Fatal("NewRecipientSession() with a tampered wrapped key expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to unwrap data key") {
+		t.Errorf("NewRecipientSession() returned unexpected error: %v", err)
+	}
+}
+
+func TestNewRecipientSession_InvalidInput(t *testing.T) {
+	meta, _, recipient, identity := createRecipientMeta(t)
+	tests := []struct {
+		name     string
+		mutate   func(r *model.Recipient, i *model.Identity)
+		contains string
+	}{
+		{
+			name:     "malformed private key",
+			mutate:   func(r *model.Recipient, i *model.Identity) { i.PrivateKey = "not-a-key" },
+			contains: "invalid identity",
+		},
+		{
+			name:     "short wrapped key",
+			mutate:   func(r *model.Recipient, i *model.Identity) { r.WrappedKey = "c2hvcnQ=" },
+			contains: "too short",
+		},
+		{
+			name:     "invalid wrapped key encoding",
+			mutate:   func(r *model.Recipient, i *model.Identity) { r.WrappedKey = "%%%" },
+			contains: "invalid wrapped data key encoding",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, i := recipient, identity
+			tt.mutate(&r, &i)
+
+			_, err := createTestEncryptionService(t).NewRecipientSession(meta, r, i)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("expected error to contain %q, got %q", tt.contains, err.Error())
+			}
+		})
+	}
+}
+
+func TestWrapKeyForRecipient_InvalidPublicKey(t *testing.T) {
+	meta, dataKey, _, _ := createRecipientMeta(t)
+
+	for _, publicKey := range []string{"", "age1abc", publicKeyPrefix + "c2hvcnQ"} {
+		if _, err := dataKey.WrapKeyForRecipient(meta, publicKey); err == nil {
+			t.Errorf("WrapKeyForRecipient(%q) expected error, got nil", publicKey)
+		}
+	}
+}
+
+func TestWrapKeyForRecipient_LegacySession(t *testing.T) {
+	session := createTestSession(t, createTestSalt(t), testPassphrase)
+	identity, err := createTestEncryptionService(t).GenerateIdentity()
+	if err != nil {
+		t.Fatalf("GenerateIdentity() returned unexpected error: %v", err)
+	}
+
+	_, err = session.WrapKeyForRecipient(createTestMeta(t, createTestSalt(t)), identity.PublicKey)
+	if err == nil {
+		t.Fatal("WrapKeyForRecipient() on a passphrase-derived session expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "not bound to a data key") {
+		t.Errorf("WrapKeyForRecipient() returned unexpected error: %v", err)
+	}
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"github.com/ahmed-abdelgawad92/lockify/cmd"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				log.Print(exitErr.Err)
+			}
+			os.Exit(exitErr.Code)
+		}
 		log.Fatal(err)
 	}
 }
@@ -2,8 +2,14 @@ package test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ahmed-abdelgawad92/lockify/internal/domain/model"
 )
@@ -12,6 +18,7 @@ import (
 type MockPromptService struct {
 	GetUserInputFunc       func(isSecret bool) (key, value string, err error)
 	GetPassphraseInputFunc func(message string) (string, error)
+	ConfirmFunc            func(message string) (bool, error)
 }
 
 // GetUserInputForKeyAndValue mocks the GetUserInputForKeyAndValue method.
@@ -33,11 +40,23 @@ func (m *MockPromptService) GetPassphraseInput(message string) (string, error) {
 	return "test_passphrase", nil
 }
 
+// Confirm mocks the Confirm method.
+func (m *MockPromptService) Confirm(message string) (bool, error) {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(message)
+	}
+	return true, nil
+}
+
 // MockVaultService mocks the VaultService for testing.
 type MockVaultService struct {
-	OpenFunc   func(ctx context.Context, env string) (*model.Vault, error)
-	SaveFunc   func(ctx context.Context, vault *model.Vault) error
-	CreateFunc func(ctx context.Context, env string) (*model.Vault, error)
+	OpenFunc           func(ctx context.Context, env string) (*model.Vault, error)
+	OpenUnverifiedFunc func(ctx context.Context, env string) (*model.Vault, error)
+	OpenForUpdateFunc  func(ctx context.Context, env string) (*model.Vault, error)
+	SaveFunc           func(ctx context.Context, vault *model.Vault) error
+	CreateFunc         func(ctx context.Context, env string) (*model.Vault, error)
+	NewFunc            func(ctx context.Context, env, passphrase string) (*model.Vault, error)
+	SaveNewFunc        func(ctx context.Context, vault *model.Vault) error
 }
 
 // Open mocks the Open method.
@@ -45,11 +64,29 @@ func (m *MockVaultService) Open(ctx context.Context, env string) (*model.Vault,
 	if m.OpenFunc != nil {
 		return m.OpenFunc(ctx, env)
 	}
-	vault, _ := model.NewVault(env, "test-fingerprint", "test-salt")
+	vault, _ := model.NewVault(env, "test-salt")
 	vault.SetPassphrase("test-passphrase")
+	vault.SetSession(&MockSession{})
+	vault.SetUnlockedSlot(model.DefaultSlotName)
 	return vault, nil
 }
 
+// OpenUnverified mocks the OpenUnverified method.
+func (m *MockVaultService) OpenUnverified(ctx context.Context, env string) (*model.Vault, error) {
+	if m.OpenUnverifiedFunc != nil {
+		return m.OpenUnverifiedFunc(ctx, env)
+	}
+	return m.Open(ctx, env)
+}
+
+// OpenForUpdate mocks the OpenForUpdate method.
+func (m *MockVaultService) OpenForUpdate(ctx context.Context, env string) (*model.Vault, error) {
+	if m.OpenForUpdateFunc != nil {
+		return m.OpenForUpdateFunc(ctx, env)
+	}
+	return m.Open(ctx, env)
+}
+
 // Save mocks the Save method.
 func (m *MockVaultService) Save(ctx context.Context, vault *model.Vault) error {
 	if m.SaveFunc != nil {
@@ -63,39 +100,251 @@ func (m *MockVaultService) Create(ctx context.Context, env string) (*model.Vault
 	if m.CreateFunc != nil {
 		return m.CreateFunc(ctx, env)
 	}
-	vault, _ := model.NewVault(env, "test-fingerprint", "test-salt")
+	vault, _ := model.NewVault(env, "test-salt")
+	return vault, nil
+}
+
+// New mocks the New method.
+func (m *MockVaultService) New(ctx context.Context, env, passphrase string) (*model.Vault, error) {
+	if m.NewFunc != nil {
+		return m.NewFunc(ctx, env, passphrase)
+	}
+	vault, err := model.NewVault(env, "test-salt")
+	if err != nil {
+		return nil, err
+	}
+	vault.SetPassphrase(passphrase)
+	vault.SetSession(&MockSession{})
+	vault.SetUnlockedSlot(model.DefaultSlotName)
 	return vault, nil
 }
 
+// SaveNew mocks the SaveNew method.
+func (m *MockVaultService) SaveNew(ctx context.Context, vault *model.Vault) error {
+	if m.SaveNewFunc != nil {
+		return m.SaveNewFunc(ctx, vault)
+	}
+	return nil
+}
+
+// FixtureTimestamp is when the entries of fixture vaults were created and last updated.
+const FixtureTimestamp = "2024-01-01T00:00:00Z"
+
+// NewPrefixCipherVault returns an unlocked vault for env holding entries, given as plaintext.
+// Its mock session encrypts by prefixing the plaintext with the env and a colon, so tests
+// can tell values encrypted for different environments apart.
+func NewPrefixCipherVault(env string, entries map[string]string) *model.Vault {
+	prefix := env + ":"
+	vault, _ := model.NewVault(env, "test-salt")
+	vault.SetSession(&MockSession{
+		EncryptFunc: func(key string, plaintext []byte) (string, error) {
+			return prefix + string(plaintext), nil
+		},
+		DecryptFunc: func(key, ciphertext string) ([]byte, error) {
+			return []byte(strings.TrimPrefix(ciphertext, prefix)), nil
+		},
+	})
+	vault.SetUnlockedSlot(model.DefaultSlotName)
+	for key, plaintext := range entries {
+		vault.Entries[key] = model.Entry{
+			Value:     prefix + plaintext,
+			CreatedAt: FixtureTimestamp,
+			UpdatedAt: FixtureTimestamp,
+		}
+	}
+	return vault
+}
+
+// NewPrefixCipherVaultService returns a MockVaultService that opens, for reading or for
+// update, a fresh NewPrefixCipherVault for each environment of envs, and fails for others.
+func NewPrefixCipherVaultService(envs map[string]map[string]string) *MockVaultService {
+	open := func(ctx context.Context, env string) (*model.Vault, error) {
+		entries, ok := envs[env]
+		if !ok {
+			return nil, fmt.Errorf("vault for env %s does not exist", env)
+		}
+		return NewPrefixCipherVault(env, entries), nil
+	}
+	return &MockVaultService{OpenFunc: open, OpenForUpdateFunc: open}
+}
+
 // MockEncryptionService mocks the EncryptionService for testing.
 type MockEncryptionService struct {
-	EncryptFunc func(plaintext []byte, encodedSalt, passphrase string) (string, error)
-	DecryptFunc func(ciphertext, encodedSalt, passphrase string) ([]byte, error)
+	NewSessionFunc          func(meta model.Meta, passphrase string) (model.Session, error)
+	NewDataKeyFunc          func(meta model.Meta) (model.Session, error)
+	NewKeySessionFunc       func(meta model.Meta, key []byte) (model.Session, error)
+	NewRecipientSessionFunc func(
+		meta model.Meta,
+		recipient model.Recipient,
+		identity model.Identity,
+	) (model.Session, error)
+	GenerateIdentityFunc  func() (model.Identity, error)
+	IdentityPublicKeyFunc func(privateKey string) (string, error)
+	NewSaltFunc           func() (string, error)
+	DefaultParamsFunc     func() (model.CipherParams, model.KDFParams)
+}
+
+// NewSession mocks the NewSession method.
+func (m *MockEncryptionService) NewSession(
+	meta model.Meta,
+	passphrase string,
+) (model.Session, error) {
+	if m.NewSessionFunc != nil {
+		return m.NewSessionFunc(meta, passphrase)
+	}
+
+	return &MockSession{}, nil
+}
+
+// NewDataKey mocks the NewDataKey method.
+func (m *MockEncryptionService) NewDataKey(meta model.Meta) (model.Session, error) {
+	if m.NewDataKeyFunc != nil {
+		return m.NewDataKeyFunc(meta)
+	}
+	return &MockSession{}, nil
+}
+
+// NewKeySession mocks the NewKeySession method.
+func (m *MockEncryptionService) NewKeySession(meta model.Meta, key []byte) (model.Session, error) {
+	if m.NewKeySessionFunc != nil {
+		return m.NewKeySessionFunc(meta, key)
+	}
+	return &MockSession{}, nil
+}
+
+// NewRecipientSession mocks the NewRecipientSession method.
+func (m *MockEncryptionService) NewRecipientSession(
+	meta model.Meta,
+	recipient model.Recipient,
+	identity model.Identity,
+) (model.Session, error) {
+	if m.NewRecipientSessionFunc != nil {
+		return m.NewRecipientSessionFunc(meta, recipient, identity)
+	}
+	return &MockSession{}, nil
+}
+
+// GenerateIdentity mocks the GenerateIdentity method.
+func (m *MockEncryptionService) GenerateIdentity() (model.Identity, error) {
+	if m.GenerateIdentityFunc != nil {
+		return m.GenerateIdentityFunc()
+	}
+	return model.Identity{PublicKey: "lockify1-test", PrivateKey: "LOCKIFY-SECRET-KEY-1-test"}, nil
+}
+
+// IdentityPublicKey mocks the IdentityPublicKey method.
+func (m *MockEncryptionService) IdentityPublicKey(privateKey string) (string, error) {
+	if m.IdentityPublicKeyFunc != nil {
+		return m.IdentityPublicKeyFunc(privateKey)
+	}
+	return "lockify1-test", nil
+}
+
+// NewSalt mocks the NewSalt method.
+func (m *MockEncryptionService) NewSalt() (string, error) {
+	if m.NewSaltFunc != nil {
+		return m.NewSaltFunc()
+	}
+	return "test-salt", nil
+}
+
+// DefaultParams mocks the DefaultParams method.
+func (m *MockEncryptionService) DefaultParams() (model.CipherParams, model.KDFParams) {
+	if m.DefaultParamsFunc != nil {
+		return m.DefaultParamsFunc()
+	}
+
+	return model.LegacyCipherParams(), model.LegacyKDFParams()
+}
+
+// MockSession mocks an unlocked vault Session for testing.
+type MockSession struct {
+	EncryptFunc             func(key string, plaintext []byte) (string, error)
+	DecryptFunc             func(key, ciphertext string) ([]byte, error)
+	SealRecordFunc          func(domain string, plaintext []byte) (string, error)
+	OpenRecordFunc          func(domain, record string) ([]byte, error)
+	WrapKeyFunc             func(meta model.Meta, passphrase string) (string, error)
+	WrapKeyForRecipientFunc func(meta model.Meta, publicKey string) (string, error)
+	ExportKeyFunc           func() ([]byte, error)
+	MACFunc                 func(data []byte) (string, error)
+	Closed                  bool
 }
 
 // Encrypt mocks the Encrypt method.
-func (m *MockEncryptionService) Encrypt(
-	plaintext []byte,
-	encodedSalt, passphrase string,
-) (string, error) {
+func (m *MockSession) Encrypt(key string, plaintext []byte) (string, error) {
 	if m.EncryptFunc != nil {
-		return m.EncryptFunc(plaintext, encodedSalt, passphrase)
+		return m.EncryptFunc(key, plaintext)
 	}
 
 	return "encrypted-value", nil
 }
 
 // Decrypt mocks the Decrypt method.
-func (m *MockEncryptionService) Decrypt(
-	ciphertext, encodedSalt, passphrase string,
-) ([]byte, error) {
+func (m *MockSession) Decrypt(key, ciphertext string) ([]byte, error) {
 	if m.DecryptFunc != nil {
-		return m.DecryptFunc(ciphertext, encodedSalt, passphrase)
+		return m.DecryptFunc(key, ciphertext)
 	}
 
 	return []byte("decrypted-value"), nil
 }
 
+// SealRecord mocks the SealRecord method.
+func (m *MockSession) SealRecord(domain string, plaintext []byte) (string, error) {
+	if m.SealRecordFunc != nil {
+		return m.SealRecordFunc(domain, plaintext)
+	}
+
+	return "sealed-record", nil
+}
+
+// OpenRecord mocks the OpenRecord method.
+func (m *MockSession) OpenRecord(domain, record string) ([]byte, error) {
+	if m.OpenRecordFunc != nil {
+		return m.OpenRecordFunc(domain, record)
+	}
+
+	return []byte("opened-record"), nil
+}
+
+// WrapKey mocks the WrapKey method.
+func (m *MockSession) WrapKey(meta model.Meta, passphrase string) (string, error) {
+	if m.WrapKeyFunc != nil {
+		return m.WrapKeyFunc(meta, passphrase)
+	}
+	return "wrapped-key", nil
+}
+
+// WrapKeyForRecipient mocks the WrapKeyForRecipient method.
+func (m *MockSession) WrapKeyForRecipient(meta model.Meta, publicKey string) (string, error) {
+	if m.WrapKeyForRecipientFunc != nil {
+		return m.WrapKeyForRecipientFunc(meta, publicKey)
+	}
+	return "wrapped-key-" + publicKey, nil
+}
+
+// ExportKey mocks the ExportKey method.
+func (m *MockSession) ExportKey() ([]byte, error) {
+	if m.ExportKeyFunc != nil {
+		return m.ExportKeyFunc()
+	}
+	return []byte("data-key"), nil
+}
+
+// MAC mocks the MAC method.
+func (m *MockSession) MAC(data []byte) (string, error) {
+	if m.MACFunc != nil {
+		return m.MACFunc(data)
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// Close mocks the Close method.
+func (m *MockSession) Close() {
+	m.Closed = true
+}
+
 // MockLogger mocks the MockLogger for testing.
 type MockLogger struct {
 	InfoLogs     []string
@@ -195,10 +444,15 @@ func (m *MockImportService) FromDotEnv(r io.Reader) (map[string]string, error) {
 
 // MockVaultRepository mocks the VaultRepository for testing.
 type MockVaultRepository struct {
-	CreateFunc func(ctx context.Context, vault *model.Vault) error
-	LoadFunc   func(ctx context.Context, env string) (*model.Vault, error)
-	SaveFunc   func(ctx context.Context, vault *model.Vault) error
-	ExistsFunc func(ctx context.Context, env string) (bool, error)
+	CreateFunc  func(ctx context.Context, vault *model.Vault) error
+	LoadFunc    func(ctx context.Context, env string) (*model.Vault, error)
+	SaveFunc    func(ctx context.Context, vault *model.Vault) error
+	ExistsFunc  func(ctx context.Context, env string) (bool, error)
+	ListFunc    func(ctx context.Context) ([]string, error)
+	BackupFunc  func(ctx context.Context, env string) (string, error)
+	RestoreFunc func(ctx context.Context, env string, generation int) (string, error)
+	LockFunc    func(ctx context.Context, env string, exclusive bool) (func(), error)
+	DeleteFunc  func(ctx context.Context, env string) (string, error)
 }
 
 // Create mocks the Create method.
@@ -214,7 +468,7 @@ func (m *MockVaultRepository) Load(ctx context.Context, env string) (*model.Vaul
 	if m.LoadFunc != nil {
 		return m.LoadFunc(ctx, env)
 	}
-	vault, _ := model.NewVault(env, "test-fingerprint", "test-salt")
+	vault, _ := model.NewVault(env, "test-salt")
 	return vault, nil
 }
 
@@ -234,6 +488,80 @@ func (m *MockVaultRepository) Exists(ctx context.Context, env string) (bool, err
 	return false, nil
 }
 
+// List mocks the List method.
+func (m *MockVaultRepository) List(ctx context.Context) ([]string, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []string{}, nil
+}
+
+// Backup mocks the Backup method.
+func (m *MockVaultRepository) Backup(ctx context.Context, env string) (string, error) {
+	if m.BackupFunc != nil {
+		return m.BackupFunc(ctx, env)
+	}
+	return env + ".vault.enc.bak.1", nil
+}
+
+// Restore mocks the Restore method.
+func (m *MockVaultRepository) Restore(
+	ctx context.Context,
+	env string,
+	generation int,
+) (string, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, env, generation)
+	}
+	return fmt.Sprintf("%s.vault.enc.bak.%d", env, generation), nil
+}
+
+// Lock mocks the Lock method.
+func (m *MockVaultRepository) Lock(
+	ctx context.Context,
+	env string,
+	exclusive bool,
+) (func(), error) {
+	if m.LockFunc != nil {
+		return m.LockFunc(ctx, env, exclusive)
+	}
+	return func() {}, nil
+}
+
+// Delete mocks the Delete method.
+func (m *MockVaultRepository) Delete(ctx context.Context, env string) (string, error) {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, env)
+	}
+	return env + ".vault.enc.bak.1", nil
+}
+
+// MockIdentityRepository mocks the IdentityRepository for testing.
+type MockIdentityRepository struct {
+	LoadFunc func(ctx context.Context) (model.Identity, error)
+	SaveFunc func(ctx context.Context, identity model.Identity, overwrite bool) (string, error)
+}
+
+// Load mocks the Load method.
+func (m *MockIdentityRepository) Load(ctx context.Context) (model.Identity, error) {
+	if m.LoadFunc != nil {
+		return m.LoadFunc(ctx)
+	}
+	return model.Identity{}, model.ErrNoIdentity
+}
+
+// Save mocks the Save method.
+func (m *MockIdentityRepository) Save(
+	ctx context.Context,
+	identity model.Identity,
+	overwrite bool,
+) (string, error) {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, identity, overwrite)
+	}
+	return "identity.txt", nil
+}
+
 // MockHashService mocks the HashService for testing.
 type MockHashService struct {
 	HashFunc         func(passphrase string) (string, error)
@@ -268,9 +596,14 @@ func (m *MockHashService) GenerateSalt(size int) (string, error) {
 // MockPassphraseService mocks the PassphraseService for testing.
 type MockPassphraseService struct {
 	GetFunc      func(ctx context.Context, env string) (string, error)
+	GetNewFunc   func(ctx context.Context, env string) (string, error)
 	ClearFunc    func(ctx context.Context, env string) error
 	ClearAllFunc func(ctx context.Context) error
-	ValidateFunc func(ctx context.Context, vault *model.Vault, passphrase string) error
+	ValidateFunc func(
+		ctx context.Context,
+		vault *model.Vault,
+		passphrase string,
+	) (model.KeySlot, error)
 }
 
 // Get mocks the Get method.
@@ -281,6 +614,14 @@ func (m *MockPassphraseService) Get(ctx context.Context, env string) (string, er
 	return "test-passphrase", nil
 }
 
+// GetNew mocks the GetNew method, falling back to Get.
+func (m *MockPassphraseService) GetNew(ctx context.Context, env string) (string, error) {
+	if m.GetNewFunc != nil {
+		return m.GetNewFunc(ctx, env)
+	}
+	return m.Get(ctx, env)
+}
+
 // Clear mocks the Clear method.
 func (m *MockPassphraseService) Clear(ctx context.Context, env string) error {
 	if m.ClearFunc != nil {
@@ -302,9 +643,308 @@ func (m *MockPassphraseService) Validate(
 	ctx context.Context,
 	vault *model.Vault,
 	passphrase string,
-) error {
+) (model.KeySlot, error) {
 	if m.ValidateFunc != nil {
 		return m.ValidateFunc(ctx, vault, passphrase)
 	}
+	return vault.Meta.KeySlots()[0], nil
+}
+
+// MockPassphrasePolicy mocks the PassphrasePolicy for testing, accepting every passphrase
+// unless CheckFunc is set.
+type MockPassphrasePolicy struct {
+	CheckFunc func(ctx context.Context, env, passphrase string) error
+}
+
+// Check mocks the Check method.
+func (m *MockPassphrasePolicy) Check(ctx context.Context, env, passphrase string) error {
+	if m.CheckFunc != nil {
+		return m.CheckFunc(ctx, env, passphrase)
+	}
+	return nil
+}
+
+// MockCache mocks the Cache for testing, keeping values in Values and their TTLs in TTLs.
+type MockCache struct {
+	Values map[string]string
+	TTLs   map[string]time.Duration
+}
+
+// Set mocks the Set method.
+func (m *MockCache) Set(key, value string, ttl time.Duration) error {
+	if m.Values == nil {
+		m.Values = make(map[string]string)
+	}
+	if m.TTLs == nil {
+		m.TTLs = make(map[string]time.Duration)
+	}
+	m.Values[key] = value
+	m.TTLs[key] = ttl
+	return nil
+}
+
+// Get mocks the Get method.
+func (m *MockCache) Get(key string) (string, error) {
+	value, ok := m.Values[key]
+	if !ok {
+		return "", errors.New("not found in cache")
+	}
+	return value, nil
+}
+
+// Delete mocks the Delete method.
+func (m *MockCache) Delete(key string) error {
+	delete(m.Values, key)
+	return nil
+}
+
+// DeleteAll mocks the DeleteAll method.
+func (m *MockCache) DeleteAll() error {
+	clear(m.Values)
+	return nil
+}
+
+// MockEditorService mocks the EditorService for testing.
+type MockEditorService struct {
+	EditFunc func(name string, content []byte) ([]byte, error)
+}
+
+// Edit mocks the Edit method, returning the content unchanged by default.
+func (m *MockEditorService) Edit(name string, content []byte) ([]byte, error) {
+	if m.EditFunc != nil {
+		return m.EditFunc(name, content)
+	}
+	return content, nil
+}
+
+// MockProcessRunner mocks the ProcessRunner for testing.
+type MockProcessRunner struct {
+	RunFunc func(ctx context.Context, name string, args, env []string) (int, error)
+}
+
+// Run mocks the Run method.
+func (m *MockProcessRunner) Run(ctx context.Context, name string, args, env []string) (int, error) {
+	if m.RunFunc != nil {
+		return m.RunFunc(ctx, name, args, env)
+	}
+	return 0, nil
+}
+
+// MockAuthorService mocks the AuthorService for testing.
+type MockAuthorService struct {
+	AuthorFunc func() string
+	HostFunc   func() string
+}
+
+// Author mocks the Author method, returning "test-author" by default.
+func (m *MockAuthorService) Author() string {
+	if m.AuthorFunc != nil {
+		return m.AuthorFunc()
+	}
+	return "test-author"
+}
+
+// Host mocks the Host method, returning "test-host" by default.
+func (m *MockAuthorService) Host() string {
+	if m.HostFunc != nil {
+		return m.HostFunc()
+	}
+	return "test-host"
+}
+
+// MockAuditLog mocks the AuditLog for testing, keeping recorded events in Recorded.
+type MockAuditLog struct {
+	RecordFunc func(
+		ctx context.Context,
+		vault *model.Vault,
+		operation model.AuditOperation,
+		keys ...string,
+	) error
+	EventsFunc  func(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error)
+	RewriteFunc func(ctx context.Context, vault *model.Vault, events []model.AuditEvent) error
+	Recorded    []model.AuditEvent
+}
+
+// Record mocks the Record method, storing the event with its keys sorted.
+func (m *MockAuditLog) Record(
+	ctx context.Context,
+	vault *model.Vault,
+	operation model.AuditOperation,
+	keys ...string,
+) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, vault, operation, keys...)
+	}
+	keys = slices.Clone(keys)
+	slices.Sort(keys)
+	m.Recorded = append(m.Recorded, model.AuditEvent{
+		Seq:       len(m.Recorded) + 1,
+		Operation: operation,
+		Keys:      keys,
+		Slot:      vault.UnlockedSlot(),
+	})
+	return nil
+}
+
+// Events mocks the Events method, returning the recorded events by default.
+func (m *MockAuditLog) Events(ctx context.Context, vault *model.Vault) ([]model.AuditEvent, error) {
+	if m.EventsFunc != nil {
+		return m.EventsFunc(ctx, vault)
+	}
+	return m.Recorded, nil
+}
+
+// Rewrite mocks the Rewrite method, replacing the recorded events by default.
+func (m *MockAuditLog) Rewrite(
+	ctx context.Context,
+	vault *model.Vault,
+	events []model.AuditEvent,
+) error {
+	if m.RewriteFunc != nil {
+		return m.RewriteFunc(ctx, vault, events)
+	}
+	m.Recorded = slices.Clone(events)
+	return nil
+}
+
+// MockAuditRepository mocks the AuditRepository for testing, keeping records per environment
+// in Records.
+type MockAuditRepository struct {
+	LoadFunc    func(ctx context.Context, env string) ([]string, error)
+	AppendFunc  func(ctx context.Context, env string, record string) error
+	ReplaceFunc func(ctx context.Context, env string, records []string) error
+	Records     map[string][]string
+}
+
+// Load mocks the Load method, returning the stored records by default.
+func (m *MockAuditRepository) Load(ctx context.Context, env string) ([]string, error) {
+	if m.LoadFunc != nil {
+		return m.LoadFunc(ctx, env)
+	}
+	return m.Records[env], nil
+}
+
+// Append mocks the Append method, storing the sealed record by default.
+func (m *MockAuditRepository) Append(
+	ctx context.Context,
+	env string,
+	seal func(last string, count int) (string, error),
+) error {
+	records := m.Records[env]
+	last := ""
+	if len(records) > 0 {
+		last = records[len(records)-1]
+	}
+	record, err := seal(last, len(records))
+	if err != nil {
+		return err
+	}
+	if m.AppendFunc != nil {
+		return m.AppendFunc(ctx, env, record)
+	}
+	if m.Records == nil {
+		m.Records = map[string][]string{}
+	}
+	m.Records[env] = append(records, record)
+	return nil
+}
+
+// Replace mocks the Replace method, replacing the stored records by default.
+func (m *MockAuditRepository) Replace(ctx context.Context, env string, records []string) error {
+	if m.ReplaceFunc != nil {
+		return m.ReplaceFunc(ctx, env, records)
+	}
+	if m.Records == nil {
+		m.Records = map[string][]string{}
+	}
+	m.Records[env] = records
+	return nil
+}
+
+// MockKeyAgent mocks the KeyAgent for testing, keeping added keys in Added. It holds no
+// keys by default.
+type MockKeyAgent struct {
+	KeyFunc    func(ctx context.Context, id string) (model.AgentKey, error)
+	AddFunc    func(ctx context.Context, key model.AgentKey) error
+	ForgetFunc func(ctx context.Context, env string) (int, error)
+	LockFunc   func(ctx context.Context) (int, error)
+	StatusFunc func(ctx context.Context) (model.AgentStatus, error)
+	Added      []model.AgentKey
+}
+
+// Key mocks the Key method.
+func (m *MockKeyAgent) Key(ctx context.Context, id string) (model.AgentKey, error) {
+	if m.KeyFunc != nil {
+		return m.KeyFunc(ctx, id)
+	}
+	return model.AgentKey{}, model.ErrAgentNoKey
+}
+
+// Add mocks the Add method.
+func (m *MockKeyAgent) Add(ctx context.Context, key model.AgentKey) error {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, key)
+	}
+	m.Added = append(m.Added, key)
+	return nil
+}
+
+// Forget mocks the Forget method.
+func (m *MockKeyAgent) Forget(ctx context.Context, env string) (int, error) {
+	if m.ForgetFunc != nil {
+		return m.ForgetFunc(ctx, env)
+	}
+	return 0, nil
+}
+
+// Lock mocks the Lock method.
+func (m *MockKeyAgent) Lock(ctx context.Context) (int, error) {
+	if m.LockFunc != nil {
+		return m.LockFunc(ctx)
+	}
+	return 0, nil
+}
+
+// Status mocks the Status method.
+func (m *MockKeyAgent) Status(ctx context.Context) (model.AgentStatus, error) {
+	if m.StatusFunc != nil {
+		return m.StatusFunc(ctx)
+	}
+	return model.AgentStatus{}, nil
+}
+
+// MockVaultUpgrader mocks the VaultUpgrader for testing. By default it only moves the vault
+// to the current format version.
+type MockVaultUpgrader struct {
+	UpgradeFunc func(vault *model.Vault) error
+}
+
+// Upgrade mocks the Upgrade method.
+func (m *MockVaultUpgrader) Upgrade(vault *model.Vault) error {
+	if m.UpgradeFunc != nil {
+		return m.UpgradeFunc(vault)
+	}
+	vault.Meta.FormatVersion = model.CurrentFormatVersion
+	return nil
+}
+
+// MockAgentServer mocks the AgentServer for testing. By default it reports ready on
+// "agent.sock" and returns.
+type MockAgentServer struct {
+	ServeFunc func(ctx context.Context, policy model.AgentPolicy, ready func(string)) error
+}
+
+// Serve mocks the Serve method.
+func (m *MockAgentServer) Serve(
+	ctx context.Context,
+	policy model.AgentPolicy,
+	ready func(socket string),
+) error {
+	if m.ServeFunc != nil {
+		return m.ServeFunc(ctx, policy, ready)
+	}
+	if ready != nil {
+		ready("agent.sock")
+	}
 	return nil
 }